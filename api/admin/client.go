@@ -77,3 +77,34 @@ func (c *Client) Stacktrace() (bool, error) {
 	err := c.requester.SendRequest("stacktrace", struct{}{}, res)
 	return res.Success, err
 }
+
+func (c *Client) VerifyDatabase(chains []string) ([]ChainFindings, error) {
+	res := &VerifyDatabaseReply{}
+	err := c.requester.SendRequest("verifyDatabase", &VerifyDatabaseArgs{
+		Chains: chains,
+	}, res)
+	return res.Chains, err
+}
+
+func (c *Client) AddStaticPeer(nodeID, ip string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("addStaticPeer", &AddStaticPeerArgs{
+		NodeID: nodeID,
+		IP:     ip,
+	}, res)
+	return res.Success, err
+}
+
+func (c *Client) RemoveStaticPeer(nodeID string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("removeStaticPeer", &RemoveStaticPeerArgs{
+		NodeID: nodeID,
+	}, res)
+	return res.Success, err
+}
+
+func (c *Client) GetStaticPeers() ([]StaticPeer, error) {
+	res := &GetStaticPeersReply{}
+	err := c.requester.SendRequest("getStaticPeers", struct{}{}, res)
+	return res.Peers, err
+}
@@ -61,6 +61,9 @@ func (mc *mockClient) SendRequest(method string, params interface{}, reply inter
 	case *GetChainAliasesReply:
 		response := mc.response.(*GetChainAliasesReply)
 		*p = *response
+	case *GetStaticPeersReply:
+		response := mc.response.(*GetStaticPeersReply)
+		*p = *response
 	default:
 		panic("illegal type")
 	}
@@ -221,3 +224,68 @@ func TestStacktrace(t *testing.T) {
 		}
 	}
 }
+
+func TestAddStaticPeer(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := Client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.AddStaticPeer("NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET", "127.0.0.1:9651")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestRemoveStaticPeer(t *testing.T) {
+	tests := GetSuccessResponseTests()
+
+	for _, test := range tests {
+		mockClient := Client{requester: NewMockClient(api.SuccessResponse{Success: test.Success}, test.Err)}
+		success, err := mockClient.RemoveStaticPeer("NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET")
+		// if there is error as expected, the test passes
+		if err != nil && test.Err != nil {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexepcted error: %s", err)
+		}
+		if success != test.Success {
+			t.Fatalf("Expected success response to be: %v, but found: %v", test.Success, success)
+		}
+	}
+}
+
+func TestGetStaticPeers(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		expectedReply := []StaticPeer{
+			{
+				NodeID: "NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET",
+				IP:     "127.0.0.1:9651",
+			},
+		}
+		mockClient := Client{requester: NewMockClient(&GetStaticPeersReply{
+			Peers: expectedReply,
+		}, nil)}
+
+		reply, err := mockClient.GetStaticPeers()
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedReply, reply)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockClient := Client{requester: NewMockClient(&GetStaticPeersReply{}, errors.New("some error"))}
+
+		_, err := mockClient.GetStaticPeers()
+
+		assert.EqualError(t, err, "some error")
+	})
+}
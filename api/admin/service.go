@@ -5,15 +5,21 @@ package admin
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
@@ -29,28 +35,39 @@ const (
 	stacktraceFile = "stacktrace.txt"
 )
 
-var errAliasTooLong = errors.New("alias length is too long")
+var (
+	errAliasTooLong = errors.New("alias length is too long")
+	errNoChains     = errors.New("no chains were provided")
+	errNotStatic    = errors.New("node isn't a static peer")
+)
 
 // Admin is the API service for node admin management
 type Admin struct {
 	log          logging.Logger
 	profiler     profiler.Profiler
 	chainManager chains.Manager
+	indexer      indexer.Indexer
 	httpServer   *server.Server
+	net          network.Network
 }
 
 // NewService returns a new admin API service
-func NewService(log logging.Logger, chainManager chains.Manager, httpServer *server.Server, profileDir string) (*common.HTTPHandler, error) {
+func NewService(log logging.Logger, chainManager chains.Manager, indexer indexer.Indexer, httpServer *server.Server, net network.Network, profileDir string) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Admin{
-		log:          log,
-		chainManager: chainManager,
-		httpServer:   httpServer,
-		profiler:     profiler.New(profileDir),
-	}, "admin"); err != nil {
+	if err := openrpc.RegisterServices(newServer, openrpc.Service{
+		Receiver: &Admin{
+			log:          log,
+			chainManager: chainManager,
+			indexer:      indexer,
+			httpServer:   httpServer,
+			net:          net,
+			profiler:     profiler.New(profileDir),
+		},
+		Name: "admin",
+	}); err != nil {
 		return nil, err
 	}
 	return &common.HTTPHandler{Handler: newServer}, nil
@@ -162,3 +179,135 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.Succes
 	stacktrace := []byte(logging.Stacktrace{Global: true}.String())
 	return perms.WriteFile(stacktraceFile, stacktrace, perms.ReadWrite)
 }
+
+// VerifyDatabaseArgs are the arguments for calling VerifyDatabase
+type VerifyDatabaseArgs struct {
+	Chains []string `json:"chains"`
+}
+
+// ChainFindings are the inconsistencies found in the database of a chain
+type ChainFindings struct {
+	Chain    string   `json:"chain"`
+	Findings []string `json:"findings"`
+}
+
+// VerifyDatabaseReply are the inconsistencies found in the databases of the
+// given chains
+type VerifyDatabaseReply struct {
+	Chains []ChainFindings `json:"chains"`
+}
+
+// VerifyDatabase checks the state and the indices of the given chains for
+// inconsistencies. The databases aren't modified.
+func (service *Admin) VerifyDatabase(_ *http.Request, args *VerifyDatabaseArgs, reply *VerifyDatabaseReply) error {
+	service.log.Debug("Admin: VerifyDatabase called with Chains: %v", args.Chains)
+
+	if len(args.Chains) == 0 {
+		return errNoChains
+	}
+
+	reply.Chains = make([]ChainFindings, len(args.Chains))
+	for i, chain := range args.Chains {
+		chainID, err := service.chainManager.Lookup(chain)
+		if err != nil {
+			return err
+		}
+
+		findings, err := service.chainManager.VerifyDatabase(chainID)
+		if err != nil {
+			return fmt.Errorf("couldn't verify the database of chain %s: %w", chain, err)
+		}
+		indexFindings, err := service.indexer.VerifyChain(chainID)
+		if err != nil {
+			return fmt.Errorf("couldn't verify the indices of chain %s: %w", chain, err)
+		}
+		findings = append(findings, indexFindings...)
+		if len(findings) > 0 {
+			service.log.Warn("found %d inconsistencies in the database of chain %s", len(findings), chain)
+		}
+
+		reply.Chains[i] = ChainFindings{
+			Chain:    chain,
+			Findings: findings,
+		}
+	}
+	return nil
+}
+
+// AddStaticPeerArgs are the arguments for calling AddStaticPeer
+type AddStaticPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	IP     string `json:"ip"`
+}
+
+// AddStaticPeer makes the node always stay connected to the given peer
+func (service *Admin) AddStaticPeer(_ *http.Request, args *AddStaticPeerArgs, reply *api.SuccessResponse) error {
+	service.log.Debug("Admin: AddStaticPeer called with NodeID: %s, IP: %s", args.NodeID, args.IP)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID %q: %w", args.NodeID, err)
+	}
+	ip, err := utils.ToIPDesc(args.IP)
+	if err != nil {
+		return fmt.Errorf("couldn't parse ip %q: %w", args.IP, err)
+	}
+
+	if err := service.net.AddStaticPeer(nodeID, ip); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// RemoveStaticPeerArgs are the arguments for calling RemoveStaticPeer
+type RemoveStaticPeerArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// RemoveStaticPeer stops the node from reconnecting to the given static peer
+// and closes an existing connection to it. If the peer is a validator the node
+// would connect to anyways, it's reconnected to.
+func (service *Admin) RemoveStaticPeer(_ *http.Request, args *RemoveStaticPeerArgs, reply *api.SuccessResponse) error {
+	service.log.Debug("Admin: RemoveStaticPeer called with NodeID: %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("couldn't parse nodeID %q: %w", args.NodeID, err)
+	}
+
+	if !service.net.RemoveStaticPeer(nodeID) {
+		return fmt.Errorf("%w: %s", errNotStatic, args.NodeID)
+	}
+	reply.Success = true
+	return nil
+}
+
+// StaticPeer is a peer the node always stays connected to
+type StaticPeer struct {
+	NodeID string `json:"nodeID"`
+	IP     string `json:"ip"`
+}
+
+// GetStaticPeersReply are the static peers of the node
+type GetStaticPeersReply struct {
+	Peers []StaticPeer `json:"peers"`
+}
+
+// GetStaticPeers returns the static peers of the node, sorted by node ID
+func (service *Admin) GetStaticPeers(_ *http.Request, _ *struct{}, reply *GetStaticPeersReply) error {
+	service.log.Debug("Admin: GetStaticPeers called")
+
+	staticPeers := service.net.StaticPeers()
+	reply.Peers = make([]StaticPeer, 0, len(staticPeers))
+	for nodeID, ip := range staticPeers {
+		reply.Peers = append(reply.Peers, StaticPeer{
+			NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+			IP:     ip.String(),
+		})
+	}
+	sort.Slice(reply.Peers, func(i, j int) bool {
+		return reply.Peers[i].NodeID < reply.Peers[j].NodeID
+	})
+	return nil
+}
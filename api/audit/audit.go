@@ -0,0 +1,273 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	headerKey      = "Authorization"
+	headerValStart = "Bearer "
+
+	// maxLineSize is the maximum size of a line of the audit log
+	maxLineSize = 1 << 20
+
+	// keyLen is the number of bytes of the key that entries are MACed with
+	keyLen = 32
+)
+
+var (
+	keyKey  = []byte("key")
+	headKey = []byte("head")
+)
+
+// DefaultMethods are the patterns, as accepted by path.Match, of the methods
+// that are audited by default
+var DefaultMethods = []string{
+	"admin.*",
+	"auth.*",
+	"avm.exportKey",
+	"avm.importKey",
+	"ipcs.*",
+	"keystore.*",
+	"platform.exportKey",
+	"platform.importKey",
+}
+
+// TokenIntrospector returns the claims of an auth token
+type TokenIntrospector interface {
+	IntrospectToken(token string) (auth.TokenInfo, error)
+}
+
+// Auditor records calls to the API methods that match one of its patterns to
+// an audit log. Each entry of the log holds the hash of the entry before it,
+// so that modifying or removing entries can be detected with Verify. The
+// hashes are MACs under a key that is kept in the auditor's database, along
+// with the hash of the last entry written, so that the chain continues across
+// restarts.
+type Auditor struct {
+	log logging.Logger
+	db  database.Database
+	// key that entries are MACed with
+	key []byte
+	// patterns, as accepted by path.Match, of the methods to audit
+	methods []string
+	// Used to identify the token that authorized a call. May be nil.
+	tokens TokenIntrospector
+
+	// Used to mock time.
+	clock timer.Clock
+
+	lock sync.Mutex
+	// hash of the last entry written
+	lastHash ids.ID
+}
+
+// New returns an Auditor that writes to [log] an entry for each call to a
+// method matching one of [methods]. If [tokens] is non-nil, it is used to
+// record the ID of the token that authorized each call. The key and the chain
+// head are kept in [db]. If [db] doesn't hold a key yet, a new one is
+// generated.
+func New(log logging.Logger, methods []string, tokens TokenIntrospector, db database.Database) (*Auditor, error) {
+	for _, method := range methods {
+		if _, err := path.Match(method, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := Key(db)
+	if err == database.ErrNotFound {
+		key = make([]byte, keyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("couldn't generate audit key: %w", err)
+		}
+		err = db.Put(keyKey, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load audit key: %w", err)
+	}
+	lastHash, err := Head(db)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load audit chain head: %w", err)
+	}
+	return &Auditor{
+		log:      log,
+		db:       db,
+		key:      key,
+		methods:  methods,
+		tokens:   tokens,
+		lastHash: lastHash,
+	}, nil
+}
+
+// Key returns the key that the entries of the audit log whose auditor kept its
+// state in [db] are MACed with. Returns database.ErrNotFound if no entries
+// were ever MACed with a key from [db].
+func Key(db database.KeyValueReader) ([]byte, error) {
+	return db.Get(keyKey)
+}
+
+// Head returns the hash of the last entry written to the audit log whose
+// auditor kept its state in [db], or ids.Empty if no entry was written.
+func Head(db database.KeyValueReader) (ids.ID, error) {
+	headBytes, err := db.Get(headKey)
+	if err == database.ErrNotFound {
+		return ids.Empty, nil
+	}
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ids.ToID(headBytes)
+}
+
+// rpcRequest is the part of a JSON-RPC request that is recorded
+type rpcRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// WrapHandler records the audited calls of each request before passing it to
+// [h]
+func (a *Auditor) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Body != nil {
+			body, err := ioutil.ReadAll(r.Body)
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				a.recordUnparsed(r)
+			} else {
+				a.record(r, body)
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// record writes an entry for each audited call in [body], which is the body
+// of [r]
+func (a *Auditor) record(r *http.Request, body []byte) {
+	requests, err := parseRequests(body)
+	if err != nil {
+		a.recordUnparsed(r)
+		return
+	}
+
+	identity := ""
+	for _, request := range requests {
+		if !a.audits(request.Method) {
+			continue
+		}
+		if identity == "" {
+			identity = a.identity(r)
+		}
+		a.write(Entry{
+			Time:       a.clock.Time().UTC(),
+			RemoteAddr: r.RemoteAddr,
+			TokenID:    identity,
+			Endpoint:   r.URL.Path,
+			Method:     request.Method,
+			Args:       redact(request.Params),
+		})
+	}
+}
+
+// recordUnparsed writes an entry for [r], whose body couldn't be parsed. The
+// request is still passed on, so it may call any method.
+func (a *Auditor) recordUnparsed(r *http.Request) {
+	a.write(Entry{
+		Time:       a.clock.Time().UTC(),
+		RemoteAddr: r.RemoteAddr,
+		TokenID:    a.identity(r),
+		Endpoint:   r.URL.Path,
+		Unparsed:   true,
+	})
+}
+
+// parseRequests returns the JSON-RPC requests in [body], which is either a
+// request or a batch request. Like the JSON-RPC codec, only the first JSON
+// value of [body] is decoded and anything after it is ignored, so that the
+// calls that are recorded are the calls that are executed.
+func parseRequests(body []byte) ([]rpcRequest, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var requests []rpcRequest
+		err := decoder.Decode(&requests)
+		return requests, err
+	}
+	request := rpcRequest{}
+	err := decoder.Decode(&request)
+	return []rpcRequest{request}, err
+}
+
+// audits returns true if calls to [method] are recorded
+func (a *Auditor) audits(method string) bool {
+	for _, pattern := range a.methods {
+		// The patterns were validated in New, so path.ErrBadPattern can't be
+		// returned.
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// identity returns the client that [r] was authenticated as, or the ID of the
+// auth token passed with [r] if [r] wasn't authenticated. Returns the empty
+// string if neither is known.
+func (a *Auditor) identity(r *http.Request) string {
+	if identity := auth.Identity(r); identity != "" {
+		return identity
+	}
+	if a.tokens == nil {
+		return ""
+	}
+	rawHeader := r.Header.Get(headerKey)
+	if !strings.HasPrefix(rawHeader, headerValStart) {
+		return ""
+	}
+	info, err := a.tokens.IntrospectToken(rawHeader[len(headerValStart):])
+	if err != nil {
+		return ""
+	}
+	return info.ID
+}
+
+// write chains [entry] to the last entry written and writes it to the log
+func (a *Auditor) write(entry Entry) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	entry.PrevHash = a.lastHash
+	hash, err := entry.computeHash(a.key)
+	if err != nil {
+		a.log.Error("couldn't hash audit log entry for %s: %s", entry.Method, err)
+		return
+	}
+	entry.Hash = hash
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		a.log.Error("couldn't marshal audit log entry for %s: %s", entry.Method, err)
+		return
+	}
+	a.log.Info("%s", string(entryBytes))
+	a.lastHash = hash
+	if err := a.db.Put(headKey, hash[:]); err != nil {
+		a.log.Error("couldn't persist audit chain head after %s: %s", entry.Method, err)
+	}
+}
@@ -0,0 +1,238 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// recordingLog records the messages logged at the Info level
+type recordingLog struct {
+	logging.NoLog
+	lines []string
+}
+
+func (l *recordingLog) Info(format string, args ...interface{}) {
+	l.lines = append(l.lines, "INFO [10-18|00:00:00] audit.go#1: "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLog) entries(t *testing.T) []Entry {
+	entries, err := ParseEntries(strings.NewReader(strings.Join(l.lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+type testIntrospector map[string]string
+
+func (ti testIntrospector) IntrospectToken(token string) (auth.TokenInfo, error) {
+	id, ok := ti[token]
+	if !ok {
+		return auth.TokenInfo{}, errors.New("unknown token")
+	}
+	return auth.TokenInfo{ID: id}, nil
+}
+
+func serveAudited(t *testing.T, a *Auditor, body, token string) {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/keystore", strings.NewReader(body))
+	req.RemoteAddr = "1.2.3.4:5000"
+	if token != "" {
+		req.Header.Set(headerKey, headerValStart+token)
+	}
+
+	called := false
+	a.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		// The handler should receive the whole body
+		handlerBody, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(handlerBody))
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, called)
+}
+
+func TestAuditorRecordsAuditedMethods(t *testing.T) {
+	assert := assert.New(t)
+	log := &recordingLog{}
+	a, err := New(log, DefaultMethods, testIntrospector{"token": "tokenID"}, memdb.New())
+	assert.NoError(err)
+
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"keystore.exportUser","params":{"username":"bob","password":"hunter2"},"id":1}`, "token")
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"avm.getBalance","params":{},"id":1}`, "")
+	serveAudited(t, a, `[
+		{"jsonrpc":"2.0","method":"info.getNodeID","id":1},
+		{"jsonrpc":"2.0","method":"avm.exportKey","params":{"username":"bob","password":"hunter2","address":"X-local1"},"id":2}
+	]`, "unknown token")
+
+	entries := log.entries(t)
+	if !assert.Len(entries, 2) {
+		return
+	}
+
+	assert.Equal("keystore.exportUser", entries[0].Method)
+	assert.Equal("1.2.3.4:5000", entries[0].RemoteAddr)
+	assert.Equal("tokenID", entries[0].TokenID)
+	assert.Equal("/ext/keystore", entries[0].Endpoint)
+	assert.JSONEq(`{"username":"bob","password":"[redacted]"}`, string(entries[0].Args))
+	assert.Equal(ids.Empty, entries[0].PrevHash)
+
+	assert.Equal("avm.exportKey", entries[1].Method)
+	assert.Empty(entries[1].TokenID)
+	assert.JSONEq(`{"username":"bob","password":"[redacted]","address":"X-local1"}`, string(entries[1].Args))
+	assert.Equal(entries[0].Hash, entries[1].PrevHash)
+
+	assert.NoError(Verify(entries, a.key))
+}
+
+func TestAuditorTruncatesLongArgs(t *testing.T) {
+	assert := assert.New(t)
+	log := &recordingLog{}
+	a, err := New(log, []string{"keystore.importUser"}, nil, memdb.New())
+	assert.NoError(err)
+
+	user := strings.Repeat("a", 1000)
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"keystore.importUser","params":{"user":"`+user+`"},"id":1}`, "")
+
+	entries := log.entries(t)
+	if !assert.Len(entries, 1) {
+		return
+	}
+	assert.JSONEq(fmt.Sprintf(`{"user":"%s...(1000 bytes)"}`, user[:maxStringLen]), string(entries[0].Args))
+}
+
+func TestAuditorIgnoresTrailingBytes(t *testing.T) {
+	assert := assert.New(t)
+	log := &recordingLog{}
+	a, err := New(log, DefaultMethods, nil, memdb.New())
+	assert.NoError(err)
+
+	// The codec executes the first request and ignores what follows it
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"keystore.exportUser","params":{"username":"bob"},"id":1} garbage`, "")
+
+	entries := log.entries(t)
+	if !assert.Len(entries, 1) {
+		return
+	}
+	assert.Equal("keystore.exportUser", entries[0].Method)
+	assert.False(entries[0].Unparsed)
+}
+
+func TestAuditorRecordsUnparsedRequests(t *testing.T) {
+	assert := assert.New(t)
+	log := &recordingLog{}
+	a, err := New(log, DefaultMethods, testIntrospector{"token": "tokenID"}, memdb.New())
+	assert.NoError(err)
+
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":`, "token")
+
+	entries := log.entries(t)
+	if !assert.Len(entries, 1) {
+		return
+	}
+	assert.True(entries[0].Unparsed)
+	assert.Empty(entries[0].Method)
+	assert.Equal("tokenID", entries[0].TokenID)
+	assert.Equal("/ext/keystore", entries[0].Endpoint)
+	assert.NoError(Verify(entries, a.key))
+}
+
+func TestAuditorInvalidPattern(t *testing.T) {
+	_, err := New(&recordingLog{}, []string{"["}, nil, memdb.New())
+	assert.Error(t, err)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	log := &recordingLog{}
+	a, err := New(log, []string{"*"}, nil, memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		serveAudited(t, a, fmt.Sprintf(`{"jsonrpc":"2.0","method":"admin.aliasChain","params":{"alias":"%d"},"id":1}`, i), "")
+	}
+
+	entries := log.entries(t)
+	assert.NoError(t, Verify(entries, a.key))
+
+	modified := append([]Entry(nil), entries...)
+	modified[1].Args = []byte(`{"alias":"other"}`)
+	assert.Error(t, Verify(modified, a.key))
+
+	removed := []Entry{entries[0], entries[2]}
+	assert.Error(t, Verify(removed, a.key))
+
+	// Restarting the chain requires the key to recompute the hashes
+	restarted := append([]Entry(nil), entries[2:]...)
+	restarted = append(restarted, entries[0])
+	assert.Error(t, Verify(restarted, a.key))
+	otherKey := make([]byte, keyLen)
+	for i := range restarted {
+		if i > 0 {
+			restarted[i].PrevHash = restarted[i-1].Hash
+		}
+		restarted[i].Hash, err = restarted[i].computeHash(otherKey)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, Verify(restarted, otherKey))
+	assert.Error(t, Verify(restarted, a.key))
+}
+
+func TestAuditorChainPersists(t *testing.T) {
+	assert := assert.New(t)
+	db := memdb.New()
+	log := &recordingLog{}
+
+	a, err := New(log, []string{"*"}, nil, db)
+	assert.NoError(err)
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"admin.aliasChain","params":{},"id":1}`, "")
+
+	// The restarted auditor continues the chain with the same key
+	a, err = New(log, []string{"*"}, nil, db)
+	assert.NoError(err)
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"admin.aliasChain","params":{},"id":1}`, "")
+
+	entries := log.entries(t)
+	if !assert.Len(entries, 2) {
+		return
+	}
+	assert.Equal(ids.Empty, entries[0].PrevHash)
+	assert.Equal(entries[0].Hash, entries[1].PrevHash)
+
+	key, err := Key(db)
+	assert.NoError(err)
+	assert.NoError(Verify(entries, key))
+
+	head, err := Head(db)
+	assert.NoError(err)
+	assert.Equal(entries[1].Hash, head)
+}
+
+func TestAuditorRedactsSensitiveArgs(t *testing.T) {
+	log := &recordingLog{}
+	a, err := New(log, []string{"*"}, nil, memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveAudited(t, a, `{"jsonrpc":"2.0","method":"keystore.importUser","params":{"username":"bob","mnemonic":"a b c","passphrase":"d","privateKey":"e"},"id":1}`, "")
+
+	entries := log.entries(t)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+	assert.JSONEq(t, `{"username":"bob","mnemonic":"[redacted]","passphrase":"[redacted]","privateKey":"[redacted]"}`, string(entries[0].Args))
+}
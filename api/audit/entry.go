@@ -0,0 +1,100 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Entry records a call to a sensitive API method. [TokenID] identifies the
+// client that made the call, by the ID of its auth token or the fingerprint of
+// its client certificate.
+type Entry struct {
+	Time       time.Time       `json:"time"`
+	RemoteAddr string          `json:"remoteAddr"`
+	TokenID    string          `json:"tokenID,omitempty"`
+	Endpoint   string          `json:"endpoint"`
+	Method     string          `json:"method"`
+	Args       json.RawMessage `json:"args,omitempty"`
+
+	// True if the body of the request couldn't be parsed, in which case the
+	// called method isn't known
+	Unparsed bool `json:"unparsed,omitempty"`
+
+	// Hash of the previous entry. Empty for the first entry written to the
+	// node's audit log.
+	PrevHash ids.ID `json:"prevHash"`
+	// HMAC-SHA256 of this entry under the node's audit key, computed with this
+	// field empty
+	Hash ids.ID `json:"hash"`
+}
+
+// computeHash returns the MAC of [e] under [key]. The MAC depends on
+// [e.PrevHash] so that modifying or removing an entry changes the hashes of
+// the entries after it, and on [key] so that the hashes can't be recomputed
+// without it.
+func (e Entry) computeHash(key []byte) (ids.ID, error) {
+	e.Hash = ids.Empty
+	entryBytes, err := json.Marshal(e)
+	if err != nil {
+		return ids.Empty, err
+	}
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(entryBytes)
+	return ids.ToID(mac.Sum(nil))
+}
+
+// ParseEntries returns the entries in the audit log read from [r]. Lines that
+// don't hold an entry are skipped.
+func ParseEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Each line is prefixed by the log's level, time and location.
+		start := bytes.IndexByte(line, '{')
+		if start == -1 {
+			continue
+		}
+		entry := Entry{}
+		if err := json.Unmarshal(line[start:], &entry); err != nil {
+			return nil, fmt.Errorf("couldn't parse audit log entry %q: %w", line[start:], err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Verify checks that each of [entries] has a correct MAC under [key], as
+// returned by Key, and that each entry after the first refers to the hash of
+// the entry before it. The first entry may follow an entry that was written to
+// a previous log file. Returns an error describing the first entry that was
+// tampered with.
+// Entries removed from the end of the log are detected by comparing the hash
+// of the last entry with the chain head returned by Head.
+func Verify(entries []Entry, key []byte) error {
+	for i, entry := range entries {
+		hash, err := entry.computeHash(key)
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return fmt.Errorf("entry %d has hash %s but should have hash %s", i, entry.Hash, hash)
+		}
+		if i > 0 && entry.PrevHash != entries[i-1].Hash {
+			return fmt.Errorf("entry %d follows an entry with hash %s but the previous entry has hash %s", i, entry.PrevHash, entries[i-1].Hash)
+		}
+	}
+	return nil
+}
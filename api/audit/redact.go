@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	redacted = "[redacted]"
+
+	// maxStringLen is the length after which string arguments are truncated
+	maxStringLen = 128
+)
+
+// sensitiveKeys are the substrings of the names of arguments whose values are
+// never recorded
+var sensitiveKeys = []string{
+	"mnemonic",
+	"passphrase",
+	"password",
+	"privatekey",
+	"secret",
+	"token",
+}
+
+// redact returns a summary of the JSON-RPC params [params] in which the values
+// of sensitive arguments are replaced and long strings are truncated
+func redact(params json.RawMessage) json.RawMessage {
+	if len(params) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(params, &value); err != nil {
+		return nil
+	}
+	summary, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return summary
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range value {
+			if isSensitive(key) {
+				value[key] = redacted
+			} else {
+				value[key] = redactValue(elem)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, elem := range value {
+			value[i] = redactValue(elem)
+		}
+		return value
+	case string:
+		if len(value) > maxStringLen {
+			return fmt.Sprintf("%s...(%d bytes)", value[:maxStringLen], len(value))
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(key, sensitiveKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,9 +16,11 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/units"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
 )
@@ -33,6 +36,11 @@ const (
 	defaultTokenLifespan = time.Hour * 12
 
 	maxEndpoints = 128
+	maxMethods   = 128
+
+	// maxRequestBodySize is the largest request body that will be read when
+	// checking which methods a request calls
+	maxRequestBodySize = 4 * units.MiB
 )
 
 var (
@@ -45,11 +53,13 @@ var (
 	errInvalidSigningMethod        = fmt.Errorf("auth token didn't specify the HS256 signing method correctly")
 	errTokenRevoked                = errors.New("the provided auth token was revoked")
 	errTokenInsufficientPermission = errors.New("the provided auth token does not allow access to this endpoint")
+	errMethodNotAllowed            = errors.New("the provided auth token does not allow calling this method")
 	errWrongPassword               = errors.New("incorrect password")
 	errSamePassword                = errors.New("new password can't be same as old password")
 	errNoPassword                  = errors.New("no password")
 	errNoEndpoints                 = errors.New("must name at least one endpoint")
 	errTooManyEndpoints            = fmt.Errorf("can only name at most %d endpoints", maxEndpoints)
+	errTooManyMethods              = fmt.Errorf("can only name at most %d methods", maxMethods)
 
 	_ Auth = &auth{}
 )
@@ -58,7 +68,10 @@ type Auth interface {
 	// Create and return a new token that allows access to each API endpoint for
 	// [duration] such that the API's path ends with an element of [endpoints].
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
-	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
+	// The token only allows calling the JSON-RPC methods that match an element
+	// of [methods], which may contain wildcards and presets such as
+	// ReadOnlyPreset. If [methods] is empty, all methods may be called.
+	NewToken(pw string, duration time.Duration, endpoints, methods []string) (string, error)
 
 	// Revokes [token]; it will not be accepted as authorization for future API
 	// calls. If the token is invalid, this is a no-op.  If a token is revoked
@@ -67,8 +80,23 @@ type Auth interface {
 	// re-used before previously revoked tokens have expired.
 	RevokeToken(pw, token string) error
 
-	// Authenticates [token] for access to [url].
-	AuthenticateToken(token, url string) error
+	// Authenticates [token] for calling each of [methods] at [url].
+	AuthenticateToken(token, url string, methods []string) error
+
+	// Returns the tokens issued since the password was last changed that
+	// haven't expired. Tokens issued before the node started aren't returned.
+	ListTokens(pw string) ([]TokenInfo, error)
+
+	// Returns the claims of [token]. Expired and revoked tokens are described
+	// as well, but tokens that weren't signed with the current password
+	// aren't.
+	IntrospectToken(token string) (TokenInfo, error)
+
+	// Grant the API clients that present a verified TLS certificate matching
+	// an element of [permissions] access to the endpoints and methods it
+	// names, without requiring an auth token. Replaces the previously set
+	// permissions.
+	SetClientCertPermissions(permissions []ClientCertPermissions) error
 
 	// Change the password required to create and revoke tokens.
 	// [oldPW] is the current password.
@@ -82,10 +110,23 @@ type Auth interface {
 	CreateHandler() (http.Handler, error)
 
 	// WrapHandler wraps an http.Handler. Before passing a request to the
-	// provided handler, the auth token is authenticated.
+	// provided handler, the auth token is authenticated. The identity the
+	// request was authenticated as is available to the provided handler
+	// through Identity.
 	WrapHandler(h http.Handler) http.Handler
 }
 
+// TokenInfo describes the claims of a token
+type TokenInfo struct {
+	ID        string    `json:"id"`
+	Endpoints []string  `json:"endpoints"`
+	Methods   []string  `json:"methods"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Expired   bool      `json:"expired"`
+	Revoked   bool      `json:"revoked"`
+}
+
 type auth struct {
 	// Used to mock time.
 	clock timer.Clock
@@ -98,6 +139,11 @@ type auth struct {
 	password password.Hash
 	// Set of token IDs that have been revoked
 	revoked map[string]struct{}
+	// Token ID --> claims of the tokens issued since the password was last
+	// changed that haven't expired
+	issued map[string]*endpointClaims
+	// Claims granted to the clients that present matching certificates
+	certClaims []certClaims
 }
 
 func New(log logging.Logger, endpoint, pw string) (Auth, error) {
@@ -105,6 +151,7 @@ func New(log logging.Logger, endpoint, pw string) (Auth, error) {
 		log:      log,
 		endpoint: endpoint,
 		revoked:  make(map[string]struct{}),
+		issued:   make(map[string]*endpointClaims),
 	}
 	return a, a.password.Set(pw)
 }
@@ -115,10 +162,11 @@ func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
 		endpoint: endpoint,
 		password: pw,
 		revoked:  make(map[string]struct{}),
+		issued:   make(map[string]*endpointClaims),
 	}
 }
 
-func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+func (a *auth) NewToken(pw string, duration time.Duration, endpoints, methods []string) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
@@ -127,9 +175,16 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(methods) > maxMethods {
+		return "", errTooManyMethods
+	}
+	methods, err := expandMethods(methods)
+	if err != nil {
+		return "", err
+	}
 
-	a.lock.RLock()
-	defer a.lock.RUnlock()
+	a.lock.Lock()
+	defer a.lock.Unlock()
 
 	if !a.password.Check(pw) {
 		return "", errWrongPassword
@@ -149,11 +204,14 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 	}
 	id := base64.URLEncoding.EncodeToString(idBytes[:])
 
+	now := a.clock.Time()
 	claims := endpointClaims{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: a.clock.Time().Add(duration).Unix(),
+			ExpiresAt: now.Add(duration).Unix(),
+			IssuedAt:  now.Unix(),
 			Id:        id,
 		},
+		Methods: methods,
 	}
 	if canAccessAll {
 		claims.Endpoints = []string{"*"}
@@ -161,7 +219,14 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 		claims.Endpoints = endpoints
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
-	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
+	tokenStr, err := token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
+	if err != nil {
+		return "", err
+	}
+
+	a.pruneIssued()
+	a.issued[id] = &claims
+	return tokenStr, nil
 }
 
 func (a *auth) RevokeToken(tokenStr, pw string) error {
@@ -198,13 +263,23 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 	return nil
 }
 
-func (a *auth) AuthenticateToken(tokenStr, url string) error {
+func (a *auth) AuthenticateToken(tokenStr, url string, methods []string) error {
+	claims, err := a.authenticateEndpoint(tokenStr, url)
+	if err != nil {
+		return err
+	}
+	return authorizeMethods(claims, methods, errMethodNotAllowed)
+}
+
+// authenticateEndpoint authenticates [tokenStr] for access to [url] and
+// returns its claims
+func (a *auth) authenticateEndpoint(tokenStr, url string) (*endpointClaims, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil { // Probably because signature wrong
-		return err
+		return nil, err
 	}
 
 	// Make sure this token gives access to the requested endpoint
@@ -212,20 +287,113 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 	if !ok {
 		// Error is intentionally dropped here as there is nothing left to do
 		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+		return nil, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
 
 	_, revoked := a.revoked[claims.Id]
 	if revoked {
-		return errTokenRevoked
+		return nil, errTokenRevoked
+	}
+
+	if !claims.allowsEndpoint(url) {
+		return nil, errTokenInsufficientPermission
 	}
+	return claims, nil
+}
 
-	for _, endpoint := range claims.Endpoints {
-		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+// authorizeMethods returns nil if [claims] allow calling each of [methods].
+// Otherwise, returns [errNotAllowed] naming the first method that isn't
+// allowed.
+func authorizeMethods(claims *endpointClaims, methods []string, errNotAllowed error) error {
+	for _, method := range methods {
+		if !claims.allowsMethod(method) {
+			return fmt.Errorf("%w: %q", errNotAllowed, method)
+		}
+	}
+	return nil
+}
+
+func (a *auth) SetClientCertPermissions(permissions []ClientCertPermissions) error {
+	allCertClaims := make([]certClaims, len(permissions))
+	for i, certPermissions := range permissions {
+		certClaims, err := newCertClaims(certPermissions)
+		if err != nil {
+			return err
+		}
+		allCertClaims[i] = certClaims
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.certClaims = allCertClaims
+	return nil
+}
+
+func (a *auth) ListTokens(pw string) ([]TokenInfo, error) {
+	if pw == "" {
+		return nil, errNoPassword
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.password.Check(pw) {
+		return nil, errWrongPassword
+	}
+
+	a.pruneIssued()
+	tokens := make([]TokenInfo, 0, len(a.issued))
+	for _, claims := range a.issued {
+		tokens = append(tokens, a.tokenInfo(claims))
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].IssuedAt.Before(tokens[j].IssuedAt)
+	})
+	return tokens, nil
+}
+
+func (a *auth) IntrospectToken(tokenStr string) (TokenInfo, error) {
+	if tokenStr == "" {
+		return TokenInfo{}, errNoToken
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	// The expiration is reported rather than treated as an error.
+	parser := jwt.Parser{SkipClaimsValidation: true}
+	claims := &endpointClaims{}
+	if _, err := parser.ParseWithClaims(tokenStr, claims, a.getTokenKey); err != nil {
+		return TokenInfo{}, err
+	}
+	return a.tokenInfo(claims), nil
+}
+
+// tokenInfo describes [claims].
+// Assumes [a.lock] is held.
+func (a *auth) tokenInfo(claims *endpointClaims) TokenInfo {
+	_, revoked := a.revoked[claims.Id]
+	return TokenInfo{
+		ID:        claims.Id,
+		Endpoints: claims.Endpoints,
+		Methods:   claims.Methods,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		Expired:   a.clock.Time().Unix() >= claims.ExpiresAt,
+		Revoked:   revoked,
+	}
+}
+
+// pruneIssued stops tracking issued tokens that have expired.
+// Assumes [a.lock] is held.
+func (a *auth) pruneIssued() {
+	now := a.clock.Time().Unix()
+	for id, claims := range a.issued {
+		if claims.ExpiresAt <= now {
+			delete(a.issued, id)
 		}
 	}
-	return errTokenInsufficientPermission
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
@@ -249,6 +417,7 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 	// All the revoked tokens are now invalid; no need to mark specifically as
 	// revoked.
 	a.revoked = make(map[string]struct{})
+	a.issued = make(map[string]*endpointClaims)
 	return nil
 }
 
@@ -257,10 +426,7 @@ func (a *auth) CreateHandler() (http.Handler, error) {
 	codec := cjson.NewCodec()
 	server.RegisterCodec(codec, "application/json")
 	server.RegisterCodec(codec, "application/json;charset=UTF-8")
-	return server, server.RegisterService(
-		&service{auth: a},
-		"auth",
-	)
+	return server, openrpc.RegisterServices(server, openrpc.Service{Receiver: &service{auth: a}, Name: "auth"})
 }
 
 func (a *auth) WrapHandler(h http.Handler) http.Handler {
@@ -271,6 +437,21 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 			return
 		}
 
+		// Clients that present a certificate that is granted permissions
+		// don't need an auth token
+		if claims, fingerprint := a.clientCertClaims(r); claims != nil {
+			if !claims.allowsEndpoint(r.URL.Path) {
+				writeUnauthorizedResponse(w, errCertInsufficientPermission)
+				return
+			}
+			if err := authorizeRequestMethods(claims, w, r, errCertMethodNotAllowed); err != nil {
+				writeUnauthorizedResponse(w, err)
+				return
+			}
+			h.ServeHTTP(w, withIdentity(r, certIdentityPrefix+fingerprint))
+			return
+		}
+
 		// Should be "Bearer AUTH.TOKEN.HERE"
 		rawHeader := r.Header.Get(headerKey)
 		if rawHeader == "" {
@@ -286,15 +467,43 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		claims, err := a.authenticateEndpoint(tokenStr, r.URL.Path)
+		if err != nil {
+			writeUnauthorizedResponse(w, err)
+			return
+		}
+
+		if err := authorizeRequestMethods(claims, w, r, errMethodNotAllowed); err != nil {
 			writeUnauthorizedResponse(w, err)
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, withIdentity(r, tokenIdentityPrefix+claims.Id))
 	})
 }
 
+// authorizeRequestMethods returns nil if [claims] allow calling each of the
+// methods that [r] calls
+func authorizeRequestMethods(claims *endpointClaims, w http.ResponseWriter, r *http.Request, errNotAllowed error) error {
+	// Only inspect the request body if the claims restrict the methods that
+	// can be called
+	if claims.allowsAllMethods() {
+		return nil
+	}
+	// Requests that aren't JSON-RPC calls, such as health checks over GET and
+	// websocket upgrades, don't name a method. They are authorized by the
+	// endpoints the claims allow, which have already been checked.
+	if r.Method != http.MethodPost {
+		return nil
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	methods, err := cjson.RequestMethods(r)
+	if err != nil {
+		return err
+	}
+	return authorizeMethods(claims, methods, errNotAllowed)
+}
+
 // getTokenKey returns the key to use when making and parsing tokens
 func (a *auth) getTokenKey(t *jwt.Token) (interface{}, error) {
 	if t.Method != jwt.SigningMethodHS256 {
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -37,10 +38,10 @@ var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request)
 func TestNewTokenWrongPassword(t *testing.T) {
 	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
 
-	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err := auth.NewToken("", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 
-	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"})
+	_, err = auth.NewToken("notThePassword", defaultTokenLifespan, []string{"endpoint1, endpoint2"}, nil)
 	assert.Error(t, err, "should have failed because password is wrong")
 }
 
@@ -52,7 +53,7 @@ func TestNewTokenHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	// Parse the token
@@ -76,7 +77,7 @@ func TestTokenHasWrongSig(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"endpoint1", "endpoint2", "endpoint3"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	// Try to parse the token using the wrong password
@@ -129,7 +130,7 @@ func TestRevokeToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -142,7 +143,7 @@ func TestWrapHandlerHappyPath(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -161,7 +162,7 @@ func TestWrapHandlerRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -187,7 +188,7 @@ func TestWrapHandlerExpiredToken(t *testing.T) {
 
 	// Make a token that expired well in the past
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -223,7 +224,7 @@ func TestWrapHandlerUnauthorizedEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	unauthorizedEndpoints := []string{"/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
@@ -245,7 +246,7 @@ func TestWrapHandlerAuthEndpoint(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/info/foo"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -261,7 +262,7 @@ func TestWrapHandlerAccessAll(t *testing.T) {
 
 	// Make a token that allows access to all endpoints
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics", "", "/foo", "/ext/foo/info"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil)
 	assert.NoError(t, err)
 
 	wrappedHandler := auth.WrapHandler(dummyHandler)
@@ -286,7 +287,7 @@ func TestWrapHandlerMutatedRevokedToken(t *testing.T) {
 
 	// Make a token
 	endpoints := []string{"/ext/info", "/ext/bc/X", "/ext/metrics"}
-	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints)
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, endpoints, nil)
 	assert.NoError(t, err)
 
 	err = auth.RevokeToken(tokenStr, testPassword)
@@ -341,3 +342,216 @@ func TestWrapHandlerInvalidSigningMethod(t *testing.T) {
 		assert.Regexp(t, unAuthorizedResponseRegex, rr.Body.String())
 	}
 }
+
+func TestNewTokenInvalidMethods(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	_, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"@notapreset"})
+	assert.Error(t, err, "should have failed because the preset doesn't exist")
+
+	_, err = auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"avm.[get"})
+	assert.Error(t, err, "should have failed because the pattern is malformed")
+}
+
+func TestWrapHandlerMethods(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"}, []string{"avm.get*", "wallet.getBalance"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		body         string
+		expectedCode int
+	}{
+		{
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.getBalance","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			body:         `{"jsonrpc":"2.0","id":1,"method":"wallet.getBalance","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			body:         `[{"jsonrpc":"2.0","id":1,"method":"avm.getTx"},{"jsonrpc":"2.0","id":2,"method":"avm.getUTXOs"}]`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			body:         `[{"jsonrpc":"2.0","id":1,"method":"avm.getTx"},{"jsonrpc":"2.0","id":2,"method":"avm.exportKey"}]`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			body:         `not json`,
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(test.body))
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		assert.Equal(t, test.expectedCode, rr.Code, test.body)
+	}
+}
+
+func TestWrapHandlerMethodsPreservesBody(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"info.*"})
+	assert.NoError(t, err)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"info.peers"}`
+	var readBody []byte
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readBody, err = ioutil.ReadAll(r.Body)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(body))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(readBody))
+}
+
+func TestWrapHandlerReadOnlyPreset(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{ReadOnlyPreset})
+	assert.NoError(t, err)
+
+	allowed := []string{"avm.getBalance", "platform.getCurrentValidators", "info.isBootstrapped", "info.peers", "health.health"}
+	denied := []string{"avm.send", "avm.exportKey", "platform.addValidator", "admin.alias", "keystore.createUser"}
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+	for _, methods := range []struct {
+		methods      []string
+		expectedCode int
+	}{
+		{methods: allowed, expectedCode: http.StatusOK},
+		{methods: denied, expectedCode: http.StatusUnauthorized},
+	} {
+		for _, method := range methods.methods {
+			body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q}`, method)
+			req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(body))
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+			rr := httptest.NewRecorder()
+			wrappedHandler.ServeHTTP(rr, req)
+			assert.Equal(t, methods.expectedCode, rr.Code, method)
+		}
+	}
+}
+
+func TestWrapHandlerMethodsNonJSONRPC(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/health"}, []string{ReadOnlyPreset})
+	assert.NoError(t, err)
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+
+	// A GET health check is authorized by the token's endpoints
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/health", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// But not for endpoints the token doesn't allow
+	req = httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/info", nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	rr = httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestWrapHandlerMethodsBodyTooLarge(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, []string{"info.*"})
+	assert.NoError(t, err)
+
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"info.peers","params":%q}`, strings.Repeat("a", maxRequestBodySize))
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(body))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	rr := httptest.NewRecorder()
+	auth.WrapHandler(dummyHandler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestWrapHandlerIdentity(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil)
+	assert.NoError(t, err)
+	info, err := auth.IntrospectToken(tokenStr)
+	assert.NoError(t, err)
+
+	var identity string
+	wrappedHandler := auth.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = Identity(r)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(""))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, tokenIdentityPrefix+info.ID, identity)
+
+	// Requests to the auth endpoint aren't authenticated
+	req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/auth", strings.NewReader(""))
+	rr = httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, identity)
+}
+
+func TestListAndIntrospectTokens(t *testing.T) {
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword).(*auth)
+
+	now := time.Now()
+	auth.clock.Set(now)
+
+	token1, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/info"}, nil)
+	assert.NoError(t, err)
+	auth.clock.Set(now.Add(time.Second))
+	token2, err := auth.NewToken(testPassword, time.Minute, []string{"/ext/bc/X"}, []string{ReadOnlyPreset})
+	assert.NoError(t, err)
+
+	_, err = auth.ListTokens("notThePassword")
+	assert.Error(t, err, "should have failed because password is wrong")
+
+	tokens, err := auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, []string{"/ext/info"}, tokens[0].Endpoints)
+	assert.Empty(t, tokens[0].Methods)
+	assert.Equal(t, now.Add(defaultTokenLifespan).Unix(), tokens[0].ExpiresAt.Unix())
+	assert.Equal(t, methodPresets[ReadOnlyPreset], tokens[1].Methods)
+
+	assert.NoError(t, auth.RevokeToken(token1, testPassword))
+	info, err := auth.IntrospectToken(token1)
+	assert.NoError(t, err)
+	assert.True(t, info.Revoked)
+	assert.False(t, info.Expired)
+
+	// Expired tokens can still be introspected but are no longer listed
+	auth.clock.Set(now.Add(2 * time.Minute))
+	info, err = auth.IntrospectToken(token2)
+	assert.NoError(t, err)
+	assert.True(t, info.Expired)
+
+	tokens, err = auth.ListTokens(testPassword)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// Changing the password invalidates the issued tokens
+	assert.NoError(t, auth.ChangePassword(testPassword, "fejhkefjhefjhefhje"))
+	_, err = auth.IntrospectToken(token1)
+	assert.Error(t, err)
+}
@@ -1,9 +1,36 @@
 package auth
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	// presetPrefix is the prefix of an element of a token's methods that names
+	// a preset rather than a method
+	presetPrefix = "@"
+
+	// ReadOnlyPreset expands to the methods that only read state
+	ReadOnlyPreset = presetPrefix + "readonly"
+)
+
+// methodPresets maps the name of a preset to the method patterns it expands to
+var methodPresets = map[string][]string{
+	ReadOnlyPreset: {
+		"*.get*",
+		"*.is*",
+		"*.peers",
+		"*.sampleValidators",
+		"*.validates",
+		"*.validatedBy",
+		"health.*",
+		"index.*",
+	},
+}
+
 // Custom claim type used for API access token
 type endpointClaims struct {
 	jwt.StandardClaims
@@ -12,4 +39,69 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Each element is a pattern, as accepted by path.Match, of the JSON-RPC
+	// methods that the token allows calling. e.g. "avm.getBalance" or "avm.*"
+	// If methods is empty or has an element "*", allows calling all methods
+	Methods []string `json:"methods,omitempty"`
+}
+
+// allowsEndpoint returns true if these claims allow access to [url]
+func (c *endpointClaims) allowsEndpoint(url string) bool {
+	for _, endpoint := range c.Endpoints {
+		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAllMethods returns true if these claims don't restrict which methods
+// can be called
+func (c *endpointClaims) allowsAllMethods() bool {
+	if len(c.Methods) == 0 {
+		return true
+	}
+	for _, method := range c.Methods {
+		if method == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsMethod returns true if these claims allow calling [method]
+func (c *endpointClaims) allowsMethod(method string) bool {
+	if c.allowsAllMethods() {
+		return true
+	}
+	for _, pattern := range c.Methods {
+		// The patterns were validated when the token was created, so
+		// path.ErrBadPattern can't be returned.
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// expandMethods replaces the presets in [methods] with the patterns they
+// expand to and verifies that each pattern is well-formed
+func expandMethods(methods []string) ([]string, error) {
+	expanded := make([]string, 0, len(methods))
+	for _, method := range methods {
+		if !strings.HasPrefix(method, presetPrefix) {
+			if _, err := path.Match(method, ""); err != nil {
+				return nil, fmt.Errorf("invalid method pattern %q: %w", method, err)
+			}
+			expanded = append(expanded, method)
+			continue
+		}
+		patterns, ok := methodPresets[method]
+		if !ok {
+			return nil, fmt.Errorf("unknown method preset %q", method)
+		}
+		expanded = append(expanded, patterns...)
+	}
+	return expanded, nil
 }
@@ -0,0 +1,119 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	errCertInsufficientPermission = errors.New("the provided client certificate does not allow access to this endpoint")
+	errCertMethodNotAllowed       = errors.New("the provided client certificate does not allow calling this method")
+	errCertNotIdentified          = errors.New("client certificate permissions must name exactly one of a subject and a fingerprint")
+)
+
+// ClientCertPermissions grants the API clients that present a verified TLS
+// certificate matching [Subject] or [Fingerprint] the same access that an auth
+// token with [Endpoints] and [Methods] grants.
+type ClientCertPermissions struct {
+	// Subject of the matching certificates, as formatted by
+	// pkix.Name.String(). e.g. "CN=indexer,O=Example"
+	Subject string `json:"subject,omitempty"`
+	// Hex encoded SHA-256 hash of the DER encoding of the matching certificate
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// As passed to NewToken
+	Endpoints []string `json:"endpoints"`
+	Methods   []string `json:"methods"`
+}
+
+// certClaims are the claims granted to the clients whose certificates match
+// [subject] or [fingerprint]
+type certClaims struct {
+	subject     string
+	fingerprint string
+	claims      *endpointClaims
+}
+
+// newCertClaims verifies [permissions] and returns the claims they grant
+func newCertClaims(permissions ClientCertPermissions) (certClaims, error) {
+	if (permissions.Subject == "") == (permissions.Fingerprint == "") {
+		return certClaims{}, errCertNotIdentified
+	}
+	if l := len(permissions.Endpoints); l == 0 {
+		return certClaims{}, errNoEndpoints
+	} else if l > maxEndpoints {
+		return certClaims{}, errTooManyEndpoints
+	}
+	if len(permissions.Methods) > maxMethods {
+		return certClaims{}, errTooManyMethods
+	}
+	methods, err := expandMethods(permissions.Methods)
+	if err != nil {
+		return certClaims{}, err
+	}
+	fingerprint := strings.ToLower(strings.ReplaceAll(permissions.Fingerprint, ":", ""))
+	if fingerprint != "" {
+		if fingerprintBytes, err := hex.DecodeString(fingerprint); err != nil || len(fingerprintBytes) != sha256.Size {
+			return certClaims{}, fmt.Errorf("invalid client certificate fingerprint %q", permissions.Fingerprint)
+		}
+	}
+	return certClaims{
+		subject:     permissions.Subject,
+		fingerprint: fingerprint,
+		claims: &endpointClaims{
+			Endpoints: permissions.Endpoints,
+			Methods:   methods,
+		},
+	}, nil
+}
+
+// matches returns true if [cert] is granted these claims
+func (c *certClaims) matches(cert *x509.Certificate, fingerprint string) bool {
+	if c.fingerprint != "" {
+		return c.fingerprint == fingerprint
+	}
+	return c.subject == cert.Subject.String()
+}
+
+// clientCertClaims returns the claims granted to the verified client
+// certificate of [r] and the certificate's fingerprint. Returns nil if [r]
+// doesn't have a verified client certificate or if it isn't granted any
+// claims.
+func (a *auth) clientCertClaims(r *http.Request) (*endpointClaims, string) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, ""
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	fingerprintBytes := sha256.Sum256(cert.Raw)
+	fingerprint := hex.EncodeToString(fingerprintBytes[:])
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	// Permissions naming the fingerprint are more specific than permissions
+	// naming the subject, so they take precedence.
+	var subjectClaims *endpointClaims
+	for _, certClaims := range a.certClaims {
+		if !certClaims.matches(cert, fingerprint) {
+			continue
+		}
+		if certClaims.fingerprint != "" {
+			return certClaims.claims, fingerprint
+		}
+		if subjectClaims == nil {
+			subjectClaims = certClaims.claims
+		}
+	}
+	if subjectClaims == nil {
+		return nil, ""
+	}
+	return subjectClaims, fingerprint
+}
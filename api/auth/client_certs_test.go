@@ -0,0 +1,192 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestClientCert(t *testing.T, commonName string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(hash[:])
+}
+
+func newClientCertRequest(cert *x509.Certificate, url, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+	return req
+}
+
+func TestSetClientCertPermissionsInvalid(t *testing.T) {
+	tests := map[string]ClientCertPermissions{
+		"no identity": {
+			Endpoints: []string{"*"},
+		},
+		"subject and fingerprint": {
+			Subject:     "CN=indexer",
+			Fingerprint: strings.Repeat("00", sha256.Size),
+			Endpoints:   []string{"*"},
+		},
+		"malformed fingerprint": {
+			Fingerprint: "not hex",
+			Endpoints:   []string{"*"},
+		},
+		"no endpoints": {
+			Subject: "CN=indexer",
+		},
+		"unknown preset": {
+			Subject:   "CN=indexer",
+			Endpoints: []string{"*"},
+			Methods:   []string{"@unknown"},
+		},
+	}
+	for name, permissions := range tests {
+		t.Run(name, func(t *testing.T) {
+			auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+			err := auth.SetClientCertPermissions([]ClientCertPermissions{permissions})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWrapHandlerClientCert(t *testing.T) {
+	indexerCert := newTestClientCert(t, "indexer")
+	adminCert := newTestClientCert(t, "admin")
+	unknownCert := newTestClientCert(t, "unknown")
+
+	auth := NewFromHash(logging.NoLog{}, "auth", hashedPassword)
+	err := auth.SetClientCertPermissions([]ClientCertPermissions{
+		{
+			Subject:   "CN=indexer",
+			Endpoints: []string{"/ext/bc/X"},
+			Methods:   []string{ReadOnlyPreset},
+		},
+		{
+			Fingerprint: strings.ToUpper(fingerprint(adminCert)),
+			Endpoints:   []string{"*"},
+		},
+	})
+	assert.NoError(t, err)
+
+	tokenStr, err := auth.NewToken(testPassword, defaultTokenLifespan, []string{"*"}, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		cert         *x509.Certificate
+		token        string
+		url          string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "subject allows read only method",
+			cert:         indexerCert,
+			url:          "http://127.0.0.1:9650/ext/bc/X",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.getTx","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "subject doesn't allow writing method",
+			cert:         indexerCert,
+			url:          "http://127.0.0.1:9650/ext/bc/X",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "subject doesn't allow endpoint",
+			cert:         indexerCert,
+			url:          "http://127.0.0.1:9650/ext/bc/P",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"platform.getHeight","params":{}}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "fingerprint allows everything",
+			cert:         adminCert,
+			url:          "http://127.0.0.1:9650/ext/admin",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"admin.aliasChain","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown cert requires token",
+			cert:         unknownCert,
+			url:          "http://127.0.0.1:9650/ext/bc/X",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.getTx","params":{}}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown cert with token",
+			cert:         unknownCert,
+			token:        tokenStr,
+			url:          "http://127.0.0.1:9650/ext/bc/X",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "no cert with token",
+			token:        tokenStr,
+			url:          "http://127.0.0.1:9650/ext/bc/X",
+			body:         `{"jsonrpc":"2.0","id":1,"method":"avm.send","params":{}}`,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	wrappedHandler := auth.WrapHandler(dummyHandler)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := newClientCertRequest(test.cert, test.url, test.body)
+			if test.token != "" {
+				req.Header.Add("Authorization", "Bearer "+test.token)
+			}
+			rr := httptest.NewRecorder()
+			wrappedHandler.ServeHTTP(rr, req)
+			assert.Equal(t, test.expectedCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	tokenIdentityPrefix = "token:"
+	certIdentityPrefix  = "cert:"
+)
+
+type identityKey struct{}
+
+// withIdentity returns a shallow copy of [r] that was authenticated as
+// [identity]
+func withIdentity(r *http.Request, identity string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identityKey{}, identity))
+}
+
+// Identity returns the client that [r] was authenticated as by a handler
+// wrapped with Auth.WrapHandler. Requests made with an auth token are
+// identified by the token's ID and requests made with a client certificate
+// are identified by the certificate's fingerprint. Returns the empty string if
+// [r] wasn't authenticated.
+func Identity(r *http.Request) string {
+	identity, _ := r.Context().Value(identityKey{}).(string)
+	return identity
+}
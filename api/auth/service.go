@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 )
@@ -23,20 +24,66 @@ type NewTokenArgs struct {
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// JSON-RPC methods that may be called with this token e.g. if methods is
+	// ["avm.get*", "info.*"] then the token holder can call avm.getBalance and
+	// info.peers but not avm.send. Elements may also name a preset such as
+	// "@readonly". If [Methods] is empty, all methods may be called. [Methods]
+	// must have at most [maxMethods] elements
+	Methods []string `json:"methods"`
 }
 
 type Token struct {
 	Token string `json:"token"` // The new token. Expires in [TokenLifespan].
 }
 
-func (s *service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) error {
+type NewTokenReply struct {
+	Token
+	ExpiresAt time.Time `json:"expiresAt"` // When the new token expires
+}
+
+func (s *service) NewToken(_ *http.Request, args *NewTokenArgs, reply *NewTokenReply) error {
 	s.auth.log.Debug("Auth: NewToken called")
 
+	token, err := s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints, args.Methods)
+	if err != nil {
+		return err
+	}
+	info, err := s.auth.IntrospectToken(token)
+	if err != nil {
+		return err
+	}
+	reply.Token.Token = token
+	reply.ExpiresAt = info.ExpiresAt
+	return nil
+}
+
+type ListTokensReply struct {
+	Tokens []TokenInfo `json:"tokens"`
+}
+
+// ListTokens returns the unexpired tokens issued since the password was last
+// changed
+func (s *service) ListTokens(_ *http.Request, args *Password, reply *ListTokensReply) error {
+	s.auth.log.Debug("Auth: ListTokens called")
+
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Tokens, err = s.auth.ListTokens(args.Password)
 	return err
 }
 
+// IntrospectToken returns the endpoints and methods that a token allows access
+// to and whether it is still valid
+func (s *service) IntrospectToken(_ *http.Request, args *Token, reply *TokenInfo) error {
+	s.auth.log.Debug("Auth: IntrospectToken called")
+
+	info, err := s.auth.IntrospectToken(args.Token)
+	if err != nil {
+		return err
+	}
+	*reply = info
+	return nil
+}
+
 type RevokeTokenArgs struct {
 	Password
 	Token
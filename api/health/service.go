@@ -9,6 +9,7 @@ import (
 
 	stdjson "encoding/json"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -52,7 +53,7 @@ func (as *apiServer) Handler() (*common.HTTPHandler, error) {
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(as, "health"); err != nil {
+	if err := openrpc.RegisterServices(newServer, openrpc.Service{Receiver: as, Name: "health"}); err != nil {
 		return nil, err
 	}
 
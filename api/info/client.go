@@ -67,6 +67,14 @@ func (c *Client) Peers() ([]network.PeerID, error) {
 	return res.Peers, err
 }
 
+func (c *Client) PeerScores(nodeIDs []string) ([]PeerScore, error) {
+	res := &PeerScoresReply{}
+	err := c.requester.SendRequest("peerScores", &PeerScoresArgs{
+		NodeIDs: nodeIDs,
+	}, res)
+	return res.Scores, err
+}
+
 func (c *Client) IsBootstrapped(chain string) (bool, error) {
 	res := &IsBootstrappedResponse{}
 	err := c.requester.SendRequest("isBootstrapped", &IsBootstrappedArgs{
@@ -75,6 +83,14 @@ func (c *Client) IsBootstrapped(chain string) (bool, error) {
 	return res.IsBootstrapped, err
 }
 
+func (c *Client) GetVMProcessStatus(chain string) (*GetVMProcessStatusReply, error) {
+	res := &GetVMProcessStatusReply{}
+	err := c.requester.SendRequest("getVMProcessStatus", &GetVMProcessStatusArgs{
+		Chain: chain,
+	}, res)
+	return res, err
+}
+
 func (c *Client) GetTxFee() (*GetTxFeeResponse, error) {
 	res := &GetTxFeeResponse{}
 	err := c.requester.SendRequest("getTxFee", struct{}{}, res)
@@ -9,10 +9,12 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -27,6 +29,7 @@ type Info struct {
 	networkID     uint32
 	log           logging.Logger
 	networking    network.Network
+	peerScores    score.Tracker
 	chainManager  chains.Manager
 	vmManager     vms.Manager
 	creationTxFee uint64
@@ -42,6 +45,7 @@ func NewService(
 	chainManager chains.Manager,
 	vmManager vms.Manager,
 	peers network.Network,
+	peerScores score.Tracker,
 	creationTxFee uint64,
 	txFee uint64,
 ) (*common.HTTPHandler, error) {
@@ -49,17 +53,21 @@ func NewService(
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Info{
-		version:       version,
-		nodeID:        nodeID,
-		networkID:     networkID,
-		log:           log,
-		chainManager:  chainManager,
-		vmManager:     vmManager,
-		networking:    peers,
-		creationTxFee: creationTxFee,
-		txFee:         txFee,
-	}, "info"); err != nil {
+	if err := openrpc.RegisterServices(newServer, openrpc.Service{
+		Receiver: &Info{
+			version:       version,
+			nodeID:        nodeID,
+			networkID:     networkID,
+			log:           log,
+			chainManager:  chainManager,
+			vmManager:     vmManager,
+			networking:    peers,
+			peerScores:    peerScores,
+			creationTxFee: creationTxFee,
+			txFee:         txFee,
+		},
+		Name: "info",
+	}); err != nil {
 		return nil, err
 	}
 	return &common.HTTPHandler{Handler: newServer}, nil
@@ -191,6 +199,63 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 	return nil
 }
 
+// PeerScoresArgs are the arguments for calling PeerScores
+type PeerScoresArgs struct {
+	// If non-empty, only the scores of these nodes are returned
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// PeerScore is the reputation score of a peer and the inputs it was
+// calculated from
+type PeerScore struct {
+	NodeID          string       `json:"nodeID"`
+	Score           json.Float64 `json:"score"`
+	Latency         string       `json:"latency"`
+	FailureRate     json.Float64 `json:"failureRate"`
+	InvalidMessages json.Float64 `json:"invalidMessages"`
+	CPUUtilization  json.Float64 `json:"cpuUtilization"`
+}
+
+// PeerScoresReply are the results from calling PeerScores
+type PeerScoresReply struct {
+	Scores []PeerScore `json:"scores"`
+}
+
+// PeerScores returns the reputation scores of this node's peers
+func (service *Info) PeerScores(_ *http.Request, args *PeerScoresArgs, reply *PeerScoresReply) error {
+	service.log.Debug("Info: PeerScores called")
+
+	scores := service.peerScores.Scores()
+	if len(args.NodeIDs) > 0 {
+		filtered := make(map[ids.ShortID]score.PeerScore, len(args.NodeIDs))
+		for _, nodeID := range args.NodeIDs {
+			nID, err := ids.ShortFromPrefixedString(nodeID, constants.NodeIDPrefix)
+			if err != nil {
+				return err
+			}
+			peerScore, exists := scores[nID]
+			if !exists {
+				peerScore.Score = service.peerScores.Score(nID)
+			}
+			filtered[nID] = peerScore
+		}
+		scores = filtered
+	}
+
+	reply.Scores = make([]PeerScore, 0, len(scores))
+	for nodeID, peerScore := range scores {
+		reply.Scores = append(reply.Scores, PeerScore{
+			NodeID:          nodeID.PrefixedString(constants.NodeIDPrefix),
+			Score:           json.Float64(peerScore.Score),
+			Latency:         peerScore.Latency.String(),
+			FailureRate:     json.Float64(peerScore.FailureRate),
+			InvalidMessages: json.Float64(peerScore.InvalidMessages),
+			CPUUtilization:  json.Float64(peerScore.CPUUtilization),
+		})
+	}
+	return nil
+}
+
 // IsBootstrappedArgs are the arguments for calling IsBootstrapped
 type IsBootstrappedArgs struct {
 	// Alias of the chain
@@ -220,6 +285,44 @@ func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, r
 	return nil
 }
 
+// GetVMProcessStatusArgs are the arguments for calling GetVMProcessStatus
+type GetVMProcessStatusArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+}
+
+// GetVMProcessStatusReply are the results from calling GetVMProcessStatus
+type GetVMProcessStatusReply struct {
+	// One of "running", "restarting" or "stopped"
+	State string `json:"state"`
+	// The times the VM's process exited, oldest first
+	Exits []chains.VMProcessExit `json:"exits"`
+}
+
+// GetVMProcessStatus returns the state of the process the VM of [args.Chain]
+// runs in and the times that process exited.
+// Returns an error if the chain doesn't exist or if its VM doesn't run in a
+// separate process.
+func (service *Info) GetVMProcessStatus(_ *http.Request, args *GetVMProcessStatusArgs, reply *GetVMProcessStatusReply) error {
+	service.log.Debug("Info: GetVMProcessStatus called with chain: %s", args.Chain)
+
+	if args.Chain == "" {
+		return fmt.Errorf("argument 'chain' not given")
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("there is no chain with alias/ID '%s'", args.Chain)
+	}
+	status, err := service.chainManager.VMProcessStatus(chainID)
+	if err != nil {
+		return err
+	}
+	reply.State = status.State
+	reply.Exits = status.Exits
+	return nil
+}
+
 type GetTxFeeResponse struct {
 	CreationTxFee json.Uint64 `json:"creationTxFee"`
 	TxFee         json.Uint64 `json:"txFee"`
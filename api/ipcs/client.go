@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -31,6 +32,19 @@ func (c *Client) PublishBlockchain(blockchainID string) (*PublishBlockchainReply
 	return res, err
 }
 
+// PublishBlockchainFromIndex requests the node to begin publishing consensus
+// and decision events, starting with the containers accepted from [index]
+// onwards
+func (c *Client) PublishBlockchainFromIndex(blockchainID string, index uint64) (*PublishBlockchainReply, error) {
+	res := &PublishBlockchainReply{}
+	fromIndex := json.Uint64(index)
+	err := c.requester.SendRequest("publishBlockchain", &PublishBlockchainArgs{
+		BlockchainID: blockchainID,
+		FromIndex:    &fromIndex,
+	}, res)
+	return res, err
+}
+
 // UnpublishBlockchain requests the node to stop publishing consensus and decision events
 func (c *Client) UnpublishBlockchain(blockchainID string) (bool, error) {
 	res := &api.SuccessResponse{}
@@ -0,0 +1,15 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api/openrpc"
+)
+
+func TestOpenRPCDocument(t *testing.T) {
+	openrpc.VerifyGolden(t, filepath.Join("testdata", "openrpc.json"), openrpc.Service{Receiver: &IPCServer{}, Name: "ipcs"})
+}
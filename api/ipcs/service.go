@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
@@ -42,12 +43,15 @@ func NewService(log logging.Logger, chainManager chains.Manager, httpServer *ser
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 
-	return &common.HTTPHandler{Handler: newServer}, newServer.RegisterService(ipcServer, "ipcs")
+	return &common.HTTPHandler{Handler: newServer}, openrpc.RegisterServices(newServer, openrpc.Service{Receiver: ipcServer, Name: "ipcs"})
 }
 
 // PublishBlockchainArgs are the arguments for calling PublishBlockchain
 type PublishBlockchainArgs struct {
 	BlockchainID string `json:"blockchainID"`
+	// If set, the containers accepted from this index onwards are published
+	// before new events. Requires the chain to be indexed.
+	FromIndex *json.Uint64 `json:"fromIndex,omitempty"`
 }
 
 // PublishBlockchainReply are the results from calling PublishBlockchain
@@ -56,7 +60,7 @@ type PublishBlockchainReply struct {
 	DecisionsURL string `json:"decisionsURL"`
 }
 
-// PublishBlockchain publishes the finalized accepted transactions from the blockchainID over the IPC
+// PublishBlockchain publishes the events of the blockchainID over the IPC
 func (ipc *IPCServer) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs, reply *PublishBlockchainReply) error {
 	ipc.log.Debug("IPCs: PublishBlockchain called with BlockchainID: %s", args.BlockchainID)
 
@@ -66,14 +70,19 @@ func (ipc *IPCServer) PublishBlockchain(r *http.Request, args *PublishBlockchain
 		return err
 	}
 
-	ipcs, err := ipc.ipcs.Publish(chainID)
+	var sockets *ipcs.EventSockets
+	if args.FromIndex != nil {
+		sockets, err = ipc.ipcs.PublishFromIndex(chainID, uint64(*args.FromIndex))
+	} else {
+		sockets, err = ipc.ipcs.Publish(chainID)
+	}
 	if err != nil {
 		ipc.log.Error("couldn't publish blockchainID: %s", err)
 		return err
 	}
 
-	reply.ConsensusURL = ipcs.ConsensusURL()
-	reply.DecisionsURL = ipcs.DecisionsURL()
+	reply.ConsensusURL = sockets.ConsensusURL()
+	reply.DecisionsURL = sockets.DecisionsURL()
 
 	return nil
 }
@@ -0,0 +1,534 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/encdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/password"
+)
+
+var (
+	errChecksumMismatch     = errors.New("backup checksum mismatch")
+	errNoPassphrase         = errors.New("backup is encrypted but no passphrase was provided")
+	errWrongPassphrase      = errors.New("couldn't decrypt backup with the provided passphrase")
+	errMalformedUserKey     = errors.New("user data key is shorter than a chain prefix")
+	errUnexpectedPassphrase = errors.New("backup isn't encrypted but a passphrase was provided")
+)
+
+// BackupConfig configures the backup of a user
+type BackupConfig struct {
+	// Chains whose data is backed up. If empty, the data of every chain is.
+	ChainIDs []ids.ID
+	// If non-empty, the user's data is decrypted and the backup is encrypted
+	// with a key derived from [Passphrase] instead. Such a backup can be
+	// imported under any password. Otherwise, the backup contains the user's
+	// encrypted data and can only be imported under the user's password.
+	Passphrase string
+}
+
+// RestoreConfig configures the import of a backup
+type RestoreConfig struct {
+	// Chains whose data is imported. If empty, the data of every chain in the
+	// backup is.
+	ChainIDs []ids.ID
+	// Passphrase the backup was encrypted with, if any
+	Passphrase string
+	// If true, the backup is checked but nothing is imported
+	DryRun bool
+}
+
+// BackupSummary describes the data that was, or would be, imported from a
+// backup
+type BackupSummary struct {
+	// Time the backup was created at
+	CreatedAt time.Time
+	// True if the backup was encrypted with a passphrase
+	Encrypted bool
+	Chains    []ChainSummary
+}
+
+// ChainSummary describes the data of a user on a chain
+type ChainSummary struct {
+	// ID of the chain, or ids.Empty if the node that created the backup didn't
+	// know which chain [Prefix] belongs to
+	ChainID ids.ID
+	// Hash of the chain's ID, which the user's data on the chain is stored
+	// under
+	Prefix ids.ID
+	// Number of keys the user has on the chain
+	NumKeys int
+}
+
+// backup is the format users are exported in. It's marshalled with
+// [backupCodecVersion].
+type backup struct {
+	// True if [Payload] is encrypted with a key derived from a passphrase
+	Encrypted bool `serialize:"true"`
+	// Parameters and salt the key that encrypts [Payload] is derived with
+	KeyParams password.KDFParams `serialize:"true"`
+	KeySalt   [16]byte           `serialize:"true"`
+	Nonce     []byte             `serialize:"true"`
+	// Marshalled backupPayload. The length limit matches [maxPackerSize].
+	Payload []byte `serialize:"true" len:"1073741823"`
+	// SHA256 of [Payload]
+	Checksum ids.ID `serialize:"true"`
+}
+
+// backupPayload is the content of a backup
+type backupPayload struct {
+	// Unix time, in seconds, the backup was created at
+	CreatedAt uint64 `serialize:"true"`
+	// Credentials the values in [Chains] are encrypted with. Unused if the
+	// backup is encrypted, in which case the values are stored decrypted.
+	Credentials credentials   `serialize:"true"`
+	Chains      []backupChain `serialize:"true"`
+}
+
+// backupChain is the data of a user on a chain
+type backupChain struct {
+	Prefix  ids.ID   `serialize:"true"`
+	ChainID ids.ID   `serialize:"true"`
+	Data    []kvPair `serialize:"true"`
+}
+
+// chainPrefix returns the prefix the data of users on the chain [chainID] is
+// stored under
+func chainPrefix(chainID ids.ID) ids.ID {
+	return hashing.ComputeHash256Array(chainID[:])
+}
+
+func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return nil, errUserMaxLength
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	creds, err := ks.authenticate(username, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+
+	userData := user{Credentials: *creds}
+	it := userDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		userData.Data = append(userData.Data, kvPair{
+			Key:   it.Key(),
+			Value: it.Value(),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	// Return the byte representation of the user
+	return c.Marshal(codecVersion, &userData)
+}
+
+func (ks *keystore) ExportBackup(username, pw string, config BackupConfig) ([]byte, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return nil, errUserMaxLength
+	}
+	if config.Passphrase != "" {
+		if err := password.IsValid(config.Passphrase, password.OK); err != nil {
+			return nil, err
+		}
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	creds, err := ks.authenticate(username, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	var userDB database.Database = prefixdb.New([]byte(username), ks.bcDB)
+	payload := backupPayload{CreatedAt: uint64(time.Now().Unix())}
+	if config.Passphrase == "" {
+		payload.Credentials = *creds
+	} else {
+		key, err := creds.deriveKey(pw)
+		if err != nil {
+			return nil, err
+		}
+		userDB, err = encdb.NewWithKey(key, userDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prefixes := ids.Set{}
+	for _, chainID := range config.ChainIDs {
+		prefixes.Add(chainPrefix(chainID))
+	}
+
+	it := userDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) < hashing.HashLen {
+			return nil, errMalformedUserKey
+		}
+		prefix, err := ids.ToID(key[:hashing.HashLen])
+		if err != nil {
+			return nil, err
+		}
+		if prefixes.Len() > 0 && !prefixes.Contains(prefix) {
+			continue
+		}
+
+		payload.add(prefix, key[hashing.HashLen:], it.Value(), ks.prefixToChainID[prefix])
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := c.Marshal(codecVersion, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	b := backup{}
+	if config.Passphrase == "" {
+		b.Payload = payloadBytes
+	} else {
+		b.Encrypted = true
+		b.KeyParams = ks.kdfParams
+		if _, err := rand.Read(b.KeySalt[:]); err != nil {
+			return nil, err
+		}
+		b.Nonce = make([]byte, chacha20poly1305.NonceSizeX)
+		if _, err := rand.Read(b.Nonce); err != nil {
+			return nil, err
+		}
+		key, err := b.KeyParams.DeriveKey(config.Passphrase, b.KeySalt[:], chacha20poly1305.KeySize)
+		if err != nil {
+			return nil, err
+		}
+		cipher, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, err
+		}
+		b.Payload = cipher.Seal(nil, b.Nonce, payloadBytes, nil)
+	}
+	b.Checksum = hashing.ComputeHash256Array(b.Payload)
+	return c.Marshal(backupCodecVersion, &b)
+}
+
+func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
+	_, err := ks.ImportBackup(username, pw, userBytes, RestoreConfig{})
+	return err
+}
+
+func (ks *keystore) ImportBackup(username, pw string, backupBytes []byte, config RestoreConfig) (*BackupSummary, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return nil, errUserMaxLength
+	}
+	if err := ks.checkUserAvailable(username); err != nil {
+		return nil, err
+	}
+
+	// Keys are derived without holding the lock, so that importing a backup
+	// doesn't block other users of the keystore. The parameters they're
+	// derived with come from the backup, so they're capped to what this node
+	// would use.
+	maxParams := ks.maxImportParams()
+	summary, payload, err := parseBackup(backupBytes, config.Passphrase, maxParams)
+	if err != nil {
+		return nil, err
+	}
+	if err := payload.filter(config.ChainIDs); err != nil {
+		return nil, err
+	}
+
+	// If the backup was encrypted, its values are decrypted and need to be
+	// encrypted under the new user's password
+	var (
+		creds      *credentials
+		userDataDB database.Database = prefixdb.New([]byte(username), ks.bcDB)
+		dataDB                       = userDataDB
+	)
+	if summary.Encrypted {
+		if err := password.IsValid(pw, password.OK); err != nil {
+			return nil, err
+		}
+		creds, err = newCredentials(pw, ks.kdfParams)
+		if err != nil {
+			return nil, err
+		}
+		key, err := creds.deriveKey(pw)
+		if err != nil {
+			return nil, err
+		}
+		dataDB, err = encdb.NewWithKey(key, userDataDB)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		creds = &payload.Credentials
+		if err := creds.verify(); err != nil {
+			return nil, err
+		}
+		if err := creds.verifyCost(maxParams); err != nil {
+			return nil, err
+		}
+		if !creds.check(pw) {
+			return nil, fmt.Errorf("incorrect password for user %q", username)
+		}
+	}
+
+	for _, chain := range payload.Chains {
+		summary.Chains = append(summary.Chains, ChainSummary{
+			ChainID: chain.ChainID,
+			Prefix:  chain.Prefix,
+			NumKeys: len(chain.Data),
+		})
+	}
+	if config.DryRun {
+		return summary, nil
+	}
+
+	credsBytes, err := c.Marshal(codecVersion, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	// The user may have been created while the backup was being parsed
+	existingCreds, err := ks.getCredentials(username)
+	if err != nil {
+		return nil, err
+	}
+	if existingCreds != nil {
+		return nil, fmt.Errorf("user already exists: %s", username)
+	}
+
+	userBatch := ks.userDB.NewBatch()
+	if err := userBatch.Put([]byte(username), credsBytes); err != nil {
+		return nil, err
+	}
+
+	dataBatch := dataDB.NewBatch()
+	for _, chain := range payload.Chains {
+		for _, kvp := range chain.Data {
+			key := make([]byte, 0, hashing.HashLen+len(kvp.Key))
+			key = append(key, chain.Prefix[:]...)
+			key = append(key, kvp.Key...)
+			if err := dataBatch.Put(key, kvp.Value); err != nil {
+				return nil, fmt.Errorf("error on database put: %w", err)
+			}
+		}
+	}
+
+	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
+		return nil, err
+	}
+	ks.usernameToCredentials[username] = creds
+	return summary, nil
+}
+
+// checkUserAvailable returns an error if [username] already exists
+func (ks *keystore) checkUserAvailable(username string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	creds, err := ks.getCredentials(username)
+	if err != nil {
+		return err
+	}
+	if creds != nil {
+		return fmt.Errorf("user already exists: %s", username)
+	}
+	return nil
+}
+
+// maxImportParams returns the most expensive parameters a key may be derived
+// with when a backup is imported. Users created before the parameters were
+// configurable had their password hashed with [password.HashParams], so
+// those are always allowed.
+func (ks *keystore) maxImportParams() password.KDFParams {
+	maxParams := ks.kdfParams
+	maxParams.KDF = password.Argon2id
+	if password.HashParams.Time > maxParams.Time {
+		maxParams.Time = password.HashParams.Time
+	}
+	if password.HashParams.Memory > maxParams.Memory {
+		maxParams.Memory = password.HashParams.Memory
+	}
+	if password.HashParams.Threads > maxParams.Threads {
+		maxParams.Threads = password.HashParams.Threads
+	}
+	return maxParams
+}
+
+// parseBackup parses a backup, or a user exported by a previous version of
+// the keystore, decrypting it with [passphrase] if needed. Returns an error if
+// the backup's key would be derived with parameters more expensive than
+// [maxParams].
+func parseBackup(backupBytes []byte, passphrase string, maxParams password.KDFParams) (*BackupSummary, *backupPayload, error) {
+	version, err := peekCodecVersion(backupBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != backupCodecVersion {
+		if passphrase != "" {
+			return nil, nil, errUnexpectedPassphrase
+		}
+		userData, err := parseUser(backupBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		payload, err := userData.payload()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &BackupSummary{}, payload, nil
+	}
+
+	b := backup{}
+	if _, err := c.Unmarshal(backupBytes, &b); err != nil {
+		return nil, nil, err
+	}
+	if checksum := hashing.ComputeHash256Array(b.Payload); checksum != b.Checksum {
+		return nil, nil, errChecksumMismatch
+	}
+
+	payloadBytes := b.Payload
+	switch {
+	case b.Encrypted && passphrase == "":
+		return nil, nil, errNoPassphrase
+	case !b.Encrypted && passphrase != "":
+		return nil, nil, errUnexpectedPassphrase
+	case b.Encrypted:
+		if err := b.KeyParams.Verify(); err != nil {
+			return nil, nil, fmt.Errorf("invalid key derivation parameters: %w", err)
+		}
+		if err := verifyCost(b.KeyParams, maxParams); err != nil {
+			return nil, nil, err
+		}
+		key, err := b.KeyParams.DeriveKey(passphrase, b.KeySalt[:], chacha20poly1305.KeySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		cipher, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(b.Nonce) != cipher.NonceSize() {
+			return nil, nil, fmt.Errorf("expected a nonce of %d bytes but got %d", cipher.NonceSize(), len(b.Nonce))
+		}
+		payloadBytes, err = cipher.Open(nil, b.Nonce, b.Payload, nil)
+		if err != nil {
+			return nil, nil, errWrongPassphrase
+		}
+	}
+
+	payload := &backupPayload{}
+	if _, err := c.Unmarshal(payloadBytes, payload); err != nil {
+		return nil, nil, err
+	}
+	return &BackupSummary{
+		CreatedAt: time.Unix(int64(payload.CreatedAt), 0),
+		Encrypted: b.Encrypted,
+	}, payload, nil
+}
+
+// payload returns the content of [userData] as it would be in a backup. The
+// chains the data belongs to are unknown.
+func (userData *user) payload() (*backupPayload, error) {
+	payload := &backupPayload{Credentials: userData.Credentials}
+	for _, kvp := range userData.Data {
+		if len(kvp.Key) < hashing.HashLen {
+			return nil, errMalformedUserKey
+		}
+		prefix, err := ids.ToID(kvp.Key[:hashing.HashLen])
+		if err != nil {
+			return nil, err
+		}
+		payload.add(prefix, kvp.Key[hashing.HashLen:], kvp.Value, ids.Empty)
+	}
+	return payload, nil
+}
+
+// add appends [key] and [value] to the data of the chain whose prefix is
+// [prefix]. Keys are expected to be added in order, so that the data of each
+// chain is contiguous.
+func (payload *backupPayload) add(prefix ids.ID, key, value []byte, chainID ids.ID) {
+	numChains := len(payload.Chains)
+	if numChains == 0 || payload.Chains[numChains-1].Prefix != prefix {
+		payload.Chains = append(payload.Chains, backupChain{
+			Prefix:  prefix,
+			ChainID: chainID,
+		})
+		numChains++
+	}
+	chain := &payload.Chains[numChains-1]
+	chain.Data = append(chain.Data, kvPair{
+		Key:   key,
+		Value: value,
+	})
+}
+
+// filter removes the data of chains other than [chainIDs] from [payload]. If
+// [chainIDs] is empty, nothing is removed. Returns an error if the backup
+// doesn't contain data for one of [chainIDs].
+func (payload *backupPayload) filter(chainIDs []ids.ID) error {
+	if len(chainIDs) == 0 {
+		return nil
+	}
+
+	chains := make([]backupChain, 0, len(chainIDs))
+	included := ids.Set{}
+	for _, chainID := range chainIDs {
+		if included.Contains(chainID) {
+			continue
+		}
+		included.Add(chainID)
+
+		prefix := chainPrefix(chainID)
+		found := false
+		for _, chain := range payload.Chains {
+			if chain.Prefix != prefix {
+				continue
+			}
+			chain.ChainID = chainID
+			chains = append(chains, chain)
+			found = true
+			break
+		}
+		if !found {
+			return fmt.Errorf("backup doesn't contain data for chain %s", chainID)
+		}
+	}
+	payload.Chains = chains
+	return nil
+}
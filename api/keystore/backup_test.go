@@ -0,0 +1,212 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const backupPassphrase = "v>4Qw;_T-8]b!zZ2p#Lq@e9{Hs,Uk^7(cXy" // #nosec G101
+
+// createBackupUser creates "bob" in a new keystore, with a value on each of
+// [chainIDs]
+func createBackupUser(t *testing.T, chainIDs ...ids.ID) *keystore {
+	ksIntf, err := CreateTestKeystore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := ksIntf.(*keystore)
+	if err := ks.CreateUser("bob", strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	for _, chainID := range chainIDs {
+		db, err := ks.GetDatabase(chainID, "bob", strongPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), chainID[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ks
+}
+
+func TestBackupSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID0 := ids.GenerateTestID()
+	chainID1 := ids.GenerateTestID()
+	ks := createBackupUser(t, chainID0, chainID1)
+
+	backupBytes, err := ks.ExportBackup("bob", strongPassword, BackupConfig{
+		ChainIDs: []ids.ID{chainID1},
+	})
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+
+	_, err = newKS.ImportBackup("bob", strongPassword, backupBytes, RestoreConfig{
+		ChainIDs: []ids.ID{chainID0},
+	})
+	assert.Error(err, "should have errored due to the chain not being in the backup")
+
+	summary, err := newKS.ImportBackup("bob", strongPassword, backupBytes, RestoreConfig{})
+	assert.NoError(err)
+	assert.False(summary.Encrypted)
+	assert.Equal([]ChainSummary{{
+		ChainID: chainID1,
+		Prefix:  chainPrefix(chainID1),
+		NumKeys: 1,
+	}}, summary.Chains)
+
+	db, err := newKS.GetDatabase(chainID1, "bob", strongPassword)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal(chainID1[:], value)
+
+	db, err = newKS.GetDatabase(chainID0, "bob", strongPassword)
+	assert.NoError(err)
+	has, err := db.Has([]byte("hello"))
+	assert.NoError(err)
+	assert.False(has)
+}
+
+func TestBackupPassphrase(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	ks := createBackupUser(t, chainID)
+
+	_, err := ks.ExportBackup("bob", strongPassword, BackupConfig{Passphrase: "weak"})
+	assert.Error(err, "should have errored due to a weak passphrase")
+
+	backupBytes, err := ks.ExportBackup("bob", strongPassword, BackupConfig{Passphrase: backupPassphrase})
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+
+	_, err = newKS.ImportBackup("alice", strongPassword, backupBytes, RestoreConfig{})
+	assert.ErrorIs(err, errNoPassphrase)
+	_, err = newKS.ImportBackup("alice", strongPassword, backupBytes, RestoreConfig{Passphrase: strongPassword})
+	assert.ErrorIs(err, errWrongPassphrase)
+
+	// The data can be imported under another password
+	newPassword := strongPassword + "!"
+	summary, err := newKS.ImportBackup("alice", newPassword, backupBytes, RestoreConfig{Passphrase: backupPassphrase})
+	assert.NoError(err)
+	assert.True(summary.Encrypted)
+	assert.Len(summary.Chains, 1)
+
+	db, err := newKS.GetDatabase(chainID, "alice", newPassword)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal(chainID[:], value)
+}
+
+func TestBackupDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	ks := createBackupUser(t, chainID)
+
+	backupBytes, err := ks.ExportBackup("bob", strongPassword, BackupConfig{})
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+
+	summary, err := newKS.ImportBackup("bob", strongPassword, backupBytes, RestoreConfig{DryRun: true})
+	assert.NoError(err)
+	assert.Equal([]ChainSummary{{
+		ChainID: chainID,
+		Prefix:  chainPrefix(chainID),
+		NumKeys: 1,
+	}}, summary.Chains)
+
+	users, err := newKS.ListUsers()
+	assert.NoError(err)
+	assert.Empty(users)
+}
+
+func TestBackupChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	ks := createBackupUser(t, ids.GenerateTestID())
+
+	backupBytes, err := ks.ExportBackup("bob", strongPassword, BackupConfig{})
+	assert.NoError(err)
+
+	b := backup{}
+	_, err = c.Unmarshal(backupBytes, &b)
+	assert.NoError(err)
+	b.Payload[len(b.Payload)-1]++
+	corruptedBytes, err := c.Marshal(backupCodecVersion, &b)
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+	_, err = newKS.ImportBackup("bob", strongPassword, corruptedBytes, RestoreConfig{})
+	assert.ErrorIs(err, errChecksumMismatch)
+}
+
+func TestImportUserWithoutChainIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	ks := createBackupUser(t, chainID)
+
+	// Users exported without a backup don't record the chains of their data
+	userBytes, err := ks.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+
+	summary, err := newKS.ImportBackup("bob", strongPassword, userBytes, RestoreConfig{DryRun: true})
+	assert.NoError(err)
+	assert.True(summary.CreatedAt.IsZero())
+	assert.Equal([]ChainSummary{{
+		Prefix:  chainPrefix(chainID),
+		NumKeys: 1,
+	}}, summary.Chains)
+
+	// The chain is identified if it's requested
+	summary, err = newKS.ImportBackup("bob", strongPassword, userBytes, RestoreConfig{
+		ChainIDs: []ids.ID{chainID},
+	})
+	assert.NoError(err)
+	assert.Equal([]ChainSummary{{
+		ChainID: chainID,
+		Prefix:  chainPrefix(chainID),
+		NumKeys: 1,
+	}}, summary.Chains)
+
+	db, err := newKS.GetDatabase(chainID, "bob", strongPassword)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal(chainID[:], value)
+}
+
+func TestBackupExpensiveParams(t *testing.T) {
+	assert := assert.New(t)
+
+	ks := createBackupUser(t, ids.GenerateTestID())
+	ks.kdfParams.Time = 16
+	backupBytes, err := ks.ExportBackup("bob", strongPassword, BackupConfig{Passphrase: backupPassphrase})
+	assert.NoError(err)
+
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+	_, err = newKS.ImportBackup("bob", strongPassword, backupBytes, RestoreConfig{Passphrase: backupPassphrase})
+	assert.ErrorIs(err, errExpensiveParams)
+}
@@ -20,6 +20,14 @@ type BlockchainKeystore interface {
 	// values. This Database will not perform any encrypting or decrypting of
 	// values and is not recommended to be used when implementing a VM.
 	GetRawDatabase(username, password string) (database.Database, error)
+
+	// Get the underlying database, as GetRawDatabase does, along with the key
+	// its values are encrypted with.
+	GetRawDatabaseWithKey(username, password string) (database.Database, []byte, error)
+
+	// Get the seed the user's keys are derived from, or ErrNoSeed if the user
+	// wasn't seeded from a mnemonic.
+	GetSeed(username, password string) ([]byte, error)
 }
 
 type blockchainKeystore struct {
@@ -38,3 +46,15 @@ func (bks *blockchainKeystore) GetRawDatabase(username, password string) (databa
 
 	return bks.ks.GetRawDatabase(bks.blockchainID, username, password)
 }
+
+func (bks *blockchainKeystore) GetRawDatabaseWithKey(username, password string) (database.Database, []byte, error) {
+	bks.ks.log.Debug("Keystore: GetRawDatabaseWithKey called with %s from %s", username, bks.blockchainID)
+
+	return bks.ks.GetRawDatabaseWithKey(bks.blockchainID, username, password)
+}
+
+func (bks *blockchainKeystore) GetSeed(username, password string) ([]byte, error) {
+	bks.ks.log.Debug("Keystore: GetSeed called with %s from %s", username, bks.blockchainID)
+
+	return bks.ks.GetSeed(username, password)
+}
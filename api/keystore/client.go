@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -62,6 +63,65 @@ func (c *Client) ImportUser(user api.UserPass, account []byte) (bool, error) {
 	return res.Success, err
 }
 
+// ExportUserBackup returns a backup of the data of [user] on [chainIDs],
+// encrypted with [passphrase] if it's non-empty. If [chainIDs] is empty, the
+// data of every chain is backed up.
+func (c *Client) ExportUserBackup(user api.UserPass, chainIDs []ids.ID, passphrase string) ([]byte, error) {
+	res := &ExportUserReply{
+		Encoding: formatting.Hex,
+	}
+	err := c.requester.SendRequest("exportUser", &ExportUserArgs{
+		UserPass:      user,
+		Encoding:      formatting.Hex,
+		Backup:        true,
+		BlockchainIDs: chainIDs,
+		Passphrase:    passphrase,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+	return formatting.Decode(res.Encoding, res.User)
+}
+
+// ImportUserBackup imports the data of [backup] on [chainIDs] under [user].
+// If [dryRun], nothing is imported but the chains whose data would be are
+// returned.
+func (c *Client) ImportUserBackup(user api.UserPass, backup []byte, chainIDs []ids.ID, passphrase string, dryRun bool) (*ImportUserReply, error) {
+	backupStr, err := formatting.EncodeWithChecksum(formatting.Hex, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ImportUserReply{}
+	err = c.requester.SendRequest("importUser", &ImportUserArgs{
+		UserPass:      user,
+		User:          backupStr,
+		Encoding:      formatting.Hex,
+		BlockchainIDs: chainIDs,
+		Passphrase:    passphrase,
+		DryRun:        dryRun,
+	}, res)
+	return res, err
+}
+
+// CreateMnemonic returns a new BIP-39 mnemonic
+func (c *Client) CreateMnemonic() (string, error) {
+	res := &CreateMnemonicReply{}
+	err := c.requester.SendRequest("createMnemonic", struct{}{}, res)
+	return res.Mnemonic, err
+}
+
+// ImportMnemonic seeds [user] from [mnemonic], so that their keys are derived
+// from it
+func (c *Client) ImportMnemonic(user api.UserPass, mnemonic string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("importMnemonic", &ImportMnemonicArgs{
+		UserPass: user,
+		Mnemonic: mnemonic,
+	}, res)
+	return res.Success, err
+}
+
 // DeleteUser removes [user] from the node's keystore users
 func (c *Client) DeleteUser(user api.UserPass) (bool, error) {
 	res := &api.SuccessResponse{}
@@ -8,13 +8,19 @@ import (
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/codec/reflectcodec"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 const (
 	maxPackerSize  = 1 * units.GiB // max size, in bytes, of something being marshalled by Marshal()
 	maxSliceLength = 256 * 1024
 
-	codecVersion = 0
+	// legacyCodecVersion is the version users were stored and exported with
+	// before their key derivation parameters were
+	legacyCodecVersion = 0
+	codecVersion       = 1
+	// backupCodecVersion is the version users are exported with
+	backupCodecVersion = 2
 )
 
 var c codec.Manager
@@ -22,7 +28,13 @@ var c codec.Manager
 func init() {
 	lc := linearcodec.New(reflectcodec.DefaultTagName, maxSliceLength)
 	c = codec.NewManager(maxPackerSize)
-	if err := c.RegisterCodec(codecVersion, lc); err != nil {
-		panic(err)
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterCodec(legacyCodecVersion, lc),
+		c.RegisterCodec(codecVersion, lc),
+		c.RegisterCodec(backupCodecVersion, lc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
 	}
 }
@@ -0,0 +1,142 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database/encdb"
+	"github.com/ava-labs/avalanchego/utils/password"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	// legacyKeyParams are the parameters the keys that encrypt users' data
+	// were derived with before the parameters were stored
+	legacyKeyParams = password.KDFParams{KDF: password.SHA256}
+
+	errExpensiveParams = errors.New("parameters are more expensive than this node allows")
+)
+
+// credentials are what the keystore stores about a user to check their
+// password and to decrypt their data
+type credentials struct {
+	password.Hash `serialize:"true"`
+	// Parameters [Hash] was computed with
+	HashParams password.KDFParams `serialize:"true"`
+
+	// Parameters and salt the key that encrypts the user's data is derived
+	// with
+	KeyParams password.KDFParams `serialize:"true"`
+	KeySalt   [16]byte           `serialize:"true"`
+}
+
+// newCredentials returns credentials for the password [pw] that are computed
+// with [params] and new salts
+func newCredentials(pw string, params password.KDFParams) (*credentials, error) {
+	creds := &credentials{
+		HashParams: params,
+		KeyParams:  params,
+	}
+	if err := creds.Hash.SetWithParams(pw, params); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(creds.KeySalt[:]); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// legacyCredentials returns the credentials of a user whose password hash was
+// stored before the parameters were
+func legacyCredentials(hash password.Hash) *credentials {
+	return &credentials{
+		Hash:       hash,
+		HashParams: password.HashParams,
+		KeyParams:  legacyKeyParams,
+	}
+}
+
+// parseCredentials parses credentials stored by this or a previous version of
+// the keystore
+func parseCredentials(credsBytes []byte) (*credentials, error) {
+	version, err := peekCodecVersion(credsBytes)
+	if err != nil {
+		return nil, err
+	}
+	if version == legacyCodecVersion {
+		hash := password.Hash{}
+		if _, err := c.Unmarshal(credsBytes, &hash); err != nil {
+			return nil, err
+		}
+		return legacyCredentials(hash), nil
+	}
+
+	creds := &credentials{}
+	if _, err := c.Unmarshal(credsBytes, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// verify returns an error if [creds] can't be used, or would be unreasonably
+// expensive to use
+func (creds *credentials) verify() error {
+	if err := creds.HashParams.Verify(); err != nil {
+		return fmt.Errorf("invalid password hash parameters: %w", err)
+	}
+	if err := creds.KeyParams.Verify(); err != nil {
+		return fmt.Errorf("invalid key derivation parameters: %w", err)
+	}
+	return nil
+}
+
+// verifyCost returns an error if [creds] were computed with parameters more
+// expensive than [maxParams]
+func (creds *credentials) verifyCost(maxParams password.KDFParams) error {
+	if err := verifyCost(creds.HashParams, maxParams); err != nil {
+		return fmt.Errorf("password hash %w", err)
+	}
+	if err := verifyCost(creds.KeyParams, maxParams); err != nil {
+		return fmt.Errorf("key derivation %w", err)
+	}
+	return nil
+}
+
+// verifyCost returns an error if deriving a key with [params] is more
+// expensive than deriving one with [maxParams]
+func verifyCost(params, maxParams password.KDFParams) error {
+	if params.KDF != password.Argon2id {
+		return nil
+	}
+	if params.Time > maxParams.Time || params.Memory > maxParams.Memory || params.Threads > maxParams.Threads {
+		return fmt.Errorf("%w: %+v exceeds %+v", errExpensiveParams, params, maxParams)
+	}
+	return nil
+}
+
+// check returns true iff [pw] is the password of the user
+func (creds *credentials) check(pw string) bool {
+	return creds.Hash.CheckWithParams(pw, creds.HashParams)
+}
+
+// deriveKey returns the key that encrypts the data of the user whose password
+// is [pw]
+func (creds *credentials) deriveKey(pw string) ([]byte, error) {
+	return creds.KeyParams.DeriveKey(pw, creds.KeySalt[:], encdb.KeySize)
+}
+
+// outdated returns true if [creds] weren't computed with [params]
+func (creds *credentials) outdated(params password.KDFParams) bool {
+	return creds.HashParams != params || creds.KeyParams != params
+}
+
+// peekCodecVersion returns the codec version [b] was marshalled with
+func peekCodecVersion(b []byte) (uint16, error) {
+	p := wrappers.Packer{Bytes: b}
+	version := p.UnpackShort()
+	return version, p.Err
+}
@@ -0,0 +1,183 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/encdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/password"
+)
+
+// createLegacyUser stores [username] and a value in their database for the
+// chain [bID] the way the keystore did before key derivation parameters were
+// stored
+func createLegacyUser(t *testing.T, ks *keystore, username string, bID ids.ID, key, value []byte) {
+	hash := password.Hash{}
+	if err := hash.Set(strongPassword); err != nil {
+		t.Fatal(err)
+	}
+	hashBytes, err := c.Marshal(legacyCodecVersion, &hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.userDB.Put([]byte(username), hashBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	db, err := encdb.New([]byte(strongPassword), prefixdb.NewNested(bID[:], userDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLegacyUserUpgradedOnLogin(t *testing.T) {
+	assert := assert.New(t)
+
+	ksIntf, err := CreateTestKeystore()
+	assert.NoError(err)
+	ks := ksIntf.(*keystore)
+
+	bID := ids.GenerateTestID()
+	createLegacyUser(t, ks, "bob", bID, []byte("hello"), []byte("world"))
+
+	creds, err := ks.getCredentials("bob")
+	assert.NoError(err)
+	assert.Equal(legacyKeyParams, creds.KeyParams)
+
+	db, err := ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+
+	// The upgraded credentials are persisted
+	delete(ks.usernameToCredentials, "bob")
+	creds, err = ks.getCredentials("bob")
+	assert.NoError(err)
+	assert.False(creds.outdated(ks.kdfParams))
+	assert.True(creds.check(strongPassword))
+
+	// The value is no longer encrypted with the hash of the password
+	rawDB, err := ks.GetRawDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	legacyDB, err := encdb.New([]byte(strongPassword), rawDB)
+	assert.NoError(err)
+	_, err = legacyDB.Get([]byte("hello"))
+	assert.Error(err)
+}
+
+func TestRotateUserKey(t *testing.T) {
+	assert := assert.New(t)
+
+	ksIntf, err := CreateTestKeystore()
+	assert.NoError(err)
+	ks := ksIntf.(*keystore)
+
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+	bID := ids.GenerateTestID()
+	db, err := ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	assert.NoError(db.Put([]byte("hello"), []byte("world")))
+
+	rawDB, oldKey, err := ks.GetRawDatabaseWithKey(bID, "bob", strongPassword)
+	assert.NoError(err)
+
+	assert.Error(ks.RotateUserKey("bob", "wrong password"))
+	assert.Error(ks.RotateUserKey("alice", strongPassword))
+
+	// The user's data isn't re-encrypted while their databases are open,
+	// either when asked to or when the parameters are upgraded
+	ks.kdfParams.Time++
+	assert.ErrorIs(ks.RotateUserKey("bob", strongPassword), errUserInUse)
+	assert.NoError(db.Close())
+	assert.ErrorIs(ks.RotateUserKey("bob", strongPassword), errUserInUse)
+
+	openDB, err := ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	creds, err := ks.getCredentials("bob")
+	assert.NoError(err)
+	assert.True(creds.outdated(ks.kdfParams))
+	assert.NoError(openDB.Close())
+	assert.NoError(rawDB.Close())
+
+	assert.NoError(ks.RotateUserKey("bob", strongPassword))
+
+	creds, err = ks.getCredentials("bob")
+	assert.NoError(err)
+	assert.Equal(ks.kdfParams, creds.KeyParams)
+	assert.Equal(ks.kdfParams, creds.HashParams)
+
+	_, newKey, err := ks.GetRawDatabaseWithKey(bID, "bob", strongPassword)
+	assert.NoError(err)
+	assert.NotEqual(oldKey, newKey)
+
+	db, err = ks.GetDatabase(bID, "bob", strongPassword)
+	assert.NoError(err)
+	value, err := db.Get([]byte("hello"))
+	assert.NoError(err)
+	assert.Equal([]byte("world"), value)
+}
+
+func TestImportLegacyUser(t *testing.T) {
+	assert := assert.New(t)
+
+	hash := password.Hash{}
+	assert.NoError(hash.Set(strongPassword))
+	userBytes, err := c.Marshal(legacyCodecVersion, &legacyUser{Hash: hash})
+	assert.NoError(err)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	assert.Error(ks.ImportUser("bob", "wrong password", userBytes))
+	assert.NoError(ks.ImportUser("bob", strongPassword, userBytes))
+
+	_, err = ks.GetDatabase(ids.Empty, "bob", strongPassword)
+	assert.NoError(err)
+}
+
+func TestImportUserInvalidParams(t *testing.T) {
+	assert := assert.New(t)
+
+	params := password.DefaultKDFParams
+	params.Memory = 1 << 31
+	userBytes, err := c.Marshal(codecVersion, &user{
+		Credentials: credentials{
+			HashParams: params,
+			KeyParams:  password.DefaultKDFParams,
+		},
+	})
+	assert.NoError(err)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	assert.Error(ks.ImportUser("bob", strongPassword, userBytes))
+}
+
+func TestImportUserExpensiveParams(t *testing.T) {
+	assert := assert.New(t)
+
+	// Valid, but more expensive than the keystore's parameters
+	params := password.DefaultKDFParams
+	params.Time = 16
+	userBytes, err := c.Marshal(codecVersion, &user{
+		Credentials: credentials{
+			HashParams: params,
+			KeyParams:  password.DefaultKDFParams,
+		},
+	})
+	assert.NoError(err)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	assert.ErrorIs(ks.ImportUser("bob", strongPassword, userBytes), errExpensiveParams)
+}
@@ -19,6 +19,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type KeystoreClient interface {
 	GetDatabase(ctx context.Context, in *GetDatabaseRequest, opts ...grpc.CallOption) (*GetDatabaseResponse, error)
+	GetSeed(ctx context.Context, in *GetSeedRequest, opts ...grpc.CallOption) (*GetSeedResponse, error)
 }
 
 type keystoreClient struct {
@@ -38,11 +39,21 @@ func (c *keystoreClient) GetDatabase(ctx context.Context, in *GetDatabaseRequest
 	return out, nil
 }
 
+func (c *keystoreClient) GetSeed(ctx context.Context, in *GetSeedRequest, opts ...grpc.CallOption) (*GetSeedResponse, error) {
+	out := new(GetSeedResponse)
+	err := c.cc.Invoke(ctx, "/gkeystoreproto.Keystore/GetSeed", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeystoreServer is the server API for Keystore service.
 // All implementations must embed UnimplementedKeystoreServer
 // for forward compatibility
 type KeystoreServer interface {
 	GetDatabase(context.Context, *GetDatabaseRequest) (*GetDatabaseResponse, error)
+	GetSeed(context.Context, *GetSeedRequest) (*GetSeedResponse, error)
 	mustEmbedUnimplementedKeystoreServer()
 }
 
@@ -53,6 +64,9 @@ type UnimplementedKeystoreServer struct {
 func (UnimplementedKeystoreServer) GetDatabase(context.Context, *GetDatabaseRequest) (*GetDatabaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDatabase not implemented")
 }
+func (UnimplementedKeystoreServer) GetSeed(context.Context, *GetSeedRequest) (*GetSeedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeed not implemented")
+}
 func (UnimplementedKeystoreServer) mustEmbedUnimplementedKeystoreServer() {}
 
 // UnsafeKeystoreServer may be embedded to opt out of forward compatibility for this service.
@@ -84,6 +98,24 @@ func _Keystore_GetDatabase_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Keystore_GetSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeystoreServer).GetSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gkeystoreproto.Keystore/GetSeed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeystoreServer).GetSeed(ctx, req.(*GetSeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Keystore_ServiceDesc is the grpc.ServiceDesc for Keystore service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -95,6 +127,10 @@ var Keystore_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDatabase",
 			Handler:    _Keystore_GetDatabase_Handler,
 		},
+		{
+			MethodName: "GetSeed",
+			Handler:    _Keystore_GetSeed_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gkeystore.proto",
@@ -33,27 +33,51 @@ func NewClient(client gkeystoreproto.KeystoreClient, broker *plugin.GRPCBroker)
 }
 
 func (c *Client) GetDatabase(username, password string) (*encdb.Database, error) {
-	bcDB, err := c.GetRawDatabase(username, password)
+	bcDB, key, err := c.GetRawDatabaseWithKey(username, password)
 	if err != nil {
 		return nil, err
 	}
-	return encdb.New([]byte(password), bcDB)
+	if len(key) == 0 {
+		// The keystore predates per-user keys, so the key is derived from the
+		// password.
+		return encdb.New([]byte(password), bcDB)
+	}
+	return encdb.NewWithKey(key, bcDB)
 }
 
 func (c *Client) GetRawDatabase(username, password string) (database.Database, error) {
+	bcDB, _, err := c.GetRawDatabaseWithKey(username, password)
+	return bcDB, err
+}
+
+func (c *Client) GetRawDatabaseWithKey(username, password string) (database.Database, []byte, error) {
 	resp, err := c.client.GetDatabase(context.Background(), &gkeystoreproto.GetDatabaseRequest{
 		Username: username,
 		Password: password,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	dbConn, err := c.broker.Dial(resp.DbServer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	dbClient := rpcdb.NewClient(rpcdbproto.NewDatabaseClient(dbConn))
-	return dbClient, err
+	return dbClient, resp.Key, nil
+}
+
+func (c *Client) GetSeed(username, password string) ([]byte, error) {
+	resp, err := c.client.GetSeed(context.Background(), &gkeystoreproto.GetSeedRequest{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Seed) == 0 {
+		return nil, keystore.ErrNoSeed
+	}
+	return resp.Seed, nil
 }
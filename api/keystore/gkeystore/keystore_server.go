@@ -5,6 +5,7 @@ package gkeystore
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc"
 
@@ -39,7 +40,7 @@ func (s *Server) GetDatabase(
 	_ context.Context,
 	req *gkeystoreproto.GetDatabaseRequest,
 ) (*gkeystoreproto.GetDatabaseResponse, error) {
-	db, err := s.ks.GetRawDatabase(req.Username, req.Password)
+	db, key, err := s.ks.GetRawDatabaseWithKey(req.Username, req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +56,24 @@ func (s *Server) GetDatabase(
 		rpcdbproto.RegisterDatabaseServer(server, db)
 		return server
 	})
-	return &gkeystoreproto.GetDatabaseResponse{DbServer: dbBrokerID}, nil
+	return &gkeystoreproto.GetDatabaseResponse{
+		DbServer: dbBrokerID,
+		Key:      key,
+	}, nil
+}
+
+func (s *Server) GetSeed(
+	_ context.Context,
+	req *gkeystoreproto.GetSeedRequest,
+) (*gkeystoreproto.GetSeedResponse, error) {
+	seed, err := s.ks.GetSeed(req.Username, req.Password)
+	if errors.Is(err, keystore.ErrNoSeed) {
+		return &gkeystoreproto.GetSeedResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gkeystoreproto.GetSeedResponse{Seed: seed}, nil
 }
 
 type dbCloser struct {
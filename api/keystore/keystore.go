@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/encdb"
@@ -30,7 +31,9 @@ const (
 
 var (
 	errEmptyUsername = errors.New("empty username")
+	errNoUser        = errors.New("user doesn't exist")
 	errUserMaxLength = fmt.Errorf("username exceeds maximum length of %d chars", maxUserLen)
+	errUserInUse     = errors.New("the user's databases are in use")
 
 	usersPrefix = []byte("users")
 	bcsPrefix   = []byte("bcs")
@@ -56,6 +59,10 @@ type Keystore interface {
 	// values and is not recommended to be used when implementing a VM.
 	GetRawDatabase(bID ids.ID, username, password string) (database.Database, error)
 
+	// Get the underlying database, as GetRawDatabase does, along with the key
+	// its values are encrypted with.
+	GetRawDatabaseWithKey(bID ids.ID, username, password string) (database.Database, []byte, error)
+
 	// CreateUser attempts to register this username and password as a new user
 	// of the keystore.
 	CreateUser(username, pw string) error
@@ -75,9 +82,34 @@ type Keystore interface {
 	// with encrypted database values.
 	ExportUser(username, pw string) ([]byte, error)
 
-	// Get the password that is used by [username]. If [username] doesn't exist,
-	// no error is returned and a nil password hash is returned.
-	getPassword(username string) (*password.Hash, error)
+	// ImportBackup imports the data in [backup], which was exported by
+	// ExportBackup, ExportUser or a previous version of the keystore, under a
+	// new user. Returns the chains whose data was, or would be if
+	// [config.DryRun], imported.
+	ImportBackup(username, pw string, backup []byte, config RestoreConfig) (*BackupSummary, error)
+
+	// ExportBackup exports a backup of a user's data on the chains in [config],
+	// optionally encrypted with a passphrase.
+	ExportBackup(username, pw string, config BackupConfig) ([]byte, error)
+
+	// ImportMnemonic seeds an existing user from a BIP-39 mnemonic, so that
+	// their keys can be derived deterministically. A user can only be seeded
+	// once.
+	ImportMnemonic(username, pw, mnemonic string) error
+
+	// GetSeed returns the seed of a user, or ErrNoSeed if they weren't seeded
+	// from a mnemonic.
+	GetSeed(username, pw string) ([]byte, error)
+
+	// RotateUserKey re-encrypts a user's data with a key derived with new
+	// salts and the keystore's current key derivation parameters. Fails if a
+	// database of the user that was returned by this keystore hasn't been
+	// closed, since it would still use the old key.
+	RotateUserKey(username, pw string) error
+
+	// Get the credentials of [username]. If [username] doesn't exist, no error
+	// is returned and nil credentials are returned.
+	getCredentials(username string) (*credentials, error)
 }
 
 type kvPair struct {
@@ -87,6 +119,13 @@ type kvPair struct {
 
 // user describes the full content of a user
 type user struct {
+	Credentials credentials `serialize:"true"`
+	Data        []kvPair    `serialize:"true"`
+}
+
+// legacyUser describes the full content of a user exported before their key
+// derivation parameters were stored
+type legacyUser struct {
 	password.Hash `serialize:"true"`
 	Data          []kvPair `serialize:"true"`
 }
@@ -96,9 +135,24 @@ type keystore struct {
 	lock sync.Mutex
 	log  logging.Logger
 
+	// Parameters that new keys and password hashes are derived with. Users
+	// whose credentials were derived with other parameters have their data
+	// re-encrypted the next time they use their password.
+	kdfParams password.KDFParams
+
+	// Key: username
+	// Value: The credentials of that user
+	usernameToCredentials map[string]*credentials
+
 	// Key: username
-	// Value: The hash of that user's password
-	usernameToPassword map[string]*password.Hash
+	// Value: The number of databases of that user that were returned and
+	//        haven't been closed. A user's data isn't re-encrypted while they
+	//        have open databases.
+	openDatabases map[string]int
+
+	// Key: Prefix users' data on a chain is stored under
+	// Value: ID of that chain
+	prefixToChainID map[ids.ID]ids.ID
 
 	// Used to persist users and their data
 	userDB database.Database
@@ -112,13 +166,21 @@ type keystore struct {
 	//          BID  BID  BID
 }
 
-func New(log logging.Logger, dbManager manager.Manager) (Keystore, error) {
+// New returns a keystore that stores its users in [dbManager] and derives new
+// keys and password hashes with [kdfParams]
+func New(log logging.Logger, dbManager manager.Manager, kdfParams password.KDFParams) (Keystore, error) {
+	if err := kdfParams.Verify(); err != nil {
+		return nil, err
+	}
 	currentDB := dbManager.Current()
 	keystore := &keystore{
-		log:                log,
-		usernameToPassword: make(map[string]*password.Hash),
-		userDB:             prefixdb.New(usersPrefix, currentDB.Database),
-		bcDB:               prefixdb.New(bcsPrefix, currentDB.Database),
+		log:                   log,
+		kdfParams:             kdfParams,
+		usernameToCredentials: make(map[string]*credentials),
+		openDatabases:         make(map[string]int),
+		prefixToChainID:       make(map[ids.ID]ids.ID),
+		userDB:                prefixdb.New(usersPrefix, currentDB.Database),
+		bcDB:                  prefixdb.New(bcsPrefix, currentDB.Database),
 	}
 	return keystore, keystore.migrate(dbManager)
 }
@@ -128,13 +190,17 @@ func (ks *keystore) CreateHandler() (http.Handler, error) {
 	codec := jsoncodec.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&service{ks: ks}, "keystore"); err != nil {
+	if err := openrpc.RegisterServices(newServer, openrpc.Service{Receiver: &service{ks: ks}, Name: "keystore"}); err != nil {
 		return nil, err
 	}
 	return newServer, nil
 }
 
 func (ks *keystore) NewBlockchainKeyStore(blockchainID ids.ID) BlockchainKeystore {
+	ks.lock.Lock()
+	ks.prefixToChainID[chainPrefix(blockchainID)] = blockchainID
+	ks.lock.Unlock()
+
 	return &blockchainKeystore{
 		blockchainID: blockchainID,
 		ks:           ks,
@@ -142,32 +208,77 @@ func (ks *keystore) NewBlockchainKeyStore(blockchainID ids.ID) BlockchainKeystor
 }
 
 func (ks *keystore) GetDatabase(bID ids.ID, username, password string) (*encdb.Database, error) {
-	bcDB, err := ks.GetRawDatabase(bID, username, password)
+	bcDB, key, err := ks.GetRawDatabaseWithKey(bID, username, password)
 	if err != nil {
 		return nil, err
 	}
-	return encdb.New([]byte(password), bcDB)
+	return encdb.NewWithKey(key, bcDB)
 }
 
 func (ks *keystore) GetRawDatabase(bID ids.ID, username, pw string) (database.Database, error) {
+	bcDB, _, err := ks.getRawDatabase(bID, username, pw)
+	return bcDB, err
+}
+
+func (ks *keystore) GetRawDatabaseWithKey(bID ids.ID, username, pw string) (database.Database, []byte, error) {
+	bcDB, creds, err := ks.getRawDatabase(bID, username, pw)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := creds.deriveKey(pw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bcDB, key, nil
+}
+
+// getRawDatabase returns the database of [username] for the chain [bID] and
+// their credentials. The user's data isn't re-encrypted until the returned
+// database is closed.
+func (ks *keystore) getRawDatabase(bID ids.ID, username, pw string) (database.Database, *credentials, error) {
 	if username == "" {
-		return nil, errEmptyUsername
+		return nil, nil, errEmptyUsername
 	}
 
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	passwordHash, err := ks.getPassword(username)
+	creds, err := ks.authenticate(username, pw)
 	if err != nil {
-		return nil, err
-	}
-	if passwordHash == nil || !passwordHash.Check(pw) {
-		return nil, fmt.Errorf("incorrect password for user %q", username)
+		return nil, nil, err
 	}
 
+	ks.prefixToChainID[chainPrefix(bID)] = bID
+
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 	bcDB := prefixdb.NewNested(bID[:], userDB)
-	return bcDB, nil
+	ks.openDatabases[username]++
+	return &userDatabase{
+		Database: bcDB,
+		ks:       ks,
+		username: username,
+	}, creds, nil
+}
+
+// userDatabase is a database of a user that was returned by the keystore
+type userDatabase struct {
+	database.Database
+	ks        *keystore
+	username  string
+	closeOnce sync.Once
+}
+
+func (db *userDatabase) Close() error {
+	db.closeOnce.Do(func() {
+		db.ks.lock.Lock()
+		defer db.ks.lock.Unlock()
+
+		db.ks.openDatabases[db.username]--
+		if db.ks.openDatabases[db.username] <= 0 {
+			delete(db.ks.openDatabases, db.username)
+		}
+	})
+	return db.Database.Close()
 }
 
 func (ks *keystore) CreateUser(username, pw string) error {
@@ -181,11 +292,11 @@ func (ks *keystore) CreateUser(username, pw string) error {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	passwordHash, err := ks.getPassword(username)
+	creds, err := ks.getCredentials(username)
 	if err != nil {
 		return err
 	}
-	if passwordHash != nil {
+	if creds != nil {
 		return fmt.Errorf("user already exists: %s", username)
 	}
 
@@ -193,20 +304,20 @@ func (ks *keystore) CreateUser(username, pw string) error {
 		return err
 	}
 
-	passwordHash = &password.Hash{}
-	if err := passwordHash.Set(pw); err != nil {
+	creds, err = newCredentials(pw, ks.kdfParams)
+	if err != nil {
 		return err
 	}
 
-	passwordBytes, err := c.Marshal(codecVersion, passwordHash)
+	credsBytes, err := c.Marshal(codecVersion, creds)
 	if err != nil {
 		return err
 	}
 
-	if err := ks.userDB.Put([]byte(username), passwordBytes); err != nil {
+	if err := ks.userDB.Put([]byte(username), credsBytes); err != nil {
 		return err
 	}
-	ks.usernameToPassword[username] = passwordHash
+	ks.usernameToCredentials[username] = creds
 
 	return nil
 }
@@ -223,13 +334,13 @@ func (ks *keystore) DeleteUser(username, pw string) error {
 	defer ks.lock.Unlock()
 
 	// check if user exists and valid user.
-	passwordHash, err := ks.getPassword(username)
+	creds, err := ks.getCredentials(username)
 	switch {
 	case err != nil:
 		return err
-	case passwordHash == nil:
-		return fmt.Errorf("user doesn't exist: %s", username)
-	case !passwordHash.Check(pw):
+	case creds == nil:
+		return fmt.Errorf("%w: %s", errNoUser, username)
+	case !creds.check(pw):
 		return fmt.Errorf("incorrect password for user %q", username)
 	}
 
@@ -260,7 +371,7 @@ func (ks *keystore) DeleteUser(username, pw string) error {
 	}
 
 	// delete from users map.
-	delete(ks.usernameToPassword, username)
+	delete(ks.usernameToCredentials, username)
 	return nil
 }
 
@@ -278,7 +389,7 @@ func (ks *keystore) ListUsers() ([]string, error) {
 	return users, it.Error()
 }
 
-func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
+func (ks *keystore) RotateUserKey(username, pw string) error {
 	if username == "" {
 		return errEmptyUsername
 	}
@@ -289,94 +400,121 @@ func (ks *keystore) ImportUser(username, pw string, userBytes []byte) error {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	passwordHash, err := ks.getPassword(username)
-	if err != nil {
-		return err
-	}
-	if passwordHash != nil {
-		return fmt.Errorf("user already exists: %s", username)
-	}
-
-	userData := user{}
-	if _, err := c.Unmarshal(userBytes, &userData); err != nil {
+	creds, err := ks.getCredentials(username)
+	switch {
+	case err != nil:
 		return err
-	}
-	if !userData.Hash.Check(pw) {
+	case creds == nil:
+		return fmt.Errorf("%w: %s", errNoUser, username)
+	case !creds.check(pw):
 		return fmt.Errorf("incorrect password for user %q", username)
+	case ks.openDatabases[username] > 0:
+		return fmt.Errorf("%w: %s", errUserInUse, username)
 	}
+	_, err = ks.rotate(username, pw, creds)
+	return err
+}
 
-	usrBytes, err := c.Marshal(codecVersion, &userData.Hash)
+// authenticate returns the credentials of [username] if [pw] is their
+// password. If the credentials weren't derived with the current parameters,
+// the user's data is re-encrypted first.
+// Assumes [ks.lock] is held.
+func (ks *keystore) authenticate(username, pw string) (*credentials, error) {
+	creds, err := ks.getCredentials(username)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	userBatch := ks.userDB.NewBatch()
-	if err := userBatch.Put([]byte(username), usrBytes); err != nil {
-		return err
+	if creds == nil || !creds.check(pw) {
+		return nil, fmt.Errorf("incorrect password for user %q", username)
 	}
-
-	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
-	dataBatch := userDataDB.NewBatch()
-	for _, kvp := range userData.Data {
-		if err := dataBatch.Put(kvp.Key, kvp.Value); err != nil {
-			return fmt.Errorf("error on database put: %w", err)
-		}
+	if !creds.outdated(ks.kdfParams) {
+		return creds, nil
+	}
+	if ks.openDatabases[username] > 0 {
+		// Databases with the old key are in use, so the data is re-encrypted
+		// the next time the user authenticates without open databases.
+		return creds, nil
 	}
 
-	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
-		return err
+	newCreds, err := ks.rotate(username, pw, creds)
+	if err != nil {
+		// The user can keep using their old credentials
+		ks.log.Warn("couldn't upgrade the key derivation parameters of user %q: %s", username, err)
+		return creds, nil
 	}
-	ks.usernameToPassword[username] = &userData.Hash
-	return nil
+	ks.log.Info("upgraded the key derivation parameters of user %q", username)
+	return newCreds, nil
 }
 
-func (ks *keystore) ExportUser(username, pw string) ([]byte, error) {
-	if username == "" {
-		return nil, errEmptyUsername
+// rotate re-encrypts the data of [username], whose password is [pw] and
+// credentials are [creds], with a key derived with new salts and the current
+// parameters. Returns the new credentials of the user.
+//
+// Must not be called while the user has open databases, since values written
+// through them with the old key couldn't be decrypted afterwards.
+// Assumes [ks.lock] is held.
+func (ks *keystore) rotate(username, pw string, creds *credentials) (*credentials, error) {
+	newCreds, err := newCredentials(pw, ks.kdfParams)
+	if err != nil {
+		return nil, err
 	}
-	if len(username) > maxUserLen {
-		return nil, errUserMaxLength
+	oldKey, err := creds.deriveKey(pw)
+	if err != nil {
+		return nil, err
+	}
+	newKey, err := newCreds.deriveKey(pw)
+	if err != nil {
+		return nil, err
 	}
 
-	ks.lock.Lock()
-	defer ks.lock.Unlock()
-
-	passwordHash, err := ks.getPassword(username)
+	userDataDB := prefixdb.New([]byte(username), ks.bcDB)
+	oldDB, err := encdb.NewWithKey(oldKey, userDataDB)
 	if err != nil {
 		return nil, err
 	}
-	if passwordHash == nil || !passwordHash.Check(pw) {
-		return nil, fmt.Errorf("incorrect password for user %q", username)
+	newDB, err := encdb.NewWithKey(newKey, userDataDB)
+	if err != nil {
+		return nil, err
 	}
 
-	userDB := prefixdb.New([]byte(username), ks.bcDB)
-
-	userData := user{Hash: *passwordHash}
-	it := userDB.NewIterator()
+	dataBatch := newDB.NewBatch()
+	it := oldDB.NewIterator()
 	defer it.Release()
+
 	for it.Next() {
-		userData.Data = append(userData.Data, kvPair{
-			Key:   it.Key(),
-			Value: it.Value(),
-		})
+		if err := dataBatch.Put(it.Key(), it.Value()); err != nil {
+			return nil, err
+		}
 	}
 	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("couldn't decrypt the data of user %q: %w", username, err)
+	}
+
+	credsBytes, err := c.Marshal(codecVersion, newCreds)
+	if err != nil {
+		return nil, err
+	}
+	userBatch := ks.userDB.NewBatch()
+	if err := userBatch.Put([]byte(username), credsBytes); err != nil {
 		return nil, err
 	}
 
-	// Return the byte representation of the user
-	return c.Marshal(codecVersion, &userData)
+	if err := atomic.WriteAll(dataBatch, userBatch); err != nil {
+		return nil, err
+	}
+	ks.usernameToCredentials[username] = newCreds
+	return newCreds, nil
 }
 
-func (ks *keystore) getPassword(username string) (*password.Hash, error) {
+func (ks *keystore) getCredentials(username string) (*credentials, error) {
 	// If the user is already in memory, return it
-	passwordHash, exists := ks.usernameToPassword[username]
+	creds, exists := ks.usernameToCredentials[username]
 	if exists {
-		return passwordHash, nil
+		return creds, nil
 	}
 
 	// The user is not in memory; try the database
-	userBytes, err := ks.userDB.Get([]byte(username))
+	credsBytes, err := ks.userDB.Get([]byte(username))
 	if err == database.ErrNotFound {
 		// The user doesn't exist
 		return nil, nil
@@ -386,7 +524,30 @@ func (ks *keystore) getPassword(username string) (*password.Hash, error) {
 		return nil, err
 	}
 
-	passwordHash = &password.Hash{}
-	_, err = c.Unmarshal(userBytes, passwordHash)
-	return passwordHash, err
+	return parseCredentials(credsBytes)
+}
+
+// parseUser parses a user exported by this or a previous version of the
+// keystore
+func parseUser(userBytes []byte) (*user, error) {
+	version, err := peekCodecVersion(userBytes)
+	if err != nil {
+		return nil, err
+	}
+	if version == legacyCodecVersion {
+		userData := legacyUser{}
+		if _, err := c.Unmarshal(userBytes, &userData); err != nil {
+			return nil, err
+		}
+		return &user{
+			Credentials: *legacyCredentials(userData.Hash),
+			Data:        userData.Data,
+		}, nil
+	}
+
+	userData := &user{}
+	if _, err := c.Unmarshal(userBytes, userData); err != nil {
+		return nil, err
+	}
+	return userData, nil
 }
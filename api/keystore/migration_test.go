@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,7 +27,7 @@ func TestMigrationNoPrevDB(t *testing.T) {
 	})
 	assert.NoError(err)
 
-	_, err = New(logging.NoLog{}, dbManager)
+	_, err = New(logging.NoLog{}, dbManager, password.DefaultKDFParams)
 	assert.NoError(err)
 }
 
@@ -49,7 +50,7 @@ func TestMigration1_0_0To1_4_5(t *testing.T) {
 	})
 	assert.NoError(err)
 
-	ksV1_0_0, err := New(&logging.NoLog{}, dbManagerV1_0_0)
+	ksV1_0_0, err := New(&logging.NoLog{}, dbManagerV1_0_0, password.DefaultKDFParams)
 	assert.NoError(err)
 
 	err = ksV1_0_0.CreateUser(username, strongPassword)
@@ -77,7 +78,7 @@ func TestMigration1_0_0To1_4_5(t *testing.T) {
 	})
 	assert.NoError(err)
 
-	ksV1_4_5, err := New(&logging.NoLog{}, dbManagerV1_4_5)
+	ksV1_4_5, err := New(&logging.NoLog{}, dbManagerV1_4_5, password.DefaultKDFParams)
 	assert.NoError(err)
 
 	userDatabaseVersion1_4_5, err := ksV1_4_5.GetDatabase(ids.Empty, username, strongPassword)
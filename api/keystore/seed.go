@@ -0,0 +1,91 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/encdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
+)
+
+var (
+	// ErrNoSeed is returned when the seed of a user that wasn't seeded from a
+	// mnemonic is requested
+	ErrNoSeed = errors.New("user wasn't seeded from a mnemonic")
+
+	errSeedExists = errors.New("user was already seeded from a mnemonic")
+
+	// Users' seeds are stored, encrypted, with their data under this prefix,
+	// which doesn't collide with the prefixes of chains
+	seedPrefix = []byte("hdSeed")
+	seedKey    = []byte("seed")
+)
+
+func (ks *keystore) ImportMnemonic(username, pw, mnemonic string) error {
+	if username == "" {
+		return errEmptyUsername
+	}
+	if len(username) > maxUserLen {
+		return errUserMaxLength
+	}
+	seed, err := hdwallet.Seed(mnemonic)
+	if err != nil {
+		return err
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	db, err := ks.getSeedDatabase(username, pw)
+	if err != nil {
+		return err
+	}
+	hasSeed, err := db.Has(seedKey)
+	if err != nil {
+		return err
+	}
+	if hasSeed {
+		return fmt.Errorf("%w: %s", errSeedExists, username)
+	}
+	return db.Put(seedKey, seed)
+}
+
+func (ks *keystore) GetSeed(username, pw string) ([]byte, error) {
+	if username == "" {
+		return nil, errEmptyUsername
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	db, err := ks.getSeedDatabase(username, pw)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := db.Get(seedKey)
+	if err == database.ErrNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNoSeed, username)
+	}
+	return seed, err
+}
+
+// getSeedDatabase returns the database the seed of [username] is stored in
+// Assumes [ks.lock] is held.
+func (ks *keystore) getSeedDatabase(username, pw string) (*encdb.Database, error) {
+	creds, err := ks.authenticate(username, pw)
+	if err != nil {
+		return nil, err
+	}
+	key, err := creds.deriveKey(pw)
+	if err != nil {
+		return nil, err
+	}
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	return encdb.NewWithKey(key, prefixdb.NewNested(seedPrefix, userDB))
+}
@@ -0,0 +1,49 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
+)
+
+func TestImportMnemonic(t *testing.T) {
+	assert := assert.New(t)
+
+	ks, err := CreateTestKeystore()
+	assert.NoError(err)
+	assert.NoError(ks.CreateUser("bob", strongPassword))
+
+	_, err = ks.GetSeed("bob", strongPassword)
+	assert.ErrorIs(err, ErrNoSeed)
+
+	assert.Error(ks.ImportMnemonic("bob", strongPassword, "not a mnemonic"))
+	assert.Error(ks.ImportMnemonic("alice", strongPassword, ""))
+
+	mnemonic, err := hdwallet.NewMnemonic()
+	assert.NoError(err)
+	assert.Error(ks.ImportMnemonic("bob", "wrong password", mnemonic))
+	assert.NoError(ks.ImportMnemonic("bob", strongPassword, mnemonic))
+	assert.Error(ks.ImportMnemonic("bob", strongPassword, mnemonic), "should have errored due to the user already being seeded")
+
+	expectedSeed, err := hdwallet.Seed(mnemonic)
+	assert.NoError(err)
+	seed, err := ks.NewBlockchainKeyStore(ids.GenerateTestID()).GetSeed("bob", strongPassword)
+	assert.NoError(err)
+	assert.Equal(expectedSeed, seed)
+
+	// The seed is carried over by backups
+	backupBytes, err := ks.ExportUser("bob", strongPassword)
+	assert.NoError(err)
+	newKS, err := CreateTestKeystore()
+	assert.NoError(err)
+	assert.NoError(newKS.ImportUser("bob", strongPassword, backupBytes))
+	seed, err = newKS.GetSeed("bob", strongPassword)
+	assert.NoError(err)
+	assert.Equal(expectedSeed, seed)
+}
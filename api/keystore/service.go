@@ -6,12 +6,17 @@ package keystore
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -26,6 +31,33 @@ func (s *service) CreateUser(_ *http.Request, args *api.UserPass, reply *api.Suc
 	return s.ks.CreateUser(args.Username, args.Password)
 }
 
+type CreateMnemonicReply struct {
+	Mnemonic string `json:"mnemonic"`
+}
+
+// CreateMnemonic returns a new BIP-39 mnemonic. The mnemonic isn't stored.
+func (s *service) CreateMnemonic(_ *http.Request, _ *struct{}, reply *CreateMnemonicReply) error {
+	s.ks.log.Debug("Keystore: CreateMnemonic called")
+
+	var err error
+	reply.Mnemonic, err = hdwallet.NewMnemonic()
+	return err
+}
+
+type ImportMnemonicArgs struct {
+	api.UserPass
+	// The BIP-39 mnemonic the user's keys are derived from
+	Mnemonic string `json:"mnemonic"`
+}
+
+// ImportMnemonic seeds an existing user from a BIP-39 mnemonic
+func (s *service) ImportMnemonic(_ *http.Request, args *ImportMnemonicArgs, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: ImportMnemonic called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.ImportMnemonic(args.Username, args.Password, args.Mnemonic)
+}
+
 func (s *service) DeleteUser(_ *http.Request, args *api.UserPass, reply *api.SuccessResponse) error {
 	s.ks.log.Debug("Keystore: DeleteUser called with %s", args.Username)
 
@@ -52,9 +84,38 @@ type ImportUserArgs struct {
 	User string `json:"user"`
 	// The encoding of [User] ("hex" or "cb58")
 	Encoding formatting.Encoding `json:"encoding"`
+	// Chains whose data is imported. If empty, the data of every chain in
+	// [User] is imported.
+	BlockchainIDs []ids.ID `json:"blockchainIDs"`
+	// Passphrase [User] was encrypted with, if any
+	Passphrase string `json:"passphrase"`
+	// If true, [User] is checked and the chains whose data would be imported
+	// are returned, but nothing is imported
+	DryRun bool `json:"dryRun"`
+}
+
+// ImportedChain describes the data imported for a user on a chain
+type ImportedChain struct {
+	// ID of the chain. Empty if the node that exported the user didn't know
+	// which chain [Prefix] belongs to.
+	BlockchainID string `json:"blockchainID,omitempty"`
+	// Hash of the chain's ID, which the user's data is stored under
+	Prefix ids.ID `json:"prefix"`
+	// Number of keys imported
+	NumKeys json.Uint64 `json:"numKeys"`
+}
+
+type ImportUserReply struct {
+	Success bool `json:"success"`
+	// Time the user was exported at. Zero if it was exported by a previous
+	// version of the keystore.
+	CreatedAt time.Time `json:"createdAt"`
+	// True if the user was encrypted with a passphrase
+	Encrypted bool            `json:"encrypted"`
+	Chains    []ImportedChain `json:"chains"`
 }
 
-func (s *service) ImportUser(r *http.Request, args *ImportUserArgs, reply *api.SuccessResponse) error {
+func (s *service) ImportUser(r *http.Request, args *ImportUserArgs, reply *ImportUserReply) error {
 	s.ks.log.Debug("Keystore: ImportUser called for %s", args.Username)
 
 	// Decode the user from string to bytes
@@ -63,8 +124,29 @@ func (s *service) ImportUser(r *http.Request, args *ImportUserArgs, reply *api.S
 		return fmt.Errorf("couldn't decode 'user' to bytes: %w", err)
 	}
 
+	summary, err := s.ks.ImportBackup(args.Username, args.Password, user, RestoreConfig{
+		ChainIDs:   args.BlockchainIDs,
+		Passphrase: args.Passphrase,
+		DryRun:     args.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
 	reply.Success = true
-	return s.ks.ImportUser(args.Username, args.Password, user)
+	reply.CreatedAt = summary.CreatedAt
+	reply.Encrypted = summary.Encrypted
+	reply.Chains = make([]ImportedChain, len(summary.Chains))
+	for i, chain := range summary.Chains {
+		reply.Chains[i] = ImportedChain{
+			Prefix:  chain.Prefix,
+			NumKeys: json.Uint64(chain.NumKeys),
+		}
+		if chain.ChainID != ids.Empty {
+			reply.Chains[i].BlockchainID = chain.ChainID.String()
+		}
+	}
+	return nil
 }
 
 type ExportUserArgs struct {
@@ -72,6 +154,15 @@ type ExportUserArgs struct {
 	api.UserPass
 	// The encoding for the exported user ("hex" or "cb58")
 	Encoding formatting.Encoding `json:"encoding"`
+	// If true, the user is exported as a backup, which previous versions of
+	// the keystore can't import. Implied by [BlockchainIDs] and [Passphrase].
+	Backup bool `json:"backup"`
+	// Chains whose data is exported. If empty, the data of every chain is
+	// exported.
+	BlockchainIDs []ids.ID `json:"blockchainIDs"`
+	// If non-empty, the exported user is encrypted with this passphrase and
+	// can be imported under any password
+	Passphrase string `json:"passphrase"`
 }
 
 type ExportUserReply struct {
@@ -84,7 +175,18 @@ type ExportUserReply struct {
 func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *ExportUserReply) error {
 	s.ks.log.Debug("Keystore: ExportUser called for %s", args.Username)
 
-	userBytes, err := s.ks.ExportUser(args.Username, args.Password)
+	var (
+		userBytes []byte
+		err       error
+	)
+	if args.Backup || len(args.BlockchainIDs) > 0 || args.Passphrase != "" {
+		userBytes, err = s.ks.ExportBackup(args.Username, args.Password, BackupConfig{
+			ChainIDs:   args.BlockchainIDs,
+			Passphrase: args.Passphrase,
+		})
+	} else {
+		userBytes, err = s.ks.ExportUser(args.Username, args.Password)
+	}
 	if err != nil {
 		return err
 	}
@@ -98,6 +200,15 @@ func (s *service) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Expor
 	return nil
 }
 
+// RotateUserKey re-encrypts the data of a user with a key derived with new
+// salts and the keystore's current key derivation parameters
+func (s *service) RotateUserKey(_ *http.Request, args *api.UserPass, reply *api.SuccessResponse) error {
+	s.ks.log.Debug("Keystore: RotateUserKey called for %s", args.Username)
+
+	reply.Success = true
+	return s.ks.RotateUserKey(args.Username, args.Password)
+}
+
 // CreateTestKeystore returns a new keystore that can be utilized for testing
 func CreateTestKeystore() (Keystore, error) {
 	dbManager, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{
@@ -109,5 +220,5 @@ func CreateTestKeystore() (Keystore, error) {
 	if err != nil {
 		return nil, err
 	}
-	return New(logging.NoLog{}, dbManager)
+	return New(logging.NoLog{}, dbManager, password.DefaultKDFParams)
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -287,7 +288,7 @@ func TestServiceExportImport(t *testing.T) {
 		newS := service{ks: newKS.(*keystore)}
 
 		{
-			reply := api.SuccessResponse{}
+			reply := ImportUserReply{}
 			if err := newS.ImportUser(nil, &ImportUserArgs{
 				UserPass: api.UserPass{
 					Username: "bob",
@@ -300,7 +301,7 @@ func TestServiceExportImport(t *testing.T) {
 		}
 
 		{
-			reply := api.SuccessResponse{}
+			reply := ImportUserReply{}
 			if err := newS.ImportUser(nil, &ImportUserArgs{
 				UserPass: api.UserPass{
 					Username: "",
@@ -313,7 +314,7 @@ func TestServiceExportImport(t *testing.T) {
 		}
 
 		{
-			reply := api.SuccessResponse{}
+			reply := ImportUserReply{}
 			if err := newS.ImportUser(nil, &ImportUserArgs{
 				UserPass: api.UserPass{
 					Username: "bob",
@@ -422,7 +423,7 @@ func TestServiceDeleteUser(t *testing.T) {
 			}
 
 			if err == nil && got.Success { // delete is successful
-				if _, ok := ks.usernameToPassword[testUser]; ok {
+				if _, ok := ks.usernameToCredentials[testUser]; ok {
 					t.Fatalf("DeleteUser() failed: expected the user %s should be delete from users map", testUser)
 				}
 
@@ -440,7 +441,7 @@ func TestServiceDeleteUser(t *testing.T) {
 // 3rd part -> check if data from 1.0.0 exists in 1.4.5
 func TestMigrateKeystoreUser(t *testing.T) {
 	testUser := "testUser"
-	password := "passwTest@fake01ord"
+	pw := "passwTest@fake01ord"
 	bID := ids.Empty
 	versionedDBs := []*manager.VersionedDatabase{
 		{
@@ -453,16 +454,16 @@ func TestMigrateKeystoreUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ks, err := New(logging.NoLog{}, dbManager)
+	ks, err := New(logging.NoLog{}, dbManager, password.DefaultKDFParams)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := ks.CreateUser(testUser, password); err != nil {
+	if err := ks.CreateUser(testUser, pw); err != nil {
 		t.Fatalf("Failed to create user: %s", err)
 	}
 
-	userDB, err := ks.GetDatabase(bID, testUser, password)
+	userDB, err := ks.GetDatabase(bID, testUser, pw)
 	if err != nil {
 		t.Fatalf("Failed to get user database: %s", err)
 	}
@@ -486,7 +487,7 @@ func TestMigrateKeystoreUser(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	ksUpgraded, err := New(logging.NoLog{}, upgradedDBManager)
+	ksUpgraded, err := New(logging.NoLog{}, upgradedDBManager, password.DefaultKDFParams)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -504,7 +505,7 @@ func TestMigrateKeystoreUser(t *testing.T) {
 		t.Fatalf("Expected first user to be %s, but found %s", testUser, users[0])
 	}
 
-	userDB, err = ksUpgraded.GetDatabase(bID, testUser, password)
+	userDB, err = ksUpgraded.GetDatabase(bID, testUser, pw)
 	if err != nil {
 		t.Fatalf("Failed to get user database from upgraded DB: %s", err)
 	}
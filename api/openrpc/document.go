@@ -0,0 +1,211 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/rpc/v2"
+)
+
+const (
+	// Version is the version of the OpenRPC specification that the generated
+	// documents follow
+	Version = "1.2.6"
+
+	// DocumentVersion is the version reported in the info of the generated
+	// documents
+	DocumentVersion = "1.0.0"
+
+	// DiscoverServiceName is the name of the service whose method "discover"
+	// returns the document describing the other services of a server
+	DiscoverServiceName = "rpc"
+)
+
+var (
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfRequest = reflect.TypeOf((*http.Request)(nil))
+)
+
+// Service is a JSON-RPC service, as registered with rpc.Server.RegisterService
+type Service struct {
+	// Receiver whose methods are the methods of the service
+	Receiver interface{}
+	// Name that prefixes the methods of the service. e.g. "avm"
+	Name string
+}
+
+// Document is an OpenRPC document describing the methods of JSON-RPC services
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Method struct {
+	Name           string              `json:"name"`
+	ParamStructure string              `json:"paramStructure"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         ContentDescriptor   `json:"result"`
+}
+
+type ContentDescriptor struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	// Name of a type --> schema of the type
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// NewDocument returns a document describing the methods of [services]
+func NewDocument(services ...Service) (*Document, error) {
+	names := make([]string, len(services))
+	for i, service := range services {
+		names[i] = service.Name
+	}
+	doc := &Document{
+		OpenRPC: Version,
+		Info: Info{
+			Title:   fmt.Sprintf("%s API", strings.Join(names, ", ")),
+			Version: DocumentVersion,
+		},
+		Methods: []Method{},
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+	g := newGenerator(doc.Components.Schemas)
+	for _, service := range services {
+		methods, err := g.methods(service)
+		if err != nil {
+			return nil, err
+		}
+		doc.Methods = append(doc.Methods, methods...)
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool {
+		return doc.Methods[i].Name < doc.Methods[j].Name
+	})
+	return doc, nil
+}
+
+// RegisterServices registers each of [services] with [server]. Additionally
+// registers the method "rpc.discover", which returns an OpenRPC document
+// describing the methods of [services].
+func RegisterServices(server *rpc.Server, services ...Service) error {
+	doc, err := NewDocument(services...)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		if err := server.RegisterService(service.Receiver, service.Name); err != nil {
+			return err
+		}
+	}
+	return server.RegisterService(&discoverService{document: doc}, DiscoverServiceName)
+}
+
+type discoverService struct{ document *Document }
+
+// Discover returns the OpenRPC document describing the server's methods
+func (s *discoverService) Discover(_ *http.Request, _ *struct{}, reply *Document) error {
+	*reply = *s.document
+	return nil
+}
+
+// methods returns the JSON-RPC methods of [service]. A method is included
+// under the same conditions that rpc.Server.RegisterService includes it.
+func (g *generator) methods(service Service) ([]Method, error) {
+	receiverType := reflect.TypeOf(service.Receiver)
+	var methods []Method
+	for i := 0; i < receiverType.NumMethod(); i++ {
+		method := receiverType.Method(i)
+		methodType := method.Type
+		if method.PkgPath != "" ||
+			methodType.NumIn() != 4 ||
+			methodType.In(1) != typeOfRequest ||
+			!isExportedPointer(methodType.In(2)) ||
+			!isExportedPointer(methodType.In(3)) ||
+			methodType.NumOut() != 1 ||
+			methodType.Out(0) != typeOfError {
+			continue
+		}
+
+		params, err := g.params(methodType.In(2).Elem())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't describe the arguments of %s.%s: %w", service.Name, method.Name, err)
+		}
+		result, err := g.schema(methodType.In(3).Elem())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't describe the reply of %s.%s: %w", service.Name, method.Name, err)
+		}
+		methods = append(methods, Method{
+			Name:           fmt.Sprintf("%s.%s", service.Name, lowercaseFirst(method.Name)),
+			ParamStructure: "by-name",
+			Params:         params,
+			Result: ContentDescriptor{
+				Name:   "result",
+				Schema: result,
+			},
+		})
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("service %q has no JSON-RPC methods", service.Name)
+	}
+	return methods, nil
+}
+
+// params returns the parameters of a method whose arguments are of type [t].
+// The fields of a struct are each a parameter.
+func (g *generator) params(t reflect.Type) ([]ContentDescriptor, error) {
+	if t.Kind() != reflect.Struct || isCustomMarshaler(t) {
+		schema, err := g.schema(t)
+		if err != nil {
+			return nil, err
+		}
+		return []ContentDescriptor{{Name: "args", Schema: schema}}, nil
+	}
+
+	params := []ContentDescriptor{}
+	err := g.fields(t, func(name string, schema *Schema) {
+		params = append(params, ContentDescriptor{
+			Name:   name,
+			Schema: schema,
+		})
+	})
+	return params, err
+}
+
+// isExportedPointer returns true if [t] is a pointer to an exported or builtin
+// type, as rpc.Server.RegisterService requires of arguments and replies
+func isExportedPointer(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r, _ := utf8.DecodeRuneInString(t.Name())
+	return unicode.IsUpper(r) || t.PkgPath() == ""
+}
+
+// lowercaseFirst returns [s] with its first letter in lowercase, which is how
+// methods are called through the codec in utils/json
+func lowercaseFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
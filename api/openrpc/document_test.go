@@ -0,0 +1,135 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+type Embedded struct {
+	Shared string `json:"shared"`
+}
+
+type Node struct {
+	ID       ids.ShortID `json:"id"`
+	Children []*Node     `json:"children"`
+}
+
+type TestArgs struct {
+	Embedded
+	Amount   cjson.Uint64      `json:"amount"`
+	Flag     bool              `json:"flag,omitempty"`
+	Bytes    []byte            `json:"bytes"`
+	Labels   map[string]uint32 `json:"labels"`
+	Count    int               `json:"count,string"`
+	Ignored  string            `json:"-"`
+	Untagged string
+	internal string
+}
+
+type TestReply struct {
+	Root Node `json:"root"`
+}
+
+type TestService struct{}
+
+func (s *TestService) Get(_ *http.Request, _ *TestArgs, _ *TestReply) error { return nil }
+
+func (s *TestService) Ping(_ *http.Request, _ *struct{}, _ *struct{}) error { return nil }
+
+// NotAMethod doesn't have the signature of a JSON-RPC method
+func (s *TestService) NotAMethod() {}
+
+func TestNewDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	doc, err := NewDocument(Service{Receiver: &TestService{}, Name: "test"})
+	assert.NoError(err)
+
+	assert.Equal(Version, doc.OpenRPC)
+	assert.Equal("test API", doc.Info.Title)
+	if !assert.Len(doc.Methods, 2) {
+		return
+	}
+
+	get := doc.Methods[0]
+	assert.Equal("test.get", get.Name)
+	params := make(map[string]*Schema)
+	for _, param := range get.Params {
+		params[param.Name] = param.Schema
+	}
+	assert.Equal(map[string]*Schema{
+		"shared":   {Type: "string"},
+		"amount":   {Type: "string", Pattern: uintPattern},
+		"flag":     {Type: "boolean"},
+		"bytes":    {Type: "string", Format: "byte"},
+		"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "integer"}},
+		"count":    {Type: "string"},
+		"Untagged": {Type: "string"},
+	}, params)
+	assert.Equal(&Schema{Ref: componentsPrefix + "openrpc.TestReply"}, get.Result.Schema)
+
+	assert.Equal(map[string]*Schema{
+		"openrpc.TestReply": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"root": {Ref: componentsPrefix + "openrpc.Node"},
+			},
+		},
+		"openrpc.Node": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id": {Type: "string"},
+				"children": {
+					Type:  "array",
+					Items: &Schema{Ref: componentsPrefix + "openrpc.Node"},
+				},
+			},
+		},
+	}, doc.Components.Schemas)
+
+	ping := doc.Methods[1]
+	assert.Equal("test.ping", ping.Name)
+	assert.Empty(ping.Params)
+	assert.Equal(&Schema{Type: "object", Properties: map[string]*Schema{}}, ping.Result.Schema)
+}
+
+func TestNewDocumentNoMethods(t *testing.T) {
+	_, err := NewDocument(Service{Receiver: &struct{}{}, Name: "empty"})
+	assert.Error(t, err)
+}
+
+func TestRegisterServicesDiscover(t *testing.T) {
+	assert := assert.New(t)
+
+	server := rpc.NewServer()
+	server.RegisterCodec(cjson.NewCodec(), "application/json")
+	err := RegisterServices(server, Service{Receiver: &TestService{}, Name: "test"})
+	assert.NoError(err)
+
+	body := []byte(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+
+	var response struct {
+		Result Document `json:"result"`
+	}
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(err)
+	assert.Equal(Version, response.Result.OpenRPC)
+	assert.Len(response.Result.Methods, 2)
+}
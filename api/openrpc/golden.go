@@ -0,0 +1,51 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// UpdateGoldenEnvVar is the environment variable that, if set, makes
+// VerifyGolden rewrite the golden files rather than compare against them
+const UpdateGoldenEnvVar = "UPDATE_OPENRPC_GOLDEN"
+
+// VerifyGolden fails [t] if the document describing [services] doesn't match
+// the golden file at [goldenPath]
+func VerifyGolden(t *testing.T, goldenPath string, services ...Service) {
+	doc, err := NewDocument(services...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docBytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docBytes = append(docBytes, '\n')
+
+	if os.Getenv(UpdateGoldenEnvVar) != "" {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), perms.ReadWriteExecute); err != nil {
+			t.Fatal(err)
+		}
+		if err := perms.WriteFile(goldenPath, docBytes, perms.ReadWrite); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	goldenBytes, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("couldn't read golden file: %s. Run the test with %s=1 to create it", err, UpdateGoldenEnvVar)
+	}
+	if !bytes.Equal(goldenBytes, docBytes) {
+		t.Fatalf("OpenRPC document doesn't match golden file %s. If the change to the API is intended, run the test with %s=1 to update it", goldenPath, UpdateGoldenEnvVar)
+	}
+}
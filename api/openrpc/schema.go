@@ -0,0 +1,228 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	componentsPrefix = "#/components/schemas/"
+
+	uintPattern  = "^[0-9]+$"
+	floatPattern = "^-?[0-9]+(\\.[0-9]+)?$"
+)
+
+var (
+	typeOfMarshaler     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeOfTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// Schemas of the types whose JSON encoding can't be derived from their
+	// kind because they implement json.Marshaler
+	knownSchemas = map[reflect.Type]Schema{
+		reflect.TypeOf(cjson.Uint8(0)):   {Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(cjson.Uint16(0)):  {Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(cjson.Uint32(0)):  {Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(cjson.Uint64(0)):  {Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(cjson.Float32(0)): {Type: "string", Pattern: floatPattern},
+		reflect.TypeOf(cjson.Float64(0)): {Type: "string", Pattern: floatPattern},
+		reflect.TypeOf(ids.ID{}):         {Type: "string"},
+		reflect.TypeOf(ids.ShortID{}):    {Type: "string"},
+		reflect.TypeOf(time.Time{}):      {Type: "string", Format: "date-time"},
+		reflect.TypeOf(formatting.CB58): {
+			Type: "string",
+			Enum: []string{formatting.CB58.String(), formatting.Hex.String(), formatting.JSON.String()},
+		},
+	}
+)
+
+// Schema is a JSON Schema describing the JSON encoding of a type
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// generator creates the schemas of types. Named struct types are described
+// once, in [schemas], and referenced from the schemas of other types.
+type generator struct {
+	// name of a component --> schema of the component
+	schemas map[string]*Schema
+	// struct type --> name of the component describing it
+	names map[reflect.Type]string
+}
+
+func newGenerator(schemas map[string]*Schema) *generator {
+	return &generator{
+		schemas: schemas,
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schema returns the schema of the JSON encoding of [t]
+func (g *generator) schema(t reflect.Type) (*Schema, error) {
+	if schema, ok := knownSchemas[t]; ok {
+		return &schema, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		return g.schema(t.Elem())
+	}
+	if isCustomMarshaler(t) {
+		switch t.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+			// The encoding can't be derived, so any value is allowed.
+			return &Schema{}, nil
+		default:
+			// Types of basic kinds with custom encodings, such as statuses,
+			// are encoded as strings.
+			return &Schema{Type: "string"}, nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice:
+		// Byte slices are base64 encoded
+		if t.Elem().Kind() == reflect.Uint8 && !isCustomMarshaler(t.Elem()) {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		fallthrough
+	case reflect.Array:
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return nil, fmt.Errorf("type %s of kind %s can't be encoded as JSON", t, t.Kind())
+	}
+}
+
+// structSchema returns the schema of the struct type [t]. If [t] is named, the
+// schema is added to the components and a reference to it is returned.
+func (g *generator) structSchema(t reflect.Type) (*Schema, error) {
+	if t.Name() == "" {
+		return g.objectSchema(t)
+	}
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: componentsPrefix + name}, nil
+	}
+
+	name := fmt.Sprintf("%s.%s", path.Base(t.PkgPath()), t.Name())
+	if _, ok := g.schemas[name]; ok {
+		// Another type has the same package name and type name
+		name = fmt.Sprintf("%s.%s", strings.ReplaceAll(t.PkgPath(), "/", "_"), t.Name())
+	}
+	// The name is reserved before the fields are described so that recursive
+	// types refer to themselves.
+	g.names[t] = name
+	g.schemas[name] = nil
+
+	schema, err := g.objectSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	g.schemas[name] = schema
+	return &Schema{Ref: componentsPrefix + name}, nil
+}
+
+// objectSchema returns the schema of the fields of the struct type [t]
+func (g *generator) objectSchema(t reflect.Type) (*Schema, error) {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	err := g.fields(t, func(name string, fieldSchema *Schema) {
+		schema.Properties[name] = fieldSchema
+	})
+	return schema, err
+}
+
+// fields calls [onField] with the name and schema of each field of the struct
+// type [t] that is encoded to JSON. The fields of embedded structs are
+// included as if they were fields of [t], as encoding/json does.
+func (g *generator) fields(t reflect.Type, onField func(name string, schema *Schema)) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagParts := strings.Split(tag, ",")
+		name := tagParts[0]
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct && !isCustomMarshaler(fieldType) {
+			if err := g.fields(fieldType, onField); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// Unexported fields aren't encoded
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, err := g.schema(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s of %s: %w", field.Name, t, err)
+		}
+		for _, option := range tagParts[1:] {
+			if option == "string" && schema.Type != "" && schema.Type != "object" && schema.Type != "array" {
+				schema = &Schema{Type: "string"}
+			}
+		}
+		onField(name, schema)
+	}
+	return nil
+}
+
+// isCustomMarshaler returns true if [t] defines its own JSON encoding
+func isCustomMarshaler(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return t.Implements(typeOfMarshaler) ||
+		ptr.Implements(typeOfMarshaler) ||
+		t.Implements(typeOfTextMarshaler) ||
+		ptr.Implements(typeOfTextMarshaler)
+}
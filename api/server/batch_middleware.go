@@ -0,0 +1,160 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+var (
+	errEmptyBatch        = errors.New("batch request is empty")
+	errMalformedBatch    = errors.New("batch request couldn't be parsed")
+	errNoResponseWritten = errors.New("handler didn't write a JSON-RPC response")
+)
+
+// batchRequest is the part of a JSON-RPC request of a batch that is needed to
+// report errors about it
+type batchRequest struct {
+	ID *json.RawMessage `json:"id"`
+}
+
+// batchMiddleware wraps a handler. If the body of a request is a JSON-RPC batch
+// request, each of its requests is passed to [handler] as a separate request
+// and their responses are written, in order, as a JSON array. Other requests
+// are passed to [handler] unmodified.
+// A batch request may hold at most [maxBatchSize] requests. At most
+// [maxRequestBodySize] bytes of the body of a request are read.
+func batchMiddleware(handler http.Handler, maxBatchSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Body == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+		if err != nil {
+			writeBatchError(w, json2.E_PARSE, err)
+			return
+		}
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) == 0 || trimmed[0] != '[' {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var requests []json.RawMessage
+		if err := json.Unmarshal(trimmed, &requests); err != nil {
+			writeBatchError(w, json2.E_PARSE, errMalformedBatch)
+			return
+		}
+		switch {
+		case len(requests) == 0:
+			writeBatchError(w, json2.E_INVALID_REQ, errEmptyBatch)
+			return
+		case len(requests) > maxBatchSize:
+			writeBatchError(
+				w,
+				json2.E_INVALID_REQ,
+				fmt.Errorf("batch request has %d requests but at most %d are allowed", len(requests), maxBatchSize),
+			)
+			return
+		}
+
+		responses := make([]json.RawMessage, 0, len(requests))
+		for _, request := range requests {
+			if response := serveBatchRequest(handler, r, request); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		// If every request was a notification, nothing is written back.
+		if len(responses) == 0 {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		// There isn't anything to do with the returned error, so it is dropped.
+		_ = json.NewEncoder(w).Encode(responses)
+	})
+}
+
+// serveBatchRequest passes [request], which is one of the requests of the batch
+// request [r], to [handler] and returns the response that it wrote. Returns nil
+// if [request] is a notification.
+func serveBatchRequest(handler http.Handler, r *http.Request, request json.RawMessage) json.RawMessage {
+	subRequest := r.Clone(r.Context())
+	subRequest.Body = ioutil.NopCloser(bytes.NewReader(request))
+	subRequest.ContentLength = int64(len(request))
+
+	writer := &bufferedResponseWriter{header: make(http.Header)}
+	handler.ServeHTTP(writer, subRequest)
+
+	response := bytes.TrimSpace(writer.body.Bytes())
+	if writer.status == 0 || writer.status == http.StatusOK {
+		if len(response) == 0 || json.Valid(response) {
+			return response
+		}
+	}
+
+	// The handler didn't write a JSON-RPC response, e.g. because the chain
+	// isn't done bootstrapping, so the failure is reported as one.
+	var parsedRequest batchRequest
+	if err := json.Unmarshal(request, &parsedRequest); err != nil || parsedRequest.ID == nil {
+		return nil
+	}
+	err := errNoResponseWritten
+	if len(response) > 0 {
+		err = errors.New(string(response))
+	}
+	response, err = json.Marshal(errorResponse{
+		Version: json2.Version,
+		Err: responseErr{
+			Code:    json2.E_SERVER,
+			Message: err.Error(),
+		},
+		ID: parsedRequest.ID,
+	})
+	if err != nil {
+		return nil
+	}
+	return response
+}
+
+// Write a JSON-RPC formatted response saying that the batch request failed as
+// a whole. Errors while writing are ignored.
+func writeBatchError(w http.ResponseWriter, code json2.ErrorCode, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	// There isn't anything to do with the returned error, so it is dropped.
+	_ = json.NewEncoder(w).Encode(errorResponse{
+		Version: json2.Version,
+		Err: responseErr{
+			Code:    code,
+			Message: err.Error(),
+		},
+	})
+}
+
+// bufferedResponseWriter records the response written to it
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header { return w.header }
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
@@ -0,0 +1,179 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/assert"
+)
+
+type EchoService struct{ calls int }
+
+type EchoArgs struct {
+	Value string `json:"value"`
+}
+
+type EchoReply struct {
+	Value string `json:"value"`
+}
+
+func (s *EchoService) Echo(_ *http.Request, args *EchoArgs, reply *EchoReply) error {
+	s.calls++
+	if args.Value == "" {
+		return errors.New("empty value")
+	}
+	reply.Value = args.Value
+	return nil
+}
+
+type batchResponse struct {
+	Result *EchoReply       `json:"result"`
+	Error  *json2.Error     `json:"error"`
+	ID     *json.RawMessage `json:"id"`
+}
+
+func newBatchTestHandler(t *testing.T, maxBatchSize int) (http.Handler, *EchoService) {
+	service := &EchoService{}
+	rpcServer := rpc.NewServer()
+	rpcServer.RegisterCodec(json2.NewCodec(), "application/json")
+	if err := rpcServer.RegisterService(service, "test"); err != nil {
+		t.Fatal(err)
+	}
+	return batchMiddleware(rpcServer, maxBatchSize), service
+}
+
+func serveBatch(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestBatchMiddlewareOrderedResponses(t *testing.T) {
+	assert := assert.New(t)
+	handler, service := newBatchTestHandler(t, 10)
+
+	rr := serveBatch(handler, `[
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"a"},"id":1},
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":""},"id":2},
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"notification"}},
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"c"},"id":"3"}
+	]`)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(4, service.calls)
+
+	var responses []batchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(responses, 3) {
+		return
+	}
+	assert.Equal("a", responses[0].Result.Value)
+	assert.Equal(`1`, string(*responses[0].ID))
+	assert.Nil(responses[1].Result)
+	assert.Equal("empty value", responses[1].Error.Message)
+	assert.Equal(`2`, string(*responses[1].ID))
+	assert.Equal("c", responses[2].Result.Value)
+	assert.Equal(`"3"`, string(*responses[2].ID))
+}
+
+func TestBatchMiddlewareOnlyNotifications(t *testing.T) {
+	assert := assert.New(t)
+	handler, service := newBatchTestHandler(t, 10)
+
+	rr := serveBatch(handler, `[{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"a"}}]`)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(1, service.calls)
+	assert.Empty(rr.Body.String())
+}
+
+func TestBatchMiddlewareSingleRequest(t *testing.T) {
+	assert := assert.New(t)
+	handler, service := newBatchTestHandler(t, 10)
+
+	rr := serveBatch(handler, `{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"a"},"id":1}`)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(1, service.calls)
+
+	var response batchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal("a", response.Result.Value)
+}
+
+func TestBatchMiddlewareInvalidBatches(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		code json2.ErrorCode
+	}{
+		"empty": {
+			body: `[]`,
+			code: json2.E_INVALID_REQ,
+		},
+		"too large": {
+			body: `[{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"a"},"id":1},{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"b"},"id":2}]`,
+			code: json2.E_INVALID_REQ,
+		},
+		"malformed": {
+			body: `[{"jsonrpc":"2.0",`,
+			code: json2.E_PARSE,
+		},
+		"body too large": {
+			body: "[" + strings.Repeat(" ", maxRequestBodySize) + "]",
+			code: json2.E_PARSE,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			handler, service := newBatchTestHandler(t, 1)
+
+			rr := serveBatch(handler, test.body)
+			assert.Equal(0, service.calls)
+
+			var response batchResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if assert.NotNil(response.Error) {
+				assert.Equal(test.code, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestBatchMiddlewareNonJSONResponse(t *testing.T) {
+	assert := assert.New(t)
+	handler := batchMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("unavailable"))
+	}), 10)
+
+	rr := serveBatch(handler, `[
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"a"},"id":1},
+		{"jsonrpc":"2.0","method":"test.Echo","params":{"value":"b"}}
+	]`)
+
+	var responses []batchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(responses, 1) {
+		return
+	}
+	assert.Equal(json2.E_SERVER, responses[0].Error.Code)
+	assert.Equal("unavailable", responses[0].Error.Message)
+	assert.Equal(`1`, string(*responses[0].ID))
+}
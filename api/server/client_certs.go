@@ -0,0 +1,59 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	errNoClientCAs          = errors.New("client certificates can't be required without client CAs")
+	errClientCAsNotParsable = errors.New("client CA file doesn't contain any PEM encoded certificates")
+)
+
+// ClientCertConfig describes the TLS certificates that API clients may present
+type ClientCertConfig struct {
+	// File holding the PEM encoded certificates of the CAs that client
+	// certificates must be signed by. If empty, client certificates aren't
+	// requested.
+	CAFile string
+	// If true, clients that don't present a verified certificate are rejected.
+	// Otherwise, clients may connect without a certificate and authenticate
+	// with an auth token instead.
+	Required bool
+}
+
+// tlsConfig returns the TLS configuration that requests client certificates as
+// described by [c]
+func (c ClientCertConfig) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CAFile == "" {
+		if c.Required {
+			return nil, errNoClientCAs
+		}
+		return config, nil
+	}
+
+	caBytes, err := ioutil.ReadFile(c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read client CA file %q: %w", c.CAFile, err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBytes) {
+		return nil, errClientCAsNotParsable
+	}
+	config.ClientCAs = clientCAs
+	if c.Required {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
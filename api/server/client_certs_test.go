@@ -0,0 +1,50 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/staking"
+)
+
+func TestClientCertConfig(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	keyFile := filepath.Join(dir, "ca.key")
+	assert.NoError(staking.InitNodeStakingKeyPair(keyFile, caFile))
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	assert.NoError(ioutil.WriteFile(invalidFile, []byte("not a certificate"), 0o600))
+
+	config, err := ClientCertConfig{}.tlsConfig()
+	assert.NoError(err)
+	assert.Equal(tls.NoClientCert, config.ClientAuth)
+	assert.Nil(config.ClientCAs)
+
+	config, err = ClientCertConfig{CAFile: caFile}.tlsConfig()
+	assert.NoError(err)
+	assert.Equal(tls.VerifyClientCertIfGiven, config.ClientAuth)
+	assert.NotNil(config.ClientCAs)
+
+	config, err = ClientCertConfig{CAFile: caFile, Required: true}.tlsConfig()
+	assert.NoError(err)
+	assert.Equal(tls.RequireAndVerifyClientCert, config.ClientAuth)
+
+	_, err = ClientCertConfig{Required: true}.tlsConfig()
+	assert.ErrorIs(err, errNoClientCAs)
+
+	_, err = ClientCertConfig{CAFile: invalidFile}.tlsConfig()
+	assert.ErrorIs(err, errClientCAsNotParsable)
+
+	_, err = ClientCertConfig{CAFile: filepath.Join(dir, "missing.pem")}.tlsConfig()
+	assert.Error(err)
+}
@@ -5,6 +5,7 @@ package server
 
 import (
 	"net/http"
+	"sync"
 )
 
 type middlewareHandler struct {
@@ -21,3 +22,25 @@ func (mh middlewareHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 	}
 	mh.handler.ServeHTTP(writer, request)
 }
+
+// replaceableHandler passes requests to a handler that can be replaced while
+// requests are being served
+type replaceableHandler struct {
+	lock    sync.RWMutex
+	handler http.Handler
+}
+
+func (rh *replaceableHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	rh.lock.RLock()
+	handler := rh.handler
+	rh.lock.RUnlock()
+
+	handler.ServeHTTP(writer, request)
+}
+
+func (rh *replaceableHandler) setHandler(handler http.Handler) {
+	rh.lock.Lock()
+	defer rh.lock.Unlock()
+
+	rh.handler = handler
+}
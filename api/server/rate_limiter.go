@@ -0,0 +1,290 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/timer"
+
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// DefaultMethodCost is the cost of calling a method that isn't given a
+	// cost in RateLimiterConfig.MethodCosts
+	DefaultMethodCost = 1
+
+	// unknownMethod is the method that requests which aren't JSON-RPC requests
+	// are reported as
+	unknownMethod = "unknown"
+)
+
+var (
+	errRateLimited      = errors.New("rate limit exceeded")
+	errRequestTooCostly = errors.New("request costs more than the rate limit allows at once")
+
+	_ Wrapper = &ipRateLimiter{}
+	_ Wrapper = &identityRateLimiter{}
+)
+
+type rateLimiterContextKey int
+
+// ipReservationKey is the context key of the cost that IPWrapper spent from
+// the limit of the remote IP of a request
+const ipReservationKey rateLimiterContextKey = iota
+
+// RateLimiterConfig configures the rate limits of API requests
+type RateLimiterConfig struct {
+	// Cost that each remote IP may spend per second. If 0, requests aren't
+	// limited per remote IP.
+	IPRate float64
+	// Maximum cost that each remote IP may spend at once
+	IPBurst int
+
+	// Cost that each authenticated client, identified by its auth token or
+	// client certificate, may spend per second. If 0, requests aren't limited
+	// per authenticated client.
+	TokenRate float64
+	// Maximum cost that each authenticated client may spend at once
+	TokenBurst int
+
+	// Method --> cost of calling it. Methods that aren't listed cost
+	// [DefaultMethodCost].
+	MethodCosts map[string]int
+
+	// Maximum number of remote IPs and of authenticated clients whose spending is
+	// tracked. If exceeded, the least recently seen client is forgotten.
+	MaxClients int
+}
+
+// RateLimiter rejects API requests from clients that exceed their rate limit
+// with http.StatusTooManyRequests. Each request costs the sum of the costs of
+// the JSON-RPC methods it calls.
+// The limits of remote IPs are applied by IPWrapper, which must wrap the
+// handler that authenticates requests so that requests that don't
+// authenticate are limited too. The limits of authenticated clients are
+// applied by IdentityWrapper, which must be wrapped by that handler.
+type RateLimiter struct {
+	config RateLimiterConfig
+	log    logging.Logger
+	// Records the calls that were rate limited as failed calls
+	metrics metric.APIInterceptor
+
+	// Used to mock time.
+	clock timer.Clock
+
+	lock sync.Mutex
+	// remote IP --> *rate.Limiter
+	ipLimiters linkedhashmap.LinkedHashmap
+	// authenticated identity --> *rate.Limiter
+	tokenLimiters linkedhashmap.LinkedHashmap
+}
+
+// NewRateLimiter returns a new RateLimiter that registers its metrics with
+// [registerer]
+func NewRateLimiter(
+	config RateLimiterConfig,
+	log logging.Logger,
+	namespace string,
+	registerer prometheus.Registerer,
+) (*RateLimiter, error) {
+	metrics, err := metric.NewAPIInterceptor(namespace, registerer)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't register rate limiter metrics: %w", err)
+	}
+	return &RateLimiter{
+		config:        config,
+		log:           log,
+		metrics:       metrics,
+		ipLimiters:    linkedhashmap.New(),
+		tokenLimiters: linkedhashmap.New(),
+	}, nil
+}
+
+// IPWrapper returns the Wrapper that applies the limits of remote IPs
+func (l *RateLimiter) IPWrapper() Wrapper { return &ipRateLimiter{l: l} }
+
+// IdentityWrapper returns the Wrapper that applies the limits of
+// authenticated clients
+func (l *RateLimiter) IdentityWrapper() Wrapper { return &identityRateLimiter{l: l} }
+
+type ipRateLimiter struct{ l *RateLimiter }
+
+func (w *ipRateLimiter) WrapHandler(h http.Handler) http.Handler {
+	l := w.l
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.config.IPRate <= 0 {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		methods, cost := l.cost(w, r)
+		ipKey, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ipKey = r.RemoteAddr
+		}
+
+		reservation, retryAfter, err := l.reserve(l.ipLimiters, ipKey, l.config.IPRate, l.config.IPBurst, cost)
+		if err != nil {
+			l.reject(w, r, methods, retryAfter, err)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ipReservationKey, reservation)))
+	})
+}
+
+type identityRateLimiter struct{ l *RateLimiter }
+
+func (w *identityRateLimiter) WrapHandler(h http.Handler) http.Handler {
+	l := w.l
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenKey := auth.Identity(r)
+		if l.config.TokenRate <= 0 || tokenKey == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		methods, cost := l.cost(w, r)
+		_, retryAfter, err := l.reserve(l.tokenLimiters, tokenKey, l.config.TokenRate, l.config.TokenBurst, cost)
+		if err != nil {
+			// The request isn't served, so the cost spent from the limit of
+			// its remote IP is given back
+			if reservation, ok := r.Context().Value(ipReservationKey).(*rate.Reservation); ok {
+				reservation.CancelAt(l.clock.Time())
+			}
+			l.reject(w, r, methods, retryAfter, err)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// cost returns the JSON-RPC methods called by [r] and the cost of calling
+// them. At most [maxRequestBodySize] bytes of the body of [r] are read.
+func (l *RateLimiter) cost(w http.ResponseWriter, r *http.Request) ([]string, int) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	methods, err := cjson.RequestMethods(r)
+	if err != nil {
+		methods = []string{unknownMethod}
+	}
+
+	cost := 0
+	for _, method := range methods {
+		methodCost, ok := l.config.MethodCosts[method]
+		if !ok {
+			methodCost = DefaultMethodCost
+		}
+		cost += methodCost
+	}
+	return methods, cost
+}
+
+// reject writes the response to [r], which wasn't served because calling
+// [methods] exceeded a rate limit
+func (l *RateLimiter) reject(w http.ResponseWriter, r *http.Request, methods []string, retryAfter time.Duration, err error) {
+	l.log.Verbo("rate limited API request from %s calling %v: %s", r.RemoteAddr, methods, err)
+	for _, method := range methods {
+		info := &rpc.RequestInfo{
+			Method:  method,
+			Request: r,
+		}
+		info.Request = l.metrics.InterceptRequest(info)
+		info.Error = err
+		info.StatusCode = http.StatusTooManyRequests
+		l.metrics.AfterRequest(info)
+	}
+	writeTooManyRequestsResponse(w, retryAfter, err)
+}
+
+// reserve spends [cost] from the limit of [key] in [limiters], which allows
+// [r] per second with bursts of [burst]. If the limit can't afford [cost] now,
+// nothing is spent and the time after which the request may be retried is
+// returned with an error.
+func (l *RateLimiter) reserve(
+	limiters linkedhashmap.LinkedHashmap,
+	key string,
+	r float64,
+	burst int,
+	cost int,
+) (*rate.Reservation, time.Duration, error) {
+	now := l.clock.Time()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	limiter := l.getLimiter(limiters, key, r, burst)
+	reservation := limiter.ReserveN(now, cost)
+	if !reservation.OK() {
+		return nil, 0, errRequestTooCostly
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return nil, delay, errRateLimited
+	}
+	return reservation, 0, nil
+}
+
+// getLimiter returns the limiter of [key] in [limiters], creating it if it
+// doesn't exist.
+// Assumes [l.lock] is held.
+func (l *RateLimiter) getLimiter(limiters linkedhashmap.LinkedHashmap, key string, r float64, burst int) *rate.Limiter {
+	limiterIntf, ok := limiters.Get(key)
+	if ok {
+		// Mark [key] as the most recently seen client
+		limiters.Put(key, limiterIntf)
+		return limiterIntf.(*rate.Limiter)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(r), burst)
+	limiters.Put(key, limiter)
+	for l.config.MaxClients > 0 && limiters.Len() > l.config.MaxClients {
+		oldestKey, _, _ := limiters.Oldest()
+		limiters.Delete(oldestKey)
+	}
+	return limiter
+}
+
+// Write a JSON-RPC formatted response saying that the API call was rate
+// limited. The response has header http.StatusTooManyRequests. If
+// [retryAfter] is positive, the Retry-After header is set to it, rounded up to
+// the second. Errors while writing are ignored.
+func writeTooManyRequestsResponse(w http.ResponseWriter, retryAfter time.Duration, err error) {
+	w.Header().Add("Content-Type", "application/json")
+	if retryAfter > 0 {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	w.WriteHeader(http.StatusTooManyRequests)
+
+	// There isn't anything to do with the returned error, so it is dropped.
+	_ = json.NewEncoder(w).Encode(errorResponse{
+		Version: json2.Version,
+		Err: responseErr{
+			Code:    json2.E_SERVER,
+			Message: err.Error(),
+		},
+		ID: 1,
+	})
+}
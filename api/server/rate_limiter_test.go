@@ -0,0 +1,222 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const testAuthPassword = "password!@#$%$#@!"
+
+var dummyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+func newTestAuth(t *testing.T) auth.Auth {
+	a, err := auth.New(logging.NoLog{}, "auth", testAuthPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func newTestToken(t *testing.T, a auth.Auth) string {
+	token, err := a.NewToken(testAuthPassword, time.Hour, []string{"*"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newTestRateLimiter(t *testing.T, config RateLimiterConfig) *RateLimiter {
+	l, err := NewRateLimiter(config, logging.NoLog{}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.clock.Set(time.Unix(1000000, 0))
+	return l
+}
+
+func newRateLimitedRequest(remoteAddr, token, method string) *http.Request {
+	body := `{"jsonrpc":"2.0","method":"` + method + `","params":{},"id":1}`
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/X", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func serveRateLimited(l *RateLimiter, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	l.IPWrapper().WrapHandler(l.IdentityWrapper().WrapHandler(dummyHandler)).ServeHTTP(rr, req)
+	return rr
+}
+
+// serveAuthenticated serves [req] with a handler that limits its remote IP,
+// authenticates it with [a] and then limits its identity, as the node does
+func serveAuthenticated(a auth.Auth, l *RateLimiter, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler := l.IdentityWrapper().WrapHandler(dummyHandler)
+	handler = a.WrapHandler(handler)
+	handler = l.IPWrapper().WrapHandler(handler)
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimiterIP(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		IPRate:  1,
+		IPBurst: 2,
+	})
+
+	for i := 0; i < 2; i++ {
+		rr := serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getBalance"))
+		assert.Equal(http.StatusOK, rr.Code)
+	}
+
+	rr := serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5001", "", "avm.getBalance"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+	assert.Equal("1", rr.Header().Get("Retry-After"))
+	assert.Contains(rr.Body.String(), errRateLimited.Error())
+
+	// A different IP has its own limit
+	rr = serveRateLimited(l, newRateLimitedRequest("5.6.7.8:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// After a second, the IP can afford another request
+	l.clock.Set(l.clock.Time().Add(time.Second))
+	rr = serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+}
+
+func TestRateLimiterMethodCosts(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		IPRate:  1,
+		IPBurst: 10,
+		MethodCosts: map[string]int{
+			"avm.getUTXOs":       5,
+			"platform.getUTXOs":  20,
+			"platform.getHeight": 0,
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		rr := serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getUTXOs"))
+		assert.Equal(http.StatusOK, rr.Code)
+	}
+	rr := serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getUTXOs"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+	assert.Equal("5", rr.Header().Get("Retry-After"))
+
+	// Methods that cost nothing are never limited
+	rr = serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "platform.getHeight"))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// Methods that cost more than the burst can never be called
+	rr = serveRateLimited(l, newRateLimitedRequest("5.6.7.8:5000", "", "platform.getUTXOs"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+	assert.Empty(rr.Header().Get("Retry-After"))
+	assert.Contains(rr.Body.String(), errRequestTooCostly.Error())
+}
+
+func TestRateLimiterToken(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		TokenRate:  1,
+		TokenBurst: 1,
+	})
+	a := newTestAuth(t)
+	token1 := newTestToken(t, a)
+	token2 := newTestToken(t, a)
+
+	rr := serveAuthenticated(a, l, newRateLimitedRequest("1.2.3.4:5000", token1, "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// The token is limited regardless of the IP it is used from
+	rr = serveAuthenticated(a, l, newRateLimitedRequest("5.6.7.8:5000", token1, "avm.getBalance"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+
+	rr = serveAuthenticated(a, l, newRateLimitedRequest("5.6.7.8:5000", token2, "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// Requests with a token that doesn't authenticate are rejected before
+	// they are rate limited, so they don't get a limit of their own
+	rr = serveAuthenticated(a, l, newRateLimitedRequest("5.6.7.8:5000", "not.a.token", "avm.getBalance"))
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+	assert.Equal(2, l.tokenLimiters.Len())
+
+	// Requests that weren't authenticated aren't limited per token, even if
+	// they carry an Authorization header
+	for i := 0; i < 3; i++ {
+		rr = serveRateLimited(l, newRateLimitedRequest("5.6.7.8:5000", token1, "avm.getBalance"))
+		assert.Equal(http.StatusOK, rr.Code)
+	}
+}
+
+func TestRateLimiterIPBeforeAuth(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		IPRate:  1,
+		IPBurst: 1,
+	})
+	a := newTestAuth(t)
+
+	// Requests that don't authenticate are limited by their remote IP too
+	rr := serveAuthenticated(a, l, newRateLimitedRequest("1.2.3.4:5000", "not.a.token", "avm.getBalance"))
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+	rr = serveAuthenticated(a, l, newRateLimitedRequest("1.2.3.4:5000", "not.a.token", "avm.getBalance"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+}
+
+func TestRateLimiterRejectedRequestSpendsNothing(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		IPRate:     1,
+		IPBurst:    2,
+		TokenRate:  1,
+		TokenBurst: 1,
+	})
+	a := newTestAuth(t)
+	token := newTestToken(t, a)
+
+	rr := serveAuthenticated(a, l, newRateLimitedRequest("1.2.3.4:5000", token, "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// Rejected by the token's limit, so the IP's limit shouldn't be spent
+	rr = serveAuthenticated(a, l, newRateLimitedRequest("1.2.3.4:5000", token, "avm.getBalance"))
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+
+	rr = serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+}
+
+func TestRateLimiterMaxClients(t *testing.T) {
+	assert := assert.New(t)
+	l := newTestRateLimiter(t, RateLimiterConfig{
+		IPRate:     1,
+		IPBurst:    1,
+		MaxClients: 1,
+	})
+
+	rr := serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+	rr = serveRateLimited(l, newRateLimitedRequest("5.6.7.8:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(1, l.ipLimiters.Len())
+
+	// The first IP was forgotten, so it has a full limit again
+	rr = serveRateLimited(l, newRateLimitedRequest("1.2.3.4:5000", "", "avm.getBalance"))
+	assert.Equal(http.StatusOK, rr.Code)
+}
@@ -0,0 +1,19 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+type responseErr struct {
+	Code    json2.ErrorCode `json:"code"`
+	Message string          `json:"message"`
+}
+
+type errorResponse struct {
+	Version string      `json:"jsonrpc"`
+	Err     responseErr `json:"error"`
+	ID      interface{} `json:"id"`
+}
@@ -26,11 +26,16 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 const (
 	baseURL               = "/ext"
 	serverShutdownTimeout = 10 * time.Second
+
+	// maxRequestBodySize is the largest request body that is read to inspect
+	// the JSON-RPC calls of a request
+	maxRequestBodySize = 4 * units.MiB
 )
 
 var (
@@ -57,6 +62,15 @@ type Server struct {
 	// Listens for HTTP traffic on this address
 	listenHost string
 	listenPort uint16
+	// Maximum number of requests in a JSON-RPC batch request. If 0, batch
+	// requests aren't supported.
+	maxBatchSize int
+
+	// chainRoutesLock protects chainRoutes
+	chainRoutesLock sync.Mutex
+	// The handlers of the chains' endpoints, by URL. A chain's handlers are
+	// replaced when the chain is registered again after its VM restarted.
+	chainRoutes map[string]*replaceableHandler
 
 	// http server
 	srv *http.Server
@@ -69,6 +83,7 @@ func (s *Server) Initialize(
 	host string,
 	port uint16,
 	allowedOrigins []string,
+	maxBatchSize int,
 	nodeID ids.ShortID,
 	wrappers ...Wrapper,
 ) {
@@ -76,8 +91,10 @@ func (s *Server) Initialize(
 	s.factory = factory
 	s.listenHost = host
 	s.listenPort = port
+	s.maxBatchSize = maxBatchSize
 	s.router = newRouter()
 	s.nodeID = nodeID
+	s.chainRoutes = make(map[string]*replaceableHandler)
 
 	s.log.Info("API created with allowed origins: %v", allowedOrigins)
 
@@ -118,8 +135,14 @@ func (s *Server) Dispatch() error {
 	return s.srv.Serve(listener)
 }
 
-// DispatchTLS starts the API server with the provided TLS certificate
-func (s *Server) DispatchTLS(certFile, keyFile string) error {
+// DispatchTLS starts the API server with the provided TLS certificate. Client
+// certificates are requested as described by [clientCerts].
+func (s *Server) DispatchTLS(certFile, keyFile string, clientCerts ClientCertConfig) error {
+	tlsConfig, err := clientCerts.tlsConfig()
+	if err != nil {
+		return err
+	}
+
 	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
 	listener, err := net.Listen("tcp", listenAddress)
 	if err != nil {
@@ -133,7 +156,11 @@ func (s *Server) DispatchTLS(certFile, keyFile string) error {
 		s.log.Info("HTTPS API server listening on \"%s:%d\"", s.listenHost, ipDesc.Port)
 	}
 
-	return http.ServeTLS(listener, s.handler, certFile, keyFile)
+	s.srv = &http.Server{
+		Handler:   s.handler,
+		TLSConfig: tlsConfig,
+	}
+	return s.srv.ServeTLS(listener, certFile, keyFile)
 }
 
 // RegisterChain registers the API endpoints associated with this chain. That is,
@@ -186,7 +213,8 @@ func (s *Server) registerChain(chainName string, ctx *snow.Context, engine commo
 	}
 }
 
-// AddChainRoute registers a route to a chain's handler
+// AddChainRoute registers a route to a chain's handler. If the route was
+// already registered, its handler is replaced.
 func (s *Server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.Context, base, endpoint string, loggingWriter io.Writer) error {
 	url := fmt.Sprintf("%s/%s", baseURL, base)
 	s.log.Info("adding route %s%s", url, endpoint)
@@ -199,7 +227,21 @@ func (s *Server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.Context, b
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
-	return s.router.AddRouter(url, endpoint, h)
+	h = s.wrapBatch(h)
+
+	s.chainRoutesLock.Lock()
+	defer s.chainRoutesLock.Unlock()
+
+	if route, exists := s.chainRoutes[url+endpoint]; exists {
+		route.setHandler(h)
+		return nil
+	}
+	route := &replaceableHandler{handler: h}
+	if err := s.router.AddRouter(url, endpoint, route); err != nil {
+		return err
+	}
+	s.chainRoutes[url+endpoint] = route
+	return nil
 }
 
 // AddRoute registers a route to a handler.
@@ -213,7 +255,16 @@ func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
-	return s.router.AddRouter(url, endpoint, h)
+	return s.router.AddRouter(url, endpoint, s.wrapBatch(h))
+}
+
+// wrapBatch applies middleware to split JSON-RPC batch requests into requests
+// that are each passed to [handler], if batch requests are supported.
+func (s *Server) wrapBatch(handler http.Handler) http.Handler {
+	if s.maxBatchSize <= 0 {
+		return handler
+	}
+	return batchMiddleware(handler, s.maxBatchSize)
 }
 
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
@@ -14,6 +14,7 @@ import (
 	"github.com/gorilla/rpc/v2/json2"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -37,6 +38,7 @@ func TestCall(t *testing.T) {
 		"localhost",
 		8080,
 		[]string{"*"},
+		0,
 		ids.GenerateTestShortID(),
 	)
 
@@ -78,3 +80,60 @@ func TestCall(t *testing.T) {
 		t.Fatalf("Should have been called")
 	}
 }
+
+func TestAddChainRouteReplacesHandler(t *testing.T) {
+	s := Server{}
+	s.Initialize(
+		logging.NoLog{},
+		logging.NoFactory{},
+		"localhost",
+		8080,
+		[]string{"*"},
+		0,
+		ids.GenerateTestShortID(),
+	)
+
+	ctx := snow.DefaultContextTest()
+	ctx.Bootstrapped()
+
+	services := []*Service{{}, {}}
+	for _, serv := range services {
+		newServer := rpc.NewServer()
+		newServer.RegisterCodec(json2.NewCodec(), "application/json")
+		if err := newServer.RegisterService(serv, "test"); err != nil {
+			t.Fatal(err)
+		}
+
+		err := s.AddChainRoute(
+			&common.HTTPHandler{Handler: newServer},
+			ctx,
+			"vm/chain",
+			"",
+			logging.NoLog{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf, err := json2.EncodeClientRequest("test.Call", &Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := httptest.NewRecorder()
+	body := bytes.NewBuffer(buf)
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if err := s.Call(writer, "POST", "chain", "", body, headers); err != nil {
+		t.Fatal(err)
+	}
+
+	if services[0].called {
+		t.Fatalf("Replaced handler shouldn't have been called")
+	}
+	if !services[1].called {
+		t.Fatalf("Should have been called")
+	}
+}
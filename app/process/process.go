@@ -11,7 +11,9 @@ import (
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/rocksdb"
+	"github.com/ava-labs/avalanchego/database/snapshot"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -102,14 +104,24 @@ func (a *App) Start() int {
 		dbManager, err = manager.NewRocksDB(path, a.log, version.CurrentDatabase, !a.config.FetchOnly)
 	case leveldb.Name:
 		dbManager, err = manager.NewLevelDB(a.config.DBPath, a.log, version.CurrentDatabase, !a.config.FetchOnly)
+	case pebble.Name:
+		path := filepath.Join(a.config.DBPath, pebble.Name)
+		if a.config.DBCopyLevelDB {
+			err = manager.CopyLevelDBToPebble(a.config.DBPath, path, a.log, version.CurrentDatabase)
+			if err != nil {
+				break
+			}
+		}
+		dbManager, err = manager.NewPebble(path, a.log, version.CurrentDatabase, !a.config.FetchOnly)
 	case memdb.Name:
 		dbManager = manager.NewMemDB(version.CurrentDatabase)
 	default:
 		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s, %s}",
+			"db-type was %q but should have been one of {%s, %s, %s, %s}",
 			a.config.DBName,
 			leveldb.Name,
 			rocksdb.Name,
+			pebble.Name,
 			memdb.Name,
 		)
 	}
@@ -118,6 +130,50 @@ func (a *App) Start() int {
 		return 1
 	}
 
+	// A database that only holds part of a snapshot is only used to retry
+	// the import
+	interrupted, err := snapshot.Interrupted(dbManager.Current().Database)
+	if err != nil {
+		a.log.Fatal("couldn't get whether a snapshot import was interrupted: %s", err)
+		return 1
+	}
+	if interrupted && a.config.SnapshotImportDir == "" {
+		a.log.Fatal("the import of a snapshot into database version %s was interrupted. Set the snapshot import directory to retry it", version.CurrentDatabase)
+		return 1
+	}
+
+	switch {
+	case a.config.VerifyDatabase:
+		numFindings, err := a.verifyDatabase(dbManager)
+		if closeErr := dbManager.Close(); closeErr != nil {
+			a.log.Warn("failed to close the node's DB: %s", closeErr)
+		}
+		if err != nil {
+			a.log.Fatal("couldn't verify the database: %s", err)
+			return 1
+		}
+		if numFindings > 0 {
+			a.log.Error("found %d inconsistencies in the database", numFindings)
+			return 1
+		}
+		return 0
+	case a.config.SnapshotExportDir != "":
+		err := a.exportSnapshot(dbManager)
+		if closeErr := dbManager.Close(); closeErr != nil {
+			a.log.Warn("failed to close the node's DB: %s", closeErr)
+		}
+		if err != nil {
+			a.log.Fatal("couldn't export snapshot to %s: %s", a.config.SnapshotExportDir, err)
+			return 1
+		}
+		return 0
+	case a.config.SnapshotImportDir != "":
+		if err := a.importSnapshot(dbManager); err != nil {
+			a.log.Fatal("couldn't import snapshot from %s: %s", a.config.SnapshotImportDir, err)
+			return 1
+		}
+	}
+
 	// ensure migrations are done
 	currentDBBootstrapped, err := dbManager.Current().Database.Has(chains.BootstrappedKey)
 	if err != nil {
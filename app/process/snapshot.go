@@ -0,0 +1,92 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package process
+
+import (
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/snapshot"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// snapshotHeader returns the header that snapshots of this node's database
+// are identified by
+func (a *App) snapshotHeader() snapshot.Header {
+	return snapshot.Header{
+		DatabaseVersion: version.CurrentDatabase,
+		GenesisID:       ids.ID(hashing.ComputeHash256Array(a.config.GenesisBytes)),
+	}
+}
+
+// exportSnapshot exports the current database of [dbManager] with a partition
+// for each of the chains created in genesis. The keystore and the API
+// auditor's state aren't exported.
+func (a *App) exportSnapshot(dbManager manager.Manager) error {
+	_, chainAliases, err := genesis.Aliases(a.config.GenesisBytes)
+	if err != nil {
+		return err
+	}
+	prefixes := make(map[string][]byte, len(chainAliases))
+	for chainID, aliases := range chainAliases {
+		chainID := chainID
+		name := chainID.String()
+		if len(aliases) > 0 {
+			name = aliases[0]
+		}
+		prefixes[name] = chainID[:]
+	}
+
+	a.log.Info("exporting database version %s to %s", version.CurrentDatabase, a.config.SnapshotExportDir)
+	manifest, err := snapshot.Export(
+		dbManager.Current().Database,
+		a.config.SnapshotExportDir,
+		a.snapshotHeader(),
+		prefixes,
+		[][]byte{node.KeystoreDBPrefix, node.AuditDBPrefix},
+	)
+	if err != nil {
+		return err
+	}
+	for _, partition := range manifest.Partitions {
+		a.log.Info("exported %d entries of partition %s", partition.Entries, partition.Name)
+	}
+	return nil
+}
+
+// importSnapshot imports the snapshot into the current database of
+// [dbManager]. If a snapshot was already imported, nothing is done so that the
+// node can be restarted without removing the import config. If an import was
+// interrupted, it's started over.
+func (a *App) importSnapshot(dbManager manager.Manager) error {
+	db := dbManager.Current().Database
+	imported, err := snapshot.Imported(db)
+	if err != nil {
+		return err
+	}
+	if imported {
+		a.log.Info("snapshot was already imported. Ignoring %s", a.config.SnapshotImportDir)
+		return nil
+	}
+
+	interrupted, err := snapshot.Interrupted(db)
+	if err != nil {
+		return err
+	}
+	if interrupted {
+		a.log.Warn("an import of a snapshot into database version %s was interrupted. Deleting the imported part and starting over", version.CurrentDatabase)
+	}
+
+	a.log.Info("importing snapshot from %s into database version %s", a.config.SnapshotImportDir, version.CurrentDatabase)
+	manifest, err := snapshot.Import(db, a.config.SnapshotImportDir, a.snapshotHeader())
+	if err != nil {
+		return err
+	}
+	for _, partition := range manifest.Partitions {
+		a.log.Info("imported %d entries of partition %s", partition.Entries, partition.Name)
+	}
+	return nil
+}
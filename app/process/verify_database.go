@@ -0,0 +1,112 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package process
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// verifyDatabase checks the current database of [dbManager] for
+// inconsistencies in the P-chain and X-chain state and in the indices of the
+// chains created in genesis. Returns the number of inconsistencies found.
+// The database isn't modified.
+func (a *App) verifyDatabase(dbManager manager.Manager) (int, error) {
+	db := dbManager.Current().Database
+
+	createXChainTx, err := genesis.VMGenesis(a.config.GenesisBytes, avm.ID)
+	if err != nil {
+		return 0, err
+	}
+	xChainID := createXChainTx.ID()
+	xChainFxs, err := newXChainFxs(createXChainTx.UnsignedTx.(*platformvm.UnsignedCreateChainTx).FxIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	_, chainAliases, err := genesis.Aliases(a.config.GenesisBytes)
+	if err != nil {
+		return 0, err
+	}
+	chainIDs := []ids.ID{constants.PlatformChainID}
+	for chainID := range chainAliases {
+		chainIDs = append(chainIDs, chainID)
+	}
+
+	numFindings := 0
+	report := func(name string, findings []string) {
+		for _, finding := range findings {
+			a.log.Warn("%s: %s", name, finding)
+		}
+		a.log.Info("found %d inconsistencies in the %s", len(findings), name)
+		numFindings += len(findings)
+	}
+
+	a.log.Info("verifying the P-chain state")
+	findings, err := platformvm.VerifyDatabase(chainVMDB(db, constants.PlatformChainID))
+	if err != nil {
+		return 0, fmt.Errorf("couldn't verify the P-chain state: %w", err)
+	}
+	report("P-chain state", findings)
+
+	a.log.Info("verifying the X-chain state")
+	findings, err = avm.VerifyDatabase(chainVMDB(db, xChainID), xChainFxs)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't verify the X-chain state: %w", err)
+	}
+	report("X-chain state", findings)
+
+	a.log.Info("verifying the indices")
+	findings, err = indexer.VerifyDatabase(prefixdb.New(node.IndexerDBPrefix, db), chainIDs)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't verify the indices: %w", err)
+	}
+	report("indices", findings)
+
+	return numFindings, nil
+}
+
+// chainVMDB returns the database that the chain manager gives to the VM of
+// the chain [chainID]
+func chainVMDB(db database.Database, chainID ids.ID) database.Database {
+	return prefixdb.New(chains.VMDBPrefix, prefixdb.New(chainID[:], db))
+}
+
+// newXChainFxs returns the fxs the X-chain is created with
+func newXChainFxs(fxIDs []ids.ID) ([]*common.Fx, error) {
+	fxs := make([]*common.Fx, len(fxIDs))
+	for i, fxID := range fxIDs {
+		var fx interface{}
+		switch fxID {
+		case secp256k1fx.ID:
+			fx = &secp256k1fx.Fx{}
+		case nftfx.ID:
+			fx = &nftfx.Fx{}
+		case propertyfx.ID:
+			fx = &propertyfx.Fx{}
+		default:
+			return nil, fmt.Errorf("unknown fx %s", fxID)
+		}
+		fxs[i] = &common.Fx{
+			ID: fxID,
+			Fx: fx,
+		}
+	}
+	return fxs, nil
+}
@@ -4,6 +4,8 @@
 package chains
 
 import (
+	"crypto"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"sync"
@@ -24,14 +26,18 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/subprocess"
+	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/metervm"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
 
 	dbManager "github.com/ava-labs/avalanchego/database/manager"
 
@@ -49,8 +55,19 @@ const (
 )
 
 var (
-	BootstrappedKey         = []byte{0x00}
-	_               Manager = &manager{}
+	BootstrappedKey = []byte{0x00}
+
+	// VMDBPrefix is the prefix, under a chain's prefix, of the database that
+	// is given to the chain's VM
+	VMDBPrefix = []byte("vm")
+
+	errPlatformChainNotValidatorState = errors.New("platform chain's VM doesn't implement validators.State")
+	errUnknownChain                   = errors.New("unknown chain ID")
+	errNoDatabaseVerifier             = errors.New("chain's VM doesn't support database verification")
+	errNoVMProcess                    = errors.New("chain's VM doesn't run in a separate process")
+	errVMNotLimited                   = errors.New("chain's VM doesn't support resource limits")
+
+	_ Manager = &manager{}
 )
 
 // Manager manages the chains running on this node.
@@ -92,6 +109,16 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns a description of each inconsistency found in the database of the
+	// chain with the given ID. Returns an error if the chain's VM doesn't
+	// implement common.DatabaseVerifier.
+	VerifyDatabase(chainID ids.ID) ([]string, error)
+
+	// Returns the status of the process the VM of the chain with the given ID
+	// runs in. Returns an error if the chain's VM doesn't run in a separate
+	// process.
+	VMProcessStatus(chainID ids.ID) (VMProcessStatus, error)
+
 	Shutdown()
 }
 
@@ -113,6 +140,21 @@ type chain struct {
 	Ctx     *snow.Context
 	VM      interface{}
 	Beacons validators.Set
+
+	// DatabaseVerifier is the chain's VM if it implements
+	// common.DatabaseVerifier, and nil otherwise. Unlike [VM], it is never
+	// wrapped.
+	DatabaseVerifier common.DatabaseVerifier
+
+	// MsgChan is the channel through which the VM sends messages to the
+	// engine
+	MsgChan chan common.Message
+	// VMExited is closed when the process the VM runs in exits unexpectedly.
+	// It's nil if the VM doesn't run in a separate process.
+	VMExited <-chan struct{}
+	// VMProcess tracks the exits and restarts of the VM's process. It's nil
+	// if the VM doesn't run in a separate process.
+	VMProcess *vmProcess
 }
 
 // ChainConfig is configuration settings for the current execution.
@@ -121,6 +163,9 @@ type chain struct {
 type ChainConfig struct {
 	Config  []byte
 	Upgrade []byte
+	// Limits on the resources the chain's VM may use if it runs in a
+	// separate process
+	Limits subprocess.Limits
 }
 
 type ManagerConfig struct {
@@ -138,6 +183,7 @@ type ManagerConfig struct {
 	EpochDuration             time.Duration
 	Validators                validators.Manager // Validators validating on this chain
 	NodeID                    ids.ShortID        // The ID of this node
+	StakingCert               tls.Certificate    // Used to sign the blocks this node proposes
 	NetworkID                 uint32             // ID of the network this node is connected to
 	Server                    *server.Server     // Handles HTTP API calls
 	Keystore                  keystore.Keystore
@@ -160,6 +206,12 @@ type ManagerConfig struct {
 	ShutdownNodeFunc func(exitCode int)
 	MeterVMEnabled   bool // Should each VM be wrapped with a MeterVM
 
+	// PeerScores biases the validators this node queries towards validators
+	// with higher scores. A validator's weight is scaled by at least
+	// [PeerScoreMinSamplingFactor] when sampling.
+	PeerScores                 score.Tracker
+	PeerScoreMinSamplingFactor float64
+
 	// Max Time to spend fetching a container and its
 	// ancestors when responding to a GetAncestors
 	BootstrapMaxTimeGetAncestors time.Duration
@@ -168,6 +220,16 @@ type ManagerConfig struct {
 	// This node will only consider the first [MultiputMaxContainersReceived]
 	// containers in a multiput it receives.
 	BootstrapMultiputMaxContainersReceived int
+
+	// If true, a chain whose VM runs in a separate process is restarted when
+	// that process exits, at most [VMRestartMaxAttempts] times in a row without
+	// a stable run in between. Otherwise, the chain is shut down.
+	VMRestartEnabled     bool
+	VMRestartMaxAttempts int
+	// Time to wait before the first restart of a chain's VM. The wait doubles
+	// after each restart, up to [VMRestartMaxBackoff].
+	VMRestartBackoff    time.Duration
+	VMRestartMaxBackoff time.Duration
 }
 
 type manager struct {
@@ -186,18 +248,42 @@ type manager struct {
 	// Value: Subnet description
 	subnets map[ids.ID]Subnet
 
+	// validatorState is the validator state of the P-chain. It is set when
+	// the P-chain is created and must only be used while holding the
+	// P-chain's lock. lockedValidatorState grabs the P-chain's lock and is
+	// provided to all other chains.
+	validatorState       validators.State
+	lockedValidatorState validators.State
+
 	chainsLock sync.Mutex
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]*router.Handler
+	// Key: Chain's ID
+	// Value: The chain's VM, if it supports database verification
+	databaseVerifiers map[ids.ID]common.DatabaseVerifier
+	// Key: Chain's ID
+	// Value: The process the chain's VM runs in, if it runs in a separate
+	// process
+	vmProcesses map[ids.ID]*vmProcess
+
+	// closing is closed when the manager starts shutting down
+	closing chan struct{}
 }
 
 // New returns a new Manager
 func New(config *ManagerConfig) Manager {
+	if config.PeerScores == nil {
+		config.PeerScores = score.NewNoTracker()
+	}
 	m := &manager{
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]*router.Handler),
+
+		databaseVerifiers: make(map[ids.ID]common.DatabaseVerifier),
+		vmProcesses:       make(map[ids.ID]*vmProcess),
+		closing:           make(chan struct{}),
 	}
 	m.Initialize()
 	return m
@@ -286,6 +372,12 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	if chain.DatabaseVerifier != nil {
+		m.databaseVerifiers[chainParams.ID] = chain.DatabaseVerifier
+	}
+	if chain.VMProcess != nil {
+		m.vmProcesses[chainParams.ID] = chain.VMProcess
+	}
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
@@ -306,15 +398,14 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	// Allows messages to be routed to the new chain
 	m.ManagerConfig.Router.AddChain(chain.Handler)
+
+	if chain.VMProcess != nil {
+		go m.superviseVM(chainParams, sb, chain)
+	}
 }
 
 // Create a chain
 func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, error) {
-	vmID, err := m.VMManager.Lookup(chainParams.VMAlias)
-	if err != nil {
-		return nil, fmt.Errorf("error while looking up VM: %w", err)
-	}
-
 	primaryAlias, err := m.PrimaryAlias(chainParams.ID)
 	if err != nil {
 		primaryAlias = chainParams.ID.String()
@@ -341,24 +432,93 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		BCLookup:             m,
 		SNLookup:             m,
 		Namespace:            fmt.Sprintf("%s_%s_vm", constants.PlatformName, primaryAlias),
+		ValidatorState:       m.lockedValidatorState,
 		Metrics:              m.ConsensusParams.Metrics,
 		EpochFirstTransition: m.EpochFirstTransition,
 		EpochDuration:        m.EpochDuration,
 	}
 
+	ctx.Lock.Lock()
+	chain, err := m.initChain(ctx, chainParams, sb, nil)
+	ctx.Lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Register the chain with the timeout manager
+	consensusNamespace := fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
+	if err := m.TimeoutManager.RegisterChain(ctx, consensusNamespace); err != nil {
+		return nil, err
+	}
+
+	// Register health check for this chain. The engine is looked up on each
+	// call as it's replaced when the chain's VM is restarted.
+	checkFn := func() (interface{}, error) {
+		// Grab the context lock before calling the chain's health check
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+		return chain.Handler.Engine().HealthCheck()
+	}
+	if chain.VMExited != nil {
+		chain.VMProcess = newVMProcess()
+		checkFn = chain.VMProcess.healthCheck(checkFn)
+	}
+	if err := m.HealthService.RegisterCheck(chain.Name, checkFn); err != nil {
+		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chain.Name, err)
+	}
+	return chain, nil
+}
+
+// initChain creates the VM and the engine of the chain described by
+// [chainParams]. If [prev] is non-nil, the chain is being restarted and the
+// handler of [prev] is reused.
+//
+// Assumes [ctx.Lock] is held.
+func (m *manager) initChain(
+	ctx *snow.Context,
+	chainParams ChainParameters,
+	sb Subnet,
+	prev *chain,
+) (*chain, error) {
+	vmID, err := m.VMManager.Lookup(chainParams.VMAlias)
+	if err != nil {
+		return nil, fmt.Errorf("error while looking up VM: %w", err)
+	}
+
 	// Get a factory for the vm we want to use on our chain
 	vmFactory, err := m.VMManager.GetFactory(vmID)
 	if err != nil {
 		return nil, fmt.Errorf("error while getting vmFactory: %w", err)
 	}
 
-	// Create the chain
-	vm, err := vmFactory.New(ctx)
+	// Create the chain. The resources used by VMs that run in a separate
+	// process may be limited.
+	var vm interface{}
+	limits := m.getChainConfig(ctx.ChainID).Limits
+	switch limitedFactory, ok := vmFactory.(vms.LimitedFactory); {
+	case ok:
+		vm, err = limitedFactory.NewWithLimits(ctx, limits)
+	case !limits.IsEmpty():
+		return nil, errVMNotLimited
+	default:
+		vm, err = vmFactory.New(ctx)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error while creating vm: %w", err)
 	}
 	// TODO: Shutdown VM if an error occurs
 
+	if chainParams.ID == constants.PlatformChainID {
+		vdrState, ok := vm.(validators.State)
+		if !ok {
+			return nil, errPlatformChainNotValidatorState
+		}
+		m.validatorState = vdrState
+		m.lockedValidatorState = validators.NewLockedState(&ctx.Lock, vdrState)
+		// The P-chain holds its own lock whenever it uses its context
+		ctx.ValidatorState = vdrState
+	}
+
 	fxs := make([]*common.Fx, len(chainParams.FxAliases))
 	for i, fxAlias := range chainParams.FxAliases {
 		fxID, err := m.VMManager.Lookup(fxAlias)
@@ -384,8 +544,14 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		}
 	}
 
+	primaryAlias, err := m.PrimaryAlias(chainParams.ID)
+	if err != nil {
+		primaryAlias = chainParams.ID.String()
+	}
+
 	consensusParams := m.ConsensusParams
 	consensusParams.Namespace = fmt.Sprintf("%s_%s", constants.PlatformName, primaryAlias)
+	consensusParams.Metrics = ctx.Metrics
 
 	// The validators of this blockchain
 	var vdrs validators.Set // Validators validating this blockchain
@@ -419,6 +585,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			consensusParams,
 			bootstrapWeight,
 			sb,
+			prev,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error while creating new avalanche vm %w", err)
@@ -434,6 +601,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 			consensusParams.Parameters,
 			bootstrapWeight,
 			sb,
+			prev,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error while creating new snowman vm %w", err)
@@ -442,11 +610,12 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 		return nil, fmt.Errorf("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
 	}
 
-	// Register the chain with the timeout manager
-	if err := m.TimeoutManager.RegisterChain(ctx, consensusParams.Namespace); err != nil {
-		return nil, err
+	if verifier, ok := vm.(common.DatabaseVerifier); ok {
+		chain.DatabaseVerifier = verifier
+	}
+	if exitable, ok := vm.(common.Exitable); ok {
+		chain.VMExited = exitable.Exited()
 	}
-
 	return chain, nil
 }
 
@@ -473,10 +642,8 @@ func (m *manager) createAvalancheChain(
 	consensusParams avcon.Parameters,
 	bootstrapWeight uint64,
 	sb Subnet,
+	prev *chain,
 ) (*chain, error) {
-	ctx.Lock.Lock()
-	defer ctx.Lock.Unlock()
-
 	if m.MeterVMEnabled {
 		vm = metervm.NewVertexVM(vm)
 	}
@@ -485,7 +652,7 @@ func (m *manager) createAvalancheChain(
 		return nil, err
 	}
 	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
-	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
+	vmDBManager := prefixDBManager.NewPrefixDBManager(VMDBPrefix)
 
 	db := prefixDBManager.Current()
 	vertexDB := prefixdb.New([]byte("vertex"), db.Database)
@@ -504,6 +671,12 @@ func (m *manager) createAvalancheChain(
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
 	msgChan := make(chan common.Message, defaultChannelSize)
+	// Asynchronously passes messages from the network to the consensus engine
+	handler := &router.Handler{}
+	if prev != nil {
+		msgChan = prev.MsgChan
+		handler = prev.Handler
+	}
 
 	chainConfig := m.getChainConfig(ctx.ChainID)
 	if err := vm.Initialize(ctx, vmDBManager, genesisData, chainConfig.Upgrade, chainConfig.Config, msgChan, fxs); err != nil {
@@ -527,9 +700,6 @@ func (m *manager) createAvalancheChain(
 		sampleK = int(bootstrapWeight)
 	}
 
-	// Asynchronously passes messages from the network to the consensus engine
-	handler := &router.Handler{}
-
 	timer := &router.Timer{
 		Handler: handler,
 		Preempt: sb.afterBootstrapped(),
@@ -541,7 +711,7 @@ func (m *manager) createAvalancheChain(
 		Config: avbootstrap.Config{
 			Config: common.Config{
 				Ctx:                           ctx,
-				Validators:                    validators,
+				Validators:                    score.NewWeightedSet(validators, m.PeerScores, m.PeerScoreMinSamplingFactor),
 				Beacons:                       beacons,
 				SampleK:                       sampleK,
 				StartupAlpha:                  (3*bootstrapWeight + 3) / 4,
@@ -567,28 +737,27 @@ func (m *manager) createAvalancheChain(
 		return nil, fmt.Errorf("error initializing avalanche engine: %w", err)
 	}
 
-	// Register health check for this chain
+	// The handler of a restarted chain keeps dispatching to the previous
+	// engine until it's replaced by the caller
+	if prev == nil {
+		err = handler.Initialize(
+			engine,
+			validators,
+			m.PeerScores,
+			msgChan,
+			fmt.Sprintf("%s_handler", consensusParams.Namespace),
+			consensusParams.Metrics,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't initialize message handler: %s", err)
+		}
+		m.PeerScores.RegisterCPUTracker(ctx.ChainID, handler.CPUTracker())
+	}
+
 	chainAlias, err := m.PrimaryAlias(ctx.ChainID)
 	if err != nil {
 		chainAlias = ctx.ChainID.String()
 	}
-	// Grab the context lock before calling the chain's health check
-	checkFn := func() (interface{}, error) {
-		ctx.Lock.Lock()
-		defer ctx.Lock.Unlock()
-		return engine.HealthCheck()
-	}
-	if err := m.HealthService.RegisterCheck(chainAlias, checkFn); err != nil {
-		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
-	}
-
-	err = handler.Initialize(
-		engine,
-		validators,
-		msgChan,
-		fmt.Sprintf("%s_handler", consensusParams.Namespace),
-		consensusParams.Metrics,
-	)
 
 	return &chain{
 		Name:    chainAlias,
@@ -596,7 +765,8 @@ func (m *manager) createAvalancheChain(
 		Handler: handler,
 		VM:      vm,
 		Ctx:     ctx,
-	}, err
+		MsgChan: msgChan,
+	}, nil
 }
 
 // Create a linear chain using the Snowman consensus engine
@@ -610,10 +780,26 @@ func (m *manager) createSnowmanChain(
 	consensusParams snowball.Parameters,
 	bootstrapWeight uint64,
 	sb Subnet,
+	prev *chain,
 ) (*chain, error) {
-	ctx.Lock.Lock()
-	defer ctx.Lock.Unlock()
-
+	vdrState := m.lockedValidatorState
+	if ctx.ChainID == constants.PlatformChainID {
+		vdrState = m.validatorState
+	}
+	if vdrState != nil {
+		stakingKey, _ := m.StakingCert.PrivateKey.(crypto.Signer)
+		stakingCert := m.StakingCert.Leaf
+		if stakingKey == nil {
+			stakingCert = nil
+		}
+		vm = proposervm.New(
+			vm,
+			version.GetApricotPhase3Time(m.NetworkID),
+			vdrState,
+			stakingCert,
+			stakingKey,
+		)
+	}
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
 	}
@@ -622,7 +808,7 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
-	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
+	vmDBManager := prefixDBManager.NewPrefixDBManager(VMDBPrefix)
 
 	db := prefixDBManager.Current()
 	bootstrappingDB := prefixdb.New([]byte("bs"), db.Database)
@@ -635,6 +821,12 @@ func (m *manager) createSnowmanChain(
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
 	msgChan := make(chan common.Message, defaultChannelSize)
+	// Asynchronously passes messages from the network to the consensus engine
+	handler := &router.Handler{}
+	if prev != nil {
+		msgChan = prev.MsgChan
+		handler = prev.Handler
+	}
 
 	// Initialize the VM
 	chainConfig := m.getChainConfig(ctx.ChainID)
@@ -661,9 +853,6 @@ func (m *manager) createSnowmanChain(
 		sampleK = int(bootstrapWeight)
 	}
 
-	// Asynchronously passes messages from the network to the consensus engine
-	handler := &router.Handler{}
-
 	timer := &router.Timer{
 		Handler: handler,
 		Preempt: sb.afterBootstrapped(),
@@ -675,7 +864,7 @@ func (m *manager) createSnowmanChain(
 		Config: smbootstrap.Config{
 			Config: common.Config{
 				Ctx:                           ctx,
-				Validators:                    validators,
+				Validators:                    score.NewWeightedSet(validators, m.PeerScores, m.PeerScoreMinSamplingFactor),
 				Beacons:                       beacons,
 				SampleK:                       sampleK,
 				StartupAlpha:                  (3*bootstrapWeight + 3) / 4,
@@ -699,38 +888,35 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("error initializing snowman engine: %w", err)
 	}
 
-	err = handler.Initialize(
-		engine,
-		validators,
-		msgChan,
-		fmt.Sprintf("%s_handler", consensusParams.Namespace),
-		consensusParams.Metrics,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't initialize message handler: %s", err)
+	// The handler of a restarted chain keeps dispatching to the previous
+	// engine until it's replaced by the caller
+	if prev == nil {
+		err = handler.Initialize(
+			engine,
+			validators,
+			m.PeerScores,
+			msgChan,
+			fmt.Sprintf("%s_handler", consensusParams.Namespace),
+			consensusParams.Metrics,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't initialize message handler: %s", err)
+		}
+		m.PeerScores.RegisterCPUTracker(ctx.ChainID, handler.CPUTracker())
 	}
 
-	// Register health checks
 	chainAlias, err := m.PrimaryAlias(ctx.ChainID)
 	if err != nil {
 		chainAlias = ctx.ChainID.String()
 	}
 
-	checkFn := func() (interface{}, error) {
-		ctx.Lock.Lock()
-		defer ctx.Lock.Unlock()
-		return engine.HealthCheck()
-	}
-	if err := m.HealthService.RegisterCheck(chainAlias, checkFn); err != nil {
-		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
-	}
-
 	return &chain{
 		Name:    chainAlias,
 		Engine:  engine,
 		Handler: handler,
 		VM:      vm,
 		Ctx:     ctx,
+		MsgChan: msgChan,
 	}, nil
 }
 
@@ -740,7 +926,7 @@ func (m *manager) SubnetID(chainID ids.ID) (ids.ID, error) {
 
 	chain, exists := m.chains[chainID]
 	if !exists {
-		return ids.ID{}, errors.New("unknown chain ID")
+		return ids.ID{}, errUnknownChain
 	}
 	return chain.Context().SubnetID, nil
 }
@@ -756,9 +942,43 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Engine().IsBootstrapped()
 }
 
+func (m *manager) VerifyDatabase(chainID ids.ID) ([]string, error) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	verifier, verifiable := m.databaseVerifiers[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, errUnknownChain
+	}
+	if !verifiable {
+		return nil, errNoDatabaseVerifier
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return verifier.VerifyDatabase()
+}
+
+func (m *manager) VMProcessStatus(chainID ids.ID) (VMProcessStatus, error) {
+	m.chainsLock.Lock()
+	_, exists := m.chains[chainID]
+	proc, hasProcess := m.vmProcesses[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return VMProcessStatus{}, errUnknownChain
+	}
+	if !hasProcess {
+		return VMProcessStatus{}, errNoVMProcess
+	}
+	return proc.Status(), nil
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.Log.Info("shutting down chain manager")
+	close(m.closing)
 	m.ManagerConfig.Router.Shutdown()
 }
 
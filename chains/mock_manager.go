@@ -19,6 +19,12 @@ func (mm MockManager) Shutdown()                        {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)  { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool       { return false }
 
+func (mm MockManager) VerifyDatabase(ids.ID) ([]string, error) { return nil, nil }
+
+func (mm MockManager) VMProcessStatus(ids.ID) (VMProcessStatus, error) {
+	return VMProcessStatus{}, nil
+}
+
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
 	if err == nil {
@@ -0,0 +1,274 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The states of the process a chain's VM runs in
+const (
+	VMProcessRunning    = "running"
+	VMProcessRestarting = "restarting"
+	VMProcessStopped    = "stopped"
+)
+
+// vmStableRunTime is how long a restarted VM must run before its process
+// exits for the exit to no longer count towards the limit on restarts
+const vmStableRunTime = 10 * time.Minute
+
+var (
+	errVMRestartDisabled = errors.New("VM restarts are disabled")
+	errVMRestartLimit    = errors.New("VM was restarted too many times")
+)
+
+// VMProcessStatus describes the process a chain's VM runs in
+type VMProcessStatus struct {
+	// One of [VMProcessRunning], [VMProcessRestarting] or [VMProcessStopped]
+	State string `json:"state"`
+	// The times the process exited, oldest first
+	Exits []VMProcessExit `json:"exits"`
+}
+
+// VMProcessExit describes an exit of the process a chain's VM runs in
+type VMProcessExit struct {
+	Time time.Time `json:"time"`
+	// True if the VM was restarted after this exit
+	Restarted bool `json:"restarted"`
+	// Why the chain was shut down after this exit, if it was
+	Error string `json:"error,omitempty"`
+}
+
+// vmProcessHealth is the health of a chain whose VM's process has exited
+type vmProcessHealth struct {
+	Engine    interface{}     `json:"engine,omitempty"`
+	VMProcess VMProcessStatus `json:"vmProcess"`
+}
+
+// vmProcess tracks the exits and restarts of the process a chain's VM runs in
+type vmProcess struct {
+	lock   sync.Mutex
+	status VMProcessStatus
+}
+
+func newVMProcess() *vmProcess {
+	return &vmProcess{
+		status: VMProcessStatus{State: VMProcessRunning},
+	}
+}
+
+// Status returns a copy of the status of the process
+func (p *vmProcess) Status() VMProcessStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.status
+	status.Exits = make([]VMProcessExit, len(p.status.Exits))
+	copy(status.Exits, p.status.Exits)
+	return status
+}
+
+// exited records that the process exited at [exitTime]
+func (p *vmProcess) exited(exitTime time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.status.State = VMProcessRestarting
+	p.status.Exits = append(p.status.Exits, VMProcessExit{Time: exitTime})
+}
+
+// restarted records that the VM was restarted after the last exit
+func (p *vmProcess) restarted() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.status.State = VMProcessRunning
+	p.status.Exits[len(p.status.Exits)-1].Restarted = true
+}
+
+// stopped records that the chain was shut down after the last exit because
+// of [err]
+func (p *vmProcess) stopped(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.status.State = VMProcessStopped
+	p.status.Exits[len(p.status.Exits)-1].Error = err.Error()
+}
+
+// healthCheck returns a health check that fails while the process is down
+// and otherwise runs [engineCheck]. Once the process has exited, its exits are
+// reported along with the engine's health.
+func (p *vmProcess) healthCheck(engineCheck func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		status := p.Status()
+		if status.State != VMProcessRunning {
+			return vmProcessHealth{VMProcess: status}, fmt.Errorf("VM process is %s", status.State)
+		}
+
+		details, err := engineCheck()
+		if len(status.Exits) == 0 {
+			return details, err
+		}
+		return vmProcessHealth{
+			Engine:    details,
+			VMProcess: status,
+		}, err
+	}
+}
+
+// replacingRegisterer registers collectors in place of the collectors that
+// were registered with the same descriptors before. The metrics of a chain
+// whose VM is restarted are registered again through it.
+type replacingRegisterer struct {
+	prometheus.Registerer
+}
+
+func (r replacingRegisterer) Register(c prometheus.Collector) error {
+	err := r.Registerer.Register(c)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if !errors.As(err, &alreadyRegistered) {
+		return err
+	}
+	r.Registerer.Unregister(alreadyRegistered.ExistingCollector)
+	return r.Registerer.Register(c)
+}
+
+func (r replacingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// superviseVM waits for the process [chain]'s VM runs in to exit. Then, the
+// VM is restarted if restarts are enabled and the limit on restarts hasn't
+// been reached. Otherwise, the chain is shut down. Restarts stop counting
+// towards the limit once the VM ran for [vmStableRunTime].
+func (m *manager) superviseVM(chainParams ChainParameters, sb Subnet, chain *chain) {
+	proc := chain.VMProcess
+	restarts := 0
+	var restartTime time.Time
+	for {
+		select {
+		case <-chain.VMExited:
+		case <-m.closing:
+			return
+		}
+
+		ctx := chain.Ctx
+		ctx.Log.Error("the process of the chain's VM exited unexpectedly")
+		exitTime := time.Now()
+		proc.exited(exitTime)
+		if restarts > 0 && exitTime.Sub(restartTime) >= vmStableRunTime {
+			restarts = 0
+		}
+
+		switch {
+		case !m.VMRestartEnabled:
+			m.stopChain(chain, errVMRestartDisabled)
+			return
+		case restarts >= m.VMRestartMaxAttempts:
+			m.stopChain(chain, errVMRestartLimit)
+			return
+		}
+
+		// Messages are held back until the VM is restarted so that the engine
+		// isn't used while the VM is down. The context lock isn't held while
+		// waiting, so that nothing else waiting on it is blocked by the backoff.
+		chain.Handler.Pause()
+		backoff := m.vmRestartBackoff(restarts)
+		ctx.Log.Info("restarting the chain's VM in %s", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-m.closing:
+			return
+		}
+
+		ctx.Lock.Lock()
+		newChain, err := m.restartChain(chainParams, sb, chain)
+		if err != nil {
+			ctx.Lock.Unlock()
+			m.stopChain(chain, fmt.Errorf("couldn't restart VM: %w", err))
+			return
+		}
+		chain.Handler.SetEngine(newChain.Engine)
+		ctx.Lock.Unlock()
+		chain.Handler.Resume()
+
+		chain = newChain
+		restarts++
+		restartTime = time.Now()
+		proc.restarted()
+		ctx.Log.Info("restarted the chain's VM")
+
+		m.chainsLock.Lock()
+		if chain.DatabaseVerifier != nil {
+			m.databaseVerifiers[chainParams.ID] = chain.DatabaseVerifier
+		} else {
+			delete(m.databaseVerifiers, chainParams.ID)
+		}
+		m.chainsLock.Unlock()
+
+		// Tell the new engine about the connected peers, which causes it to
+		// bootstrap, and serve the new VM's APIs
+		m.ManagerConfig.Router.AddChain(chain.Handler)
+		m.notifyRegistrants(chain.Name, chain.Ctx, chain.Engine)
+
+		if chain.VMExited == nil {
+			return
+		}
+	}
+}
+
+// restartChain replaces the VM and the engine of [prev], whose VM's process
+// exited. The chain's context, database and handler are kept. The new engine
+// bootstraps once it's told about the connected peers.
+//
+// Assumes [prev.Ctx.Lock] is held.
+func (m *manager) restartChain(chainParams ChainParameters, sb Subnet, prev *chain) (*chain, error) {
+	ctx := prev.Ctx
+	// The VM's process is gone, so shutting the VM down is expected to fail
+	if err := prev.Engine.Shutdown(); err != nil {
+		ctx.Log.Debug("error while shutting down the exited VM: %s", err)
+	}
+
+	// The new VM and engine register the same metrics as the previous ones
+	if _, ok := ctx.Metrics.(replacingRegisterer); !ok {
+		ctx.Metrics = replacingRegisterer{Registerer: ctx.Metrics}
+	}
+
+	chain, err := m.initChain(ctx, chainParams, sb, prev)
+	if err != nil {
+		return nil, err
+	}
+	chain.VMProcess = prev.VMProcess
+	return chain, nil
+}
+
+// stopChain shuts down [chain] after its VM's process exited
+func (m *manager) stopChain(chain *chain, err error) {
+	chain.Ctx.Log.Error("shutting down the chain as its VM won't be restarted: %s", err)
+	chain.VMProcess.stopped(err)
+	chain.Handler.StartShutdown()
+}
+
+// vmRestartBackoff returns how long to wait before restarting a chain's VM
+// that was already restarted [restarts] times
+func (m *manager) vmRestartBackoff(restarts int) time.Duration {
+	backoff := m.VMRestartBackoff
+	for i := 0; i < restarts && backoff < m.VMRestartMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > m.VMRestartMaxBackoff {
+		backoff = m.VMRestartMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,114 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVMProcessHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	errEngine := errors.New("engine is unhealthy")
+	engineCheck := func() (interface{}, error) { return "engine details", errEngine }
+
+	proc := newVMProcess()
+	checkFn := proc.healthCheck(engineCheck)
+
+	// Before the process exits, the engine's health is reported as is
+	details, err := checkFn()
+	assert.Equal("engine details", details)
+	assert.Equal(errEngine, err)
+
+	// While the process is down, the chain is unhealthy
+	exitTime := time.Unix(1, 0)
+	proc.exited(exitTime)
+	details, err = checkFn()
+	assert.Error(err)
+	assert.Equal(vmProcessHealth{
+		VMProcess: VMProcessStatus{
+			State: VMProcessRestarting,
+			Exits: []VMProcessExit{{Time: exitTime}},
+		},
+	}, details)
+
+	// After a restart, the exits are reported along with the engine's health
+	proc.restarted()
+	details, err = checkFn()
+	assert.Equal(errEngine, err)
+	assert.Equal(vmProcessHealth{
+		Engine: "engine details",
+		VMProcess: VMProcessStatus{
+			State: VMProcessRunning,
+			Exits: []VMProcessExit{{Time: exitTime, Restarted: true}},
+		},
+	}, details)
+
+	// Once the chain is shut down, it stays unhealthy
+	proc.exited(exitTime)
+	proc.stopped(errVMRestartLimit)
+	_, err = checkFn()
+	assert.Error(err)
+
+	status := proc.Status()
+	assert.Equal(VMProcessStopped, status.State)
+	assert.Len(status.Exits, 2)
+	assert.Equal(errVMRestartLimit.Error(), status.Exits[1].Error)
+}
+
+func TestVMRestartBackoff(t *testing.T) {
+	m := &manager{
+		ManagerConfig: ManagerConfig{
+			VMRestartBackoff:    time.Second,
+			VMRestartMaxBackoff: 5 * time.Second,
+		},
+	}
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	}
+	for restarts, backoff := range expected {
+		assert.Equal(t, backoff, m.vmRestartBackoff(restarts))
+	}
+}
+
+func TestReplacingRegisterer(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	newCounter := func() prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "restarts",
+			Help: "Number of restarts",
+		})
+	}
+
+	first := newCounter()
+	assert.NoError(registry.Register(first))
+	assert.Error(registry.Register(newCounter()))
+
+	second := newCounter()
+	second.Inc()
+	registerer := replacingRegisterer{Registerer: registry}
+	assert.NoError(registerer.Register(second))
+
+	metrics, err := registry.Gather()
+	assert.NoError(err)
+	assert.Len(metrics, 1)
+	assert.Equal(1.0, metrics[0].Metric[0].Counter.GetValue())
+
+	// Collectors that conflict with registered ones are still rejected
+	assert.Error(registerer.Register(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "restarts",
+		Help: "Number of times the VM was restarted",
+	})))
+}
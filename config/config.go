@@ -8,15 +8,18 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/app/process"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
@@ -28,12 +31,15 @@ import (
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
+	"github.com/ava-labs/avalanchego/utils/subprocess"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
 )
@@ -43,6 +49,7 @@ const (
 	avalanchegoPreupgrade = "avalanchego-preupgrade"
 	chainConfigFileName   = "config"
 	chainUpgradeFileName  = "upgrade"
+	chainLimitsFileName   = "limits"
 )
 
 var (
@@ -169,10 +176,17 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 
 	// DB:
 	nodeConfig.DBName = v.GetString(DBTypeKey)
+	nodeConfig.DBCopyLevelDB = v.GetBool(DBCopyLevelDBKey)
 	nodeConfig.DBPath = filepath.Join(
 		os.ExpandEnv(v.GetString(DBPathKey)),
 		constants.NetworkName(nodeConfig.NetworkID),
 	)
+	nodeConfig.SnapshotExportDir = os.ExpandEnv(v.GetString(SnapshotExportDirKey))
+	nodeConfig.SnapshotImportDir = os.ExpandEnv(v.GetString(SnapshotImportDirKey))
+	if nodeConfig.SnapshotExportDir != "" && nodeConfig.SnapshotImportDir != "" {
+		return node.Config{}, fmt.Errorf("%q and %q can't both be set", SnapshotExportDirKey, SnapshotImportDirKey)
+	}
+	nodeConfig.VerifyDatabase = v.GetBool(VerifyDatabaseKey)
 
 	// IP configuration
 	// Resolves our public IP, or does nothing
@@ -262,6 +276,10 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	if err := initStaticPeers(v, &nodeConfig); err != nil {
+		return node.Config{}, err
+	}
+
 	nodeConfig.WhitelistedSubnets.Add(constants.PrimaryNetworkID)
 	for _, subnet := range strings.Split(v.GetString(WhitelistedSubnetsKey), ",") {
 		if subnet != "" {
@@ -279,7 +297,18 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.HTTPSEnabled = v.GetBool(HTTPSEnabledKey)
 	nodeConfig.HTTPSKeyFile = os.ExpandEnv(v.GetString(HTTPSKeyFileKey))
 	nodeConfig.HTTPSCertFile = os.ExpandEnv(v.GetString(HTTPSCertFileKey))
+	nodeConfig.HTTPSClientCerts = server.ClientCertConfig{
+		CAFile:   os.ExpandEnv(v.GetString(HTTPSClientCAFileKey)),
+		Required: v.GetBool(HTTPSClientCertRequiredKey),
+	}
+	if !nodeConfig.HTTPSEnabled && (nodeConfig.HTTPSClientCerts.CAFile != "" || nodeConfig.HTTPSClientCerts.Required) {
+		return node.Config{}, fmt.Errorf("%s and %s require %s", HTTPSClientCAFileKey, HTTPSClientCertRequiredKey, HTTPSEnabledKey)
+	}
 	nodeConfig.APIAllowedOrigins = v.GetStringSlice(HTTPAllowedOrigins)
+	nodeConfig.APIMaxBatchSize = v.GetInt(APIMaxBatchSizeKey)
+	if nodeConfig.APIMaxBatchSize < 0 {
+		return node.Config{}, fmt.Errorf("%s must be non-negative", APIMaxBatchSizeKey)
+	}
 
 	// API Auth
 	nodeConfig.APIRequireAuthToken = v.GetBool(APIAuthRequiredKey)
@@ -294,11 +323,72 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 			return node.Config{}, errors.New("api-auth-password is not strong enough")
 		}
 	}
+	if clientCertsFile := os.ExpandEnv(v.GetString(APIAuthClientCertsFileKey)); clientCertsFile != "" {
+		if !nodeConfig.APIRequireAuthToken {
+			return node.Config{}, fmt.Errorf("%s requires %s", APIAuthClientCertsFileKey, APIAuthRequiredKey)
+		}
+		if nodeConfig.HTTPSClientCerts.CAFile == "" {
+			return node.Config{}, fmt.Errorf("%s requires %s", APIAuthClientCertsFileKey, HTTPSClientCAFileKey)
+		}
+		clientCertsBytes, err := ioutil.ReadFile(clientCertsFile)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("%s %q failed to be read with: %w", APIAuthClientCertsFileKey, clientCertsFile, err)
+		}
+		if err := json.Unmarshal(clientCertsBytes, &nodeConfig.APIAuthClientCertPermissions); err != nil {
+			return node.Config{}, fmt.Errorf("%s %q couldn't be parsed: %w", APIAuthClientCertsFileKey, clientCertsFile, err)
+		}
+	}
+
+	// API Auditing
+	nodeConfig.APIAuditEnabled = v.GetBool(APIAuditEnabledKey)
+	nodeConfig.APIAuditMethods = v.GetStringSlice(APIAuditMethodsKey)
+
+	// API Rate Limiting
+	nodeConfig.APIRateLimiterConfig = server.RateLimiterConfig{
+		IPRate:      v.GetFloat64(APIRateLimitIPRateKey),
+		IPBurst:     v.GetInt(APIRateLimitIPBurstKey),
+		TokenRate:   v.GetFloat64(APIRateLimitTokenRateKey),
+		TokenBurst:  v.GetInt(APIRateLimitTokenBurstKey),
+		MethodCosts: make(map[string]int),
+		MaxClients:  v.GetInt(APIRateLimitMaxClientsKey),
+	}
+	switch {
+	case nodeConfig.APIRateLimiterConfig.IPRate < 0:
+		return node.Config{}, fmt.Errorf("%s must be non-negative", APIRateLimitIPRateKey)
+	case nodeConfig.APIRateLimiterConfig.TokenRate < 0:
+		return node.Config{}, fmt.Errorf("%s must be non-negative", APIRateLimitTokenRateKey)
+	case nodeConfig.APIRateLimiterConfig.MaxClients < 0:
+		return node.Config{}, fmt.Errorf("%s must be non-negative", APIRateLimitMaxClientsKey)
+	}
+	for _, methodCost := range v.GetStringSlice(APIRateLimitMethodCostsKey) {
+		parts := strings.Split(methodCost, "=")
+		if len(parts) != 2 {
+			return node.Config{}, fmt.Errorf("%s contains %q which isn't of the form method=cost", APIRateLimitMethodCostsKey, methodCost)
+		}
+		cost, err := strconv.Atoi(parts[1])
+		if err != nil || cost < 0 {
+			return node.Config{}, fmt.Errorf("%s contains %q which doesn't have a non-negative cost", APIRateLimitMethodCostsKey, methodCost)
+		}
+		nodeConfig.APIRateLimiterConfig.MethodCosts[parts[0]] = cost
+	}
 
 	// APIs
 	nodeConfig.AdminAPIEnabled = v.GetBool(AdminAPIEnabledKey)
 	nodeConfig.InfoAPIEnabled = v.GetBool(InfoAPIEnabledKey)
 	nodeConfig.KeystoreAPIEnabled = v.GetBool(KeystoreAPIEnabledKey)
+	kdfTime, kdfMemory, kdfThreads := v.GetUint(KeystoreKDFTimeKey), v.GetUint(KeystoreKDFMemoryKey), v.GetUint(KeystoreKDFThreadsKey)
+	if kdfTime > math.MaxUint32 || kdfMemory > math.MaxUint32 || kdfThreads > math.MaxUint8 {
+		return node.Config{}, fmt.Errorf("%s, %s or %s is too large", KeystoreKDFTimeKey, KeystoreKDFMemoryKey, KeystoreKDFThreadsKey)
+	}
+	nodeConfig.KeystoreKDFParams = password.KDFParams{
+		KDF:     password.Argon2id,
+		Time:    uint32(kdfTime),
+		Memory:  uint32(kdfMemory),
+		Threads: uint8(kdfThreads),
+	}
+	if err := nodeConfig.KeystoreKDFParams.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid keystore KDF parameters: %w", err)
+	}
 	nodeConfig.MetricsAPIEnabled = v.GetBool(MetricsAPIEnabledKey)
 	nodeConfig.HealthAPIEnabled = v.GetBool(HealthAPIEnabledKey)
 	nodeConfig.IPCAPIEnabled = v.GetBool(IpcAPIEnabledKey)
@@ -335,6 +425,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		nodeConfig.IPCPath = ipcs.DefaultBaseURL
 	}
 
+	nodeConfig.IPCTransport.Transport, err = ipcs.ParseTransport(v.GetString(IpcsTransportKey))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("couldn't parse %s: %w", IpcsTransportKey, err)
+	}
+	nodeConfig.IPCTransport.TCPHost = v.GetString(IpcsTCPHostKey)
+
 	// Metrics
 	nodeConfig.MeterVMEnabled = v.GetBool(MeterVMsEnabledKey)
 
@@ -353,6 +449,24 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		VdrAllocSize:        v.GetUint64(OutboundThrottlerVdrAllocSizeKey),
 		NodeMaxAtLargeBytes: v.GetUint64(OutboundThrottlerNodeMaxAtLargeBytesKey),
 	}
+	nodeConfig.NetworkConfig.InboundBandwidthConfig = throttling.BandwidthThrottlerConfig{
+		RefillRate:       v.GetUint64(InboundBandwidthRefillRateKey),
+		MaxBurstSize:     v.GetUint64(InboundBandwidthMaxBurstSizeKey),
+		PeerRefillRate:   v.GetUint64(InboundNodeBandwidthRefillRateKey),
+		PeerMaxBurstSize: v.GetUint64(InboundNodeBandwidthMaxBurstSizeKey),
+	}
+	nodeConfig.NetworkConfig.OutboundBandwidthConfig = throttling.BandwidthThrottlerConfig{
+		RefillRate:       v.GetUint64(OutboundBandwidthRefillRateKey),
+		MaxBurstSize:     v.GetUint64(OutboundBandwidthMaxBurstSizeKey),
+		PeerRefillRate:   v.GetUint64(OutboundNodeBandwidthRefillRateKey),
+		PeerMaxBurstSize: v.GetUint64(OutboundNodeBandwidthMaxBurstSizeKey),
+	}
+	if err := nodeConfig.NetworkConfig.InboundBandwidthConfig.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid inbound bandwidth limits: %w", err)
+	}
+	if err := nodeConfig.NetworkConfig.OutboundBandwidthConfig.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid outbound bandwidth limits: %w", err)
+	}
 
 	// Health
 	nodeConfig.HealthCheckFreq = v.GetDuration(HealthCheckFreqKey)
@@ -399,6 +513,34 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 
 	nodeConfig.CompressionEnabled = v.GetBool(NetworkCompressionEnabledKey)
+	nodeConfig.CompressionType, err = compression.TypeFromString(v.GetString(NetworkCompressionTypeKey))
+	if err != nil {
+		return node.Config{}, fmt.Errorf("%s is invalid: %w", NetworkCompressionTypeKey, err)
+	}
+	if nodeConfig.CompressionType != compression.Gzip && nodeConfig.CompressionType != compression.Zstd {
+		return node.Config{}, fmt.Errorf("%s must be one of: %s, %s", NetworkCompressionTypeKey, compression.Gzip, compression.Zstd)
+	}
+	if dictionaryFile := os.ExpandEnv(v.GetString(NetworkCompressionDictionaryFileKey)); dictionaryFile != "" {
+		nodeConfig.CompressionDictionary, err = ioutil.ReadFile(dictionaryFile)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("%s %q failed to be read with: %w", NetworkCompressionDictionaryFileKey, dictionaryFile, err)
+		}
+		if _, err := compression.DictionaryID(nodeConfig.CompressionDictionary); err != nil {
+			return node.Config{}, fmt.Errorf("%s %q couldn't be parsed: %w", NetworkCompressionDictionaryFileKey, dictionaryFile, err)
+		}
+	}
+
+	nodeConfig.PrivateMode = v.GetBool(NetworkPrivateModeKey)
+	for _, id := range strings.Split(v.GetString(NetworkRelayedNodeIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return node.Config{}, fmt.Errorf("couldn't parse relayed node id: %w", err)
+		}
+		nodeConfig.RelayedNodeIDs.Add(nodeID)
+	}
 
 	// Node will gossip [PeerListSize] peers to [PeerListGossipSize] every
 	// [PeerListGossipFreq]
@@ -418,6 +560,33 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.BenchlistConfig.Duration = v.GetDuration(BenchlistDurationKey)
 	nodeConfig.BenchlistConfig.MinimumFailingDuration = v.GetDuration(BenchlistMinFailingDurationKey)
 	nodeConfig.BenchlistConfig.MaxPortion = (1.0 - (float64(nodeConfig.ConsensusParams.Alpha) / float64(nodeConfig.ConsensusParams.K))) / 3.0
+	nodeConfig.BenchlistConfig.MinScore = v.GetFloat64(BenchlistMinPeerScoreKey)
+	if nodeConfig.BenchlistConfig.MinScore < 0 || nodeConfig.BenchlistConfig.MinScore > 1 {
+		return node.Config{}, fmt.Errorf("%q must be in [0, 1]", BenchlistMinPeerScoreKey)
+	}
+
+	// Peer scores
+	nodeConfig.PeerScoreConfig = score.Config{
+		Halflife:           v.GetDuration(PeerScoreHalflifeKey),
+		MaxLatency:         v.GetDuration(PeerScoreMaxLatencyKey),
+		MaxInvalidMessages: v.GetFloat64(PeerScoreMaxInvalidMessagesKey),
+		MaxCPUUtilization:  v.GetFloat64(PeerScoreMaxCPUUtilizationKey),
+		MinSamplingFactor:  v.GetFloat64(PeerScoreMinSamplingFactorKey),
+		MaxPeers:           v.GetInt(PeerScoreMaxPeersKey),
+		Expiry:             v.GetDuration(PeerScoreExpiryKey),
+	}
+	if nodeConfig.PeerScoreConfig.Halflife <= 0 {
+		return node.Config{}, fmt.Errorf("%q must be positive", PeerScoreHalflifeKey)
+	}
+	if nodeConfig.PeerScoreConfig.MinSamplingFactor <= 0 || nodeConfig.PeerScoreConfig.MinSamplingFactor > 1 {
+		return node.Config{}, fmt.Errorf("%q must be in (0, 1]", PeerScoreMinSamplingFactorKey)
+	}
+	if nodeConfig.PeerScoreConfig.MaxPeers <= 0 {
+		return node.Config{}, fmt.Errorf("%q must be positive", PeerScoreMaxPeersKey)
+	}
+	if nodeConfig.PeerScoreConfig.Expiry < 0 {
+		return node.Config{}, fmt.Errorf("%q can't be negative", PeerScoreExpiryKey)
+	}
 
 	if nodeConfig.ConsensusGossipFrequency < 0 {
 		return node.Config{}, errors.New("gossip frequency can't be negative")
@@ -500,6 +669,21 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	nodeConfig.BootstrapMultiputMaxContainersSent = int(v.GetUint(BootstrapMultiputMaxContainersSentKey))
 	nodeConfig.BootstrapMultiputMaxContainersReceived = int(v.GetUint(BootstrapMultiputMaxContainersReceivedKey))
 
+	// VM restarts
+	nodeConfig.VMRestartEnabled = v.GetBool(VMRestartEnabledKey)
+	nodeConfig.VMRestartMaxAttempts = v.GetInt(VMRestartMaxAttemptsKey)
+	if nodeConfig.VMRestartMaxAttempts < 0 {
+		return node.Config{}, fmt.Errorf("%s must be non-negative", VMRestartMaxAttemptsKey)
+	}
+	nodeConfig.VMRestartBackoff = v.GetDuration(VMRestartBackoffKey)
+	nodeConfig.VMRestartMaxBackoff = v.GetDuration(VMRestartMaxBackoffKey)
+	if nodeConfig.VMRestartBackoff <= 0 || nodeConfig.VMRestartMaxBackoff < nodeConfig.VMRestartBackoff {
+		return node.Config{}, fmt.Errorf("%s must be positive and at most %s", VMRestartBackoffKey, VMRestartMaxBackoffKey)
+	}
+
+	// Plugin resource limits
+	nodeConfig.PluginCgroupDir = os.ExpandEnv(v.GetString(PluginCgroupDirKey))
+
 	// Peer alias
 	nodeConfig.PeerAliasTimeout = v.GetDuration(PeerAliasTimeoutKey)
 
@@ -510,6 +694,16 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 	nodeConfig.ChainConfigs = chainConfigs
 
+	// Memory and CPU limits are applied in cgroups created under the plugin
+	// cgroup, which must be delegated to the node
+	if nodeConfig.PluginCgroupDir == "" {
+		for chain, chainConfig := range chainConfigs {
+			if chainConfig.Limits.MaxMemory != 0 || chainConfig.Limits.CPUShare != 0 {
+				return node.Config{}, fmt.Errorf("%q must be set to limit the memory or CPU usage of %s", PluginCgroupDirKey, chain)
+			}
+		}
+	}
+
 	// Profile config
 	nodeConfig.ProfilerConfig.Dir = os.ExpandEnv(v.GetString(ProfileDirKey))
 	nodeConfig.ProfilerConfig.Enabled = v.GetBool(ProfileContinuousEnabledKey)
@@ -633,6 +827,36 @@ func initBootstrapPeers(v *viper.Viper, config *node.Config) error {
 	return nil
 }
 
+// Initialize config.StaticPeerIPs and config.StaticPeerIDs.
+func initStaticPeers(v *viper.Viper, config *node.Config) error {
+	for _, ip := range strings.Split(v.GetString(StaticPeerIPsKey), ",") {
+		if ip == "" {
+			continue
+		}
+		addr, err := utils.ToIPDesc(ip)
+		if err != nil {
+			return fmt.Errorf("couldn't parse static peer ip %s: %w", ip, err)
+		}
+		config.StaticPeerIPs = append(config.StaticPeerIPs, addr)
+	}
+
+	for _, id := range strings.Split(v.GetString(StaticPeerIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse static peer id: %w", err)
+		}
+		config.StaticPeerIDs = append(config.StaticPeerIDs, nodeID)
+	}
+
+	if len(config.StaticPeerIPs) != len(config.StaticPeerIDs) {
+		return fmt.Errorf("%s has %d ips but %s has %d ids", StaticPeerIPsKey, len(config.StaticPeerIPs), StaticPeerIDsKey, len(config.StaticPeerIDs))
+	}
+	return nil
+}
+
 // ReadsChainConfigs reads chain config files from static directories and returns map with contents,
 // if successful.
 func readChainConfigDirs(chainDirs []string) (map[string]chains.ChainConfig, error) {
@@ -659,9 +883,22 @@ func readChainConfigDirs(chainDirs []string) (map[string]chains.ChainConfig, err
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/limits.*
+		limitsData, err := readSingleFile(chainDir, chainLimitsFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+		var limits subprocess.Limits
+		if len(limitsData) > 0 {
+			if err := json.Unmarshal(limitsData, &limits); err != nil {
+				return chainConfigMap, fmt.Errorf("couldn't parse limits of %s: %w", dirInfo.Name(), err)
+			}
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
 			Config:  configData,
 			Upgrade: upgradeData,
+			Limits:  limits,
 		}
 	}
 
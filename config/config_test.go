@@ -19,6 +19,9 @@ import (
 
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/subprocess"
 )
 
 func TestSetChainConfigs(t *testing.T) {
@@ -220,6 +223,103 @@ func TestSetChainConfigDefaultDir(t *testing.T) {
 	assert.Equal(expected, chainConfigs)
 }
 
+func TestSetChainLimits(t *testing.T) {
+	tests := map[string]struct {
+		limits     string
+		errMessage string
+		expected   subprocess.Limits
+	}{
+		"limits": {
+			limits: `{"maxMemory": 1073741824, "cpuShare": 0.5, "maxOpenFiles": 1024, "allowedDirs": ["/data"]}`,
+			expected: subprocess.Limits{
+				MaxMemory:    1073741824,
+				CPUShare:     0.5,
+				MaxOpenFiles: 1024,
+				AllowedDirs:  []string{"/data"},
+			},
+		},
+		"invalid limits": {
+			limits:     `{"maxMemory": -1}`,
+			errMessage: "couldn't parse limits of C",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			root := t.TempDir()
+			configFile := setupConfigJSON(t, root, fmt.Sprintf(`{%q: %q}`, ChainConfigDirKey, root))
+			setupFile(t, path.Join(root, "C"), chainLimitsFileName+".json", test.limits)
+			v := setupViper(configFile)
+
+			chainConfigs, err := getChainConfigs(v)
+			if len(test.errMessage) > 0 {
+				assert.Error(err)
+				if err != nil {
+					assert.Contains(err.Error(), test.errMessage)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, chainConfigs["C"].Limits)
+		})
+	}
+}
+
+func TestInitStaticPeers(t *testing.T) {
+	nodeID, err := ids.ShortFromPrefixedString("NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET", "NodeID-")
+	assert.NoError(t, err)
+	ip, err := utils.ToIPDesc("127.0.0.1:9651")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		ips         string
+		ids         string
+		errMessage  string
+		expectedIDs []ids.ShortID
+		expectedIPs []utils.IPDesc
+	}{
+		"no static peers": {},
+		"static peer": {
+			ips:         "127.0.0.1:9651",
+			ids:         "NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET",
+			expectedIDs: []ids.ShortID{nodeID},
+			expectedIPs: []utils.IPDesc{ip},
+		},
+		"invalid ip": {
+			ips:        "127.0.0.1",
+			ids:        "NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET",
+			errMessage: "couldn't parse static peer ip",
+		},
+		"missing id": {
+			ips:        "127.0.0.1:9651",
+			errMessage: "has 1 ips but",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			root := t.TempDir()
+			configFile := setupConfigJSON(t, root, fmt.Sprintf(`{%q: %q, %q: %q}`, StaticPeerIPsKey, test.ips, StaticPeerIDsKey, test.ids))
+			v := setupViper(configFile)
+
+			config := node.Config{}
+			err := initStaticPeers(v, &config)
+			if len(test.errMessage) > 0 {
+				assert.Error(err)
+				if err != nil {
+					assert.Contains(err.Error(), test.errMessage)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expectedIDs, config.StaticPeerIDs)
+			assert.Equal(test.expectedIPs, config.StaticPeerIPs)
+		})
+	}
+}
+
 func TestReadVMAliases(t *testing.T) {
 	tests := map[string]struct {
 		givenJSON  string
@@ -9,15 +9,22 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kardianos/osext"
 
+	"github.com/ava-labs/avalanchego/api/audit"
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/rocksdb"
+	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/ulimit"
 	"github.com/ava-labs/avalanchego/utils/units"
 )
@@ -91,8 +98,12 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(CreationTxFeeKey, units.MilliAvax, "Transaction fee, in nAVAX, for transactions that create new state")
 
 	// Database
-	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s}", leveldb.Name, rocksdb.Name, memdb.Name))
+	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s, %s}", leveldb.Name, rocksdb.Name, pebble.Name, memdb.Name))
+	fs.Bool(DBCopyLevelDBKey, false, fmt.Sprintf("If true and the database type is %s, copy the existing %s databases into %s before starting", pebble.Name, leveldb.Name, pebble.Name))
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
+	fs.String(SnapshotExportDirKey, "", "If non-empty, export a snapshot of the database to this directory then stop")
+	fs.String(SnapshotImportDirKey, "", "If non-empty, import the snapshot in this directory into the empty database before starting. Ignored once a snapshot has been imported. If an import was interrupted, it's started over")
+	fs.Bool(VerifyDatabaseKey, false, "If true, check the P-chain state, the X-chain state and the indices in the database for inconsistencies then stop")
 
 	// Coreth config
 	fs.String(CorethConfigKey, "", "Specifies config to pass into coreth")
@@ -138,6 +149,12 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkTimeoutHalflifeKey, 5*time.Minute, "Halflife of average network response time. Higher value --> network timeout is less volatile. Can't be 0.")
 	fs.Float64(NetworkTimeoutCoefficientKey, 2, "Multiplied by average network response time to get the network timeout. Must be >= 1.")
 	fs.Bool(NetworkCompressionEnabledKey, true, "If true, compress Put, PushQuery, PeerList and Multiput messages sent to peers that support compression")
+	fs.String(NetworkCompressionTypeKey, compression.Gzip.String(), fmt.Sprintf("Compression type to prefer when compressing messages. One of: %s, %s. Peers that don't support zstd are sent gzip compressed messages", compression.Gzip, compression.Zstd))
+	fs.String(NetworkCompressionDictionaryFileKey, "", fmt.Sprintf("Path to a zstd dictionary to compress messages with when %s is %s. Only used with peers that have the same dictionary", NetworkCompressionTypeKey, compression.Zstd))
+	fs.Bool(NetworkPrivateModeKey, false, "If true, don't advertise this node's IP to peers, so that it isn't gossiped, and only connect to static peers and bootstrap peers. Messages to other nodes are relayed through static peers that relay messages for this node")
+	fs.String(NetworkRelayedNodeIDsKey, "", "Comma separated list of the ids of nodes in private mode that this node is a sentry for. Messages to and from these nodes are relayed by this node")
+	fs.String(StaticPeerIPsKey, "", "Comma separated list of static peer ips to always stay connected to. Example: 127.0.0.1:9630,127.0.0.1:9631")
+	fs.String(StaticPeerIDsKey, "", fmt.Sprintf("Comma separated list of the ids of the peers in --%s, in the same order. Example: NodeID-JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,NodeID-8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z", StaticPeerIPsKey))
 
 	// Peer alias configuration
 	fs.Duration(PeerAliasTimeoutKey, 10*time.Minute, "How often the node will attempt to connect "+
@@ -148,6 +165,16 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(BenchlistPeerSummaryEnabledKey, false, "Enables peer specific query latency metrics.")
 	fs.Duration(BenchlistDurationKey, 30*time.Minute, "Max amount of time a peer is benchlisted after surpassing the threshold.")
 	fs.Duration(BenchlistMinFailingDurationKey, 5*time.Minute, "Minimum amount of time messages to a peer must be failing before the peer is benched.")
+	fs.Float64(BenchlistMinPeerScoreKey, 0.5, "Benched peers whose score is below this value are benched for the full benchlist duration. Must be in [0, 1].")
+
+	// Peer scores
+	fs.Duration(PeerScoreHalflifeKey, 5*time.Minute, "Halflife of the averages that peer scores are calculated from.")
+	fs.Duration(PeerScoreMaxLatencyKey, 5*time.Second, "Average response latency at which a peer receives no credit for its latency.")
+	fs.Float64(PeerScoreMaxInvalidMessagesKey, 10, "Number of recent invalid messages at which a peer receives no credit for its messages.")
+	fs.Float64(PeerScoreMaxCPUUtilizationKey, 0.25, "Portion of this node's message handling time at which a peer receives no credit for its CPU usage.")
+	fs.Float64(PeerScoreMinSamplingFactorKey, 0.5, "Minimum factor a validator's weight is scaled by, based on its score, when sampling validators to query. Must be in (0, 1].")
+	fs.Int(PeerScoreMaxPeersKey, 10000, "Maximum number of peers whose scores are kept. Once exceeded, the peer observed least recently is forgotten.")
+	fs.Duration(PeerScoreExpiryKey, 7*24*time.Hour, "Scores of peers that haven't been observed for this long are forgotten. If 0, scores don't expire.")
 
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers.")
@@ -159,11 +186,19 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(InboundThrottlerAtLargeAllocSizeKey, 32*units.MiB, "Size, in bytes, of at-large byte allocation in inbound message throttler.")
 	fs.Uint64(InboundThrottlerVdrAllocSizeKey, 32*units.MiB, "Size, in bytes, of validator byte allocation in inbound message throttler.")
 	fs.Uint64(InboundThrottlerNodeMaxAtLargeBytesKey, 2*uint64(network.DefaultMaxMessageSize), "Max number of bytes a node can take from the inbound message throttler's at-large allocation.")
+	fs.Uint64(InboundBandwidthRefillRateKey, 0, "Max average number of bytes per second received from all peers. If 0, not limited.")
+	fs.Uint64(InboundBandwidthMaxBurstSizeKey, 2*uint64(network.DefaultMaxMessageSize), "Max number of bytes received from all peers in a burst.")
+	fs.Uint64(InboundNodeBandwidthRefillRateKey, 0, "Max average number of bytes per second received from a node. If 0, not limited.")
+	fs.Uint64(InboundNodeBandwidthMaxBurstSizeKey, uint64(network.DefaultMaxMessageSize), "Max number of bytes received from a node in a burst.")
 
 	// Outbound Throttling
 	fs.Uint64(OutboundThrottlerAtLargeAllocSizeKey, 32*units.MiB, "Size, in bytes, of at-large byte allocation in outbound message throttler.")
 	fs.Uint64(OutboundThrottlerVdrAllocSizeKey, 32*units.MiB, "Size, in bytes, of validator byte allocation in outbound message throttler.")
 	fs.Uint64(OutboundThrottlerNodeMaxAtLargeBytesKey, 2*uint64(network.DefaultMaxMessageSize), "Max number of bytes a node can take from the outbound message throttler's at-large allocation.")
+	fs.Uint64(OutboundBandwidthRefillRateKey, 0, "Max average number of bytes per second sent to all peers. If 0, not limited. Gossip is dropped before other messages when the limit is reached.")
+	fs.Uint64(OutboundBandwidthMaxBurstSizeKey, 2*uint64(network.DefaultMaxMessageSize), "Max number of bytes sent to all peers in a burst.")
+	fs.Uint64(OutboundNodeBandwidthRefillRateKey, 0, "Max average number of bytes per second sent to a node. If 0, not limited. Gossip is dropped before other messages when the limit is reached.")
+	fs.Uint64(OutboundNodeBandwidthMaxBurstSizeKey, uint64(network.DefaultMaxMessageSize), "Max number of bytes sent to a node in a burst.")
 
 	// HTTP APIs
 	fs.String(HTTPHostKey, "127.0.0.1", "Address of the HTTP server")
@@ -171,13 +206,28 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(HTTPSEnabledKey, false, "Upgrade the HTTP server to HTTPs")
 	fs.String(HTTPSKeyFileKey, "", "TLS private key file for the HTTPs server")
 	fs.String(HTTPSCertFileKey, "", "TLS certificate file for the HTTPs server")
+	fs.String(HTTPSClientCAFileKey, "", "File of PEM encoded CA certificates that signs the TLS certificates that clients of the HTTPs server may present. If empty, client certificates aren't requested")
+	fs.Bool(HTTPSClientCertRequiredKey, false, "If true, clients of the HTTPs server must present a TLS certificate signed by a CA in the client CA file")
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
+	fs.Int(APIMaxBatchSizeKey, 100, "Maximum number of requests in a JSON-RPC batch request to the HTTP APIs. If 0, batch requests aren't supported")
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
 	fs.String(APIAuthPasswordFileKey, "", "Password file used to initially create/validate API authorization tokens. Leading and trailing whitespace is removed from the password. Can be changed via API call.")
+	fs.String(APIAuthClientCertsFileKey, "", "JSON file mapping the subjects or SHA-256 fingerprints of client TLS certificates to the endpoints and methods they may access without an authorization token")
+	fs.Bool(APIAuditEnabledKey, false, "If true, calls to sensitive API methods are recorded in the audit log")
+	fs.String(APIAuditMethodsKey, strings.Join(audit.DefaultMethods, " "), "Space separated list of patterns of the API methods that are recorded in the audit log. e.g. keystore.* or avm.exportKey")
+	fs.Float64(APIRateLimitIPRateKey, 0, "Cost of API requests that each remote IP may spend per second. If 0, API requests aren't limited per remote IP")
+	fs.Int(APIRateLimitIPBurstKey, 100, "Maximum cost of API requests that each remote IP may spend at once")
+	fs.Float64(APIRateLimitTokenRateKey, 0, "Cost of API requests that each authenticated client, identified by its authorization token or client certificate, may spend per second. If 0, API requests aren't limited per authenticated client")
+	fs.Int(APIRateLimitTokenBurstKey, 100, "Maximum cost of API requests that each authenticated client may spend at once")
+	fs.String(APIRateLimitMethodCostsKey, "avm.getUTXOs=10 platform.getUTXOs=10 index.getContainerRange=10", fmt.Sprintf("Space separated list of method=cost pairs giving the cost of calling API methods. Methods that aren't listed cost %d", server.DefaultMethodCost))
+	fs.Int(APIRateLimitMaxClientsKey, 10000, "Maximum number of remote IPs and of authenticated clients whose API request costs are tracked")
 	// Enable/Disable APIs
 	fs.Bool(AdminAPIEnabledKey, false, "If true, this node exposes the Admin API")
 	fs.Bool(InfoAPIEnabledKey, true, "If true, this node exposes the Info API")
 	fs.Bool(KeystoreAPIEnabledKey, true, "If true, this node exposes the Keystore API")
+	fs.Uint(KeystoreKDFTimeKey, uint(password.DefaultKDFParams.Time), "Number of passes the Argon2id function that keystore keys and password hashes are derived with makes over its memory")
+	fs.Uint(KeystoreKDFMemoryKey, uint(password.DefaultKDFParams.Memory), "Memory, in KiB, that the Argon2id function that keystore keys and password hashes are derived with uses")
+	fs.Uint(KeystoreKDFThreadsKey, uint(password.DefaultKDFParams.Threads), "Number of threads that the Argon2id function that keystore keys and password hashes are derived with uses")
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
@@ -251,6 +301,8 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// IPC
 	fs.String(IpcsChainIDsKey, "", "Comma separated list of chain ids to add to the IPC engine. Example: 11111111111111111111111111111111LpoYY,4R5p2RXDGLqaifZE4hHWH9owe34pfoBULn1DrQTWivjg8o4aH")
 	fs.String(IpcsPathKey, "", "The directory (Unix) or named pipe name prefix (Windows) for IPC sockets")
+	fs.String(IpcsTransportKey, string(ipcs.UnixTransport), "Transport that IPC events are published over. One of unix, tcp or file")
+	fs.String(IpcsTCPHostKey, "127.0.0.1", "Host that IPC TCP sockets listen on. Only used if the IPC transport is tcp")
 
 	// Indexer
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
@@ -265,6 +317,13 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(ProfileContinuousFreqKey, 15*time.Minute, "How frequently to rotate performance profiles")
 	fs.Int(ProfileContinuousMaxFilesKey, 5, "Maximum number of historical profiles to keep")
 	fs.String(VMAliasesFileKey, defaultVMAliasFilePath, "Specifies a JSON file that maps vmIDs with custom aliases.")
+
+	// VM restarts
+	fs.Bool(VMRestartEnabledKey, false, "If true, a chain whose VM runs as a plugin is restarted when the plugin's process exits. Otherwise, the chain is shut down")
+	fs.Int(VMRestartMaxAttemptsKey, 5, "Max number of times in a row a chain's VM is restarted. Restarts after which the VM ran for 10 minutes aren't counted")
+	fs.Duration(VMRestartBackoffKey, time.Second, "Time to wait before the first restart of a chain's VM. The wait doubles after each restart")
+	fs.Duration(VMRestartMaxBackoffKey, time.Minute, "Max time to wait before restarting a chain's VM")
+	fs.String(PluginCgroupDirKey, "", "Path to the cgroups v2 cgroup that a cgroup is created under for each plugin with memory or CPU limits. It must be delegated to the node, and must not contain processes. Required if a plugin has memory or CPU limits")
 }
 
 // BuildFlagSet returns a complete set of flags for avalanchego
@@ -23,6 +23,10 @@ const (
 	SignatureVerificationEnabledKey           = "signature-verification-enabled"
 	DBTypeKey                                 = "db-type"
 	DBPathKey                                 = "db-dir"
+	DBCopyLevelDBKey                          = "db-copy-leveldb"
+	SnapshotExportDirKey                      = "snapshot-export-dir"
+	SnapshotImportDirKey                      = "snapshot-import-dir"
+	VerifyDatabaseKey                         = "verify-database"
 	PublicIPKey                               = "public-ip"
 	DynamicUpdateDurationKey                  = "dynamic-update-duration"
 	DynamicPublicIPResolverKey                = "dynamic-public-ip"
@@ -35,9 +39,21 @@ const (
 	HTTPSEnabledKey                           = "http-tls-enabled"
 	HTTPSKeyFileKey                           = "http-tls-key-file"
 	HTTPSCertFileKey                          = "http-tls-cert-file"
+	HTTPSClientCAFileKey                      = "http-tls-client-ca-file"
+	HTTPSClientCertRequiredKey                = "http-tls-client-cert-required"
 	HTTPAllowedOrigins                        = "http-allowed-origins"
+	APIMaxBatchSizeKey                        = "api-max-batch-size"
 	APIAuthRequiredKey                        = "api-auth-required"
 	APIAuthPasswordFileKey                    = "api-auth-password-file" // #nosec G101
+	APIAuthClientCertsFileKey                 = "api-auth-client-certs-file"
+	APIAuditEnabledKey                        = "api-audit-enabled"
+	APIAuditMethodsKey                        = "api-audit-methods"
+	APIRateLimitIPRateKey                     = "api-rate-limit-ip-rate"
+	APIRateLimitIPBurstKey                    = "api-rate-limit-ip-burst"
+	APIRateLimitTokenRateKey                  = "api-rate-limit-token-rate"
+	APIRateLimitTokenBurstKey                 = "api-rate-limit-token-burst"
+	APIRateLimitMethodCostsKey                = "api-rate-limit-method-costs"
+	APIRateLimitMaxClientsKey                 = "api-rate-limit-max-clients"
 	BootstrapIPsKey                           = "bootstrap-ips"
 	BootstrapIDsKey                           = "bootstrap-ids"
 	StakingPortKey                            = "staking-port"
@@ -63,10 +79,24 @@ const (
 	NetworkPeerListGossipSizeKey              = "network-peer-list-gossip-size"
 	NetworkPeerListGossipFreqKey              = "network-peer-list-gossip-frequency"
 	NetworkCompressionEnabledKey              = "network-compression-enabled"
+	NetworkCompressionTypeKey                 = "network-compression-type"
+	NetworkCompressionDictionaryFileKey       = "network-compression-dictionary-file"
+	NetworkPrivateModeKey                     = "network-private-mode"
+	NetworkRelayedNodeIDsKey                  = "network-relayed-node-ids"
+	StaticPeerIPsKey                          = "static-peer-ips"
+	StaticPeerIDsKey                          = "static-peer-ids"
 	BenchlistFailThresholdKey                 = "benchlist-fail-threshold"
 	BenchlistPeerSummaryEnabledKey            = "benchlist-peer-summary-enabled"
 	BenchlistDurationKey                      = "benchlist-duration"
 	BenchlistMinFailingDurationKey            = "benchlist-min-failing-duration"
+	BenchlistMinPeerScoreKey                  = "benchlist-min-peer-score"
+	PeerScoreHalflifeKey                      = "peer-score-halflife"
+	PeerScoreMaxLatencyKey                    = "peer-score-max-latency"
+	PeerScoreMaxInvalidMessagesKey            = "peer-score-max-invalid-messages"
+	PeerScoreMaxCPUUtilizationKey             = "peer-score-max-cpu-utilization"
+	PeerScoreMinSamplingFactorKey             = "peer-score-min-sampling-factor"
+	PeerScoreMaxPeersKey                      = "peer-score-max-peers"
+	PeerScoreExpiryKey                        = "peer-score-expiry"
 	BuildDirKey                               = "build-dir"
 	LogsDirKey                                = "log-dir"
 	LogLevelKey                               = "log-level"
@@ -88,11 +118,16 @@ const (
 	AdminAPIEnabledKey                        = "api-admin-enabled"
 	InfoAPIEnabledKey                         = "api-info-enabled"
 	KeystoreAPIEnabledKey                     = "api-keystore-enabled"
+	KeystoreKDFTimeKey                        = "keystore-kdf-time"
+	KeystoreKDFMemoryKey                      = "keystore-kdf-memory"
+	KeystoreKDFThreadsKey                     = "keystore-kdf-threads"
 	MetricsAPIEnabledKey                      = "api-metrics-enabled"
 	HealthAPIEnabledKey                       = "api-health-enabled"
 	IpcAPIEnabledKey                          = "api-ipcs-enabled"
 	IpcsChainIDsKey                           = "ipcs-chain-ids"
 	IpcsPathKey                               = "ipcs-path"
+	IpcsTransportKey                          = "ipcs-transport"
+	IpcsTCPHostKey                            = "ipcs-tcp-host"
 	MeterVMsEnabledKey                        = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey               = "consensus-gossip-frequency"
 	ConsensusGossipAcceptedFrontierSizeKey    = "consensus-accepted-frontier-gossip-size"
@@ -125,5 +160,18 @@ const (
 	OutboundThrottlerAtLargeAllocSizeKey      = "throttler-outbound-at-large-alloc-size"
 	OutboundThrottlerVdrAllocSizeKey          = "throttler-outbound-validator-alloc-size"
 	OutboundThrottlerNodeMaxAtLargeBytesKey   = "throttler-outbound-node-max-at-large-bytes"
+	InboundBandwidthRefillRateKey             = "throttler-inbound-bandwidth-refill-rate"
+	InboundBandwidthMaxBurstSizeKey           = "throttler-inbound-bandwidth-max-burst-size"
+	InboundNodeBandwidthRefillRateKey         = "throttler-inbound-node-bandwidth-refill-rate"
+	InboundNodeBandwidthMaxBurstSizeKey       = "throttler-inbound-node-bandwidth-max-burst-size"
+	OutboundBandwidthRefillRateKey            = "throttler-outbound-bandwidth-refill-rate"
+	OutboundBandwidthMaxBurstSizeKey          = "throttler-outbound-bandwidth-max-burst-size"
+	OutboundNodeBandwidthRefillRateKey        = "throttler-outbound-node-bandwidth-refill-rate"
+	OutboundNodeBandwidthMaxBurstSizeKey      = "throttler-outbound-node-bandwidth-max-burst-size"
 	VMAliasesFileKey                          = "vm-aliases-file"
+	VMRestartEnabledKey                       = "vm-restart-enabled"
+	VMRestartMaxAttemptsKey                   = "vm-restart-max-attempts"
+	VMRestartBackoffKey                       = "vm-restart-backoff"
+	VMRestartMaxBackoffKey                    = "vm-restart-max-backoff"
+	PluginCgroupDirKey                        = "plugin-cgroup-dir"
 )
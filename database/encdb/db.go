@@ -20,6 +20,9 @@ import (
 
 const (
 	codecVersion = 0
+
+	// KeySize is the size, in bytes, of the keys values are encrypted with
+	KeySize = chacha20poly1305.KeySize
 )
 
 var (
@@ -36,10 +39,15 @@ type Database struct {
 	db     database.Database
 }
 
-// New returns a new encrypted database
+// New returns a new encrypted database whose key is the hash of [password]
 func New(password []byte, db database.Database) (*Database, error) {
-	h := hashing.ComputeHash256(password)
-	aead, err := chacha20poly1305.NewX(h)
+	return NewWithKey(hashing.ComputeHash256(password), db)
+}
+
+// NewWithKey returns a new encrypted database that encrypts values with [key],
+// which must be KeySize bytes long
+func NewWithKey(key []byte, db database.Database) (*Database, error) {
+	aead, err := chacha20poly1305.NewX(key)
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +172,9 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(start, limit)
 }
 
-// Close implements the Database interface
+// Close implements the Database interface. The database that values are
+// encrypted into is closed as well, so that its owner is told that the key
+// is no longer in use.
 func (db *Database) Close() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -172,8 +182,9 @@ func (db *Database) Close() error {
 	if db.db == nil {
 		return database.ErrClosed
 	}
+	err := db.db.Close()
 	db.db = nil
-	return nil
+	return err
 }
 
 type keyValue struct {
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/meterdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/database/rocksdb"
 	"github.com/ava-labs/avalanchego/utils"
@@ -111,6 +112,25 @@ func NewLevelDB(
 	)
 }
 
+// NewPebble creates a database manager of pebble databases at [filePath] by
+// creating a database instance from each directory with a version <=
+// [currentVersion]. If [includePreviousVersions], opens previous database
+// versions and includes them in the returned Manager.
+func NewPebble(
+	dbDirPath string,
+	log logging.Logger,
+	currentVersion version.Version,
+	includePreviousVersions bool,
+) (Manager, error) {
+	return new(
+		pebble.New,
+		dbDirPath,
+		log,
+		currentVersion,
+		includePreviousVersions,
+	)
+}
+
 // new creates a database manager at [filePath] by creating a database instance
 // from each directory with a version <= [currentVersion]. If
 // [includePreviousVersions], opens previous database versions and includes them
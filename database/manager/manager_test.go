@@ -408,3 +408,46 @@ func TestDoesNotIncludePreviousVersions(t *testing.T) {
 	_, err = NewLevelDB(dir, logging.NoLog{}, v2, true)
 	assert.Error(t, err, "should error because trying to open open database (1.1.0)")
 }
+
+func TestCopyLevelDBToPebble(t *testing.T) {
+	assert := assert.New(t)
+
+	levelDBDir := t.TempDir()
+	pebbleDir := t.TempDir()
+
+	v1 := version.DefaultVersion1_0_0
+	v2 := version.NewDefaultVersion(1, 1, 0)
+
+	levelDBManager, err := NewLevelDB(levelDBDir, logging.NoLog{}, v1, true)
+	assert.NoError(err)
+	assert.NoError(levelDBManager.Current().Database.Put([]byte{1}, []byte{2}))
+	assert.NoError(levelDBManager.Close())
+
+	levelDBManager, err = NewLevelDB(levelDBDir, logging.NoLog{}, v2, true)
+	assert.NoError(err)
+	assert.NoError(levelDBManager.Current().Database.Put([]byte{3}, []byte{4}))
+	assert.NoError(levelDBManager.Close())
+
+	err = CopyLevelDBToPebble(levelDBDir, pebbleDir, logging.NoLog{}, v2)
+	assert.NoError(err)
+
+	pebbleManager, err := NewPebble(pebbleDir, logging.NoLog{}, v2, true)
+	assert.NoError(err)
+
+	dbs := pebbleManager.GetDatabases()
+	assert.Len(dbs, 2)
+
+	value, err := dbs[0].Database.Get([]byte{3})
+	assert.NoError(err)
+	assert.Equal([]byte{4}, value)
+
+	value, err = dbs[1].Database.Get([]byte{1})
+	assert.NoError(err)
+	assert.Equal([]byte{2}, value)
+
+	assert.NoError(pebbleManager.Close())
+
+	// Copying again should skip the existing databases
+	err = CopyLevelDBToPebble(levelDBDir, pebbleDir, logging.NoLog{}, v2)
+	assert.NoError(err)
+}
@@ -0,0 +1,103 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebble"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	copyBatchSize = 4 * units.MiB
+
+	incompleteCopySuffix = ".incomplete"
+)
+
+// CopyLevelDBToPebble copies each of the LevelDB databases in
+// [levelDBDirPath] with a version <= [currentVersion] into a pebble database
+// of the same version in [pebbleDirPath].
+//
+// Versions that already exist in [pebbleDirPath] are skipped. Each database is
+// copied into a temporary directory that is only renamed once the copy has
+// finished, so an interrupted copy is restarted from scratch the next time
+// this is called.
+func CopyLevelDBToPebble(
+	levelDBDirPath string,
+	pebbleDirPath string,
+	log logging.Logger,
+	currentVersion version.Version,
+) error {
+	levelDBManager, err := NewLevelDB(levelDBDirPath, log, currentVersion, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := levelDBManager.Close(); err != nil {
+			log.Error("failed to close the leveldb databases: %s", err)
+		}
+	}()
+
+	for _, db := range levelDBManager.GetDatabases() {
+		dstPath := filepath.Join(pebbleDirPath, db.Version.String())
+		if _, err := os.Stat(dstPath); err == nil {
+			log.Info("skipping copy of database version %s as it already exists at %s", db.Version, dstPath)
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		log.Info("copying leveldb database version %s to %s", db.Version, dstPath)
+		tmpPath := dstPath + incompleteCopySuffix
+		if err := os.RemoveAll(tmpPath); err != nil {
+			return err
+		}
+		dst, err := pebble.New(tmpPath, log)
+		if err != nil {
+			return fmt.Errorf("couldn't create db at %s: %w", tmpPath, err)
+		}
+		copyErr := copyDatabase(dst, db.Database)
+		if err := dst.Close(); err != nil && copyErr == nil {
+			copyErr = err
+		}
+		if copyErr != nil {
+			return fmt.Errorf("couldn't copy database version %s: %w", db.Version, copyErr)
+		}
+		if err := os.Rename(tmpPath, dstPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyDatabase writes every key/value pair in [src] into [dst]
+func copyDatabase(dst, src database.Database) error {
+	batch := dst.NewBatch()
+	it := src.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		if batch.Size() < copyBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
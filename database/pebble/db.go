@@ -0,0 +1,495 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "pebble"
+
+	// MetricsStat is the property passed to Stat to get a summary of the
+	// database's internal metrics.
+	MetricsStat = "pebble.metrics"
+
+	// BlockCacheSize is the number of bytes to use for block caching in
+	// pebble.
+	BlockCacheSize = 12 * units.MiB
+
+	// MemTableSize is the number of bytes to use for each memtable in pebble.
+	MemTableSize = 6 * units.MiB
+
+	// HandleCap is the number of files descriptors to cap pebble to use.
+	HandleCap = 64
+
+	// BitsPerKey is the number of bits to add to the bloom filter per key.
+	BitsPerKey = 10
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+	_ database.Iterator = &iter{}
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace
+// in binary-alphabetical order.
+type Database struct {
+	lock sync.RWMutex
+	db   *pebble.DB
+	log  logging.Logger
+
+	// closed is set once Close is called. The underlying database is only
+	// closed once all of the iterators opened on it are released, so that
+	// iterators may continue to be used after Close.
+	closed bool
+	// number of iterators that haven't been released yet
+	openIterators int
+
+	// 1 if there was previously an error other than "not found" or "closed"
+	// while performing a db operation. If [errored] == 1, Has, Get, Put,
+	// Delete and batch writes fail with ErrAvoidCorruption.
+	errored uint64
+}
+
+// New returns a wrapped Pebble object.
+func New(file string, log logging.Logger) (database.Database, error) {
+	cache := pebble.NewCache(BlockCacheSize)
+	defer cache.Unref()
+
+	db, err := pebble.Open(file, &pebble.Options{
+		Cache:        cache,
+		MemTableSize: MemTableSize,
+		MaxOpenFiles: HandleCap,
+		Levels: []pebble.LevelOptions{{
+			FilterPolicy: bloom.FilterPolicy(BitsPerKey),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		db:  db,
+		log: log,
+	}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	_, err := db.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return nil, database.ErrClosed
+	case db.corrupted():
+		return nil, database.ErrAvoidCorruption
+	}
+
+	value, closer, err := db.db.Get(key)
+	if err != nil {
+		return nil, db.handleError(err)
+	}
+	value = utils.CopyBytes(value)
+	return value, db.handleError(closer.Close())
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return database.ErrClosed
+	case db.corrupted():
+		return database.ErrAvoidCorruption
+	}
+	return db.handleError(db.db.Set(key, value, pebble.Sync))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return database.ErrClosed
+	case db.corrupted():
+		return database.ErrAvoidCorruption
+	}
+	return db.handleError(db.db.Delete(key, pebble.Sync))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.newIterator(&pebble.IterOptions{})
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.newIterator(&pebble.IterOptions{
+		LowerBound: utils.CopyBytes(start),
+	})
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.newIterator(&pebble.IterOptions{
+		LowerBound: utils.CopyBytes(prefix),
+		UpperBound: prefixUpperBound(prefix),
+	})
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	lowerBound := prefix
+	if bytes.Compare(start, prefix) == 1 {
+		lowerBound = start
+	}
+	return db.newIterator(&pebble.IterOptions{
+		LowerBound: utils.CopyBytes(lowerBound),
+		UpperBound: prefixUpperBound(prefix),
+	})
+}
+
+func (db *Database) newIterator(opts *pebble.IterOptions) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	switch {
+	case db.closed:
+		return &nodb.Iterator{Err: database.ErrClosed}
+	case db.corrupted():
+		return &nodb.Iterator{Err: database.ErrAvoidCorruption}
+	}
+
+	db.openIterators++
+	return &iter{
+		db:   db,
+		iter: db.db.NewIter(opts),
+	}
+}
+
+// Stat returns a particular internal stat of the database. The only supported
+// property is MetricsStat.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return "", database.ErrClosed
+	case property != MetricsStat:
+		return "", database.ErrNotFound
+	}
+	return db.db.Metrics().String(), nil
+}
+
+// Compact the underlying DB for the given key range.
+// Specifically, deleted and overwritten versions are discarded,
+// and the data is rearranged to reduce the cost of operations
+// needed to access the data. This operation should typically only
+// be invoked by users who understand the underlying implementation.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	switch {
+	case db.closed:
+		return database.ErrClosed
+	case db.corrupted():
+		return database.ErrAvoidCorruption
+	}
+
+	if limit == nil {
+		// Pebble requires an exclusive upper bound, so compact up to just after
+		// the last key in the database.
+		it := db.db.NewIter(&pebble.IterOptions{})
+		if it.Last() {
+			limit = append(utils.CopyBytes(it.Key()), 0)
+		}
+		if err := it.Close(); err != nil {
+			return db.handleError(err)
+		}
+	}
+	if bytes.Compare(start, limit) >= 0 {
+		// There is nothing to compact in an empty range.
+		return nil
+	}
+	return db.handleError(db.db.Compact(start, limit))
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	if db.openIterators > 0 {
+		// The last iterator to be released will close the database.
+		return nil
+	}
+	return db.db.Close()
+}
+
+// releaseIterator must be called exactly once for each iterator created by
+// newIterator.
+func (db *Database) releaseIterator(it *pebble.Iterator) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	err := it.Close()
+	db.openIterators--
+	if db.closed && db.openIterators == 0 {
+		if closeErr := db.db.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (db *Database) corrupted() bool {
+	return atomic.LoadUint64(&db.errored) == 1
+}
+
+func (db *Database) handleError(err error) error {
+	err = updateError(err)
+	switch err {
+	case nil, database.ErrNotFound, database.ErrClosed:
+	// If we get an error other than "not found" or "closed", disallow future
+	// database operations to avoid possible corruption
+	default:
+		db.log.Fatal("pebble error: %s", err)
+		atomic.StoreUint64(&db.errored, 1)
+	}
+	return err
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers writes so that it can be written more than once. A pebble
+// batch can only be applied a single time.
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return nil
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	b.size += len(key) + pebbleByteOverhead
+	return nil
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	switch {
+	case b.db.closed:
+		return database.ErrClosed
+	case b.db.corrupted():
+		return database.ErrAvoidCorruption
+	}
+
+	pebbleBatch := b.db.db.NewBatch()
+	for _, kv := range b.writes {
+		var err error
+		if kv.delete {
+			err = pebbleBatch.Delete(kv.key, nil)
+		} else {
+			err = pebbleBatch.Set(kv.key, kv.value, nil)
+		}
+		if err != nil {
+			_ = pebbleBatch.Close()
+			return err
+		}
+	}
+	err := pebbleBatch.Commit(pebble.Sync)
+	if closeErr := pebbleBatch.Close(); err == nil {
+		err = closeErr
+	}
+	return b.db.handleError(err)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+type iter struct {
+	db   *Database
+	iter *pebble.Iterator
+
+	started  bool
+	released bool
+	key      []byte
+	value    []byte
+	err      error
+}
+
+// Next implements the Iterator interface
+func (it *iter) Next() bool {
+	if it.released {
+		it.key = nil
+		it.value = nil
+		return false
+	}
+
+	var valid bool
+	if it.started {
+		valid = it.iter.Next()
+	} else {
+		valid = it.iter.First()
+	}
+	it.started = true
+
+	if !valid {
+		it.key = nil
+		it.value = nil
+		return false
+	}
+	it.key = utils.CopyBytes(it.iter.Key())
+	it.value = utils.CopyBytes(it.iter.Value())
+	return true
+}
+
+// Error implements the Iterator interface
+func (it *iter) Error() error {
+	if it.released {
+		return it.err
+	}
+	return updateError(it.iter.Error())
+}
+
+// Key implements the Iterator interface
+func (it *iter) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iter) Value() []byte { return it.value }
+
+// Release implements the Iterator interface
+func (it *iter) Release() {
+	if it.released {
+		return
+	}
+	it.released = true
+	it.key = nil
+	it.value = nil
+	it.err = updateError(it.db.releaseIterator(it.iter))
+}
+
+// prefixUpperBound returns the smallest key that is larger than every key with
+// the provided prefix. Returns nil if there is no such key.
+func prefixUpperBound(prefix []byte) []byte {
+	upperBound := utils.CopyBytes(prefix)
+	for i := len(upperBound) - 1; i >= 0; i-- {
+		upperBound[i]++
+		if upperBound[i] != 0 {
+			return upperBound[:i+1]
+		}
+	}
+	return nil
+}
+
+func updateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pebble.ErrClosed):
+		return database.ErrClosed
+	case errors.Is(err, pebble.ErrNotFound):
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,174 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var errReservedPartition = errors.New("partition name is reserved")
+
+// Export writes a snapshot of [db] into [dir].
+//
+// [prefixes] maps partition names to the prefixes that were passed to
+// prefixdb.New when the partition was created, such as a chain's ID. Every
+// key under one of these prefixes is written to that partition's file. Keys
+// under one of the [excluded] prefixes, such as the keystore's, aren't
+// written. All other keys are written to the remainder partition.
+//
+// [db] must not be written to while the export is running.
+func Export(db database.Database, dir string, header Header, prefixes map[string][]byte, excluded [][]byte) (*Manifest, error) {
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return nil, err
+	}
+
+	writers := make(map[string]*partitionWriter, len(prefixes)+1)
+	byPrefix := make(map[[hashing.HashLen]byte]*partitionWriter, len(prefixes))
+	defer func() {
+		for _, w := range writers {
+			_ = w.file.Close()
+		}
+	}()
+
+	remainder, err := newPartitionWriter(dir, RemainderPartition, nil)
+	if err != nil {
+		return nil, err
+	}
+	writers[RemainderPartition] = remainder
+	for name, rawPrefix := range prefixes {
+		if _, exists := writers[name]; exists {
+			return nil, fmt.Errorf("%w: %s", errReservedPartition, name)
+		}
+		if err := verifyPartitionName(name); err != nil {
+			return nil, err
+		}
+		w, err := newPartitionWriter(dir, name, hashing.ComputeHash256(rawPrefix))
+		if err != nil {
+			return nil, err
+		}
+		writers[name] = w
+		byPrefix[hashing.ComputeHash256Array(rawPrefix)] = w
+	}
+	excludedPrefixes := make(map[[hashing.HashLen]byte]struct{}, len(excluded))
+	for _, rawPrefix := range excluded {
+		excludedPrefixes[hashing.ComputeHash256Array(rawPrefix)] = struct{}{}
+	}
+
+	it := db.NewIterator()
+	for it.Next() {
+		key := it.Key()
+		if bytes.Equal(key, importedKey) || bytes.Equal(key, importingKey) {
+			continue
+		}
+		w := remainder
+		if len(key) >= hashing.HashLen {
+			var prefix [hashing.HashLen]byte
+			copy(prefix[:], key)
+			if _, ok := excludedPrefixes[prefix]; ok {
+				continue
+			}
+			if prefixWriter, ok := byPrefix[prefix]; ok {
+				w = prefixWriter
+			}
+		}
+		if err := w.write(key, it.Value()); err != nil {
+			it.Release()
+			return nil, err
+		}
+	}
+	err = it.Error()
+	it.Release()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't iterate over the database: %w", err)
+	}
+
+	manifest := &Manifest{
+		Format:          FormatVersion,
+		DatabaseVersion: header.DatabaseVersion.String(),
+		GenesisID:       header.GenesisID,
+	}
+	for name, w := range writers {
+		partition, err := w.finish(name)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Partitions = append(manifest.Partitions, partition)
+	}
+	sortPartitions(manifest.Partitions)
+	return manifest, writeManifest(dir, manifest)
+}
+
+type partitionWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	hasher  hash.Hash
+	prefix  []byte
+	entries uint64
+	lenBuf  [wrappers.IntLen]byte
+}
+
+func newPartitionWriter(dir, name string, prefix []byte) (*partitionWriter, error) {
+	file, err := perms.Create(partitionPath(dir, name), perms.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+	hasher := sha256.New()
+	return &partitionWriter{
+		file:   file,
+		buf:    bufio.NewWriter(io.MultiWriter(file, hasher)),
+		hasher: hasher,
+		prefix: prefix,
+	}, nil
+}
+
+// write appends the key/value pair to the partition. Each of the key and the
+// value is written as a big endian uint32 length followed by its bytes.
+func (w *partitionWriter) write(key, value []byte) error {
+	if err := w.writeBytes(key); err != nil {
+		return err
+	}
+	if err := w.writeBytes(value); err != nil {
+		return err
+	}
+	w.entries++
+	return nil
+}
+
+func (w *partitionWriter) writeBytes(b []byte) error {
+	binary.BigEndian.PutUint32(w.lenBuf[:], uint32(len(b)))
+	if _, err := w.buf.Write(w.lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := w.buf.Write(b)
+	return err
+}
+
+func (w *partitionWriter) finish(name string) (Partition, error) {
+	if err := w.buf.Flush(); err != nil {
+		return Partition{}, err
+	}
+	if err := w.file.Sync(); err != nil {
+		return Partition{}, err
+	}
+	return Partition{
+		Name:     name,
+		Prefix:   hex.EncodeToString(w.prefix),
+		Entries:  w.entries,
+		Checksum: hex.EncodeToString(w.hasher.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,263 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	importBatchSize = 4 * units.MiB
+
+	// maxEntrySize is the largest key or value that is read from a snapshot.
+	// It's far larger than anything the node writes, but keeps a corrupted
+	// length from causing a huge allocation before the checksum is verified.
+	maxEntrySize = 64 * units.MiB
+)
+
+var (
+	// importedKey is put into a database once a snapshot has been imported
+	// into it
+	importedKey = []byte("snapshot imported")
+	// importingKey is put into a database before a snapshot is written into
+	// it, and deleted once the snapshot has been imported. If it's present,
+	// the database only holds part of a snapshot.
+	importingKey = []byte("snapshot importing")
+
+	errDatabaseNotEmpty = errors.New("database must be empty to import a snapshot")
+	errEntryTooLarge    = errors.New("snapshot entry is too large")
+	errWrongChecksum    = errors.New("snapshot partition checksum mismatch")
+	errWrongEntries     = errors.New("snapshot partition entry count mismatch")
+	errWrongPrefix      = errors.New("snapshot key is outside of its partition")
+)
+
+// Verify checks the snapshot in [dir] against [header] and checks every
+// partition against its checksum, without writing anything.
+func Verify(dir string, header Header) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.Verify(header); err != nil {
+		return nil, err
+	}
+	for _, partition := range manifest.Partitions {
+		if err := readPartition(dir, partition, nil); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// Imported returns true if a snapshot has been imported into [db]
+func Imported(db database.KeyValueReader) (bool, error) {
+	return db.Has(importedKey)
+}
+
+// Interrupted returns true if an import of a snapshot into [db] was started
+// but didn't finish, in which case [db] only holds part of the snapshot and
+// must not be used until the import is retried.
+func Interrupted(db database.KeyValueReader) (bool, error) {
+	return db.Has(importingKey)
+}
+
+// Import verifies the snapshot in [dir] against [header] and then writes its
+// contents into [db]. [db] must be empty, or hold the part of a snapshot
+// written by an interrupted import, which is deleted first. Once the import is
+// done, Imported returns true for [db].
+func Import(db database.Database, dir string, header Header) (*Manifest, error) {
+	interrupted, err := Interrupted(db)
+	if err != nil {
+		return nil, err
+	}
+	if !interrupted {
+		it := db.NewIterator()
+		hasKeys := it.Next()
+		err := it.Error()
+		it.Release()
+		switch {
+		case err != nil:
+			return nil, err
+		case hasKeys:
+			return nil, errDatabaseNotEmpty
+		}
+	}
+
+	// All of the partitions are verified before anything is written so that a
+	// corrupted snapshot doesn't leave a partially populated database behind.
+	manifest, err := Verify(dir, header)
+	if err != nil {
+		return nil, err
+	}
+
+	// The marker is written before anything else so that an import that is
+	// interrupted is detected, and it's kept while the partially written
+	// snapshot is deleted.
+	if err := db.Put(importingKey, nil); err != nil {
+		return nil, err
+	}
+	if interrupted {
+		if err := clear(db); err != nil {
+			return nil, err
+		}
+	}
+	for _, partition := range manifest.Partitions {
+		batch := db.NewBatch()
+		err := readPartition(dir, partition, func(key, value []byte) error {
+			if err := batch.Put(key, value); err != nil {
+				return err
+			}
+			if batch.Size() < importBatchSize {
+				return nil
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := batch.Write(); err != nil {
+			return nil, err
+		}
+	}
+	batch := db.NewBatch()
+	if err := batch.Put(importedKey, nil); err != nil {
+		return nil, err
+	}
+	if err := batch.Delete(importingKey); err != nil {
+		return nil, err
+	}
+	return manifest, batch.Write()
+}
+
+// clear deletes every key of [db] except [importingKey]
+func clear(db database.Database) error {
+	it := db.NewIterator()
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		key := it.Key()
+		if bytes.Equal(key, importingKey) {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		if batch.Size() < importBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// readPartition reads every key/value pair in [partition], checking the
+// partition's prefix, entry count, and checksum. If [onEntry] is non-nil, it's
+// called with each pair. The checksum is only known once the whole file has
+// been read, so [onEntry] may be called with data that fails verification.
+func readPartition(dir string, partition Partition, onEntry func(key, value []byte) error) error {
+	prefix, err := hex.DecodeString(partition.Prefix)
+	if err != nil {
+		return fmt.Errorf("couldn't parse prefix of partition %s: %w", partition.Name, err)
+	}
+
+	file, err := os.Open(partitionPath(dir, partition.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := &partitionReader{hasher: sha256.New()}
+	r.buf = bufio.NewReader(io.TeeReader(file, r.hasher))
+
+	entries := uint64(0)
+	for {
+		key, value, err := r.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't read partition %s: %w", partition.Name, err)
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			return fmt.Errorf("%w: partition %s", errWrongPrefix, partition.Name)
+		}
+		entries++
+		if onEntry == nil {
+			continue
+		}
+		if err := onEntry(key, value); err != nil {
+			return err
+		}
+	}
+
+	if entries != partition.Entries {
+		return fmt.Errorf("%w: partition %s expected %d but got %d", errWrongEntries, partition.Name, partition.Entries, entries)
+	}
+	if checksum := hex.EncodeToString(r.hasher.Sum(nil)); checksum != partition.Checksum {
+		return fmt.Errorf("%w: partition %s expected %s but got %s", errWrongChecksum, partition.Name, partition.Checksum, checksum)
+	}
+	return nil
+}
+
+type partitionReader struct {
+	buf    *bufio.Reader
+	hasher hash.Hash
+	lenBuf [wrappers.IntLen]byte
+}
+
+// read returns the next key/value pair in the partition. Returns io.EOF if
+// there are no more pairs.
+func (r *partitionReader) read() ([]byte, []byte, error) {
+	key, err := r.readBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := r.readBytes()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return key, value, err
+}
+
+func (r *partitionReader) readBytes() ([]byte, error) {
+	if _, err := io.ReadFull(r.buf, r.lenBuf[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(r.lenBuf[:])
+	if size > maxEntrySize {
+		return nil, fmt.Errorf("%w: %d bytes", errEntryTooLarge, size)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r.buf, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,137 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// FormatVersion is the version of the snapshot layout written by Export.
+	FormatVersion uint32 = 1
+
+	// ManifestFileName is the name of the manifest file in a snapshot
+	// directory.
+	ManifestFileName = "manifest.json"
+
+	// RemainderPartition is the name of the partition that holds every key
+	// that isn't under a named prefix.
+	RemainderPartition = "node"
+
+	partitionFileExtension = ".kv"
+)
+
+var (
+	errWrongFormat          = errors.New("unsupported snapshot format")
+	errWrongDatabaseVersion = errors.New("snapshot database version mismatch")
+	errWrongGenesis         = errors.New("snapshot genesis mismatch")
+	errDuplicatePartition   = errors.New("duplicate snapshot partition")
+	errInvalidPartitionName = errors.New("invalid snapshot partition name")
+)
+
+// Header identifies the database a snapshot was taken from
+type Header struct {
+	// DatabaseVersion is the version of the exported database
+	DatabaseVersion version.Version
+	// GenesisID is the hash of the genesis the exported node was started with
+	GenesisID ids.ID
+}
+
+// Manifest describes the contents of a snapshot directory
+type Manifest struct {
+	Format          uint32      `json:"format"`
+	DatabaseVersion string      `json:"databaseVersion"`
+	GenesisID       ids.ID      `json:"genesisID"`
+	Partitions      []Partition `json:"partitions"`
+}
+
+// Partition describes one file of key/value pairs in a snapshot
+type Partition struct {
+	// Name of the partition. The partition's pairs are stored in the file
+	// [Name].kv
+	Name string `json:"name"`
+	// Prefix is the hex encoded database prefix of every key in this
+	// partition. Empty for the remainder partition.
+	Prefix string `json:"prefix,omitempty"`
+	// Entries is the number of key/value pairs in the partition
+	Entries uint64 `json:"entries"`
+	// Checksum is the hex encoded SHA-256 hash of the partition file
+	Checksum string `json:"checksum"`
+}
+
+// Verify returns nil iff this manifest can be imported into a database with
+// the provided header
+func (m *Manifest) Verify(header Header) error {
+	switch {
+	case m.Format != FormatVersion:
+		return fmt.Errorf("%w: expected %d but got %d", errWrongFormat, FormatVersion, m.Format)
+	case m.DatabaseVersion != header.DatabaseVersion.String():
+		return fmt.Errorf("%w: expected %s but got %s", errWrongDatabaseVersion, header.DatabaseVersion, m.DatabaseVersion)
+	case m.GenesisID != header.GenesisID:
+		return fmt.Errorf("%w: expected %s but got %s", errWrongGenesis, header.GenesisID, m.GenesisID)
+	}
+	names := make(map[string]struct{}, len(m.Partitions))
+	for _, partition := range m.Partitions {
+		if err := verifyPartitionName(partition.Name); err != nil {
+			return err
+		}
+		if _, ok := names[partition.Name]; ok {
+			return fmt.Errorf("%w: %s", errDuplicatePartition, partition.Name)
+		}
+		names[partition.Name] = struct{}{}
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest of the snapshot in [dir]
+func ReadManifest(dir string) (*Manifest, error) {
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("couldn't parse snapshot manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func writeManifest(dir string, manifest *Manifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	return perms.WriteFile(filepath.Join(dir, ManifestFileName), manifestBytes, perms.ReadWrite)
+}
+
+// verifyPartitionName returns nil iff [name] can't refer to a file outside of
+// the snapshot directory
+func verifyPartitionName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") || filepath.Base(name) != name {
+		return fmt.Errorf("%w: %q", errInvalidPartitionName, name)
+	}
+	return nil
+}
+
+func partitionPath(dir, name string) string {
+	return filepath.Join(dir, name+partitionFileExtension)
+}
+
+type innerSortPartitions []Partition
+
+func (p innerSortPartitions) Less(i, j int) bool { return p[i].Name < p[j].Name }
+func (p innerSortPartitions) Len() int           { return len(p) }
+func (p innerSortPartitions) Swap(i, j int)      { p[j], p[i] = p[i], p[j] }
+
+func sortPartitions(p []Partition) { sort.Sort(innerSortPartitions(p)) }
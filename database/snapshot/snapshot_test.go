@@ -0,0 +1,214 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+var testHeader = Header{
+	DatabaseVersion: version.CurrentDatabase,
+	GenesisID:       ids.ID{1},
+}
+
+func TestExportImport(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	db := memdb.New()
+	chainDB := prefixdb.New(chainID[:], db)
+	assert.NoError(db.Put([]byte{0x00}, nil))
+	assert.NoError(chainDB.Put([]byte("key1"), []byte("value1")))
+	assert.NoError(chainDB.Put([]byte("key2"), []byte("value2")))
+
+	dir := t.TempDir()
+	manifest, err := Export(db, dir, testHeader, map[string][]byte{
+		"chain": chainID[:],
+	}, nil)
+	assert.NoError(err)
+	assert.Len(manifest.Partitions, 2)
+	assert.Equal("chain", manifest.Partitions[0].Name)
+	assert.Equal(uint64(2), manifest.Partitions[0].Entries)
+	assert.Equal(RemainderPartition, manifest.Partitions[1].Name)
+	assert.Equal(uint64(1), manifest.Partitions[1].Entries)
+
+	readManifest, err := ReadManifest(dir)
+	assert.NoError(err)
+	assert.Equal(manifest, readManifest)
+
+	importedDB := memdb.New()
+	imported, err := Imported(importedDB)
+	assert.NoError(err)
+	assert.False(imported)
+	_, err = Import(importedDB, dir, testHeader)
+	assert.NoError(err)
+	imported, err = Imported(importedDB)
+	assert.NoError(err)
+	assert.True(imported)
+
+	has, err := importedDB.Has([]byte{0x00})
+	assert.NoError(err)
+	assert.True(has)
+	value, err := prefixdb.New(chainID[:], importedDB).Get([]byte("key2"))
+	assert.NoError(err)
+	assert.Equal([]byte("value2"), value)
+
+	_, err = Import(importedDB, dir, testHeader)
+	assert.True(errors.Is(err, errDatabaseNotEmpty))
+}
+
+func TestImportWrongHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{0x00}, nil))
+
+	dir := t.TempDir()
+	_, err := Export(db, dir, testHeader, nil, nil)
+	assert.NoError(err)
+
+	_, err = Import(memdb.New(), dir, Header{
+		DatabaseVersion: version.NewDefaultVersion(1, 0, 0),
+		GenesisID:       testHeader.GenesisID,
+	})
+	assert.True(errors.Is(err, errWrongDatabaseVersion))
+
+	_, err = Import(memdb.New(), dir, Header{
+		DatabaseVersion: testHeader.DatabaseVersion,
+		GenesisID:       ids.ID{2},
+	})
+	assert.True(errors.Is(err, errWrongGenesis))
+}
+
+func TestImportCorrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{0x00}, []byte{0x01}))
+
+	dir := t.TempDir()
+	_, err := Export(db, dir, testHeader, nil, nil)
+	assert.NoError(err)
+
+	path := filepath.Join(dir, RemainderPartition+partitionFileExtension)
+	partitionBytes, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	partitionBytes[len(partitionBytes)-1] ^= 0xff
+	assert.NoError(ioutil.WriteFile(path, partitionBytes, 0o600))
+
+	importedDB := memdb.New()
+	_, err = Import(importedDB, dir, testHeader)
+	assert.True(errors.Is(err, errWrongChecksum))
+
+	// Nothing should have been written
+	it := importedDB.NewIterator()
+	assert.False(it.Next())
+	it.Release()
+}
+
+func TestImportInterrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{0x00}, []byte{0x01}))
+
+	dir := t.TempDir()
+	_, err := Export(db, dir, testHeader, nil, nil)
+	assert.NoError(err)
+
+	// Fake an import that was interrupted after writing part of a snapshot
+	importedDB := memdb.New()
+	assert.NoError(importedDB.Put(importingKey, nil))
+	assert.NoError(importedDB.Put([]byte{0x02}, nil))
+	interrupted, err := Interrupted(importedDB)
+	assert.NoError(err)
+	assert.True(interrupted)
+	imported, err := Imported(importedDB)
+	assert.NoError(err)
+	assert.False(imported)
+
+	// Importing again starts over
+	_, err = Import(importedDB, dir, testHeader)
+	assert.NoError(err)
+	interrupted, err = Interrupted(importedDB)
+	assert.NoError(err)
+	assert.False(interrupted)
+	imported, err = Imported(importedDB)
+	assert.NoError(err)
+	assert.True(imported)
+
+	value, err := importedDB.Get([]byte{0x00})
+	assert.NoError(err)
+	assert.Equal([]byte{0x01}, value)
+	has, err := importedDB.Has([]byte{0x02})
+	assert.NoError(err)
+	assert.False(has)
+}
+
+func TestExportExcluded(t *testing.T) {
+	assert := assert.New(t)
+
+	excludedPrefix := []byte("keystore")
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{0x00}, nil))
+	assert.NoError(prefixdb.New(excludedPrefix, db).Put([]byte("user"), []byte("password")))
+
+	dir := t.TempDir()
+	manifest, err := Export(db, dir, testHeader, nil, [][]byte{excludedPrefix})
+	assert.NoError(err)
+	assert.Len(manifest.Partitions, 1)
+	assert.Equal(uint64(1), manifest.Partitions[0].Entries)
+}
+
+func TestImportInvalidPartitionName(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	_, err := Export(memdb.New(), dir, testHeader, nil, nil)
+	assert.NoError(err)
+
+	manifest, err := ReadManifest(dir)
+	assert.NoError(err)
+	for _, name := range []string{"../chain", "chain/..", "..", "a/b", `a\b`, ""} {
+		manifest.Partitions[0].Name = name
+		assert.NoError(writeManifest(dir, manifest))
+
+		_, err = Import(memdb.New(), dir, testHeader)
+		assert.True(errors.Is(err, errInvalidPartitionName), name)
+	}
+
+	_, err = Export(memdb.New(), t.TempDir(), testHeader, map[string][]byte{"../chain": {0x01}}, nil)
+	assert.True(errors.Is(err, errInvalidPartitionName))
+}
+
+func TestImportEntryTooLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	assert.NoError(db.Put([]byte{0x00}, nil))
+
+	dir := t.TempDir()
+	_, err := Export(db, dir, testHeader, nil, nil)
+	assert.NoError(err)
+
+	// Claim that the first key is 4GiB long
+	path := filepath.Join(dir, RemainderPartition+partitionFileExtension)
+	partitionBytes, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	copy(partitionBytes, []byte{0xff, 0xff, 0xff, 0xff})
+	assert.NoError(ioutil.WriteFile(path, partitionBytes, 0o600))
+
+	_, err = Import(memdb.New(), dir, testHeader)
+	assert.True(errors.Is(err, errEntryTooLarge))
+}
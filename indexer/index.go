@@ -46,7 +46,12 @@ type Index interface {
 	GetContainerRange(startIndex uint64, numToFetch uint64) ([]Container, error)
 	GetLastAccepted() (Container, error)
 	GetIndex(containerID ids.ID) (uint64, error)
+	// NextAcceptedIndex returns the index that the next accepted container
+	// will have
+	NextAcceptedIndex() uint64
 	GetContainerByID(containerID ids.ID) (Container, error)
+	// Verify returns a description of each inconsistency found in the index
+	Verify() ([]string, error)
 	io.Closer
 }
 
@@ -76,10 +81,7 @@ func newIndex(
 	codec codec.Manager,
 	clock timer.Clock,
 ) (Index, error) {
-	vDB := versiondb.New(baseDB)
-	indexToContainer := prefixdb.New(indexToContainerPrefix, vDB)
-	containerToIndex := prefixdb.New(containerToIDPrefix, vDB)
-
+	vDB, indexToContainer, containerToIndex := newIndexDatabases(baseDB)
 	i := &index{
 		clock:            clock,
 		codec:            codec,
@@ -105,6 +107,20 @@ func newIndex(
 	return i, nil
 }
 
+// newIndexDatabases returns the databases that an index created with [baseDB]
+// stores its state in. Writes to the returned databases are written to
+// [baseDB] when the returned versiondb is committed.
+func newIndexDatabases(baseDB database.Database) (
+	vDB *versiondb.Database,
+	indexToContainer database.Database,
+	containerToIndex database.Database,
+) {
+	vDB = versiondb.New(baseDB)
+	indexToContainer = prefixdb.New(indexToContainerPrefix, vDB)
+	containerToIndex = prefixdb.New(containerToIDPrefix, vDB)
+	return vDB, indexToContainer, containerToIndex
+}
+
 // Close this index
 func (i *index) Close() error {
 	errs := wrappers.Errs{}
@@ -276,6 +292,33 @@ func (i *index) GetLastAccepted() (Container, error) {
 	return i.getContainerByIndex(lastAcceptedIndex)
 }
 
+func (i *index) NextAcceptedIndex() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.nextAcceptedIndex
+}
+
+func (i *index) Verify() ([]string, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	findings, err := verifyIndex(i.vDB, i.indexToContainer, i.containerToIndex, i.codec)
+	if err != nil {
+		return nil, err
+	}
+	storedNextAcceptedIndex, err := database.GetUInt64(i.vDB, nextAcceptedIndexKey)
+	if err == database.ErrNotFound {
+		storedNextAcceptedIndex = 0
+	} else if err != nil {
+		return nil, err
+	}
+	if storedNextAcceptedIndex != i.nextAcceptedIndex {
+		findings = append(findings, fmt.Sprintf("persisted next accepted index %d doesn't match in-memory next accepted index %d", storedNextAcceptedIndex, i.nextAcceptedIndex))
+	}
+	return findings, nil
+}
+
 // Assumes i.lock is held
 // Returns:
 // 1) The index of the most recently accepted transaction,
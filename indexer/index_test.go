@@ -5,10 +5,13 @@ import (
 
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -170,3 +173,53 @@ func TestDontIndexSameContainerTwice(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(gotContainer.Bytes, []byte{1, 2, 3}, "should not have accepted same container twice")
 }
+
+func TestIndexVerify(t *testing.T) {
+	assert := assert.New(t)
+	ed := &triggers.EventDispatcher{}
+	ed.Initialize(logging.NoLog{})
+	// The node gives the indexer a prefixed database
+	baseDB := memdb.New()
+	db := prefixdb.New([]byte{0x00}, baseDB)
+	idxrIntf, err := NewIndexer(Config{
+		IndexingEnabled:     true,
+		Log:                 logging.NoLog{},
+		DB:                  db,
+		ConsensusDispatcher: ed,
+		DecisionDispatcher:  ed,
+		APIServer:           &apiServerMock{},
+		ShutdownF:           func() {},
+	})
+	assert.NoError(err)
+	idxr := idxrIntf.(*indexer)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	idx, err := idxr.registerChainHelper(ctx.ChainID, blockPrefix, "chain", "block", ed)
+	assert.NoError(err)
+
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	for _, containerID := range containerIDs {
+		assert.NoError(idx.Accept(ctx, containerID, utils.RandomBytes(32)))
+	}
+
+	findings, err := idx.Verify()
+	assert.NoError(err)
+	assert.Empty(findings)
+	findings, err = VerifyDatabase(prefixdb.New([]byte{0x00}, baseDB), []ids.ID{ctx.ChainID})
+	assert.NoError(err)
+	assert.Empty(findings)
+
+	// Removing a container from the middle of the index should be reported
+	vDB, indexToContainer, _ := newIndexDatabases(newIndexBaseDB(db, ctx.ChainID, blockPrefix))
+	assert.NoError(indexToContainer.Delete(database.PackUInt64(1)))
+	assert.NoError(vDB.Commit())
+
+	// The gap and the dangling container ID mapping are both reported
+	findings, err = idx.Verify()
+	assert.NoError(err)
+	assert.Len(findings, 2)
+	findings, err = VerifyDatabase(prefixdb.New([]byte{0x00}, baseDB), []ids.ID{ctx.ChainID})
+	assert.NoError(err)
+	assert.Len(findings, 2)
+}
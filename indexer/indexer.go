@@ -1,11 +1,13 @@
 package indexer
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/network"
@@ -50,6 +52,8 @@ var (
 	previouslyIndexedPrefix = byte(0x05)
 	hasRunKey               = []byte{0x07}
 
+	errClosed = errors.New("indexer is closed")
+
 	_ Indexer = &indexer{}
 )
 
@@ -70,14 +74,27 @@ type Config struct {
 // Indexer is threadsafe.
 type Indexer interface {
 	chains.Registrant
+	// VerifyChain returns a description of each inconsistency found in the
+	// indices of the chain [chainID]
+	VerifyChain(chainID ids.ID) ([]string, error)
+	// ConsensusIndex returns the index of the containers of chain [chainID]
+	// that are accepted through the consensus dispatcher, if it's indexed
+	ConsensusIndex(chainID ids.ID) (Index, bool)
+	// DecisionIndex returns the index of the containers of chain [chainID]
+	// that are accepted through the decision dispatcher, if it's indexed
+	DecisionIndex(chainID ids.ID) (Index, bool)
 	// Close will do nothing and return nil after the first call
 	io.Closer
 }
 
 // NewIndexer returns a new Indexer and registers a new endpoint on the given API server.
 func NewIndexer(config Config) (Indexer, error) {
+	codec, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
 	indexer := &indexer{
-		codec:                codec.NewManager(codecMaxSize),
+		codec:                codec,
 		log:                  config.Log,
 		db:                   config.DB,
 		allowIncompleteIndex: config.AllowIncompleteIndex,
@@ -90,12 +107,6 @@ func NewIndexer(config Config) (Indexer, error) {
 		routeAdder:           config.APIServer,
 		shutdownF:            config.ShutdownF,
 	}
-	if err := indexer.codec.RegisterCodec(
-		codecVersion,
-		linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32),
-	); err != nil {
-		return nil, fmt.Errorf("couldn't register codec: %s", err)
-	}
 	hasRun, err := indexer.hasRun()
 	if err != nil {
 		return nil, err
@@ -104,6 +115,18 @@ func NewIndexer(config Config) (Indexer, error) {
 	return indexer, indexer.markHasRun()
 }
 
+// newCodec returns the codec used to serialize containers
+func newCodec() (codec.Manager, error) {
+	c := codec.NewManager(codecMaxSize)
+	if err := c.RegisterCodec(
+		codecVersion,
+		linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32),
+	); err != nil {
+		return nil, fmt.Errorf("couldn't register codec: %s", err)
+	}
+	return c, nil
+}
+
 // indexer implements Indexer
 type indexer struct {
 	codec  codec.Manager
@@ -261,16 +284,22 @@ func (i *indexer) RegisterChain(name string, ctx *snow.Context, engine common.En
 	}
 }
 
+// newIndexBaseDB returns the database, under the indexer's database [db], that
+// the index of kind [prefixEnd] of chain [chainID] is created with
+func newIndexBaseDB(db database.Database, chainID ids.ID, prefixEnd byte) *prefixdb.Database {
+	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(prefix, chainID[:])
+	prefix[hashing.HashLen] = prefixEnd
+	return prefixdb.New(prefix, db)
+}
+
 func (i *indexer) registerChainHelper(
 	chainID ids.ID,
 	prefixEnd byte,
 	name, endpoint string,
 	dispatcher *triggers.EventDispatcher,
 ) (Index, error) {
-	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
-	copy(prefix, chainID[:])
-	prefix[hashing.HashLen] = prefixEnd
-	indexDB := prefixdb.New(prefix, i.db)
+	indexDB := newIndexBaseDB(i.db, chainID, prefixEnd)
 	index, err := newIndex(indexDB, i.log, i.codec, i.clock)
 	if err != nil {
 		_ = indexDB.Close()
@@ -288,7 +317,7 @@ func (i *indexer) registerChainHelper(
 	codec := json.NewCodec()
 	apiServer.RegisterCodec(codec, "application/json")
 	apiServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := apiServer.RegisterService(&service{Index: index}, "index"); err != nil {
+	if err := openrpc.RegisterServices(apiServer, openrpc.Service{Receiver: &service{Index: index}, Name: "index"}); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
@@ -300,10 +329,65 @@ func (i *indexer) registerChainHelper(
 	return index, nil
 }
 
+func (i *indexer) VerifyChain(chainID ids.ID) ([]string, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.closed {
+		return nil, errClosed
+	}
+
+	var findings []string
+	for _, indices := range []struct {
+		name    string
+		indices map[ids.ID]Index
+	}{
+		{name: "tx", indices: i.txIndices},
+		{name: "vtx", indices: i.vtxIndices},
+		{name: "block", indices: i.blockIndices},
+	} {
+		index, ok := indices.indices[chainID]
+		if !ok {
+			continue
+		}
+		indexFindings, err := index.Verify()
+		if err != nil {
+			return nil, err
+		}
+		for _, finding := range indexFindings {
+			findings = append(findings, fmt.Sprintf("%s index: %s", indices.name, finding))
+		}
+	}
+	return findings, nil
+}
+
 // Close this indexer. Stops indexing all chains.
 // Closes [i.db]. Assumes Close is only called after
 // the node is done making decisions.
 // Calling Close after it has been called does nothing.
+func (i *indexer) ConsensusIndex(chainID ids.ID) (Index, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if index, ok := i.blockIndices[chainID]; ok {
+		return index, true
+	}
+	index, ok := i.vtxIndices[chainID]
+	return index, ok
+}
+
+func (i *indexer) DecisionIndex(chainID ids.ID) (Index, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	// Blocks are accepted through both dispatchers
+	if index, ok := i.blockIndices[chainID]; ok {
+		return index, true
+	}
+	index, ok := i.txIndices[chainID]
+	return index, ok
+}
+
 func (i *indexer) Close() error {
 	i.lock.Lock()
 	defer i.lock.Unlock()
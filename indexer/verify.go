@@ -0,0 +1,130 @@
+package indexer
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// indexTypes maps the prefix of each kind of index to a description of it
+var indexTypes = []struct {
+	prefix byte
+	name   string
+}{
+	{prefix: txPrefix, name: "tx"},
+	{prefix: vtxPrefix, name: "vtx"},
+	{prefix: blockPrefix, name: "block"},
+}
+
+// VerifyDatabase checks that the indices of the chains [chainIDs] stored in
+// [db] are contiguous and that their mappings from index to container and from
+// container to index agree. [db] is the database the indexer was given.
+// Returns a description of each inconsistency found. [db] isn't modified.
+func VerifyDatabase(db database.Database, chainIDs []ids.ID) ([]string, error) {
+	codec, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []string
+	for _, chainID := range chainIDs {
+		for _, indexType := range indexTypes {
+			vDB, indexToContainer, containerToIndex := newIndexDatabases(newIndexBaseDB(db, chainID, indexType.prefix))
+			indexFindings, err := verifyIndex(vDB, indexToContainer, containerToIndex, codec)
+			if err != nil {
+				return nil, err
+			}
+			for _, finding := range indexFindings {
+				findings = append(findings, fmt.Sprintf("%s index of chain %s: %s", indexType.name, chainID, finding))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// verifyIndex checks the index stored in the databases returned by
+// newIndexDatabases. Returns a description of each inconsistency found.
+func verifyIndex(db, indexToContainer, containerToIndex database.Database, codec codec.Manager) ([]string, error) {
+	nextAcceptedIndex, err := database.GetUInt64(db, nextAcceptedIndexKey)
+	if err == database.ErrNotFound {
+		nextAcceptedIndex = 0
+	} else if err != nil {
+		return nil, err
+	}
+
+	var findings []string
+
+	// Keys are big endian so the indices are iterated in increasing order.
+	expectedIndex := uint64(0)
+	it := indexToContainer.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		index, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			findings = append(findings, fmt.Sprintf("malformed index 0x%x", it.Key()))
+			continue
+		}
+		if index >= nextAcceptedIndex {
+			findings = append(findings, fmt.Sprintf("index %d is at or past the next accepted index %d", index, nextAcceptedIndex))
+		}
+		if index > expectedIndex {
+			findings = append(findings, fmt.Sprintf("indices [%d, %d] are missing", expectedIndex, index-1))
+		}
+		expectedIndex = index + 1
+
+		var container Container
+		if _, err := codec.Unmarshal(it.Value(), &container); err != nil {
+			findings = append(findings, fmt.Sprintf("container at index %d couldn't be parsed: %s", index, err))
+			continue
+		}
+		mappedIndex, err := database.GetUInt64(containerToIndex, container.ID[:])
+		switch {
+		case err == database.ErrNotFound:
+			findings = append(findings, fmt.Sprintf("container %s at index %d isn't mapped to an index", container.ID, index))
+		case err != nil:
+			return nil, err
+		case mappedIndex != index:
+			findings = append(findings, fmt.Sprintf("container %s at index %d is mapped to index %d", container.ID, index, mappedIndex))
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	if expectedIndex < nextAcceptedIndex {
+		findings = append(findings, fmt.Sprintf("indices [%d, %d] are missing", expectedIndex, nextAcceptedIndex-1))
+	}
+
+	idIt := containerToIndex.NewIterator()
+	defer idIt.Release()
+	for idIt.Next() {
+		containerID, err := ids.ToID(idIt.Key())
+		if err != nil {
+			findings = append(findings, fmt.Sprintf("malformed container ID 0x%x", idIt.Key()))
+			continue
+		}
+		index, err := database.ParseUInt64(idIt.Value())
+		if err != nil {
+			findings = append(findings, fmt.Sprintf("container %s is mapped to malformed index 0x%x", containerID, idIt.Value()))
+			continue
+		}
+		containerBytes, err := indexToContainer.Get(database.PackUInt64(index))
+		if err == database.ErrNotFound {
+			findings = append(findings, fmt.Sprintf("container %s is mapped to index %d which doesn't exist", containerID, index))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var container Container
+		if _, err := codec.Unmarshal(containerBytes, &container); err != nil {
+			// The parsing error was already reported when iterating over the
+			// indices.
+			continue
+		}
+		if container.ID != containerID {
+			findings = append(findings, fmt.Sprintf("container %s is mapped to index %d which holds container %s", containerID, index, container.ID))
+		}
+	}
+	return findings, idIt.Error()
+}
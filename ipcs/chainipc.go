@@ -4,10 +4,12 @@
 package ipcs
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
@@ -22,10 +24,20 @@ const (
 	ipcDecisionsIdentifier = "decisions"
 )
 
+var errAlreadyPublished = errors.New("blockchain is already being published")
+
+// Indices returns the indices of the containers accepted on each chain
+type Indices interface {
+	ConsensusIndex(chainID ids.ID) (indexer.Index, bool)
+	DecisionIndex(chainID ids.ID) (indexer.Index, bool)
+}
+
 type context struct {
 	log       logging.Logger
 	networkID uint32
 	path      string
+	transport TransportConfig
+	indices   Indices
 }
 
 // ChainIPCs maintains IPCs for a set of chains
@@ -36,14 +48,17 @@ type ChainIPCs struct {
 	decisionEvents  *triggers.EventDispatcher
 }
 
-// NewChainIPCs creates a new *ChainIPCs that writes consensus and decision
-// events to IPC sockets
-func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
+// NewChainIPCs creates a new *ChainIPCs that publishes consensus and decision
+// events over [transport]. [indices] is used to number accepted containers and
+// to replay them.
+func NewChainIPCs(log logging.Logger, path string, networkID uint32, transport TransportConfig, indices Indices, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
 	cipcs := &ChainIPCs{
 		context: context{
 			log:       log,
 			networkID: networkID,
 			path:      path,
+			transport: transport,
+			indices:   indices,
 		},
 		chains:          make(map[ids.ID]*EventSockets),
 		consensusEvents: consensusEvents,
@@ -59,12 +74,25 @@ func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusEv
 
 // Publish creates a set of eventSockets for the given chainID
 func (cipcs *ChainIPCs) Publish(chainID ids.ID) (*EventSockets, error) {
+	return cipcs.publish(chainID, nil)
+}
+
+// PublishFromIndex creates a set of eventSockets for the given chainID that
+// publish the containers accepted from [index] onwards before new events
+func (cipcs *ChainIPCs) PublishFromIndex(chainID ids.ID, index uint64) (*EventSockets, error) {
+	return cipcs.publish(chainID, &index)
+}
+
+func (cipcs *ChainIPCs) publish(chainID ids.ID, replayFrom *uint64) (*EventSockets, error) {
 	if es, ok := cipcs.chains[chainID]; ok {
+		if replayFrom != nil {
+			return nil, fmt.Errorf("can't replay %s: %w", chainID, errAlreadyPublished)
+		}
 		cipcs.log.Info("returning existing blockchainID %s", chainID.String())
 		return es, nil
 	}
 
-	es, err := newEventSockets(cipcs.context, chainID, cipcs.consensusEvents, cipcs.decisionEvents)
+	es, err := newEventSockets(cipcs.context, chainID, replayFrom, cipcs.consensusEvents, cipcs.decisionEvents)
 	if err != nil {
 		cipcs.log.Error("can't create ipcs: %s", err)
 		return nil, err
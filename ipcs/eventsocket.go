@@ -5,32 +5,57 @@ package ipcs
 
 import (
 	"errors"
-	"os"
-	"syscall"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/ipcs/ipcsproto"
 	"github.com/ava-labs/avalanchego/ipcs/socket"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
+// EnvelopeVersion is the version of the envelopes events are published in
+const EnvelopeVersion = 1
+
+var errNotIndexed = errors.New("chain isn't indexed")
+
 // EventSockets is a set of named eventSockets
 type EventSockets struct {
 	consensusSocket *eventSocket
 	decisionsSocket *eventSocket
 }
 
-// newEventSockets creates a *ChainIPCs with both consensus and decisions IPCs
-func newEventSockets(ctx context, chainID ids.ID, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher) (*EventSockets, error) {
-	consensusIPC, err := newEventIPCSocket(ctx, chainID, ipcConsensusIdentifier, consensusEvents)
+// newEventSockets creates a *ChainIPCs with both consensus and decisions IPCs.
+// If [replayFrom] is non-nil, the containers accepted from that index onwards
+// are published before new events.
+func newEventSockets(ctx context, chainID ids.ID, replayFrom *uint64, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher) (*EventSockets, error) {
+	consensusIndex := func() (indexer.Index, bool) { return ctx.indices.ConsensusIndex(chainID) }
+	decisionIndex := func() (indexer.Index, bool) { return ctx.indices.DecisionIndex(chainID) }
+	if replayFrom != nil {
+		if _, ok := consensusIndex(); !ok {
+			return nil, fmt.Errorf("can't replay chain %s: %w", chainID, errNotIndexed)
+		}
+	}
+
+	consensusIPC, err := newEventIPCSocket(ctx, chainID, ipcConsensusIdentifier, consensusEvents, consensusIndex, replayFrom)
 	if err != nil {
 		return nil, err
 	}
 
-	decisionsIPC, err := newEventIPCSocket(ctx, chainID, ipcDecisionsIdentifier, decisionEvents)
+	decisionsIPC, err := newEventIPCSocket(ctx, chainID, ipcDecisionsIdentifier, decisionEvents, decisionIndex, replayFrom)
 	if err != nil {
+		if err := consensusIPC.stop(); err != nil {
+			ctx.log.Error("couldn't stop consensus IPC: %s", err)
+		}
 		return nil, err
 	}
 
@@ -84,66 +109,226 @@ func (ipcs *EventSockets) DecisionsURL() string {
 
 // eventSocket is a single IPC socket for a single chain
 type eventSocket struct {
-	url          string
 	log          logging.Logger
-	socket       *socket.Socket
+	chainID      ids.ID
+	transport    transport
 	unregisterFn func() error
+	// Returns the index of the containers this socket is told are accepted,
+	// if the chain is indexed
+	index func() (indexer.Index, bool)
+
+	// Used to mock time.
+	clock timer.Clock
+
+	// Held while sending new events so that they aren't interleaved with
+	// replayed ones
+	lock sync.Mutex
 }
 
 // newEventIPCSocket creates a *eventSocket for the given chain and
-// EventDispatcher that writes to a local IPC socket
-func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *triggers.EventDispatcher) (*eventSocket, error) {
+// EventDispatcher that publishes events over the configured transport
+func newEventIPCSocket(
+	ctx context,
+	chainID ids.ID,
+	name string,
+	events *triggers.EventDispatcher,
+	index func() (indexer.Index, bool),
+	replayFrom *uint64,
+) (*eventSocket, error) {
 	var (
 		url     = ipcURL(ctx, chainID, name)
 		ipcName = ipcIdentifierPrefix + "-" + name
 	)
 
-	err := os.Remove(url)
-	if err != nil && !errors.Is(err, syscall.ENOENT) {
-		return nil, err
-	}
-
 	eis := &eventSocket{
-		log:    ctx.log,
-		url:    url,
-		socket: socket.NewSocket(url, ctx.log),
+		log:     ctx.log,
+		chainID: chainID,
+		index:   index,
 		unregisterFn: func() error {
 			return events.DeregisterChain(chainID, ipcName)
 		},
 	}
 
-	if err := eis.socket.Listen(); err != nil {
-		if err := eis.socket.Close(); err != nil {
+	// Socket transports replay to each connection. The file is replayed to
+	// once, before new events are appended to it.
+	var (
+		from   uint64
+		replay socket.ReplayFunc
+		file   *fileTransport
+		err    error
+	)
+	if replayFrom != nil {
+		from = *replayFrom
+		replay = eis.replay
+	}
+	switch ctx.transport.Transport {
+	case UnixTransport:
+		eis.transport, err = newUnixTransport(url, ctx.log, from, replay)
+	case TCPTransport:
+		eis.transport, err = newTCPTransport(ctx.transport.TCPHost, ctx.log, from, replay)
+	case FileTransport:
+		file, err = newFileTransport(url, ctx.log)
+		eis.transport = file
+	default:
+		err = fmt.Errorf("unknown IPC transport %q", ctx.transport.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if file == nil || replay == nil {
+		if err := events.RegisterChain(chainID, ipcName, eis, false); err != nil {
+			if err := eis.transport.Close(); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+		return eis, nil
+	}
+
+	// Replay most of the containers before being told about new events
+	if from, err = eis.replay(file.file, from); err != nil {
+		if err := eis.transport.Close(); err != nil {
 			return nil, err
 		}
 		return nil, err
 	}
 
+	// Replay the containers accepted since then before sending new events, so
+	// that no container is missed
+	eis.lock.Lock()
+	defer eis.lock.Unlock()
+
 	if err := events.RegisterChain(chainID, ipcName, eis, false); err != nil {
+		if err := eis.transport.Close(); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if _, err := eis.replay(file.file, from); err != nil {
 		if err := eis.stop(); err != nil {
 			return nil, err
 		}
 		return nil, err
 	}
-
 	return eis, nil
 }
 
-// Accept delivers a message to the eventSocket
-func (eis *eventSocket) Accept(_ *snow.Context, _ ids.ID, container []byte) error {
-	eis.socket.Send(container)
+// Accept publishes that [containerID] was accepted
+func (eis *eventSocket) Accept(_ *snow.Context, containerID ids.ID, container []byte) error {
+	index, indexed := eis.containerIndex(containerID)
+	return eis.send(&ipcsproto.Envelope{
+		Type:        ipcsproto.EventType_EVENT_TYPE_ACCEPT,
+		ContainerId: containerID[:],
+		Indexed:     indexed,
+		Index:       index,
+		Timestamp:   eis.clock.Time().UnixNano(),
+		Container:   container,
+	})
+}
+
+// Reject publishes that [containerID] was rejected
+func (eis *eventSocket) Reject(_ *snow.Context, containerID ids.ID, container []byte) error {
+	return eis.send(&ipcsproto.Envelope{
+		Type:        ipcsproto.EventType_EVENT_TYPE_REJECT,
+		ContainerId: containerID[:],
+		Timestamp:   eis.clock.Time().UnixNano(),
+		Container:   container,
+	})
+}
+
+// Issue publishes that [containerID] was issued
+func (eis *eventSocket) Issue(_ *snow.Context, containerID ids.ID, container []byte) error {
+	return eis.send(&ipcsproto.Envelope{
+		Type:        ipcsproto.EventType_EVENT_TYPE_ISSUE,
+		ContainerId: containerID[:],
+		Timestamp:   eis.clock.Time().UnixNano(),
+		Container:   container,
+	})
+}
+
+// send publishes [envelope] over the transport
+func (eis *eventSocket) send(envelope *ipcsproto.Envelope) error {
+	msg, err := eis.marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	eis.lock.Lock()
+	defer eis.lock.Unlock()
+
+	eis.transport.Send(msg)
 	return nil
 }
 
+// marshal sets the fields of [envelope] that are common to all the events of
+// this socket and returns its bytes
+func (eis *eventSocket) marshal(envelope *ipcsproto.Envelope) ([]byte, error) {
+	envelope.Version = EnvelopeVersion
+	envelope.ChainId = eis.chainID[:]
+	return proto.Marshal(envelope)
+}
+
+// containerIndex returns the index of the accepted container [containerID],
+// if the chain is indexed
+func (eis *eventSocket) containerIndex(containerID ids.ID) (uint64, bool) {
+	index, ok := eis.index()
+	if !ok {
+		return 0, false
+	}
+	if i, err := index.GetIndex(containerID); err == nil {
+		return i, true
+	}
+	// The index is told about [containerID] after this socket, so it will be
+	// the next container it indexes.
+	return index.NextAcceptedIndex(), true
+}
+
+// replay writes to [w] the containers accepted from index [from] onwards and
+// returns the index of the next container to write.
+//
+// A container accepted while replaying may be published twice, so consumers
+// should skip envelopes whose index they have already processed.
+func (eis *eventSocket) replay(w io.Writer, from uint64) (uint64, error) {
+	index, ok := eis.index()
+	if !ok {
+		return from, errNotIndexed
+	}
+	for next := index.NextAcceptedIndex(); from < next; next = index.NextAcceptedIndex() {
+		containers, err := index.GetContainerRange(from, math.Min64(next-from, indexer.MaxFetchedByRange))
+		if err != nil {
+			return from, err
+		}
+		for _, container := range containers {
+			msg, err := eis.marshal(&ipcsproto.Envelope{
+				Type:        ipcsproto.EventType_EVENT_TYPE_ACCEPT,
+				ContainerId: container.ID[:],
+				Indexed:     true,
+				Index:       from,
+				Timestamp:   container.Timestamp,
+				Container:   container.Bytes,
+			})
+			if err != nil {
+				return from, err
+			}
+			if err := socket.WriteMessage(w, msg); err != nil {
+				return from, err
+			}
+			from++
+		}
+	}
+	return from, nil
+}
+
 // stop unregisters the event handler and closes the eventSocket
 func (eis *eventSocket) stop() error {
 	eis.log.Info("closing Chain IPC")
 	errs := wrappers.Errs{}
-	errs.Add(eis.unregisterFn(), eis.socket.Close())
+	errs.Add(eis.unregisterFn(), eis.transport.Close())
 	return errs.Err
 }
 
 // URL returns the URL of the socket
 func (eis *eventSocket) URL() string {
-	return eis.url
+	return eis.transport.URL()
 }
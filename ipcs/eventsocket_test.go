@@ -0,0 +1,223 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
+	"github.com/ava-labs/avalanchego/ipcs/ipcsproto"
+	"github.com/ava-labs/avalanchego/ipcs/socket"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// testIndex is an in-memory index of accepted containers
+type testIndex struct {
+	indexer.Index
+	lock       sync.RWMutex
+	containers []indexer.Container
+}
+
+func (i *testIndex) add(containerID ids.ID, container []byte) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.containers = append(i.containers, indexer.Container{ID: containerID, Bytes: container, Timestamp: int64(len(i.containers))})
+}
+
+func (i *testIndex) GetIndex(containerID ids.ID) (uint64, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	for index, container := range i.containers {
+		if container.ID == containerID {
+			return uint64(index), nil
+		}
+	}
+	return 0, errors.New("not found")
+}
+
+func (i *testIndex) NextAcceptedIndex() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return uint64(len(i.containers))
+}
+
+func (i *testIndex) GetContainerRange(startIndex, numToFetch uint64) ([]indexer.Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if startIndex+numToFetch > uint64(len(i.containers)) {
+		return nil, errors.New("out of range")
+	}
+	return append([]indexer.Container(nil), i.containers[startIndex:startIndex+numToFetch]...), nil
+}
+
+// testIndices indexes every chain with the same index
+type testIndices struct {
+	index *testIndex
+}
+
+func (i testIndices) ConsensusIndex(ids.ID) (indexer.Index, bool) { return i.index, i.index != nil }
+
+func (i testIndices) DecisionIndex(ids.ID) (indexer.Index, bool) { return i.index, i.index != nil }
+
+func newTestChainIPCs(t *testing.T, transport Transport, index *testIndex) (*ChainIPCs, *triggers.EventDispatcher) {
+	consensusEvents := &triggers.EventDispatcher{}
+	consensusEvents.Initialize(logging.NoLog{})
+	decisionEvents := &triggers.EventDispatcher{}
+	decisionEvents.Initialize(logging.NoLog{})
+
+	cipcs, err := NewChainIPCs(
+		logging.NoLog{},
+		t.TempDir(),
+		1,
+		TransportConfig{Transport: transport, TCPHost: "127.0.0.1"},
+		testIndices{index: index},
+		consensusEvents,
+		decisionEvents,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, cipcs.Shutdown())
+	})
+	return cipcs, decisionEvents
+}
+
+func readEnvelope(r io.Reader) (*ipcsproto.Envelope, error) {
+	var size uint64
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	envelope := &ipcsproto.Envelope{}
+	return envelope, proto.Unmarshal(msg, envelope)
+}
+
+func TestFileTransportReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	index := &testIndex{}
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	for _, containerID := range containerIDs[:3] {
+		index.add(containerID, containerID[:])
+	}
+
+	cipcs, decisionEvents := newTestChainIPCs(t, FileTransport, index)
+	sockets, err := cipcs.PublishFromIndex(chainID, 1)
+	assert.NoError(err)
+
+	ctx := &snow.Context{ChainID: chainID}
+	assert.NoError(decisionEvents.Accept(ctx, containerIDs[3], []byte{3}))
+	assert.NoError(decisionEvents.Reject(ctx, containerIDs[0], []byte{0}))
+
+	file, err := os.Open(sockets.DecisionsURL())
+	assert.NoError(err)
+	defer file.Close()
+
+	for i := uint64(1); i < 4; i++ {
+		envelope, err := readEnvelope(file)
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(uint32(EnvelopeVersion), envelope.Version)
+		assert.Equal(ipcsproto.EventType_EVENT_TYPE_ACCEPT, envelope.Type)
+		assert.Equal(chainID[:], envelope.ChainId)
+		assert.Equal(containerIDs[i][:], envelope.ContainerId)
+		assert.True(envelope.Indexed)
+		assert.Equal(i, envelope.Index)
+	}
+
+	envelope, err := readEnvelope(file)
+	assert.NoError(err)
+	assert.Equal(ipcsproto.EventType_EVENT_TYPE_REJECT, envelope.Type)
+	assert.Equal(containerIDs[0][:], envelope.ContainerId)
+	assert.Equal([]byte{0}, envelope.Container)
+	assert.False(envelope.Indexed)
+
+	_, err = readEnvelope(file)
+	assert.Equal(io.EOF, err)
+}
+
+func TestTCPTransportReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	index := &testIndex{}
+	containerIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	for _, containerID := range containerIDs[:2] {
+		index.add(containerID, containerID[:])
+	}
+
+	cipcs, decisionEvents := newTestChainIPCs(t, TCPTransport, index)
+	sockets, err := cipcs.PublishFromIndex(chainID, 0)
+	assert.NoError(err)
+
+	client, err := socket.DialTCP(sockets.DecisionsURL())
+	if !assert.NoError(err) {
+		return
+	}
+	defer client.Close()
+
+	for i := uint64(0); i < 2; i++ {
+		envelope, err := readEnvelope(client)
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(containerIDs[i][:], envelope.ContainerId)
+		assert.Equal(i, envelope.Index)
+	}
+
+	// The index is told about the container before the socket, as the
+	// indexer would be if it's notified first.
+	index.add(containerIDs[2], containerIDs[2][:])
+	assert.NoError(decisionEvents.Accept(&snow.Context{ChainID: chainID}, containerIDs[2], containerIDs[2][:]))
+
+	envelope, err := readEnvelope(client)
+	assert.NoError(err)
+	assert.Equal(containerIDs[2][:], envelope.ContainerId)
+	assert.Equal(uint64(2), envelope.Index)
+}
+
+func TestPublishFromIndexErrors(t *testing.T) {
+	cipcs, _ := newTestChainIPCs(t, FileTransport, nil)
+
+	chainID := ids.GenerateTestID()
+	_, err := cipcs.PublishFromIndex(chainID, 0)
+	assert.True(t, errors.Is(err, errNotIndexed))
+
+	_, err = cipcs.Publish(chainID)
+	assert.NoError(t, err)
+	_, err = cipcs.PublishFromIndex(chainID, 0)
+	assert.True(t, errors.Is(err, errAlreadyPublished))
+}
+
+func TestParseTransport(t *testing.T) {
+	transport, err := ParseTransport("tcp")
+	assert.NoError(t, err)
+	assert.Equal(t, TCPTransport, transport)
+
+	_, err = ParseTransport("udp")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@
 package socket
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/units"
@@ -22,41 +24,87 @@ import (
 const (
 	// DefaultMaxMessageSize is the number of bytes to cap messages at by default
 	DefaultMaxMessageSize = 2 * units.MiB
+
+	// maxPendingMessages is the number of messages that may be sent to a
+	// connection while it is catching up before it is dropped
+	maxPendingMessages = 4096
+
+	// sendTimeout is how long Send waits to write a message to a connection
+	// before the connection is dropped as too slow
+	sendTimeout = time.Second
+)
+
+var (
+	// ErrMessageTooLarge is returned when reading a message that is larger than
+	// our max size
+	ErrMessageTooLarge = errors.New("message to large")
+
+	errTooManyPendingMessages = errors.New("too many messages were sent while catching up")
 )
 
-// ErrMessageTooLarge is returned when reading a message that is larger than
-// our max size
-var ErrMessageTooLarge = errors.New("message to large")
+// ReplayFunc writes to [w] the messages from position [from] onwards and
+// returns the position of the next message to write
+type ReplayFunc func(w io.Writer, from uint64) (uint64, error)
 
 // Socket manages sending messages over a socket to many subscribed clients
 type Socket struct {
 	log      logging.Logger
 	addr     string
+	listen   func(addr string) (net.Listener, error)
 	accept   acceptFn
+	replay   ReplayFunc
+	from     uint64
 	connLock *sync.RWMutex
-	conns    map[net.Conn]struct{}
+	conns    map[net.Conn]*subscriber
 	quitCh   chan struct{}
 	doneCh   chan struct{}
 	listener net.Listener // the current listener
 }
 
+// subscriber is the state of a connection that messages are sent to
+type subscriber struct {
+	lock sync.Mutex
+	// True while the messages the connection missed are being replayed to it
+	catchingUp bool
+	// Messages sent while catching up, which are written once the replay is
+	// done
+	pending [][]byte
+}
+
 // NewSocket creates a new socket object for the given address. It does not open
 // the socket until Listen is called.
 func NewSocket(addr string, log logging.Logger) *Socket {
 	return &Socket{
 		log:      log,
 		addr:     addr,
+		listen:   listen,
 		accept:   accept,
 		connLock: &sync.RWMutex{},
-		conns:    map[net.Conn]struct{}{},
+		conns:    map[net.Conn]*subscriber{},
 		quitCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
 	}
 }
 
+// NewTCPSocket creates a new socket object that listens for TCP connections on
+// the given address. It does not open the socket until Listen is called.
+func NewTCPSocket(addr string, log logging.Logger) *Socket {
+	s := NewSocket(addr, log)
+	s.listen = listenTCP
+	return s
+}
+
+// SetReplay makes the socket write the messages from position [from] onwards
+// to each new connection, using [replay], before sending it new messages. Must
+// be called before Listen.
+func (s *Socket) SetReplay(from uint64, replay ReplayFunc) {
+	s.from = from
+	s.replay = replay
+}
+
 // Listen starts listening on the socket for new connection
 func (s *Socket) Listen() error {
-	l, err := listen(s.addr)
+	l, err := s.listen(s.addr)
 	if err != nil {
 		return err
 	}
@@ -78,38 +126,59 @@ func (s *Socket) Listen() error {
 	return nil
 }
 
-// Send writes the given message to all connection clients
+// Send writes the given message to all connection clients. Connections that
+// don't accept the message within [sendTimeout] are closed.
 func (s *Socket) Send(msg []byte) {
-	var conns []net.Conn = nil
+	var (
+		conns []net.Conn
+		subs  []*subscriber
+	)
 
 	// Get a copy of connections
 	s.connLock.RLock()
 	if len(s.conns) > 0 {
-		conns = make([]net.Conn, len(s.conns))
-		i := 0
-		for conn := range s.conns {
-			conns[i] = conn
-			i++
+		conns = make([]net.Conn, 0, len(s.conns))
+		subs = make([]*subscriber, 0, len(s.conns))
+		for conn, sub := range s.conns {
+			conns = append(conns, conn)
+			subs = append(subs, sub)
 		}
 	}
 	s.connLock.RUnlock()
 
 	// Write to each connection
-	if len(conns) == 0 {
-		return
+	for i, conn := range conns {
+		if queued, err := subs[i].queue(msg); queued {
+			if err != nil {
+				s.removeConn(conn)
+				_ = conn.Close()
+				s.log.Debug("failed to queue message for %s: %s", conn.RemoteAddr(), err)
+			}
+			continue
+		}
+		err := conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+		if err == nil {
+			err = WriteMessage(conn, msg)
+		}
+		if err != nil {
+			s.removeConn(conn)
+			_ = conn.Close()
+			s.log.Debug("failed to write message to %s: %s", conn.RemoteAddr(), err)
+		}
 	}
+}
 
-	// Prefix the message with an 8 byte length
+// WriteMessage writes [msg] to [w], prefixed with its 8 byte length, so that
+// it can be read by a Client
+func WriteMessage(w io.Writer, msg []byte) error {
 	lenBytes := [8]byte{}
 	binary.BigEndian.PutUint64(lenBytes[:], uint64(len(msg)))
-	for _, conn := range conns {
-		for _, byteSlice := range [][]byte{lenBytes[:], msg} {
-			if _, err := conn.Write(byteSlice); err != nil {
-				s.removeConn(conn)
-				s.log.Debug("failed to write message to %s: %s", conn.RemoteAddr(), err)
-			}
+	for _, byteSlice := range [][]byte{lenBytes[:], msg} {
+		if _, err := w.Write(byteSlice); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // Close closes the socket by cutting off new connections, closing all
@@ -142,6 +211,15 @@ func (s *Socket) Close() error {
 	return errs.Err
 }
 
+// Addr returns the address the socket is listening on, or nil if it isn't
+// listening
+func (s *Socket) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
 func (s *Socket) Running() bool {
 	return s.listener != nil
 }
@@ -158,6 +236,15 @@ type Client struct {
 	maxMessageSize int64
 }
 
+// DialTCP creates a new *Client connected to the given address over TCP
+func DialTCP(addr string) (*Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Conn: c, maxMessageSize: DefaultMaxMessageSize}, nil
+}
+
 // Recv waits for a message from the socket. It's guaranteed to either return a
 // complete message or an error
 func (c *Client) Recv() ([]byte, error) {
@@ -206,6 +293,11 @@ func (e errReadTimeout) Error() string {
 	return fmt.Sprintf("read from %s timed out", e.addr)
 }
 
+// listenTCP creates a net.Listener for TCP connections on [addr]
+func listenTCP(addr string) (net.Listener, error) {
+	return net.Listen("tcp", addr)
+}
+
 // acceptFn takes accepts connections from a Listener and gives them to a Socket
 type acceptFn func(*Socket, net.Listener)
 
@@ -218,6 +310,7 @@ func accept(s *Socket, l net.Listener) {
 			return
 		}
 		s.log.Error("socket accept error: %s", err.Error())
+		return
 	}
 	if conn, ok := conn.(*net.TCPConn); ok {
 		if err := conn.SetLinger(0); err != nil {
@@ -227,9 +320,94 @@ func accept(s *Socket, l net.Listener) {
 			s.log.Warn("failed to set socket nodelay due to: %s", err)
 		}
 	}
+	if s.replay == nil {
+		s.connLock.Lock()
+		s.conns[conn] = &subscriber{}
+		s.connLock.Unlock()
+		return
+	}
+	go s.catchUp(conn)
+}
+
+// catchUp replays to [conn] the messages it missed and then adds it to the
+// connection pool
+func (s *Socket) catchUp(conn net.Conn) {
+	// Replay most of the messages without blocking Send
+	next, err := s.replay(conn, s.from)
+	if err != nil {
+		s.log.Debug("failed to replay messages to %s: %s", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+
+	// Snapshot the messages sent since then while Send is blocked, so that no
+	// message is missed, and add the connection to the pool. Messages sent
+	// after the connection is added are queued until the snapshot is written.
+	sub := &subscriber{catchingUp: true}
+	snapshot := &bytes.Buffer{}
 	s.connLock.Lock()
-	s.conns[conn] = struct{}{}
+	if s.conns == nil {
+		// The socket was closed
+		s.connLock.Unlock()
+		_ = conn.Close()
+		return
+	}
+	if _, err := s.replay(snapshot, next); err != nil {
+		s.connLock.Unlock()
+		s.log.Debug("failed to replay messages to %s: %s", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+	s.conns[conn] = sub
 	s.connLock.Unlock()
+
+	// Write the snapshot and the queued messages without blocking Send
+	_, err = snapshot.WriteTo(conn)
+	for err == nil {
+		pending := sub.takePending()
+		if len(pending) == 0 {
+			return
+		}
+		for _, msg := range pending {
+			if err = WriteMessage(conn, msg); err != nil {
+				break
+			}
+		}
+	}
+	s.removeConn(conn)
+	_ = conn.Close()
+	s.log.Debug("failed to replay messages to %s: %s", conn.RemoteAddr(), err)
+}
+
+// queue holds [msg] to be written once the subscriber caught up. Returns false
+// if the subscriber caught up, in which case [msg] should be written now.
+func (sub *subscriber) queue(msg []byte) (bool, error) {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	if !sub.catchingUp {
+		return false, nil
+	}
+	if len(sub.pending) >= maxPendingMessages {
+		return true, errTooManyPendingMessages
+	}
+	sub.pending = append(sub.pending, msg)
+	return true, nil
+}
+
+// takePending returns the messages queued since the last call. If there
+// aren't any, the subscriber is marked as caught up so that messages are no
+// longer queued.
+func (sub *subscriber) takePending() [][]byte {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	pending := sub.pending
+	sub.pending = nil
+	if len(pending) == 0 {
+		sub.catchingUp = false
+	}
+	return pending
 }
 
 // isTimeoutError checks if an error is a timeout as per the net.Error interface
@@ -1,8 +1,14 @@
 package socket
 
 import (
+	"bytes"
+	"io"
 	"net"
 	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 func TestSocketSendAndReceive(t *testing.T) {
@@ -14,7 +20,7 @@ func TestSocketSendAndReceive(t *testing.T) {
 	)
 
 	// Create socket and client; wait for client to connect
-	socket := NewSocket(socketName, nil)
+	socket := NewSocket(socketName, logging.NoLog{})
 	socket.accept, connCh = newTestAcceptFn()
 	if err := socket.Listen(); err != nil {
 		t.Fatal("Failed to listen on socket:", err.Error())
@@ -65,9 +71,156 @@ func newTestAcceptFn() (acceptFn, chan net.Conn) {
 		}
 
 		s.connLock.Lock()
-		s.conns[conn] = struct{}{}
+		s.conns[conn] = &subscriber{}
 		s.connLock.Unlock()
 
 		connCh <- conn
 	}, connCh
 }
+
+func TestTCPSocketReplay(t *testing.T) {
+	socket := NewTCPSocket("127.0.0.1:0", logging.NoLog{})
+	socket.SetReplay(1, func(w io.Writer, from uint64) (uint64, error) {
+		for ; from < 3; from++ {
+			if err := WriteMessage(w, []byte{byte(from)}); err != nil {
+				return from, err
+			}
+		}
+		return from, nil
+	})
+	if err := socket.Listen(); err != nil {
+		t.Fatal("Failed to listen on socket:", err.Error())
+	}
+	defer socket.Close()
+
+	client, err := DialTCP(socket.Addr().String())
+	if err != nil {
+		t.Fatal("Failed to dial socket:", err.Error())
+	}
+	defer client.Close()
+
+	for _, expected := range []byte{1, 2} {
+		msg, err := client.Recv()
+		if err != nil {
+			t.Fatal("Failed to receive from socket:", err.Error())
+		}
+		if !bytes.Equal(msg, []byte{expected}) {
+			t.Fatalf("Received %v but expected %v", msg, []byte{expected})
+		}
+	}
+}
+
+func TestTCPSocketSendDuringCatchUp(t *testing.T) {
+	const numReplayed = 8
+
+	msg := make([]byte, units.MiB)
+	replays := 0
+	socket := NewTCPSocket("127.0.0.1:0", logging.NoLog{})
+	socket.SetReplay(0, func(w io.Writer, from uint64) (uint64, error) {
+		// Replay everything in the snapshot, which is written after the
+		// connection is added to the pool
+		replays++
+		if replays == 1 {
+			return from, nil
+		}
+		for ; from < numReplayed; from++ {
+			if err := WriteMessage(w, msg); err != nil {
+				return from, err
+			}
+		}
+		return from, nil
+	})
+	if err := socket.Listen(); err != nil {
+		t.Fatal("Failed to listen on socket:", err.Error())
+	}
+	defer socket.Close()
+
+	client, err := DialTCP(socket.Addr().String())
+	if err != nil {
+		t.Fatal("Failed to dial socket:", err.Error())
+	}
+	defer client.Close()
+
+	for {
+		socket.connLock.RLock()
+		numConns := len(socket.conns)
+		socket.connLock.RUnlock()
+		if numConns == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The client isn't reading, so the snapshot can't be written yet. Send
+	// shouldn't wait for it.
+	sent := make(chan struct{})
+	go func() {
+		socket.Send([]byte{1})
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send blocked on a connection that is catching up")
+	}
+
+	for i := 0; i < numReplayed; i++ {
+		received, err := client.Recv()
+		if err != nil {
+			t.Fatal("Failed to receive from socket:", err.Error())
+		}
+		if len(received) != len(msg) {
+			t.Fatalf("Received %d bytes but expected %d", len(received), len(msg))
+		}
+	}
+	received, err := client.Recv()
+	if err != nil {
+		t.Fatal("Failed to receive from socket:", err.Error())
+	}
+	if !bytes.Equal(received, []byte{1}) {
+		t.Fatalf("Received %v but expected %v", received, []byte{1})
+	}
+}
+
+func TestTCPSocketDropsSlowConnection(t *testing.T) {
+	socket := NewTCPSocket("127.0.0.1:0", logging.NoLog{})
+	if err := socket.Listen(); err != nil {
+		t.Fatal("Failed to listen on socket:", err.Error())
+	}
+	defer socket.Close()
+
+	client, err := DialTCP(socket.Addr().String())
+	if err != nil {
+		t.Fatal("Failed to dial socket:", err.Error())
+	}
+	defer client.Close()
+
+	for {
+		socket.connLock.RLock()
+		numConns := len(socket.conns)
+		socket.connLock.RUnlock()
+		if numConns == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The client isn't reading, so once the socket buffers are full the
+	// connection should be dropped rather than block Send.
+	msg := make([]byte, units.MiB)
+	for i := 0; i < 256; i++ {
+		start := time.Now()
+		socket.Send(msg)
+		if elapsed := time.Since(start); elapsed > 2*sendTimeout {
+			t.Fatalf("Send blocked for %s", elapsed)
+		}
+
+		socket.connLock.RLock()
+		numConns := len(socket.conns)
+		socket.connLock.RUnlock()
+		if numConns == 0 {
+			return
+		}
+	}
+	t.Fatal("Slow connection wasn't dropped")
+}
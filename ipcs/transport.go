@@ -0,0 +1,144 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/ava-labs/avalanchego/ipcs/socket"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// Transport is the kind of channel that events are published over
+type Transport string
+
+const (
+	// UnixTransport publishes events over a Unix domain socket, or a named
+	// pipe on Windows
+	UnixTransport Transport = "unix"
+	// TCPTransport publishes events over a TCP socket listening on an
+	// ephemeral port
+	TCPTransport Transport = "tcp"
+	// FileTransport appends events to a file
+	FileTransport Transport = "file"
+)
+
+// TransportConfig describes how events are published
+type TransportConfig struct {
+	Transport Transport
+	// Host that TCP sockets listen on. Only used by TCPTransport.
+	TCPHost string
+}
+
+// ParseTransport returns the Transport named [name]
+func ParseTransport(name string) (Transport, error) {
+	switch transport := Transport(name); transport {
+	case UnixTransport, TCPTransport, FileTransport:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("unknown IPC transport %q", name)
+	}
+}
+
+// transport publishes the messages given to Send to the consumers of an
+// eventSocket
+type transport interface {
+	Send(msg []byte)
+	// URL returns where consumers read the messages from
+	URL() string
+	Close() error
+}
+
+// socketTransport publishes messages to the clients connected to a socket
+type socketTransport struct {
+	*socket.Socket
+	url string
+}
+
+// newSocketTransport starts listening on [s]. If [replay] is non-nil, each new
+// connection is sent the messages it returns from position [from] onwards
+// before receiving new messages.
+func newSocketTransport(s *socket.Socket, from uint64, replay socket.ReplayFunc) (*socketTransport, error) {
+	if replay != nil {
+		s.SetReplay(from, replay)
+	}
+	if err := s.Listen(); err != nil {
+		return nil, err
+	}
+	return &socketTransport{Socket: s}, nil
+}
+
+// newUnixTransport publishes messages over the Unix socket at [path]
+func newUnixTransport(path string, log logging.Logger, from uint64, replay socket.ReplayFunc) (transport, error) {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, syscall.ENOENT) {
+		return nil, err
+	}
+
+	t, err := newSocketTransport(socket.NewSocket(path, log), from, replay)
+	if err != nil {
+		return nil, err
+	}
+	t.url = path
+	return t, nil
+}
+
+// newTCPTransport publishes messages over a TCP socket listening on an
+// ephemeral port of [host]
+func newTCPTransport(host string, log logging.Logger, from uint64, replay socket.ReplayFunc) (transport, error) {
+	t, err := newSocketTransport(socket.NewTCPSocket(net.JoinHostPort(host, "0"), log), from, replay)
+	if err != nil {
+		return nil, err
+	}
+	t.url = t.Addr().String()
+	return t, nil
+}
+
+func (t *socketTransport) URL() string { return t.url }
+
+// fileTransport appends messages to a file
+type fileTransport struct {
+	log  logging.Logger
+	url  string
+	lock sync.Mutex
+	file *os.File
+}
+
+// newFileTransport appends messages to the file at [path], creating it if
+// it doesn't exist
+func newFileTransport(path string, log logging.Logger) (*fileTransport, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+	return &fileTransport{
+		log:  log,
+		url:  path,
+		file: file,
+	}, nil
+}
+
+func (t *fileTransport) Send(msg []byte) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err := socket.WriteMessage(t.file, msg); err != nil {
+		t.log.Error("failed to append message to %s: %s", t.url, err)
+	}
+}
+
+func (t *fileTransport) URL() string { return t.url }
+
+func (t *fileTransport) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.file.Close()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/rocksdb"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -102,14 +103,18 @@ func (m *migrationManager) shouldMigrate() (bool, error) {
 		dbManager, err = manager.NewRocksDB(path, logging.NoLog{}, version.CurrentDatabase, true)
 	case leveldb.Name:
 		dbManager, err = manager.NewLevelDB(m.rootConfig.DBPath, logging.NoLog{}, version.CurrentDatabase, true)
+	case pebble.Name:
+		path := filepath.Join(m.rootConfig.DBPath, pebble.Name)
+		dbManager, err = manager.NewPebble(path, logging.NoLog{}, version.CurrentDatabase, true)
 	case memdb.Name:
 		return false, nil
 	default:
 		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s, %s}",
+			"db-type was %q but should have been one of {%s, %s, %s, %s}",
 			m.rootConfig.DBName,
 			leveldb.Name,
 			rocksdb.Name,
+			pebble.Name,
 			memdb.Name,
 		)
 	}
@@ -0,0 +1,100 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"github.com/ava-labs/avalanchego/network/message"
+	"github.com/ava-labs/avalanchego/utils/compression"
+)
+
+// buildFunc builds a message, compressed with [compressionType] if
+// [includeIsCompressedFlag]
+type buildFunc func(includeIsCompressedFlag bool, compressionType compression.Type) (message.Message, error)
+
+// compressedMessages holds a message that is sent to many peers. Peers may
+// negotiate different compression types, so the message is built once for
+// each compression type it's sent with. Not safe for concurrent use.
+type compressedMessages struct {
+	build buildFunc
+
+	// Sent to peers that can't handle compressed messages
+	withoutIsCompressedFlag message.Message
+	// Compression type --> message sent to peers that negotiated it
+	withIsCompressedFlag map[compression.Type]message.Message
+}
+
+// newCompressedMessages builds the message sent to peers that can't handle
+// compressed messages right away, so that messages that can't be built are
+// reported before they're sent to anyone. Compressed messages are built the
+// first time they're needed.
+func newCompressedMessages(build buildFunc) (*compressedMessages, error) {
+	msg, err := build(false, compression.NoCompression)
+	if err != nil {
+		return nil, err
+	}
+	return &compressedMessages{
+		build:                   build,
+		withoutIsCompressedFlag: msg,
+		withIsCompressedFlag:    make(map[compression.Type]message.Message),
+	}, nil
+}
+
+// get returns the message to send to [p]
+func (m *compressedMessages) get(p *peer) (message.Message, error) {
+	if !p.canHandleCompressed.GetValue() {
+		return m.withoutIsCompressedFlag, nil
+	}
+	return m.getWithIsCompressedFlag(p.compressionType())
+}
+
+// relayed returns the message to relay. Relayed messages always include the
+// isCompressed flag and aren't compressed, since the node they're relayed to
+// didn't negotiate a compression type with us.
+func (m *compressedMessages) relayed() (message.Message, error) {
+	return m.getWithIsCompressedFlag(compression.NoCompression)
+}
+
+// getWithIsCompressedFlag returns the message compressed with
+// [compressionType], which includes the isCompressed flag
+func (m *compressedMessages) getWithIsCompressedFlag(compressionType compression.Type) (message.Message, error) {
+	if msg, ok := m.withIsCompressedFlag[compressionType]; ok {
+		return msg, nil
+	}
+	msg, err := m.build(true, compressionType)
+	if err != nil {
+		return nil, err
+	}
+	m.withIsCompressedFlag[compressionType] = msg
+	return msg, nil
+}
+
+// negotiateCompression returns the compression type to compress messages sent
+// to a peer with. [preferred] is the compression type this node prefers and
+// [dictionaryID] is the ID of its zstd dictionary, or 0 if it has none.
+// [peerTypes] are the compression types the peer can decompress and
+// [peerDictionaryID] is the ID of the peer's zstd dictionary. Zstd with a
+// dictionary is only used if both nodes have the same dictionary. Gzip, which
+// every peer that can handle compressed messages supports, is used otherwise.
+func negotiateCompression(
+	preferred compression.Type,
+	dictionaryID uint32,
+	peerTypes []compression.Type,
+	peerDictionaryID uint32,
+) compression.Type {
+	if preferred != compression.Zstd {
+		return compression.Gzip
+	}
+	peerSupports := make(map[compression.Type]bool, len(peerTypes))
+	for _, peerType := range peerTypes {
+		peerSupports[peerType] = true
+	}
+	switch {
+	case dictionaryID != 0 && dictionaryID == peerDictionaryID && peerSupports[compression.ZstdWithDictionary]:
+		return compression.ZstdWithDictionary
+	case peerSupports[compression.Zstd]:
+		return compression.Zstd
+	default:
+		return compression.Gzip
+	}
+}
@@ -0,0 +1,134 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/network/message"
+	"github.com/ava-labs/avalanchego/utils/compression"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	allTypes := []compression.Type{compression.Gzip, compression.Zstd, compression.ZstdWithDictionary}
+	tests := []struct {
+		name             string
+		preferred        compression.Type
+		dictionaryID     uint32
+		peerTypes        []compression.Type
+		peerDictionaryID uint32
+		expected         compression.Type
+	}{
+		{
+			name:      "prefer gzip",
+			preferred: compression.Gzip,
+			peerTypes: allTypes,
+			expected:  compression.Gzip,
+		},
+		{
+			name:      "prefer zstd",
+			preferred: compression.Zstd,
+			peerTypes: allTypes,
+			expected:  compression.Zstd,
+		},
+		{
+			name:      "peer doesn't support zstd",
+			preferred: compression.Zstd,
+			peerTypes: []compression.Type{compression.Gzip},
+			expected:  compression.Gzip,
+		},
+		{
+			name:             "same dictionary",
+			preferred:        compression.Zstd,
+			dictionaryID:     1,
+			peerTypes:        allTypes,
+			peerDictionaryID: 1,
+			expected:         compression.ZstdWithDictionary,
+		},
+		{
+			name:             "different dictionary",
+			preferred:        compression.Zstd,
+			dictionaryID:     1,
+			peerTypes:        allTypes,
+			peerDictionaryID: 2,
+			expected:         compression.Zstd,
+		},
+		{
+			name:             "peer didn't list dictionary",
+			preferred:        compression.Zstd,
+			dictionaryID:     1,
+			peerTypes:        []compression.Type{compression.Gzip, compression.Zstd},
+			peerDictionaryID: 1,
+			expected:         compression.Zstd,
+		},
+		{
+			name:             "no dictionary",
+			preferred:        compression.Zstd,
+			peerTypes:        allTypes,
+			peerDictionaryID: 0,
+			expected:         compression.Zstd,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compressionType := negotiateCompression(test.preferred, test.dictionaryID, test.peerTypes, test.peerDictionaryID)
+			assert.Equal(t, test.expected, compressionType)
+		})
+	}
+}
+
+func TestCompressedMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := message.NewCodec("", prometheus.NewRegistry(), int64(DefaultMaxMessageSize))
+	assert.NoError(err)
+	builder := message.NewBuilder(codec)
+
+	builds := make(map[compression.Type]int)
+	msgs, err := newCompressedMessages(func(includeIsCompressedFlag bool, compressionType compression.Type) (message.Message, error) {
+		builds[compressionType]++
+		return builder.PeerList(nil, includeIsCompressedFlag, compressionType)
+	})
+	assert.NoError(err)
+	assert.Equal(1, builds[compression.NoCompression])
+
+	net := &network{compressionEnabled: true}
+	oldPeer := &peer{net: net}
+	gzipPeer := &peer{net: net}
+	gzipPeer.canHandleCompressed.SetValue(true)
+	zstdPeer := &peer{net: net}
+	zstdPeer.canHandleCompressed.SetValue(true)
+	zstdPeer.negotiatedCompression.SetValue(compression.Zstd)
+
+	for i := 0; i < 2; i++ {
+		msg, err := msgs.get(oldPeer)
+		assert.NoError(err)
+		assert.Equal(msgs.withoutIsCompressedFlag, msg)
+
+		msg, err = msgs.get(gzipPeer)
+		assert.NoError(err)
+		assert.Equal(byte(compression.Gzip), msg.Bytes()[1])
+
+		msg, err = msgs.get(zstdPeer)
+		assert.NoError(err)
+		assert.Equal(byte(compression.Zstd), msg.Bytes()[1])
+	}
+
+	// Each message is only built once
+	assert.Equal(map[compression.Type]int{
+		compression.NoCompression: 1,
+		compression.Gzip:          1,
+		compression.Zstd:          1,
+	}, builds)
+
+	// Messages aren't compressed if compression is disabled
+	net.compressionEnabled = false
+	msg, err := msgs.get(zstdPeer)
+	assert.NoError(err)
+	assert.Equal(byte(compression.NoCompression), msg.Bytes()[1])
+}
@@ -4,8 +4,11 @@
 package message
 
 import (
+	"crypto/x509"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 )
 
 var _ Builder = &builder{}
@@ -27,8 +30,16 @@ type Builder interface {
 
 	PeerList(
 		peers []utils.IPCertDesc,
-		includeIsCompressedFlag,
-		compress bool,
+		includeIsCompressedFlag bool,
+		compressionType compression.Type,
+	) (Message, error)
+
+	// Compressors tells a peer the types of compression the messages it sends
+	// may be compressed with, and the ID of the zstd dictionary they may be
+	// compressed with, which is 0 if there isn't one
+	Compressors(
+		compressionTypes []compression.Type,
+		dictionaryID uint32,
 	) (Message, error)
 
 	Ping() (Message, error)
@@ -72,7 +83,7 @@ type Builder interface {
 		requestID uint32,
 		containers [][]byte,
 		includeIsCompressedFlag bool,
-		compressed bool,
+		compressionType compression.Type,
 	) (Message, error)
 
 	Get(
@@ -88,7 +99,7 @@ type Builder interface {
 		containerID ids.ID,
 		container []byte,
 		includeIsCompressedFlag bool,
-		compress bool,
+		compressionType compression.Type,
 	) (Message, error)
 
 	PushQuery(
@@ -98,7 +109,7 @@ type Builder interface {
 		containerID ids.ID,
 		container []byte,
 		includeIsCompressedFlag bool,
-		compress bool,
+		compressionType compression.Type,
 	) (Message, error)
 
 	PullQuery(
@@ -113,6 +124,20 @@ type Builder interface {
 		requestID uint32,
 		containerIDs []ids.ID,
 	) (Message, error)
+
+	// RelayRequest asks a peer to relay messages on our behalf. [sig] is our
+	// signature of the peer's node ID and [authTime], the Unix time the
+	// request was made at.
+	RelayRequest(authTime uint64, sig []byte) (Message, error)
+
+	// RelayAuth tells a peer that the node with [cert], which runs
+	// [nodeVersion], asked us to relay messages on its behalf at [authTime].
+	// [sig] is that node's signature of our node ID and [authTime].
+	RelayAuth(cert *x509.Certificate, nodeVersion string, authTime uint64, sig []byte) (Message, error)
+
+	// Relay wraps [msg], a message sent to or received from [nodeID], so that
+	// it can be relayed.
+	Relay(nodeID ids.ShortID, msg []byte) (Message, error)
 }
 
 type builder struct{ c Codec }
@@ -126,7 +151,7 @@ func (b *builder) GetVersion() (Message, error) {
 		GetVersion,
 		nil,
 		GetVersion.Compressable(), // GetVersion messages can't be compressed
-		GetVersion.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -151,7 +176,7 @@ func (b *builder) Version(
 			SigBytes:    sig,
 		},
 		Version.Compressable(), // Version Messages can't be compressed
-		Version.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -160,18 +185,34 @@ func (b *builder) GetPeerList() (Message, error) {
 		GetPeerList,
 		nil,
 		GetPeerList.Compressable(), // GetPeerList messages can't be compressed
-		GetPeerList.Compressable(),
+		compression.NoCompression,
 	)
 }
 
-func (b *builder) PeerList(peers []utils.IPCertDesc, includeIsCompressedFlag, compress bool) (Message, error) {
+func (b *builder) PeerList(peers []utils.IPCertDesc, includeIsCompressedFlag bool, compressionType compression.Type) (Message, error) {
 	return b.c.Pack(
 		PeerList,
 		map[Field]interface{}{
 			SignedPeers: peers,
 		},
 		includeIsCompressedFlag, // PeerList messages may be compressed
-		compress && PeerList.Compressable(),
+		compressionType,
+	)
+}
+
+func (b *builder) Compressors(compressionTypes []compression.Type, dictionaryID uint32) (Message, error) {
+	typeBytes := make([]byte, len(compressionTypes))
+	for i, compressionType := range compressionTypes {
+		typeBytes[i] = byte(compressionType)
+	}
+	return b.c.Pack(
+		Compressors,
+		map[Field]interface{}{
+			CompressionTypes: typeBytes,
+			DictionaryID:     dictionaryID,
+		},
+		Compressors.Compressable(), // Compressors messages can't be compressed
+		compression.NoCompression,
 	)
 }
 
@@ -180,7 +221,7 @@ func (b *builder) Ping() (Message, error) {
 		Ping,
 		nil,
 		Ping.Compressable(), // Ping messages can't be compressed
-		Ping.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -189,7 +230,7 @@ func (b *builder) Pong() (Message, error) {
 		Pong,
 		nil,
 		Pong.Compressable(), // Ping messages can't be compressed
-		Pong.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -206,7 +247,7 @@ func (b *builder) GetAcceptedFrontier(
 			Deadline:  deadline,
 		},
 		GetAcceptedFrontier.Compressable(), // GetAcceptedFrontier messages can't be compressed
-		GetAcceptedFrontier.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -228,7 +269,7 @@ func (b *builder) AcceptedFrontier(
 			ContainerIDs: containerIDBytes,
 		},
 		AcceptedFrontier.Compressable(), // AcceptedFrontier messages can't be compressed
-		AcceptedFrontier.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -252,7 +293,7 @@ func (b *builder) GetAccepted(
 			ContainerIDs: containerIDBytes,
 		},
 		GetAccepted.Compressable(), // GetAccepted messages can't be compressed
-		GetAccepted.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -274,7 +315,7 @@ func (b *builder) Accepted(
 			ContainerIDs: containerIDBytes,
 		},
 		Accepted.Compressable(), // Accepted messages can't be compressed
-		Accepted.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -293,7 +334,7 @@ func (b *builder) GetAncestors(
 			ContainerID: containerID[:],
 		},
 		GetAncestors.Compressable(), // GetAncestors messages can't be compressed
-		GetAncestors.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -302,7 +343,7 @@ func (b *builder) MultiPut(
 	requestID uint32,
 	containers [][]byte,
 	includeIsCompressedFlag bool,
-	compressed bool,
+	compressionType compression.Type,
 ) (Message, error) {
 	return b.c.Pack(
 		MultiPut,
@@ -312,7 +353,7 @@ func (b *builder) MultiPut(
 			MultiContainerBytes: containers,
 		},
 		includeIsCompressedFlag, // MultiPut messages may be compressed
-		compressed && MultiPut.Compressable(),
+		compressionType,
 	)
 }
 
@@ -331,7 +372,7 @@ func (b *builder) Get(
 			ContainerID: containerID[:],
 		},
 		Get.Compressable(), // Get messages can't be compressed
-		Get.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -341,7 +382,7 @@ func (b *builder) Put(
 	containerID ids.ID,
 	container []byte,
 	includeIsCompressedFlag bool,
-	compress bool,
+	compressionType compression.Type,
 ) (Message, error) {
 	return b.c.Pack(
 		Put,
@@ -352,7 +393,7 @@ func (b *builder) Put(
 			ContainerBytes: container,
 		},
 		includeIsCompressedFlag, // Put messages may be compressed
-		compress && Put.Compressable(),
+		compressionType,
 	)
 }
 
@@ -363,7 +404,7 @@ func (b *builder) PushQuery(
 	containerID ids.ID,
 	container []byte,
 	includeIsCompressedFlag bool,
-	compress bool,
+	compressionType compression.Type,
 ) (Message, error) {
 	return b.c.Pack(
 		PushQuery,
@@ -375,7 +416,7 @@ func (b *builder) PushQuery(
 			ContainerBytes: container,
 		},
 		includeIsCompressedFlag, // PushQuery messages may be compressed
-		compress && PushQuery.Compressable(),
+		compressionType,
 	)
 }
 
@@ -394,7 +435,7 @@ func (b *builder) PullQuery(
 			ContainerID: containerID[:],
 		},
 		PullQuery.Compressable(), // PullQuery messages can't be compressed
-		PullQuery.Compressable(),
+		compression.NoCompression,
 	)
 }
 
@@ -416,6 +457,44 @@ func (b *builder) Chits(
 			ContainerIDs: containerIDBytes,
 		},
 		Chits.Compressable(), // Chits messages can't be compressed
-		Chits.Compressable(),
+		compression.NoCompression,
+	)
+}
+
+func (b *builder) RelayRequest(authTime uint64, sig []byte) (Message, error) {
+	return b.c.Pack(
+		RelayRequest,
+		map[Field]interface{}{
+			VersionTime: authTime,
+			SigBytes:    sig,
+		},
+		RelayRequest.Compressable(), // RelayRequest messages can't be compressed
+		compression.NoCompression,
+	)
+}
+
+func (b *builder) RelayAuth(cert *x509.Certificate, nodeVersion string, authTime uint64, sig []byte) (Message, error) {
+	return b.c.Pack(
+		RelayAuth,
+		map[Field]interface{}{
+			Cert:        cert,
+			VersionStr:  nodeVersion,
+			VersionTime: authTime,
+			SigBytes:    sig,
+		},
+		RelayAuth.Compressable(), // RelayAuth messages can't be compressed
+		compression.NoCompression,
+	)
+}
+
+func (b *builder) Relay(nodeID ids.ShortID, msg []byte) (Message, error) {
+	return b.c.Pack(
+		Relay,
+		map[Field]interface{}{
+			RelayNodeID: nodeID[:],
+			RelayedMsg:  msg,
+		},
+		Relay.Compressable(), // Relay messages can't be compressed
+		compression.NoCompression,
 	)
 }
@@ -4,6 +4,7 @@
 package message
 
 import (
+	"crypto/x509"
 	"net"
 	"testing"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/version"
 )
@@ -233,7 +236,7 @@ func TestBuildPut(t *testing.T) {
 	container := []byte{2}
 
 	{ // no compression
-		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, false, false)
+		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, false, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, Put, msg.Op())
@@ -254,7 +257,7 @@ func TestBuildPut(t *testing.T) {
 	}
 
 	{ // no compression, with isCompressed flag
-		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, true, false)
+		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, true, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, Put, msg.Op())
@@ -278,7 +281,7 @@ func TestBuildPut(t *testing.T) {
 	}
 
 	{ // with compression
-		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, true, true)
+		msg, err := TestBuilder.Put(chainID, requestID, containerID, container, true, compression.Gzip)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, Put, msg.Op())
@@ -306,7 +309,7 @@ func TestBuildPushQuery(t *testing.T) {
 	container := []byte{2}
 
 	{ // no compression
-		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, false, false)
+		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, false, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, PushQuery, msg.Op())
@@ -329,7 +332,7 @@ func TestBuildPushQuery(t *testing.T) {
 	}
 
 	{ // no compression, with isCompressed flag
-		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, true, false)
+		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, true, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, PushQuery, msg.Op())
@@ -355,7 +358,7 @@ func TestBuildPushQuery(t *testing.T) {
 	}
 
 	{ // with compression
-		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, true, true)
+		msg, err := TestBuilder.PushQuery(chainID, requestID, deadline, containerID, container, true, compression.Gzip)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, PushQuery, msg.Op())
@@ -435,7 +438,7 @@ func TestBuildMultiPut(t *testing.T) {
 	containers := [][]byte{container[:], container2[:]}
 
 	{ // no compression
-		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, false, false)
+		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, false, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, MultiPut, msg.Op())
@@ -454,7 +457,7 @@ func TestBuildMultiPut(t *testing.T) {
 	}
 
 	{ // no compression, with isCompress flag
-		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, true, false)
+		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, true, compression.NoCompression)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, MultiPut, msg.Op())
@@ -476,7 +479,7 @@ func TestBuildMultiPut(t *testing.T) {
 	}
 
 	{ // with compression
-		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, true, true)
+		msg, err := TestBuilder.MultiPut(chainID, requestID, containers, true, compression.Gzip)
 		assert.NoError(t, err)
 		assert.NotNil(t, msg)
 		assert.Equal(t, MultiPut, msg.Op())
@@ -493,3 +496,76 @@ func TestBuildMultiPut(t *testing.T) {
 		assert.Equal(t, containers, parsedMsg.Get(MultiContainerBytes))
 	}
 }
+
+func TestBuildCompressors(t *testing.T) {
+	compressionTypes := []compression.Type{compression.Gzip, compression.Zstd}
+	msg, err := TestBuilder.Compressors(compressionTypes, 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Compressors, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), true)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Compressors, parsedMsg.Op())
+	assert.Equal(t, []byte{byte(compression.Gzip), byte(compression.Zstd)}, parsedMsg.Get(CompressionTypes))
+	assert.Equal(t, uint32(1), parsedMsg.Get(DictionaryID))
+}
+
+func TestBuildRelayRequest(t *testing.T) {
+	sig := make([]byte, 65)
+	authTime := uint64(time.Now().Unix())
+	msg, err := TestBuilder.RelayRequest(authTime, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, RelayRequest, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), true)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, RelayRequest, parsedMsg.Op())
+	assert.Equal(t, authTime, parsedMsg.Get(VersionTime))
+	assert.Equal(t, sig, parsedMsg.Get(SigBytes))
+}
+
+func TestBuildRelayAuth(t *testing.T) {
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	assert.NoError(t, err)
+	sig := make([]byte, 65)
+	nodeVersion := "avalanche/1.4.14"
+	authTime := uint64(time.Now().Unix())
+
+	msg, err := TestBuilder.RelayAuth(cert, nodeVersion, authTime, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, RelayAuth, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), true)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, RelayAuth, parsedMsg.Op())
+	assert.Equal(t, cert.Raw, parsedMsg.Get(Cert).(*x509.Certificate).Raw)
+	assert.Equal(t, nodeVersion, parsedMsg.Get(VersionStr))
+	assert.Equal(t, authTime, parsedMsg.Get(VersionTime))
+	assert.Equal(t, sig, parsedMsg.Get(SigBytes))
+}
+
+func TestBuildRelay(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	chits, err := TestBuilder.Chits(ids.Empty.Prefix(0), 1, []ids.ID{ids.Empty.Prefix(1)})
+	assert.NoError(t, err)
+
+	msg, err := TestBuilder.Relay(nodeID, chits.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Relay, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), true)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Relay, parsedMsg.Op())
+	assert.Equal(t, nodeID[:], parsedMsg.Get(RelayNodeID))
+	assert.Equal(t, chits.Bytes(), parsedMsg.Get(RelayedMsg))
+}
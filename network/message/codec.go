@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,6 +21,7 @@ var (
 	errMissingField      = errors.New("message missing field")
 	errBadOp             = errors.New("input field has invalid operation")
 	errCompressNeedsFlag = errors.New("compressed message requires isCompressed flag")
+	errUnknownCompressor = errors.New("message is compressed with an unknown compression type")
 
 	_ Codec = &codec{}
 )
@@ -29,12 +31,29 @@ type Codec interface {
 		op Op,
 		fieldValues map[Field]interface{},
 		includeIsCompressedFlag bool,
-		compress bool,
+		compressionType compression.Type,
 	) (Message, error)
 
 	Parse(bytes []byte, parseIsCompressedFlag bool) (Message, error)
 }
 
+// compressionMetrics track compressing, or decompressing, messages of one op
+// with one type of compression
+type compressionMetrics struct {
+	// Time in ns to (de)compress a message
+	time metric.Averager
+	// Size of the compressed payload divided by the size of the uncompressed
+	// payload
+	ratio metric.Averager
+}
+
+func (m *compressionMetrics) observe(start time.Time, uncompressedSize, compressedSize int) {
+	m.time.Observe(float64(time.Since(start)))
+	if uncompressedSize > 0 {
+		m.ratio.Observe(float64(compressedSize) / float64(uncompressedSize))
+	}
+}
+
 // codec defines the serialization and deserialization of network messages.
 // It's safe for multiple goroutines to call Pack and Parse concurrently.
 type codec struct {
@@ -42,9 +61,9 @@ type codec struct {
 	// [getBytes] must be safe for concurrent access by multiple goroutines.
 	getBytes func() []byte
 
-	compressTimeMetrics   map[Op]metric.Averager
-	decompressTimeMetrics map[Op]metric.Averager
-	compressor            compression.Compressor
+	compressMetrics   map[compression.Type]map[Op]*compressionMetrics
+	decompressMetrics map[compression.Type]map[Op]*compressionMetrics
+	compressors       map[compression.Type]compression.Compressor
 }
 
 func NewCodec(namespace string, metrics prometheus.Registerer, maxMessageSize int64) (Codec, error) {
@@ -53,39 +72,88 @@ func NewCodec(namespace string, metrics prometheus.Registerer, maxMessageSize in
 		metrics,
 		func() []byte { return nil },
 		maxMessageSize,
+		nil,
 	)
 }
 
-func NewCodecWithAllocator(namespace string, metrics prometheus.Registerer, getBytes func() []byte, maxMessageSize int64) (Codec, error) {
+// NewCodecWithAllocator returns a codec that packs messages into byte slices
+// returned by [getBytes]. If [zstdDictionary] isn't nil, messages may be
+// compressed with zstd and that dictionary.
+func NewCodecWithAllocator(
+	namespace string,
+	metrics prometheus.Registerer,
+	getBytes func() []byte,
+	maxMessageSize int64,
+	zstdDictionary []byte,
+) (Codec, error) {
+	zstdCompressor, err := compression.NewZstdCompressor(maxMessageSize, nil)
+	if err != nil {
+		return nil, err
+	}
 	c := &codec{
-		getBytes:              getBytes,
-		compressTimeMetrics:   make(map[Op]metric.Averager, len(ops)),
-		decompressTimeMetrics: make(map[Op]metric.Averager, len(ops)),
-		compressor:            compression.NewGzipCompressor(maxMessageSize),
+		getBytes:          getBytes,
+		compressMetrics:   make(map[compression.Type]map[Op]*compressionMetrics),
+		decompressMetrics: make(map[compression.Type]map[Op]*compressionMetrics),
+		compressors: map[compression.Type]compression.Compressor{
+			compression.Gzip: compression.NewGzipCompressor(maxMessageSize),
+			compression.Zstd: zstdCompressor,
+		},
+	}
+	if zstdDictionary != nil {
+		zstdDictionaryCompressor, err := compression.NewZstdCompressor(maxMessageSize, zstdDictionary)
+		if err != nil {
+			return nil, err
+		}
+		c.compressors[compression.ZstdWithDictionary] = zstdDictionaryCompressor
 	}
 
 	errs := wrappers.Errs{}
-	for _, op := range ops {
-		if !op.Compressable() {
-			continue
+	for compressionType := range c.compressors {
+		c.compressMetrics[compressionType] = make(map[Op]*compressionMetrics, len(ops))
+		c.decompressMetrics[compressionType] = make(map[Op]*compressionMetrics, len(ops))
+		for _, op := range ops {
+			if !op.Compressable() {
+				continue
+			}
+
+			c.compressMetrics[compressionType][op] = newCompressionMetrics(namespace, metrics, op, compressionType, "compress", &errs)
+			c.decompressMetrics[compressionType][op] = newCompressionMetrics(namespace, metrics, op, compressionType, "decompress", &errs)
 		}
+	}
+	return c, errs.Err
+}
 
-		c.compressTimeMetrics[op] = metric.NewAveragerWithErrs(
+// newCompressionMetrics returns the metrics of [action]ing messages of [op]
+// with [compressionType]. The metrics of gzip, which used to be the only type
+// of compression, keep their names.
+func newCompressionMetrics(
+	namespace string,
+	metrics prometheus.Registerer,
+	op Op,
+	compressionType compression.Type,
+	action string,
+	errs *wrappers.Errs,
+) *compressionMetrics {
+	prefix := op.String()
+	if compressionType != compression.Gzip {
+		prefix = fmt.Sprintf("%s_%s", op, strings.ReplaceAll(compressionType.String(), "-", "_"))
+	}
+	return &compressionMetrics{
+		time: metric.NewAveragerWithErrs(
 			namespace,
-			fmt.Sprintf("%s_compress_time", op),
-			fmt.Sprintf("time (in ns) to compress %s messages", op),
+			fmt.Sprintf("%s_%s_time", prefix, action),
+			fmt.Sprintf("time (in ns) to %s %s messages with %s", action, op, compressionType),
 			metrics,
-			&errs,
-		)
-		c.decompressTimeMetrics[op] = metric.NewAveragerWithErrs(
+			errs,
+		),
+		ratio: metric.NewAveragerWithErrs(
 			namespace,
-			fmt.Sprintf("%s_decompress_time", op),
-			fmt.Sprintf("time (in ns) to decompress %s messages", op),
+			fmt.Sprintf("%s_%s_ratio", prefix, action),
+			fmt.Sprintf("compressed size divided by uncompressed size of %s messages %sed with %s", op, action, compressionType),
 			metrics,
-			&errs,
-		)
+			errs,
+		),
 	}
-	return c, errs.Err
 }
 
 // Pack attempts to pack a map of fields into a message.
@@ -93,20 +161,26 @@ func NewCodecWithAllocator(namespace string, metrics prometheus.Registerer, getB
 // Uses [buffer] to hold the message's byte repr.
 // [buffer]'s contents may be overwritten by this method.
 // [buffer] may be nil.
-// If [includeIsCompressedFlag], include a flag that marks whether the payload
-// is compressed or not.
-// If [compress] and [includeIsCompressedFlag], compress the payload.
-// If [compress] == true, [includeIsCompressedFlag] must be true
+// If [includeIsCompressedFlag], include a flag that marks how the payload
+// is compressed, if it is. Peers that don't support other compression types
+// parse the flag as a bool, which is true for gzip.
+// If [compressionType] isn't NoCompression, compress the payload with it, in
+// which case [includeIsCompressedFlag] must be true.
 // TODO remove [includeIsCompressedFlag] after network upgrade.
 func (c *codec) Pack(
 	op Op,
 	fieldValues map[Field]interface{},
 	includeIsCompressedFlag bool,
-	compress bool,
+	compressionType compression.Type,
 ) (Message, error) {
+	compress := compressionType != compression.NoCompression
 	if compress && !includeIsCompressedFlag {
 		return nil, errCompressNeedsFlag
 	}
+	compressor, ok := c.compressors[compressionType]
+	if compress && !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownCompressor, compressionType)
+	}
 	msgFields, ok := messages[op]
 	if !ok {
 		return nil, errBadOp
@@ -120,9 +194,9 @@ func (c *codec) Pack(
 	// Pack the op code (message type)
 	p.PackByte(byte(op))
 
-	// Optionally, pack whether the payload is compressed
+	// Optionally, pack how the payload is compressed
 	if includeIsCompressedFlag && op.Compressable() {
-		p.PackBool(compress)
+		p.PackByte(byte(compressionType))
 	}
 
 	// Pack the uncompressed payload
@@ -150,11 +224,11 @@ func (c *codec) Pack(
 	// implies that len(msg.bytes) >= 2
 	payloadBytes := msg.bytes[wrappers.BoolLen+wrappers.ByteLen:]
 	startTime := time.Now()
-	compressedPayloadBytes, err := c.compressor.Compress(payloadBytes)
+	compressedPayloadBytes, err := compressor.Compress(payloadBytes)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't compress payload of %s message: %s", op, err)
 	}
-	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	c.compressMetrics[compressionType][op].observe(startTime, len(payloadBytes), len(compressedPayloadBytes))
 	msg.bytesSavedCompression = len(payloadBytes) - len(compressedPayloadBytes) // may be negative
 	// Remove the uncompressed payload (keep just the message type and isCompressed)
 	msg.bytes = msg.bytes[:wrappers.BoolLen+wrappers.ByteLen]
@@ -166,7 +240,7 @@ func (c *codec) Pack(
 // Parse attempts to convert bytes into a message.
 // The first byte of the message is the opcode of the message.
 // If [parseIsCompressedFlag], try to parse the flag that indicates
-// whether the message payload is compressed. Should only be true
+// how the message payload is compressed, if it is. Should only be true
 // if we expect this peer to send us compressed messages.
 // TODO remove [parseIsCompressedFlag] after network upgrade
 func (c *codec) Parse(bytes []byte, parseIsCompressedFlag bool) (Message, error) {
@@ -181,9 +255,9 @@ func (c *codec) Parse(bytes []byte, parseIsCompressedFlag bool) (Message, error)
 	}
 
 	// See if messages of this type may be compressed
-	compressed := false
+	compressionType := compression.NoCompression
 	if parseIsCompressedFlag && op.Compressable() {
-		compressed = p.UnpackBool()
+		compressionType = compression.Type(p.UnpackByte())
 	}
 	if p.Err != nil {
 		return nil, p.Err
@@ -192,15 +266,19 @@ func (c *codec) Parse(bytes []byte, parseIsCompressedFlag bool) (Message, error)
 	bytesSaved := 0
 
 	// If the payload is compressed, decompress it
-	if compressed {
+	if compressionType != compression.NoCompression {
+		compressor, ok := c.compressors[compressionType]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnknownCompressor, compressionType)
+		}
 		// The slice below is guaranteed to be in-bounds because [p.Err] == nil
 		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.BoolLen:]
 		startTime := time.Now()
-		payloadBytes, err := c.compressor.Decompress(compressedPayloadBytes)
+		payloadBytes, err := compressor.Decompress(compressedPayloadBytes)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't decompress payload of %s message: %s", op, err)
 		}
-		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+		c.decompressMetrics[compressionType][op].observe(startTime, len(payloadBytes), len(compressedPayloadBytes))
 		// Replace the compressed payload with the decompressed payload.
 		// Remove the compressed payload and isCompressed; keep just the message type
 		p.Bytes = p.Bytes[:wrappers.ByteLen]
@@ -4,7 +4,9 @@
 package message
 
 import (
+	"bytes"
 	"crypto/x509"
+	"io/ioutil"
 	"math"
 	"net"
 	"testing"
@@ -18,16 +20,17 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 func TestCodecPackInvalidOp(t *testing.T) {
 	codec, err := NewCodec("", prometheus.NewRegistry(), 2*units.MiB)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), false, false)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), false, compression.NoCompression)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), true, true)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), true, compression.Gzip)
 	assert.Error(t, err)
 }
 
@@ -35,10 +38,10 @@ func TestCodecPackMissingField(t *testing.T) {
 	codec, err := NewCodec("", prometheus.NewRegistry(), 2*units.MiB)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), false, false)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), false, compression.NoCompression)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), true, true)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), true, compression.Gzip)
 	assert.Error(t, err)
 }
 
@@ -64,10 +67,12 @@ func TestCodecParseExtraSpace(t *testing.T) {
 	assert.Error(t, err)
 }
 
-// If [compress] == true and [includeIsCompressedFlag] == false, error
+// If the message is compressed and [includeIsCompressedFlag] == false, error
 func TestCodecCompressNoIsCompressedFlag(t *testing.T) {
 	c := codec{
-		compressor: compression.NewGzipCompressor(2 * units.MiB),
+		compressors: map[compression.Type]compression.Compressor{
+			compression.Gzip: compression.NewGzipCompressor(2 * units.MiB),
+		},
 	}
 	id := ids.GenerateTestID()
 	fields := map[Field]interface{}{
@@ -76,7 +81,7 @@ func TestCodecCompressNoIsCompressedFlag(t *testing.T) {
 		ContainerIDs: [][]byte{id[:]},
 	}
 	// [compress] == true and [includeIsCompressedFlag] == false
-	_, err := c.Pack(Chits, fields, false, true)
+	_, err := c.Pack(Chits, fields, false, compression.Gzip)
 	assert.EqualValues(t, errCompressNeedsFlag, err)
 }
 
@@ -219,10 +224,9 @@ func TestCodecPackParseGzip(t *testing.T) {
 	}
 
 	peerSupportsCompression := false
-	compressionEnabledOnNode := false
 	// Test without compression
 	for _, m := range msgs {
-		packedIntf, err := c.Pack(m.op, m.fields, peerSupportsCompression, compressionEnabledOnNode)
+		packedIntf, err := c.Pack(m.op, m.fields, peerSupportsCompression, compression.NoCompression)
 		assert.NoError(t, err, "failed on operation %s", m.op)
 
 		unpackedIntf, err := c.Parse(packedIntf.Bytes(), peerSupportsCompression)
@@ -243,9 +247,12 @@ func TestCodecPackParseGzip(t *testing.T) {
 
 	// Test with Op based compression
 	peerSupportsCompression = true
-	compressionEnabledOnNode = true
 	for _, m := range msgs {
-		packedIntf, err := c.Pack(m.op, m.fields, peerSupportsCompression, compressionEnabledOnNode && m.op.Compressable())
+		compressionType := compression.NoCompression
+		if m.op.Compressable() {
+			compressionType = compression.Gzip
+		}
+		packedIntf, err := c.Pack(m.op, m.fields, peerSupportsCompression, compressionType)
 		assert.NoError(t, err, "failed to pack on operation %s", m.op)
 
 		unpackedIntf, err := c.Parse(packedIntf.Bytes(), peerSupportsCompression)
@@ -263,3 +270,72 @@ func TestCodecPackParseGzip(t *testing.T) {
 		}
 	}
 }
+
+// Test packing and then parsing messages compressed with zstd, with and
+// without a dictionary
+func TestCodecPackParseZstd(t *testing.T) {
+	assert := assert.New(t)
+
+	dictionary, err := ioutil.ReadFile("../../utils/compression/testdata/zstd.dict")
+	assert.NoError(err)
+	registry := prometheus.NewRegistry()
+	c, err := NewCodecWithAllocator("", registry, func() []byte { return nil }, 2*units.MiB, dictionary)
+	assert.NoError(err)
+
+	id := ids.GenerateTestID()
+	container := bytes.Repeat([]byte("\x00\x00\x00\x07transfer\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x03\xe8"), 8)
+	fields := map[Field]interface{}{
+		ChainID:        id[:],
+		RequestID:      uint32(1337),
+		ContainerID:    id[:],
+		ContainerBytes: container,
+	}
+	for _, compressionType := range []compression.Type{compression.Zstd, compression.ZstdWithDictionary} {
+		packed, err := c.Pack(Put, fields, true, compressionType)
+		assert.NoError(err)
+		assert.Equal(byte(compressionType), packed.Bytes()[1])
+		assert.Positive(packed.BytesSavedCompression())
+
+		parsed, err := c.Parse(packed.Bytes(), true)
+		assert.NoError(err)
+		assert.Equal(container, parsed.Get(ContainerBytes))
+		assert.Equal(packed.BytesSavedCompression(), parsed.BytesSavedCompression())
+	}
+
+	// Compressing with a dictionary isn't possible without one
+	noDictionaryCodec, err := NewCodec("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+	_, err = noDictionaryCodec.Pack(Put, fields, true, compression.ZstdWithDictionary)
+	assert.ErrorIs(err, errUnknownCompressor)
+
+	metrics, err := registry.Gather()
+	assert.NoError(err)
+	names := make(map[string]bool, len(metrics))
+	for _, metric := range metrics {
+		names[metric.GetName()] = true
+	}
+	assert.True(names["put_compress_time_count"])
+	assert.True(names["put_compress_ratio_sum"])
+	assert.True(names["put_zstd_compress_ratio_sum"])
+	assert.True(names["put_zstd_dictionary_decompress_time_sum"])
+}
+
+// Peers that only support gzip parse the compression type as a bool
+func TestCodecGzipFlagIsBool(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCodec("", prometheus.NewRegistry(), 2*units.MiB)
+	assert.NoError(err)
+	msg, err := NewBuilder(c).PeerList(nil, true, compression.Gzip)
+	assert.NoError(err)
+
+	p := wrappers.Packer{Bytes: msg.Bytes(), Offset: 1}
+	assert.True(p.UnpackBool())
+	assert.NoError(p.Err)
+
+	// Messages compressed with unknown types are rejected
+	msgBytes := msg.Bytes()
+	msgBytes[1] = math.MaxUint8
+	_, err = c.Parse(msgBytes, true)
+	assert.ErrorIs(err, errUnknownCompressor)
+}
@@ -28,6 +28,11 @@ const (
 	SigBytes                         // Used in handshake / peer gossiping
 	VersionTime                      // Used in handshake / peer gossiping
 	SignedPeers                      // Used in peer gossiping
+	CompressionTypes                 // Used in handshake
+	DictionaryID                     // Used in handshake
+	Cert                             // Used in relaying
+	RelayNodeID                      // Used in relaying
+	RelayedMsg                       // Used in relaying
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -65,6 +70,16 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackLong
 	case SignedPeers:
 		return wrappers.TryPackIPCertList
+	case CompressionTypes:
+		return wrappers.TryPackBytes
+	case DictionaryID:
+		return wrappers.TryPackInt
+	case Cert:
+		return wrappers.TryPackX509Certificate
+	case RelayNodeID:
+		return wrappers.TryPackBytes
+	case RelayedMsg:
+		return wrappers.TryPackBytes
 	default:
 		return nil
 	}
@@ -105,6 +120,16 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackLong
 	case SignedPeers:
 		return wrappers.TryUnpackIPCertList
+	case CompressionTypes:
+		return wrappers.TryUnpackBytes
+	case DictionaryID:
+		return wrappers.TryUnpackInt
+	case Cert:
+		return wrappers.TryUnpackX509Certificate
+	case RelayNodeID:
+		return wrappers.TryUnpackBytes
+	case RelayedMsg:
+		return wrappers.TryUnpackBytes
 	default:
 		return nil
 	}
@@ -144,6 +169,16 @@ func (f Field) String() string {
 		return "VersionTime"
 	case SignedPeers:
 		return "SignedPeers"
+	case CompressionTypes:
+		return "CompressionTypes"
+	case DictionaryID:
+		return "DictionaryID"
+	case Cert:
+		return "Cert"
+	case RelayNodeID:
+		return "RelayNodeID"
+	case RelayedMsg:
+		return "RelayedMsg"
 	default:
 		return "Unknown Field"
 	}
@@ -32,6 +32,12 @@ const (
 	// Handshake / peer gossiping
 	Version
 	PeerList
+	// Handshake:
+	Compressors
+	// Relaying:
+	RelayRequest
+	RelayAuth
+	Relay
 )
 
 var (
@@ -54,6 +60,10 @@ var (
 		Chits,
 		Version,
 		PeerList,
+		Compressors,
+		RelayRequest,
+		RelayAuth,
+		Relay,
 	}
 
 	// Defines the messages that can be sent/received with this network
@@ -63,6 +73,7 @@ var (
 		Version:     {NetworkID, NodeID, MyTime, IP, VersionStr, VersionTime, SigBytes},
 		GetPeerList: {},
 		PeerList:    {SignedPeers},
+		Compressors: {CompressionTypes, DictionaryID},
 		Ping:        {},
 		Pong:        {},
 		// Bootstrapping:
@@ -78,6 +89,10 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// Relaying:
+		RelayRequest: {VersionTime, SigBytes},
+		RelayAuth:    {Cert, VersionStr, VersionTime, SigBytes},
+		Relay:        {RelayNodeID, RelayedMsg},
 	}
 )
 
@@ -100,6 +115,8 @@ func (op Op) String() string {
 		return "get_peerlist"
 	case PeerList:
 		return "peerlist"
+	case Compressors:
+		return "compressors"
 	case Ping:
 		return "ping"
 	case Pong:
@@ -126,6 +143,12 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case RelayRequest:
+		return "relay_request"
+	case RelayAuth:
+		return "relay_auth"
+	case Relay:
+		return "relay"
 	default:
 		return "Unknown Op"
 	}
@@ -91,12 +91,14 @@ type metrics struct {
 
 	getVersion, version,
 	getPeerlist, peerList,
+	compressors,
 	ping, pong,
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	getAncestors, multiPut,
 	get, put,
-	pushQuery, pullQuery, chits messageMetrics
+	pushQuery, pullQuery, chits,
+	relayRequest, relayAuth, relay messageMetrics
 }
 
 func (m *metrics) initialize(namespace string, registerer prometheus.Registerer) error {
@@ -168,6 +170,7 @@ func (m *metrics) initialize(namespace string, registerer prometheus.Registerer)
 		m.version.initialize(message.Version, namespace, registerer),
 		m.getPeerlist.initialize(message.GetPeerList, namespace, registerer),
 		m.peerList.initialize(message.PeerList, namespace, registerer),
+		m.compressors.initialize(message.Compressors, namespace, registerer),
 		m.ping.initialize(message.Ping, namespace, registerer),
 		m.pong.initialize(message.Pong, namespace, registerer),
 		m.getAcceptedFrontier.initialize(message.GetAcceptedFrontier, namespace, registerer),
@@ -181,6 +184,9 @@ func (m *metrics) initialize(namespace string, registerer prometheus.Registerer)
 		m.pushQuery.initialize(message.PushQuery, namespace, registerer),
 		m.pullQuery.initialize(message.PullQuery, namespace, registerer),
 		m.chits.initialize(message.Chits, namespace, registerer),
+		m.relayRequest.initialize(message.RelayRequest, namespace, registerer),
+		m.relayAuth.initialize(message.RelayAuth, namespace, registerer),
+		m.relay.initialize(message.Relay, namespace, registerer),
 	)
 	return errs.Err
 }
@@ -195,6 +201,8 @@ func (m *metrics) message(msgType message.Op) *messageMetrics {
 		return &m.getPeerlist
 	case message.PeerList:
 		return &m.peerList
+	case message.Compressors:
+		return &m.compressors
 	case message.Ping:
 		return &m.ping
 	case message.Pong:
@@ -221,6 +229,12 @@ func (m *metrics) message(msgType message.Op) *messageMetrics {
 		return &m.pullQuery
 	case message.Chits:
 		return &m.chits
+	case message.RelayRequest:
+		return &m.relayRequest
+	case message.RelayAuth:
+		return &m.relayAuth
+	case message.Relay:
+		return &m.relay
 	default:
 		return nil
 	}
@@ -26,10 +26,12 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -54,14 +56,22 @@ const (
 	defaultReadBufferSize                      = 16 * units.KiB
 	defaultReadHandshakeTimeout                = 15 * time.Second
 	defaultByteSliceCap                        = 128
+
+	// When gossiping, a peer is weighted by [gossipScoreWeight] scaled by a
+	// factor in [gossipMinScoreFactor, 1] based on the peer's score.
+	gossipScoreWeight    = 1 << 16
+	gossipMinScoreFactor = 0.1
 )
 
 var (
 	errNetworkClosed         = errors.New("network closed")
 	errPeerIsMyself          = errors.New("peer is myself")
 	errNetworkLayerUnhealthy = errors.New("network layer is unhealthy")
+	errStaticPeerIPIsZero    = errors.New("static peer IP is zero")
 
-	minVersionCanHandleCompressed = version.NewDefaultVersion(1, 4, 11)
+	minVersionCanHandleCompressed     = version.NewDefaultVersion(1, 4, 11)
+	minVersionCanNegotiateCompression = version.NewDefaultVersion(1, 4, 13)
+	minVersionCanRelay                = version.NewDefaultVersion(1, 4, 14)
 )
 
 var _ Network = &network{}
@@ -104,6 +114,21 @@ type Network interface {
 	// Return the IP of the node
 	IP() utils.IPDesc
 
+	// Always attempt to connect to [nodeID] at [ip], even if it isn't a
+	// validator, and reconnect to it whenever the connection is lost. Thread
+	// safety must be managed internally to the network.
+	AddStaticPeer(nodeID ids.ShortID, ip utils.IPDesc) error
+
+	// Stop treating [nodeID] as a static peer and close an existing connection
+	// to it. If it's a validator we'd connect to anyways, it's reconnected to.
+	// Returns false if [nodeID] isn't a static peer. Thread safety must be
+	// managed internally to the network.
+	RemoveStaticPeer(nodeID ids.ShortID) bool
+
+	// Returns the static peers and the IPs they're connected to at. Thread
+	// safety must be managed internally to the network.
+	StaticPeers() map[ids.ShortID]utils.IPDesc
+
 	// Has a health check
 	health.Checkable
 }
@@ -161,6 +186,14 @@ type network struct {
 	b                            message.Builder
 	isFetchOnly                  bool
 
+	// If true, we don't advertise our IP to peers, so that it isn't gossiped,
+	// and only connect to static peers and beacons. Messages to nodes we
+	// aren't connected to are relayed through our static peers.
+	privateMode bool
+	// IDs of the nodes we're a sentry for. We relay messages to and from these
+	// nodes.
+	relayedNodeIDs ids.ShortSet
+
 	stateLock sync.RWMutex
 	closed    utils.AtomicBool
 
@@ -215,6 +248,26 @@ type network struct {
 	// TODO also remove from this map when the peer leaves the validator set
 	latestPeerIP map[ids.ShortID]signedPeerIP
 
+	// Node ID --> IP of a static peer. Static peers are always dialed at their
+	// IP and reconnected to when the connection is lost.
+	// [stateLock] should be held when accessing this map.
+	staticPeers map[ids.ShortID]utils.IPDesc
+
+	// Node ID --> IDs of the peers that relay messages to and from it -->
+	// Time the peer's authorization to relay messages expires at.
+	// [stateLock] should be held when accessing this map.
+	relayers map[ids.ShortID]map[ids.ShortID]time.Time
+
+	// Node ID --> Version of a node that we aren't connected to, but that the
+	// router was told is connected because a peer relays messages for it.
+	// [stateLock] should be held when accessing this map.
+	relayedPeers map[ids.ShortID]version.Application
+
+	// Node ID --> Its authorization for us to relay messages on its behalf.
+	// Only contains nodes in [relayedNodeIDs] that we're connected to.
+	// [stateLock] should be held when accessing this map.
+	relayAuths map[ids.ShortID]relayAuth
+
 	// Node ID --> Function to execute to stop trying to dial the node.
 	// A node is present in this map if and only if we are actively
 	// trying to dial the node.
@@ -229,11 +282,27 @@ type network struct {
 	// to send these types of messages with the isCompressed flag.
 	compressionEnabled bool
 
+	// The compression type we prefer to compress messages with. Peers with
+	// version >= [minVersionCanNegotiateCompression] tell us which types they
+	// can decompress. Other peers are sent gzip compressed messages.
+	compressionType compression.Type
+
+	// The zstd dictionary messages may be compressed with, and its ID. Peers
+	// can only compress with the dictionary if they have the same one.
+	compressionDictionary   []byte
+	compressionDictionaryID uint32
+
 	// Rate-limits incoming messages
 	inboundMsgThrottler throttling.InboundMsgThrottler
 
 	// Rate-limits outgoing messages
 	outboundMsgThrottler throttling.OutboundMsgThrottler
+
+	// Limits the bandwidth used to receive messages
+	inboundBandwidthThrottler throttling.InboundBandwidthThrottler
+
+	// Limits the bandwidth used to send messages
+	outboundBandwidthThrottler throttling.OutboundBandwidthThrottler
 }
 
 type Config struct {
@@ -241,6 +310,8 @@ type Config struct {
 	InboundConnThrottlerConfig throttling.InboundConnThrottlerConfig
 	InboundThrottlerConfig     throttling.MsgThrottlerConfig
 	OutboundThrottlerConfig    throttling.MsgThrottlerConfig
+	InboundBandwidthConfig     throttling.BandwidthThrottlerConfig
+	OutboundBandwidthConfig    throttling.BandwidthThrottlerConfig
 	timer.AdaptiveTimeoutConfig
 	DialerConfig dialer.Config
 	// [Registerer] is set in node's initMetricsAPI method
@@ -277,8 +348,14 @@ func NewDefaultNetwork(
 	gossipAcceptedFrontierSize uint,
 	gossipOnAcceptSize uint,
 	compressionEnabled bool,
+	compressionType compression.Type,
+	compressionDictionary []byte,
+	privateMode bool,
+	relayedNodeIDs ids.ShortSet,
 	inboundMsgThrottler throttling.InboundMsgThrottler,
 	outboundMsgThrottler throttling.OutboundMsgThrottler,
+	inboundBandwidthThrottler throttling.InboundBandwidthThrottler,
+	outboundBandwidthThrottler throttling.OutboundBandwidthThrottler,
 ) (Network, error) {
 	return NewNetwork(
 		namespace,
@@ -319,8 +396,14 @@ func NewDefaultNetwork(
 		tlsKey,
 		isFetchOnly,
 		compressionEnabled,
+		compressionType,
+		compressionDictionary,
+		privateMode,
+		relayedNodeIDs,
 		inboundMsgThrottler,
 		outboundMsgThrottler,
+		inboundBandwidthThrottler,
+		outboundBandwidthThrottler,
 	)
 }
 
@@ -364,9 +447,24 @@ func NewNetwork(
 	tlsKey crypto.Signer,
 	isFetchOnly bool,
 	compressionEnabled bool,
+	compressionType compression.Type,
+	compressionDictionary []byte,
+	privateMode bool,
+	relayedNodeIDs ids.ShortSet,
 	inboundMsgThrottler throttling.InboundMsgThrottler,
 	outboundMsgThrottler throttling.OutboundMsgThrottler,
+	inboundBandwidthThrottler throttling.InboundBandwidthThrottler,
+	outboundBandwidthThrottler throttling.OutboundBandwidthThrottler,
 ) (Network, error) {
+	var compressionDictionaryID uint32
+	if compressionDictionary != nil {
+		var err error
+		compressionDictionaryID, err = compression.DictionaryID(compressionDictionary)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compression dictionary: %w", err)
+		}
+	}
+
 	// #nosec G404
 	netw := &network{
 		log:                  log,
@@ -414,14 +512,25 @@ func NewNetwork(
 		tlsKey:                       tlsKey,
 		latestPeerIP:                 make(map[ids.ShortID]signedPeerIP),
 		isFetchOnly:                  isFetchOnly,
+		privateMode:                  privateMode,
+		relayedNodeIDs:               relayedNodeIDs,
+		staticPeers:                  make(map[ids.ShortID]utils.IPDesc),
+		relayers:                     make(map[ids.ShortID]map[ids.ShortID]time.Time),
+		relayedPeers:                 make(map[ids.ShortID]version.Application),
+		relayAuths:                   make(map[ids.ShortID]relayAuth),
 		byteSlicePool: sync.Pool{
 			New: func() interface{} {
 				return make([]byte, 0, defaultByteSliceCap)
 			},
 		},
-		compressionEnabled:   compressionEnabled,
-		inboundMsgThrottler:  inboundMsgThrottler,
-		outboundMsgThrottler: outboundMsgThrottler,
+		compressionEnabled:         compressionEnabled,
+		compressionType:            compressionType,
+		compressionDictionary:      compressionDictionary,
+		compressionDictionaryID:    compressionDictionaryID,
+		inboundMsgThrottler:        inboundMsgThrottler,
+		outboundMsgThrottler:       outboundMsgThrottler,
+		inboundBandwidthThrottler:  inboundBandwidthThrottler,
+		outboundBandwidthThrottler: outboundBandwidthThrottler,
 	}
 	codec, err := message.NewCodecWithAllocator(
 		fmt.Sprintf("%s_codec", namespace),
@@ -430,6 +539,7 @@ func NewNetwork(
 			return netw.byteSlicePool.Get().([]byte)
 		},
 		int64(maxMessageSize),
+		compressionDictionary,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("initializing codec failed with: %s", err)
@@ -456,7 +566,7 @@ func (n *network) GetAcceptedFrontier(nodeIDs ids.ShortSet, chainID ids.ID, requ
 	for _, peerElement := range n.getPeers(nodeIDs) {
 		peer := peerElement.peer
 		nodeID := peerElement.id
-		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
+		if !n.send(peer, nodeID, msg, false) {
 			n.log.Debug("failed to send GetAcceptedFrontier(%s, %s, %d)",
 				nodeID,
 				chainID,
@@ -495,7 +605,7 @@ func (n *network) AcceptedFrontier(nodeID ids.ShortID, chainID ids.ID, requestID
 	}
 
 	msgLen := len(msg.Bytes())
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send AcceptedFrontier(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -535,7 +645,7 @@ func (n *network) GetAccepted(nodeIDs ids.ShortSet, chainID ids.ID, requestID ui
 	for _, peerElement := range n.getPeers(nodeIDs) {
 		peer := peerElement.peer
 		vID := peerElement.id
-		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
+		if !n.send(peer, vID, msg, false) {
 			n.log.Debug("failed to send GetAccepted(%s, %s, %d, %s)",
 				vID,
 				chainID,
@@ -575,7 +685,7 @@ func (n *network) Accepted(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 	msgLen := len(msg.Bytes())
 
 	peer := n.getPeer(nodeID)
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send Accepted(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -609,7 +719,7 @@ func (n *network) GetAncestors(nodeID ids.ShortID, chainID ids.ID, requestID uin
 	}
 
 	msgLen := len(msg.Bytes())
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send GetAncestors(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -635,10 +745,20 @@ func (n *network) MultiPut(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 	now := n.clock.Time()
 
 	peer := n.getPeer(nodeID)
-	includeIsCompressedFlag := peer != nil && peer.canHandleCompressed.GetValue()
-	// Compress this message only if the peer can handle compressed
-	// messages and we have compression enabled
-	msg, err := n.b.MultiPut(chainID, requestID, containers, includeIsCompressedFlag, includeIsCompressedFlag && n.compressionEnabled)
+	if peer != nil && !peer.finishedHandshake.GetValue() {
+		// The message is relayed, if it's sent at all
+		peer = nil
+	}
+	// Relayed messages always include the isCompressed flag
+	includeIsCompressedFlag := true
+	compressionType := compression.NoCompression
+	if peer != nil {
+		includeIsCompressedFlag = peer.canHandleCompressed.GetValue()
+		// Compress this message only if the peer can handle compressed
+		// messages and we have compression enabled
+		compressionType = peer.compressionType()
+	}
+	msg, err := n.b.MultiPut(chainID, requestID, containers, includeIsCompressedFlag, compressionType)
 	if err != nil {
 		n.log.Error("failed to build MultiPut message because of container of size %d", len(containers))
 		n.sendFailRateCalculator.Observe(1, now)
@@ -646,7 +766,7 @@ func (n *network) MultiPut(nodeID ids.ShortID, chainID ids.ID, requestID uint32,
 	}
 
 	msgLen := len(msg.Bytes())
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send MultiPut(%s, %s, %d, %d)",
 			nodeID,
 			chainID,
@@ -675,7 +795,7 @@ func (n *network) Get(nodeID ids.ShortID, chainID ids.ID, requestID uint32, dead
 
 	msgLen := len(msg.Bytes())
 	peer := n.getPeer(nodeID)
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send Get(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -701,10 +821,20 @@ func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, cont
 	now := n.clock.Time()
 
 	peer := n.getPeer(nodeID)
-	includeIsCompressedFlag := peer != nil && peer.canHandleCompressed.GetValue()
-	// Compress this message only if the peer can handle compressed
-	// messages and we have compression enabled
-	msg, err := n.b.Put(chainID, requestID, containerID, container, includeIsCompressedFlag, includeIsCompressedFlag && n.compressionEnabled)
+	if peer != nil && !peer.finishedHandshake.GetValue() {
+		// The message is relayed, if it's sent at all
+		peer = nil
+	}
+	// Relayed messages always include the isCompressed flag
+	includeIsCompressedFlag := true
+	compressionType := compression.NoCompression
+	if peer != nil {
+		includeIsCompressedFlag = peer.canHandleCompressed.GetValue()
+		// Compress this message only if the peer can handle compressed
+		// messages and we have compression enabled
+		compressionType = peer.compressionType()
+	}
+	msg, err := n.b.Put(chainID, requestID, containerID, container, includeIsCompressedFlag, compressionType)
 	if err != nil {
 		n.log.Error("failed to build Put(%s, %d, %s): %s. len(container) : %d",
 			chainID,
@@ -717,7 +847,7 @@ func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, cont
 	}
 	msgLen := len(msg.Bytes())
 
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send Put(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -742,19 +872,9 @@ func (n *network) Put(nodeID ids.ShortID, chainID ids.ID, requestID uint32, cont
 func (n *network) PushQuery(nodeIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Duration, containerID ids.ID, container []byte) []ids.ShortID {
 	now := n.clock.Time()
 
-	msgWithIsCompressedFlag, err := n.b.PushQuery(chainID, requestID, uint64(deadline), containerID, container, true, n.compressionEnabled)
-	if err != nil {
-		n.log.Error("failed to build PushQuery(%s, %d, %s): %s. len(container): %d",
-			chainID,
-			requestID,
-			containerID,
-			err,
-			len(container))
-		n.log.Verbo("container: %s", formatting.DumpBytes{Bytes: container})
-		n.sendFailRateCalculator.Observe(1, now)
-		return nil // Packing message failed
-	}
-	msgWithoutIsCompressedFlag, err := n.b.PushQuery(chainID, requestID, uint64(deadline), containerID, container, false, false)
+	msgs, err := newCompressedMessages(func(includeIsCompressedFlag bool, compressionType compression.Type) (message.Message, error) {
+		return n.b.PushQuery(chainID, requestID, uint64(deadline), containerID, container, includeIsCompressedFlag, compressionType)
+	})
 	if err != nil {
 		n.log.Error("failed to build PushQuery(%s, %d, %s): %s. len(container): %d",
 			chainID,
@@ -771,14 +891,29 @@ func (n *network) PushQuery(nodeIDs ids.ShortSet, chainID ids.ID, requestID uint
 	for _, peerElement := range n.getPeers(nodeIDs) {
 		peer := peerElement.peer
 		vID := peerElement.id
-		canHandleCompressed := peer != nil && peer.canHandleCompressed.GetValue()
+		if peer != nil && !peer.finishedHandshake.GetValue() {
+			// The message is relayed, if it's sent at all
+			peer = nil
+		}
 		var msg message.Message
-		if canHandleCompressed {
-			msg = msgWithIsCompressedFlag
-		} else {
-			msg = msgWithoutIsCompressedFlag
+		if peer != nil {
+			msg, err = msgs.get(peer)
+			if err != nil {
+				n.log.Error("failed to build PushQuery(%s, %d, %s) compressed with %s: %s",
+					chainID,
+					requestID,
+					containerID,
+					peer.compressionType(),
+					err)
+			}
+		} else if msg, err = msgs.relayed(); err != nil {
+			n.log.Error("failed to build relayed PushQuery(%s, %d, %s): %s",
+				chainID,
+				requestID,
+				containerID,
+				err)
 		}
-		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
+		if msg == nil || !n.send(peer, vID, msg, false) {
 			n.log.Debug("failed to send PushQuery(%s, %s, %d, %s)",
 				vID,
 				chainID,
@@ -814,7 +949,7 @@ func (n *network) PullQuery(nodeIDs ids.ShortSet, chainID ids.ID, requestID uint
 	for _, peerElement := range n.getPeers(nodeIDs) {
 		peer := peerElement.peer
 		vID := peerElement.id
-		if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, false) {
+		if !n.send(peer, vID, msg, false) {
 			n.log.Debug("failed to send PullQuery(%s, %s, %d, %s)",
 				vID,
 				chainID,
@@ -854,7 +989,7 @@ func (n *network) Chits(nodeID ids.ShortID, chainID ids.ID, requestID uint32, vo
 	}
 	msgLen := len(msg.Bytes())
 
-	if peer == nil || !peer.finishedHandshake.GetValue() || !peer.Send(msg, true) {
+	if !n.send(peer, nodeID, msg, true) {
 		n.log.Debug("failed to send Chits(%s, %s, %d, %s)",
 			nodeID,
 			chainID,
@@ -894,12 +1029,13 @@ func (n *network) Accept(ctx *snow.Context, containerID ids.ID, container []byte
 
 // shouldUpgradeIncoming returns whether we should
 // upgrade an incoming connection from a peer
-// at the IP whose string repr. is [ipStr].
+// at [ip].
 // Assumes stateLock is not held.
-func (n *network) shouldUpgradeIncoming(ipStr string) bool {
+func (n *network) shouldUpgradeIncoming(ip utils.IPDesc) bool {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
+	ipStr := ip.IP.String()
 	if _, ok := n.connectedIPs[ipStr]; ok {
 		n.log.Debug("not upgrading connection to %s because it's connected", ipStr)
 		return false
@@ -912,7 +1048,8 @@ func (n *network) shouldUpgradeIncoming(ipStr string) bool {
 		n.log.Debug("not upgrading connection to %s because it's an alias", ipStr)
 		return false
 	}
-	if !n.inboundConnThrottler.Allow(ipStr) {
+	// Static peers aren't rate-limited
+	if !n.isStaticPeerIP(ip) && !n.inboundConnThrottler.Allow(ipStr) {
 		n.log.Debug("not upgrading connection to %s due to rate-limiting", ipStr)
 		n.metrics.inboundConnRateLimited.Inc()
 		return false
@@ -929,7 +1066,8 @@ func (n *network) shouldUpgradeIncoming(ipStr string) bool {
 // to this node.
 // Assumes [n.stateLock] is not held.
 func (n *network) Dispatch() error {
-	go n.gossipPeerList() // Periodically gossip peers
+	go n.gossipPeerList()    // Periodically gossip peers
+	go n.refreshRelayAuths() // Periodically renew and expire relay authorizations
 	go n.inboundConnThrottler.Dispatch()
 	defer n.inboundConnThrottler.Stop()
 	go func() {
@@ -980,8 +1118,7 @@ func (n *network) Dispatch() error {
 		if err != nil {
 			return fmt.Errorf("unable to convert remote address %s to IPDesc: %w", remoteAddr, err)
 		}
-		ipStr := ip.IP.String()
-		upgrade := n.shouldUpgradeIncoming(ipStr)
+		upgrade := n.shouldUpgradeIncoming(ip)
 		if !upgrade {
 			_ = conn.Close()
 			continue
@@ -1102,17 +1239,107 @@ func (n *network) IP() utils.IPDesc {
 	return n.ip.IP()
 }
 
+// AddStaticPeer implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) AddStaticPeer(nodeID ids.ShortID, ip utils.IPDesc) error {
+	switch {
+	case nodeID == n.id:
+		return errPeerIsMyself
+	case ip.IsZero():
+		return errStaticPeerIPIsZero
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if oldIP, ok := n.staticPeers[nodeID]; ok && !oldIP.Equal(ip) {
+		n.stopDialingStaticPeer(nodeID, oldIP)
+	}
+	n.staticPeers[nodeID] = ip
+	if p, ok := n.peers.getByID(nodeID); ok {
+		p.static.SetValue(true)
+	}
+	n.log.Info("added static peer %s%s at %s", constants.NodeIDPrefix, nodeID, ip)
+	n.track(ip, nodeID)
+	return nil
+}
+
+// RemoveStaticPeer implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) RemoveStaticPeer(nodeID ids.ShortID) bool {
+	n.stateLock.Lock()
+	ip, ok := n.staticPeers[nodeID]
+	if !ok {
+		n.stateLock.Unlock()
+		return false
+	}
+	delete(n.staticPeers, nodeID)
+	n.stopDialingStaticPeer(nodeID, ip)
+	n.log.Info("removed static peer %s%s at %s", constants.NodeIDPrefix, nodeID, ip)
+	p, connected := n.peers.getByID(nodeID)
+	n.stateLock.Unlock()
+
+	// The connection was kept open, and exempt from rate-limiting, because
+	// the peer was static. If it's a validator we'd connect to anyways, it's
+	// reconnected to.
+	if connected {
+		p.Close() // Grabs the stateLock
+	}
+	return true
+}
+
+// StaticPeers implements the Network interface
+// Assumes [n.stateLock] is not held.
+func (n *network) StaticPeers() map[ids.ShortID]utils.IPDesc {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	staticPeers := make(map[ids.ShortID]utils.IPDesc, len(n.staticPeers))
+	for nodeID, ip := range n.staticPeers {
+		staticPeers[nodeID] = ip
+	}
+	return staticPeers
+}
+
+// stopDialingStaticPeer stops attempting to connect to [nodeID] at [ip] unless
+// it's a validator we'd dial anyways.
+// Assumes [n.stateLock] is held.
+func (n *network) stopDialingStaticPeer(nodeID ids.ShortID, ip utils.IPDesc) {
+	if !n.privateMode && n.vdrs.Contains(nodeID) {
+		return
+	}
+	str := ip.String()
+	delete(n.disconnectedIPs, str)
+	delete(n.retryDelay, str)
+}
+
+// isStaticPeerIP returns true if a static peer is at [ip]. Both the IP and
+// the port must match, so that other hosts behind the same IP aren't treated
+// as static peers.
+// Assumes [n.stateLock] is held.
+func (n *network) isStaticPeerIP(ip utils.IPDesc) bool {
+	for _, staticIP := range n.staticPeers {
+		if staticIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStaticPeer returns true if [nodeID] is a static peer at [ip].
+// Assumes [n.stateLock] is held.
+func (n *network) isStaticPeer(nodeID ids.ShortID, ip utils.IPDesc) bool {
+	staticIP, ok := n.staticPeers[nodeID]
+	return ok && staticIP.Equal(ip)
+}
+
 // Assumes [n.stateLock] is not held.
 func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte, numToGossip uint) error {
 	now := n.clock.Time()
 
-	// Sent to peers that handle compressed messages (and messages with the isCompress flag)
-	msgWithIsCompressedFlag, err := n.b.Put(chainID, constants.GossipMsgRequestID, containerID, container, true, n.compressionEnabled)
-	if err != nil {
-		n.sendFailRateCalculator.Observe(1, now)
-		return fmt.Errorf("attempted to pack too large of a Put message.\nContainer length: %d", len(container))
-	}
-	msgWithoutIsCompressedFlag, err := n.b.Put(chainID, constants.GossipMsgRequestID, containerID, container, false, false)
+	msgs, err := newCompressedMessages(func(includeIsCompressedFlag bool, compressionType compression.Type) (message.Message, error) {
+		return n.b.Put(chainID, constants.GossipMsgRequestID, containerID, container, includeIsCompressedFlag, compressionType)
+	})
 	if err != nil {
 		n.sendFailRateCalculator.Observe(1, now)
 		return fmt.Errorf("attempted to pack too large of a Put message.\nContainer length: %d", len(container))
@@ -1124,24 +1351,17 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte,
 		numToGossip = uint(len(allPeers))
 	}
 
-	s := sampler.NewUniform()
-	if err := s.Initialize(uint64(len(allPeers))); err != nil {
-		return err
-	}
-	indices, err := s.Sample(int(numToGossip))
-	if err != nil {
-		return err
-	}
-	for _, index := range indices {
-		peer := allPeers[int(index)]
-		canHandleCompressed := peer.canHandleCompressed.GetValue()
-		var msg message.Message
-		if canHandleCompressed {
-			msg = msgWithIsCompressedFlag
-		} else {
-			msg = msgWithoutIsCompressedFlag
+	for _, peer := range n.samplePeersByScore(allPeers, int(numToGossip)) {
+		msg, err := msgs.get(peer)
+		if err != nil {
+			n.log.Error("failed to build Put(%s, %d, %s) compressed with %s: %s",
+				chainID,
+				constants.GossipMsgRequestID,
+				containerID,
+				peer.compressionType(),
+				err)
 		}
-		if peer.Send(msg, false) {
+		if err == nil && peer.Send(msg, false) {
 			n.put.numSent.Inc()
 			n.put.sentBytes.Add(float64(len(msg.Bytes())))
 			// assume that if [saved] == 0, [msg] wasn't compressed
@@ -1177,9 +1397,14 @@ func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 	if _, ok := n.myIPs[str]; ok {
 		return
 	}
-	// If we saw an IP gossiped for this node ID
-	// with a later timestamp, don't track this old IP
-	if latestIP, ok := n.latestPeerIP[nodeID]; ok {
+	// Static peers are only dialed at their static IP
+	if staticIP, ok := n.staticPeers[nodeID]; ok {
+		if !staticIP.Equal(ip) {
+			return
+		}
+	} else if latestIP, ok := n.latestPeerIP[nodeID]; ok {
+		// If we saw an IP gossiped for this node ID
+		// with a later timestamp, don't track this old IP
 		if !latestIP.ip.Equal(ip) {
 			return
 		}
@@ -1263,16 +1488,9 @@ func (n *network) gossipPeerList() {
 			continue
 		}
 
-		// Sent to peers that handle compressed messages (and messages with the isCompress flag)
-		msgWithIsCompressedFlag, err := n.b.PeerList(ipCerts, true, n.compressionEnabled)
-		if err != nil {
-			n.log.Error("failed to build signed peerlist to gossip: %s. len(ips): %d",
-				err,
-				len(ipCerts))
-			continue
-		}
-		// Sent to peers that can't handle compressed messages
-		msgWithoutIsCompressedFlag, err := n.b.PeerList(ipCerts, false, false)
+		msgs, err := newCompressedMessages(func(includeIsCompressedFlag bool, compressionType compression.Type) (message.Message, error) {
+			return n.b.PeerList(ipCerts, includeIsCompressedFlag, compressionType)
+		})
 		if err != nil {
 			n.log.Error("failed to build signed peerlist to gossip: %s. len(ips): %d",
 				err,
@@ -1281,24 +1499,37 @@ func (n *network) gossipPeerList() {
 		}
 
 		for _, index := range stakerIndices {
-			peer := stakers[int(index)]
-			if peer.canHandleCompressed.GetValue() {
-				peer.Send(msgWithIsCompressedFlag, false)
-			} else {
-				peer.Send(msgWithoutIsCompressedFlag, false)
-			}
+			n.sendPeerListGossip(stakers[int(index)], msgs)
 		}
 		for _, index := range nonStakerIndices {
-			peer := nonStakers[int(index)]
-			if peer.canHandleCompressed.GetValue() {
-				peer.Send(msgWithIsCompressedFlag, false)
-			} else {
-				peer.Send(msgWithoutIsCompressedFlag, false)
-			}
+			n.sendPeerListGossip(nonStakers[int(index)], msgs)
 		}
 	}
 }
 
+// compressionTypes returns the compression types this node can decompress
+func (n *network) compressionTypes() []compression.Type {
+	compressionTypes := []compression.Type{compression.Gzip, compression.Zstd}
+	if n.compressionDictionary != nil {
+		compressionTypes = append(compressionTypes, compression.ZstdWithDictionary)
+	}
+	return compressionTypes
+}
+
+// sendPeerListGossip sends the gossiped PeerList in [msgs] to [peer]
+func (n *network) sendPeerListGossip(peer *peer, msgs *compressedMessages) {
+	msg, err := msgs.get(peer)
+	if err != nil {
+		n.log.Error("failed to build signed peerlist to gossip to %s%s compressed with %s: %s",
+			constants.NodeIDPrefix,
+			peer.nodeID,
+			peer.compressionType(),
+			err)
+		return
+	}
+	peer.Send(msg, false)
+}
+
 // Returns when:
 // * We connected to [ip]
 // * The network is closed
@@ -1337,7 +1568,7 @@ func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 		// If we saw an IP gossiped for this node ID
 		// with a later timestamp, don't track this old IP
 		isLatestIP := true
-		if latestIP, ok := n.latestPeerIP[nodeID]; ok {
+		if latestIP, ok := n.latestPeerIP[nodeID]; ok && !n.isStaticPeer(nodeID, ip) {
 			isLatestIP = latestIP.ip.Equal(ip)
 		}
 		closed := n.closed
@@ -1501,6 +1732,8 @@ func (n *network) tryAddPeer(p *peer) error {
 		return fmt.Errorf("duplicated connection from %s at %s", p.nodeID.PrefixedString(constants.NodeIDPrefix), ip)
 	}
 
+	_, static := n.staticPeers[p.nodeID]
+	p.static.SetValue(static)
 	n.peers.add(p)
 	n.numPeers.Set(float64(n.peers.size()))
 	p.Start()
@@ -1621,8 +1854,27 @@ func (n *network) connected(p *peer) {
 		n.connectedIPs[str] = struct{}{}
 	}
 
-	n.router.Connected(p.nodeID)
+	// The router was already told that [p] is connected if a peer relays
+	// messages for it
+	if _, ok := n.relayedPeers[p.nodeID]; ok {
+		delete(n.relayedPeers, p.nodeID)
+	} else {
+		n.router.Connected(p.nodeID, p.versionStruct.GetValue().(version.Application))
+	}
 	n.metrics.connected.Inc()
+
+	if p.canRelay() {
+		// Ask our sentries to relay messages on our behalf
+		if n.isSentry(p.nodeID) {
+			p.sendRelayRequest()
+		}
+		// Tell the peer about the nodes we relay messages for
+		for nodeID := range n.relayAuths {
+			if nodeID != p.nodeID && n.relaysForUs(nodeID) {
+				p.sendRelayAuth(n.relayAuths[nodeID])
+			}
+		}
+	}
 }
 
 // should only be called after the peer is marked as connected.
@@ -1646,14 +1898,31 @@ func (n *network) disconnected(p *peer) {
 		delete(n.disconnectedIPs, str)
 		delete(n.connectedIPs, str)
 
-		if n.vdrs.Contains(p.nodeID) {
+		if !n.privateMode && n.vdrs.Contains(p.nodeID) {
 			n.track(ip, p.nodeID)
 		}
 	}
 
-	// Only send Disconnected to router if Connected was sent
+	// Static peers are always reconnected to
+	if staticIP, ok := n.staticPeers[p.nodeID]; ok {
+		n.track(staticIP, p.nodeID)
+	}
+
+	// Messages to and from [p] are no longer relayed by us, and [p] no longer
+	// relays messages for others
+	delete(n.relayAuths, p.nodeID)
+	n.removeRelayers(func(relayerID ids.ShortID, _ time.Time) bool {
+		return relayerID == p.nodeID
+	})
+
+	// Only send Disconnected to router if Connected was sent. If a peer still
+	// relays messages for [p], the router keeps considering it connected.
 	if p.finishedHandshake.GetValue() {
-		n.router.Disconnected(p.nodeID)
+		if n.hasRelayer(p.nodeID) {
+			n.relayedPeers[p.nodeID] = p.versionStruct.GetValue().(version.Application)
+		} else {
+			n.router.Disconnected(p.nodeID)
+		}
 	}
 	n.metrics.disconnected.Inc()
 }
@@ -1713,6 +1982,35 @@ func (n *network) getAllPeers() []*peer {
 	return peers
 }
 
+// samplePeersByScore returns [numToSample] distinct peers from [peers]. Peers
+// with higher scores are more likely to be sampled.
+// Assumes [numToSample] <= len([peers]).
+func (n *network) samplePeersByScore(peers []*peer, numToSample int) []*peer {
+	weights := make([]uint64, len(peers))
+	totalWeight := uint64(0)
+	for i, peer := range peers {
+		weights[i] = score.ScaleWeight(gossipScoreWeight, n.benchlistManager.Score(peer.nodeID), gossipMinScoreFactor)
+		totalWeight += weights[i]
+	}
+
+	sampled := make([]*peer, 0, numToSample)
+	for len(sampled) < numToSample && totalWeight > 0 {
+		// #nosec G404
+		value := uint64(rand.Int63n(int64(totalWeight)))
+		for i, weight := range weights {
+			if value >= weight {
+				value -= weight
+				continue
+			}
+			sampled = append(sampled, peers[i])
+			totalWeight -= weight
+			weights[i] = 0
+			break
+		}
+	}
+	return sampled
+}
+
 // Safe find a single peer
 // Assumes [n.stateLock] is not held.
 func (n *network) getPeer(nodeID ids.ShortID) *peer {
@@ -1784,7 +2082,9 @@ func (n *network) getVersion(ip utils.IPDesc) (uint64, []byte, error) {
 	n.timeForIPLock.Lock()
 	defer n.timeForIPLock.Unlock()
 
-	if !ip.Equal(n.lastVersionIP) {
+	// Private nodes advertise the empty IP, which equals [n.lastVersionIP]
+	// before anything is signed
+	if n.lastVersionSignature == nil || !ip.Equal(n.lastVersionIP) {
 		newTimestamp := n.clock.Unix()
 		msgHash := ipAndTimeHash(ip, newTimestamp)
 		sig, err := n.tlsKey.Sign(cryptorand.Reader, msgHash, crypto.SHA256)
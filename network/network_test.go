@@ -15,6 +15,8 @@ import (
 	"testing"
 	"time"
 
+	cryptorand "crypto/rand"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -198,7 +201,7 @@ type testHandler struct {
 	disconnected func(ids.ShortID)
 }
 
-func (h *testHandler) Connected(id ids.ShortID) {
+func (h *testHandler) Connected(id ids.ShortID, nodeVersion version.Application) {
 	if h.connected != nil {
 		h.connected(id)
 	}
@@ -264,8 +267,10 @@ func initCerts(t *testing.T) {
 }
 
 var (
-	defaultInboundMsgThrottler  = throttling.NewNoInboundThrottler()
-	defaultOutboundMsgThrottler = throttling.NewNoOutboundThrottler()
+	defaultInboundMsgThrottler        = throttling.NewNoInboundThrottler()
+	defaultOutboundMsgThrottler       = throttling.NewNoOutboundThrottler()
+	defaultInboundBandwidthThrottler  = throttling.NewNoInboundBandwidthThrottler()
+	defaultOutboundBandwidthThrottler = throttling.NewNoOutboundBandwidthThrottler()
 )
 
 func TestNewDefaultNetwork(t *testing.T) {
@@ -340,8 +345,14 @@ func TestNewDefaultNetwork(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net)
@@ -476,8 +487,14 @@ func TestEstablishConnection(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -510,8 +527,14 @@ func TestEstablishConnection(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -657,8 +680,14 @@ func TestDoubleTrack(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -691,8 +720,14 @@ func TestDoubleTrack(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -839,8 +874,14 @@ func TestDoubleClose(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -873,8 +914,14 @@ func TestDoubleClose(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -1026,8 +1073,14 @@ func TestTrackConnected(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -1060,8 +1113,14 @@ func TestTrackConnected(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -1187,8 +1246,14 @@ func TestTrackConnectedRace(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -1221,8 +1286,14 @@ func TestTrackConnectedRace(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -1479,8 +1550,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -1513,8 +1590,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -1547,8 +1630,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net2)
@@ -1581,8 +1670,14 @@ func TestPeerAliasesTicker(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net3)
@@ -1936,8 +2031,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -1970,8 +2071,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -2004,8 +2111,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net2)
@@ -2038,8 +2151,14 @@ func TestPeerAliasesDisconnect(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net3)
@@ -2309,8 +2428,14 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -2343,8 +2468,14 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -2377,8 +2508,14 @@ func TestPeerSignature(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net2)
@@ -2755,8 +2892,14 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net0)
@@ -2789,8 +2932,14 @@ func TestDontFinishHandshakeOnIncompatibleVersion(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, net1)
@@ -2864,3 +3013,631 @@ func isIPDescIn(targetIP utils.IPDesc, ipDescList []utils.IPCertDesc) bool {
 }
 
 // End of Helper method for TestValidatorIPs
+
+func TestStaticPeer(t *testing.T) {
+	initCerts(t)
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultApplication("app", 0, 1, 0)
+	versionParser := version.NewDefaultApplicationParser()
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	serverUpgrader0 := NewTLSServerUpgrader(tlsConfig0)
+	clientUpgrader0 := NewTLSClientUpgrader(tlsConfig0)
+
+	serverUpgrader1 := NewTLSServerUpgrader(tlsConfig1)
+	clientUpgrader1 := NewTLSClientUpgrader(tlsConfig1)
+
+	// Neither node is a validator, so they only connect because node 1 is a
+	// static peer of node 0
+	vdrs := validators.NewSet()
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	handler0 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id0 {
+				wg0.Done()
+			}
+		},
+	}
+
+	handler1 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id1 {
+				wg1.Done()
+			}
+		},
+	}
+
+	versionManager := version.NewCompatibility(
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+	)
+
+	net0, err := NewDefaultNetwork(
+		"",
+		prometheus.NewRegistry(),
+		log,
+		id0,
+		ip0,
+		networkID,
+		versionManager,
+		versionParser,
+		listener0,
+		caller0,
+		serverUpgrader0,
+		clientUpgrader0,
+		vdrs,
+		vdrs,
+		handler0,
+		throttling.InboundConnThrottlerConfig{},
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert0.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		true,
+		compression.Gzip,
+		nil,
+		true,
+		nil,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := NewDefaultNetwork(
+		"",
+		prometheus.NewRegistry(),
+		log,
+		id1,
+		ip1,
+		networkID,
+		versionManager,
+		versionParser,
+		listener1,
+		caller1,
+		serverUpgrader1,
+		clientUpgrader1,
+		vdrs,
+		vdrs,
+		handler1,
+		throttling.InboundConnThrottlerConfig{},
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert1.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	certID0 := certToID(cert0.Leaf)
+	certID1 := certToID(cert1.Leaf)
+
+	assert.ErrorIs(t, net0.AddStaticPeer(certID1, utils.IPDesc{}), errStaticPeerIPIsZero)
+	assert.Empty(t, net0.StaticPeers())
+
+	err = net0.AddStaticPeer(certID1, ip1.IP())
+	assert.NoError(t, err)
+	assert.Equal(t, map[ids.ShortID]utils.IPDesc{certID1: ip1.IP()}, net0.StaticPeers())
+
+	wg0.Wait()
+	wg1.Wait()
+
+	// Node 0 is in private mode, so node 1 doesn't learn its IP
+	net1.(*network).stateLock.RLock()
+	peer0, ok := net1.(*network).peers.getByID(certID0)
+	net1.(*network).stateLock.RUnlock()
+	assert.True(t, ok)
+	assert.True(t, peer0.getIP().IsZero())
+
+	// Node 1 is a static peer of node 0
+	net0.(*network).stateLock.RLock()
+	peer1, ok := net0.(*network).peers.getByID(certID1)
+	net0.(*network).stateLock.RUnlock()
+	assert.True(t, ok)
+	assert.True(t, peer1.static.GetValue())
+
+	assert.True(t, net0.RemoveStaticPeer(certID1))
+	assert.False(t, net0.RemoveStaticPeer(certID1))
+	assert.Empty(t, net0.StaticPeers())
+
+	// The connection to a removed static peer is closed
+	assert.True(t, peer1.closed.GetValue())
+	net0.(*network).stateLock.RLock()
+	_, ok = net0.(*network).peers.getByID(certID1)
+	net0.(*network).stateLock.RUnlock()
+	assert.False(t, ok)
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
+type relayTestHandler struct {
+	testHandler
+	pullQuery func(nodeID ids.ShortID, requestID uint32)
+	chits     func(nodeID ids.ShortID, requestID uint32)
+}
+
+func (h *relayTestHandler) PullQuery(nodeID ids.ShortID, _ ids.ID, requestID uint32, _ time.Time, _ ids.ID, onFinishedHandling func()) {
+	h.pullQuery(nodeID, requestID)
+	onFinishedHandling()
+}
+
+func (h *relayTestHandler) Chits(nodeID ids.ShortID, _ ids.ID, requestID uint32, _ []ids.ID, onFinishedHandling func()) {
+	h.chits(nodeID, requestID)
+	onFinishedHandling()
+}
+
+func TestRelay(t *testing.T) {
+	initCerts(t)
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultApplication("app", 1, 4, 14)
+	versionParser := version.NewDefaultApplicationParser()
+
+	// Node 0 is a validator in private mode. Node 1 is its sentry. Node 2 is a
+	// validator that only reaches node 0 through node 1.
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := certToID(cert0.Leaf)
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := certToID(cert1.Leaf)
+	ip2 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		2,
+	)
+	id2 := certToID(cert2.Leaf)
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener2 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 2,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller2 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 2,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip2.IP().String()] = listener2
+	caller2.outbounds[ip1.IP().String()] = listener1
+
+	serverUpgrader0 := NewTLSServerUpgrader(tlsConfig0)
+	clientUpgrader0 := NewTLSClientUpgrader(tlsConfig0)
+
+	serverUpgrader1 := NewTLSServerUpgrader(tlsConfig1)
+	clientUpgrader1 := NewTLSClientUpgrader(tlsConfig1)
+
+	serverUpgrader2 := NewTLSServerUpgrader(tlsConfig2)
+	clientUpgrader2 := NewTLSClientUpgrader(tlsConfig2)
+
+	vdrs := validators.NewSet()
+	assert.NoError(t, vdrs.AddWeight(id0, 1))
+	assert.NoError(t, vdrs.AddWeight(id2, 1))
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+		wg2 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(2)
+	wg2.Add(1)
+
+	pullQueries := make(chan ids.ShortID, 1)
+	chits := make(chan ids.ShortID, 1)
+	relayedConnected := make(chan struct{}, 1)
+	relayedDisconnected := make(chan struct{}, 1)
+
+	handler0 := &relayTestHandler{
+		testHandler: testHandler{
+			connected: func(id ids.ShortID) {
+				if id == id1 {
+					wg0.Done()
+				}
+			},
+		},
+		pullQuery: func(id ids.ShortID, _ uint32) { pullQueries <- id },
+	}
+
+	handler1 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id == id0 || id == id2 {
+				wg1.Done()
+			}
+		},
+	}
+
+	handler2 := &relayTestHandler{
+		testHandler: testHandler{
+			connected: func(id ids.ShortID) {
+				switch id {
+				case id0:
+					relayedConnected <- struct{}{}
+				case id1:
+					wg2.Done()
+				}
+			},
+			disconnected: func(id ids.ShortID) {
+				if id == id0 {
+					relayedDisconnected <- struct{}{}
+				}
+			},
+		},
+		chits: func(id ids.ShortID, _ uint32) { chits <- id },
+	}
+
+	versionManager := version.NewCompatibility(
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+		appVersion,
+		time.Now(),
+		appVersion,
+	)
+
+	net0, err := NewDefaultNetwork(
+		"",
+		prometheus.NewRegistry(),
+		log,
+		id0,
+		ip0,
+		networkID,
+		versionManager,
+		versionParser,
+		listener0,
+		caller0,
+		serverUpgrader0,
+		clientUpgrader0,
+		vdrs,
+		vdrs,
+		handler0,
+		throttling.InboundConnThrottlerConfig{},
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert0.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		true,
+		compression.Gzip,
+		nil,
+		true,
+		nil,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net0)
+
+	net1, err := NewDefaultNetwork(
+		"",
+		prometheus.NewRegistry(),
+		log,
+		id1,
+		ip1,
+		networkID,
+		versionManager,
+		versionParser,
+		listener1,
+		caller1,
+		serverUpgrader1,
+		clientUpgrader1,
+		vdrs,
+		vdrs,
+		handler1,
+		throttling.InboundConnThrottlerConfig{},
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert1.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		true,
+		compression.Gzip,
+		nil,
+		false,
+		ids.ShortSet{id0: struct{}{}},
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net1)
+
+	net2, err := NewDefaultNetwork(
+		"",
+		prometheus.NewRegistry(),
+		log,
+		id2,
+		ip2,
+		networkID,
+		versionManager,
+		versionParser,
+		listener2,
+		caller2,
+		serverUpgrader2,
+		clientUpgrader2,
+		vdrs,
+		vdrs,
+		handler2,
+		throttling.InboundConnThrottlerConfig{},
+		HealthConfig{},
+		benchlist.NewManager(&benchlist.Config{}),
+		defaultAliasTimeout,
+		cert2.PrivateKey.(crypto.Signer),
+		defaultPeerListSize,
+		defaultGossipPeerListTo,
+		defaultGossipPeerListFreq,
+		false,
+		defaultGossipAcceptedFrontierSize,
+		defaultGossipOnAcceptSize,
+		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
+		defaultInboundMsgThrottler,
+		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, net2)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net2.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	assert.NoError(t, net0.AddStaticPeer(id1, ip1.IP()))
+	net2.Track(ip1.IP(), id1)
+
+	wg0.Wait()
+	wg1.Wait()
+	wg2.Wait()
+
+	// Node 2 learns that node 1 relays messages to node 0
+	assert.Eventually(t, func() bool {
+		net2.(*network).stateLock.RLock()
+		defer net2.(*network).stateLock.RUnlock()
+		return net2.(*network).relaysFor(id1, id0)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Node 2's router considers node 0 connected through its sentry
+	<-relayedConnected
+
+	chainID := ids.GenerateTestID()
+	containerID := ids.GenerateTestID()
+
+	// Node 2 queries node 0, which is only reachable through node 1
+	sentTo := net2.PullQuery(ids.ShortSet{id0: struct{}{}}, chainID, 1, time.Second, containerID)
+	assert.Equal(t, []ids.ShortID{id0}, sentTo)
+	assert.Equal(t, id2, <-pullQueries)
+
+	// Node 0 responds through its sentry
+	net0.Chits(id2, chainID, 1, []ids.ID{containerID})
+	assert.Equal(t, id0, <-chits)
+
+	// Node 0 never connected to node 2, and its IP isn't known to its sentry
+	assert.Nil(t, net2.(*network).getPeer(id0))
+	peer0 := net1.(*network).getPeer(id0)
+	assert.NotNil(t, peer0)
+	assert.True(t, peer0.getIP().IsZero())
+
+	// Node 2's router considers node 0 disconnected once its sentry is gone
+	err = net1.Close()
+	assert.NoError(t, err)
+	<-relayedDisconnected
+
+	err = net0.Close()
+	assert.NoError(t, err)
+
+	err = net2.Close()
+	assert.NoError(t, err)
+}
+
+func TestVerifyRelayAuth(t *testing.T) {
+	initCerts(t)
+
+	relayerID := certToID(cert1.Leaf)
+	n := &network{maxClockDifference: time.Minute}
+	now := time.Unix(1000000, 0)
+	n.clock.Set(now)
+
+	sign := func(authTime time.Time) []byte {
+		sig, err := cert0.PrivateKey.(crypto.Signer).Sign(
+			cryptorand.Reader,
+			hashing.ComputeHash256(relayAuthBytes(relayerID, uint64(authTime.Unix()))),
+			crypto.SHA256,
+		)
+		assert.NoError(t, err)
+		return sig
+	}
+
+	authTime := now.Add(-relayAuthDuration / 2)
+	sig := sign(authTime)
+	assert.NoError(t, n.verifyRelayAuth(relayerID, cert0.Leaf, uint64(authTime.Unix()), sig))
+
+	// The signature covers the relayer's ID and the time
+	assert.Error(t, n.verifyRelayAuth(certToID(cert2.Leaf), cert0.Leaf, uint64(authTime.Unix()), sig))
+	assert.Error(t, n.verifyRelayAuth(relayerID, cert0.Leaf, uint64(authTime.Unix())+1, sig))
+	assert.Error(t, n.verifyRelayAuth(relayerID, cert1.Leaf, uint64(authTime.Unix()), sig))
+
+	// Expired
+	authTime = now.Add(-relayAuthDuration)
+	assert.ErrorIs(t, n.verifyRelayAuth(relayerID, cert0.Leaf, uint64(authTime.Unix()), sign(authTime)), errRelayAuthExpired)
+
+	// Too far in the future
+	authTime = now.Add(2 * time.Minute)
+	assert.Error(t, n.verifyRelayAuth(relayerID, cert0.Leaf, uint64(authTime.Unix()), sign(authTime)))
+
+	// Within the allowed clock difference
+	authTime = now.Add(time.Minute)
+	assert.NoError(t, n.verifyRelayAuth(relayerID, cert0.Leaf, uint64(authTime.Unix()), sign(authTime)))
+}
+
+func TestIsStaticPeerIP(t *testing.T) {
+	staticIP := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	n := &network{
+		staticPeers: map[ids.ShortID]utils.IPDesc{
+			ids.GenerateTestShortID(): staticIP,
+		},
+	}
+
+	assert.True(t, n.isStaticPeerIP(staticIP))
+	assert.False(t, n.isStaticPeerIP(utils.IPDesc{IP: staticIP.IP, Port: 9652}))
+	assert.False(t, n.isStaticPeerIP(utils.IPDesc{IP: net.IPv4(1, 2, 3, 5), Port: 9651}))
+}
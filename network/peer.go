@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/message"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -73,6 +74,11 @@ type peer struct {
 	// only close the peer once
 	once sync.Once
 
+	// static is true if this peer is one of the network's static peers, in
+	// which case messages to it are only limited by the byte allocations and
+	// the bandwidth shared by all peers, not by the limits of each peer
+	static utils.AtomicBool
+
 	// if the close function has been called.
 	closed utils.AtomicBool
 
@@ -138,6 +144,10 @@ type peer struct {
 
 	// True if we can compress messages sent to this peer
 	canHandleCompressed utils.AtomicBool
+
+	// Contains the compression.Type negotiated with this peer. Unset until
+	// we get a Compressors message from the peer.
+	negotiatedCompression utils.AtomicInterface
 }
 
 // newPeer returns a properly initialized *peer.
@@ -262,6 +272,12 @@ func (p *peer) ReadMessages() {
 			return
 		}
 
+		// Wait until the message fits in the bandwidth we may use to receive
+		// messages. The wait is capped well below the ping timeout so that
+		// pings queued behind this message are answered before the peer
+		// gives up on us.
+		p.net.inboundBandwidthThrottler.Acquire(uint64(msgLen), p.nodeID, p.net.pingPongTimeout/2)
+
 		// Wait until the throttler says we can proceed to read the message.
 		// Note that when we are done handling this message, or give up
 		// trying to read it, we must call [p.net.msgThrottler.Release]
@@ -297,6 +313,7 @@ func (p *peer) ReadMessages() {
 			// Couldn't parse the message. Read the next one.
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			continue
 		}
 
@@ -370,9 +387,17 @@ func (p *peer) Send(msg message.Message, canModifyMsg bool) bool {
 	msgBytes := msg.Bytes()
 	msgLen := int64(len(msgBytes))
 
+	// Messages to static peers aren't limited by the limits of each peer, but
+	// they still take from the byte allocations shared by all peers, which
+	// bound the size of the send queue
+	static := p.static.GetValue()
+
 	// Acquire space on the outbound message queue, or drop [msg] if we can't
-	dropMsg := !p.net.outboundMsgThrottler.Acquire(uint64(msgLen), p.nodeID)
-	if dropMsg {
+	acquire := p.net.outboundMsgThrottler.Acquire
+	if static {
+		acquire = p.net.outboundMsgThrottler.AcquireExempt
+	}
+	if !acquire(uint64(msgLen), p.nodeID) {
 		p.net.log.Debug("dropping %s message to %s%s at %s due to rate-limiting", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP())
 		return false
 	}
@@ -388,6 +413,19 @@ func (p *peer) Send(msg message.Message, canModifyMsg bool) bool {
 		return false
 	}
 
+	// Drop [msg] if it doesn't fit in the bandwidth we may use to send
+	// messages. Handshake and keepalive messages are always sent, so that
+	// limited bandwidth doesn't disconnect peers.
+	acquireBandwidth := p.net.outboundBandwidthThrottler.Acquire
+	if static {
+		acquireBandwidth = p.net.outboundBandwidthThrottler.AcquireExempt
+	}
+	if !isHandshakeOrKeepalive(msg) && !acquireBandwidth(msg.Op(), isLowPriority(msg), uint64(msgLen), p.nodeID) {
+		p.net.log.Debug("dropping %s message to %s%s at %s due to bandwidth limits", msg.Op(), constants.NodeIDPrefix, p.nodeID, p.getIP())
+		p.net.outboundMsgThrottler.Release(uint64(msgLen), p.nodeID)
+		return false
+	}
+
 	// If the flag says to not modify [msgBytes], copy it so that the copy,
 	// not [msgBytes], will be put back into the []byte pool after it's written.
 	toSend := msgBytes
@@ -447,6 +485,18 @@ func (p *peer) handle(msg message.Message, onFinishedHandling func()) {
 		p.handlePeerList(msg)
 		onFinishedHandling()
 		return
+	case message.Compressors:
+		p.handleCompressors(msg)
+		onFinishedHandling()
+		return
+	case message.RelayRequest:
+		p.handleRelayRequest(msg)
+		onFinishedHandling()
+		return
+	case message.RelayAuth:
+		p.handleRelayAuth(msg)
+		onFinishedHandling()
+		return
 	}
 	if !p.finishedHandshake.GetValue() {
 		p.net.log.Debug("dropping %s from %s%s at %s because handshake isn't finished", op, constants.NodeIDPrefix, p.nodeID, p.getIP())
@@ -462,29 +512,40 @@ func (p *peer) handle(msg message.Message, onFinishedHandling func()) {
 		return
 	}
 
-	switch op { // Consensus-related messages
+	if op == message.Relay {
+		p.handleRelay(msg, onFinishedHandling)
+		return
+	}
+	p.handleConsensus(p.nodeID, msg, onFinishedHandling)
+}
+
+// handleConsensus handles a consensus-related message from [nodeID], which is
+// [p.nodeID] unless [p] relayed the message.
+// assumes the [stateLock] is not held
+func (p *peer) handleConsensus(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
+	switch op := msg.Op(); op { // Consensus-related messages
 	case message.GetAcceptedFrontier:
-		p.handleGetAcceptedFrontier(msg, onFinishedHandling)
+		p.handleGetAcceptedFrontier(nodeID, msg, onFinishedHandling)
 	case message.AcceptedFrontier:
-		p.handleAcceptedFrontier(msg, onFinishedHandling)
+		p.handleAcceptedFrontier(nodeID, msg, onFinishedHandling)
 	case message.GetAccepted:
-		p.handleGetAccepted(msg, onFinishedHandling)
+		p.handleGetAccepted(nodeID, msg, onFinishedHandling)
 	case message.Accepted:
-		p.handleAccepted(msg, onFinishedHandling)
+		p.handleAccepted(nodeID, msg, onFinishedHandling)
 	case message.Get:
-		p.handleGet(msg, onFinishedHandling)
+		p.handleGet(nodeID, msg, onFinishedHandling)
 	case message.GetAncestors:
-		p.handleGetAncestors(msg, onFinishedHandling)
+		p.handleGetAncestors(nodeID, msg, onFinishedHandling)
 	case message.Put:
-		p.handlePut(msg, onFinishedHandling)
+		p.handlePut(nodeID, msg, onFinishedHandling)
 	case message.MultiPut:
-		p.handleMultiPut(msg, onFinishedHandling)
+		p.handleMultiPut(nodeID, msg, onFinishedHandling)
 	case message.PushQuery:
-		p.handlePushQuery(msg, onFinishedHandling)
+		p.handlePushQuery(nodeID, msg, onFinishedHandling)
 	case message.PullQuery:
-		p.handlePullQuery(msg, onFinishedHandling)
+		p.handlePullQuery(nodeID, msg, onFinishedHandling)
 	case message.Chits:
-		p.handleChits(msg, onFinishedHandling)
+		p.handleChits(nodeID, msg, onFinishedHandling)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s%s at %s with op %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), op)
 		onFinishedHandling()
@@ -545,6 +606,10 @@ func (p *peer) sendGetVersion() {
 func (p *peer) sendVersion() {
 	p.net.stateLock.RLock()
 	myIP := p.net.ip.IP()
+	if p.net.privateMode {
+		// Private nodes don't advertise an IP
+		myIP = utils.IPDesc{}
+	}
 	myVersionTime, myVersionSig, err := p.net.getVersion(myIP)
 	if err != nil {
 		p.net.stateLock.RUnlock()
@@ -609,8 +674,7 @@ func (p *peer) sendPeerList() {
 
 	// Compress this message only if the peer can handle compressed
 	// messages and we have compression enabled
-	canHandleCompressed := p.canHandleCompressed.GetValue()
-	msg, err := p.net.b.PeerList(peers, canHandleCompressed, canHandleCompressed && p.net.compressionEnabled)
+	msg, err := p.net.b.PeerList(peers, p.canHandleCompressed.GetValue(), p.compressionType())
 	if err != nil {
 		p.net.log.Warn("failed to send PeerList to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
 		return
@@ -633,6 +697,26 @@ func (p *peer) sendPeerList() {
 	}
 }
 
+// assumes the stateLock is not held
+func (p *peer) sendCompressors() {
+	msg, err := p.net.b.Compressors(p.net.compressionTypes(), p.net.compressionDictionaryID)
+	if err != nil {
+		p.net.log.Warn("failed to send Compressors to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.compressors.numSent.Inc()
+		p.net.compressors.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.compressors.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) sendPing() {
 	msg, err := p.net.b.Ping()
@@ -725,6 +809,7 @@ func (p *peer) handleVersion(msg message.Message) {
 		p.net.log.Debug("version of %s%s at %s could not be parsed: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
 		p.discardIP()
 		p.net.metrics.failedToParse.Inc()
+		p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 		return
 	}
 
@@ -776,6 +861,9 @@ func (p *peer) handleVersion(msg message.Message) {
 	}
 
 	p.canHandleCompressed.SetValue(peerVersion.Compare(minVersionCanHandleCompressed) >= 0)
+	if peerVersion.Compare(minVersionCanNegotiateCompression) >= 0 {
+		p.sendCompressors()
+	}
 
 	signedPeerIP := signedPeerIP{
 		ip:        peerIP,
@@ -823,6 +911,9 @@ func (p *peer) trackSignedPeer(peer utils.IPCertDesc) {
 	defer p.net.stateLock.Unlock()
 
 	switch {
+	case p.net.privateMode:
+		// In private mode, we only connect to static peers and beacons
+		return
 	case peer.IPDesc.Equal(p.net.ip.IP()):
 		return
 	case peer.IPDesc.IsZero():
@@ -896,6 +987,33 @@ func (p *peer) handlePeerList(msg message.Message) {
 	}
 }
 
+// assumes the [stateLock] is not held
+func (p *peer) handleCompressors(msg message.Message) {
+	typeBytes := msg.Get(message.CompressionTypes).([]byte)
+	peerTypes := make([]compression.Type, len(typeBytes))
+	for i, typeByte := range typeBytes {
+		peerTypes[i] = compression.Type(typeByte)
+	}
+	peerDictionaryID := msg.Get(message.DictionaryID).(uint32)
+
+	compressionType := negotiateCompression(p.net.compressionType, p.net.compressionDictionaryID, peerTypes, peerDictionaryID)
+	p.net.log.Verbo("compressing messages to %s%s at %s with %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), compressionType)
+	p.negotiatedCompression.SetValue(compressionType)
+}
+
+// compressionType returns the compression type messages sent to this peer are
+// compressed with. Peers that haven't negotiated a compression type are sent
+// gzip compressed messages.
+func (p *peer) compressionType() compression.Type {
+	if !p.net.compressionEnabled || !p.canHandleCompressed.GetValue() {
+		return compression.NoCompression
+	}
+	if compressionType, ok := p.negotiatedCompression.GetValue().(compression.Type); ok {
+		return compressionType
+	}
+	return compression.Gzip
+}
+
 // assumes the [stateLock] is not held
 func (p *peer) handlePing(_ message.Message) {
 	p.sendPong()
@@ -905,14 +1023,14 @@ func (p *peer) handlePing(_ message.Message) {
 func (p *peer) handlePong(_ message.Message) {}
 
 // assumes the [stateLock] is not held
-func (p *peer) handleGetAcceptedFrontier(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleGetAcceptedFrontier(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
 	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(message.Deadline).(uint64)))
 
 	p.net.router.GetAcceptedFrontier(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -921,7 +1039,7 @@ func (p *peer) handleGetAcceptedFrontier(msg message.Message, onFinishedHandling
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleAcceptedFrontier(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleAcceptedFrontier(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -938,6 +1056,7 @@ func (p *peer) handleAcceptedFrontier(msg message.Message, onFinishedHandling fu
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -947,6 +1066,7 @@ func (p *peer) handleAcceptedFrontier(msg message.Message, onFinishedHandling fu
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		containerIDs[i] = containerID
@@ -954,7 +1074,7 @@ func (p *peer) handleAcceptedFrontier(msg message.Message, onFinishedHandling fu
 	}
 
 	p.net.router.AcceptedFrontier(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		containerIDs,
@@ -963,7 +1083,7 @@ func (p *peer) handleAcceptedFrontier(msg message.Message, onFinishedHandling fu
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleGetAccepted(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleGetAccepted(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -981,6 +1101,7 @@ func (p *peer) handleGetAccepted(msg message.Message, onFinishedHandling func())
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -990,6 +1111,7 @@ func (p *peer) handleGetAccepted(msg message.Message, onFinishedHandling func())
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		containerIDs[i] = containerID
@@ -997,7 +1119,7 @@ func (p *peer) handleGetAccepted(msg message.Message, onFinishedHandling func())
 	}
 
 	p.net.router.GetAccepted(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -1007,7 +1129,7 @@ func (p *peer) handleGetAccepted(msg message.Message, onFinishedHandling func())
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleAccepted(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleAccepted(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1024,6 +1146,7 @@ func (p *peer) handleAccepted(msg message.Message, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -1033,6 +1156,7 @@ func (p *peer) handleAccepted(msg message.Message, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		containerIDs[i] = containerID
@@ -1040,7 +1164,7 @@ func (p *peer) handleAccepted(msg message.Message, onFinishedHandling func()) {
 	}
 
 	p.net.router.Accepted(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		containerIDs,
@@ -1049,7 +1173,7 @@ func (p *peer) handleAccepted(msg message.Message, onFinishedHandling func()) {
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleGet(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleGet(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1058,7 +1182,7 @@ func (p *peer) handleGet(msg message.Message, onFinishedHandling func()) {
 	p.net.log.AssertNoError(err)
 
 	p.net.router.Get(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -1067,7 +1191,7 @@ func (p *peer) handleGet(msg message.Message, onFinishedHandling func()) {
 	)
 }
 
-func (p *peer) handleGetAncestors(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleGetAncestors(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1076,7 +1200,7 @@ func (p *peer) handleGetAncestors(msg message.Message, onFinishedHandling func()
 	p.net.log.AssertNoError(err)
 
 	p.net.router.GetAncestors(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -1086,7 +1210,7 @@ func (p *peer) handleGetAncestors(msg message.Message, onFinishedHandling func()
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handlePut(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handlePut(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1095,7 +1219,7 @@ func (p *peer) handlePut(msg message.Message, onFinishedHandling func()) {
 	container := msg.Get(message.ContainerBytes).([]byte)
 
 	p.net.router.Put(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		containerID,
@@ -1105,14 +1229,14 @@ func (p *peer) handlePut(msg message.Message, onFinishedHandling func()) {
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleMultiPut(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleMultiPut(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
 	containers := msg.Get(message.MultiContainerBytes).([][]byte)
 
 	p.net.router.MultiPut(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		containers,
@@ -1120,7 +1244,7 @@ func (p *peer) handleMultiPut(msg message.Message, onFinishedHandling func()) {
 	)
 }
 
-func (p *peer) handlePushQuery(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handlePushQuery(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1130,7 +1254,7 @@ func (p *peer) handlePushQuery(msg message.Message, onFinishedHandling func()) {
 	container := msg.Get(message.ContainerBytes).([]byte)
 
 	p.net.router.PushQuery(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -1141,7 +1265,7 @@ func (p *peer) handlePushQuery(msg message.Message, onFinishedHandling func()) {
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handlePullQuery(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handlePullQuery(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1150,7 +1274,7 @@ func (p *peer) handlePullQuery(msg message.Message, onFinishedHandling func()) {
 	p.net.log.AssertNoError(err)
 
 	p.net.router.PullQuery(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		deadline,
@@ -1160,7 +1284,7 @@ func (p *peer) handlePullQuery(msg message.Message, onFinishedHandling func()) {
 }
 
 // assumes the [stateLock] is not held
-func (p *peer) handleChits(msg message.Message, onFinishedHandling func()) {
+func (p *peer) handleChits(nodeID ids.ShortID, msg message.Message, onFinishedHandling func()) {
 	chainID, err := ids.ToID(msg.Get(message.ChainID).([]byte))
 	p.net.log.AssertNoError(err)
 	requestID := msg.Get(message.RequestID).(uint32)
@@ -1177,6 +1301,7 @@ func (p *peer) handleChits(msg message.Message, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		if p.idSet.Contains(containerID) {
@@ -1186,6 +1311,7 @@ func (p *peer) handleChits(msg message.Message, onFinishedHandling func()) {
 			)
 			onFinishedHandling()
 			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
 			return
 		}
 		containerIDs[i] = containerID
@@ -1193,7 +1319,7 @@ func (p *peer) handleChits(msg message.Message, onFinishedHandling func()) {
 	}
 
 	p.net.router.Chits(
-		p.nodeID,
+		nodeID,
 		chainID,
 		requestID,
 		containerIDs,
@@ -1347,3 +1473,28 @@ func ipAndTimeBytes(ip utils.IPDesc, timestamp uint64) []byte {
 func ipAndTimeHash(ip utils.IPDesc, timestamp uint64) []byte {
 	return hashing.ComputeHash256(ipAndTimeBytes(ip, timestamp))
 }
+
+// isHandshakeOrKeepalive returns true if [msg] establishes or keeps alive the
+// connection to a peer
+func isHandshakeOrKeepalive(msg message.Message) bool {
+	switch msg.Op() {
+	case message.GetVersion, message.Version, message.Compressors, message.Ping, message.Pong:
+		return true
+	default:
+		return false
+	}
+}
+
+// isLowPriority returns true if [msg] is gossip. When bandwidth is limited,
+// gossip is dropped before other messages.
+func isLowPriority(msg message.Message) bool {
+	switch msg.Op() {
+	case message.PeerList:
+		return true
+	case message.Put:
+		requestID, _ := msg.Get(message.RequestID).(uint32)
+		return requestID == constants.GossipMsgRequestID
+	default:
+		return false
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/version"
@@ -117,8 +119,14 @@ func TestPeer_Close(t *testing.T) {
 		defaultGossipAcceptedFrontierSize,
 		defaultGossipOnAcceptSize,
 		true,
+		compression.Gzip,
+		nil,
+		false,
+		nil,
 		defaultInboundMsgThrottler,
 		defaultOutboundMsgThrottler,
+		defaultInboundBandwidthThrottler,
+		defaultOutboundBandwidthThrottler,
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, netwrk)
@@ -148,3 +156,57 @@ func TestPeer_Close(t *testing.T) {
 
 	peer.Close()
 }
+
+func TestIsLowPriority(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := message.NewCodec("", prometheus.NewRegistry(), int64(DefaultMaxMessageSize))
+	assert.NoError(err)
+	builder := message.NewBuilder(codec)
+	chainID := ids.GenerateTestID()
+	containerID := ids.GenerateTestID()
+
+	peerList, err := builder.PeerList(nil, true, compression.NoCompression)
+	assert.NoError(err)
+	assert.True(isLowPriority(peerList))
+
+	gossip, err := builder.Put(chainID, constants.GossipMsgRequestID, containerID, []byte{1}, true, compression.NoCompression)
+	assert.NoError(err)
+	assert.True(isLowPriority(gossip))
+
+	put, err := builder.Put(chainID, 1, containerID, []byte{1}, true, compression.NoCompression)
+	assert.NoError(err)
+	assert.False(isLowPriority(put))
+
+	chits, err := builder.Chits(chainID, 1, []ids.ID{containerID})
+	assert.NoError(err)
+	assert.False(isLowPriority(chits))
+}
+
+func TestIsHandshakeOrKeepalive(t *testing.T) {
+	assert := assert.New(t)
+
+	codec, err := message.NewCodec("", prometheus.NewRegistry(), int64(DefaultMaxMessageSize))
+	assert.NoError(err)
+	builder := message.NewBuilder(codec)
+
+	getVersion, err := builder.GetVersion()
+	assert.NoError(err)
+	assert.True(isHandshakeOrKeepalive(getVersion))
+
+	ping, err := builder.Ping()
+	assert.NoError(err)
+	assert.True(isHandshakeOrKeepalive(ping))
+
+	pong, err := builder.Pong()
+	assert.NoError(err)
+	assert.True(isHandshakeOrKeepalive(pong))
+
+	peerList, err := builder.PeerList(nil, true, compression.NoCompression)
+	assert.NoError(err)
+	assert.False(isHandshakeOrKeepalive(peerList))
+
+	chits, err := builder.Chits(ids.GenerateTestID(), 1, []ids.ID{ids.GenerateTestID()})
+	assert.NoError(err)
+	assert.False(isHandshakeOrKeepalive(chits))
+}
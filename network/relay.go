@@ -0,0 +1,482 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	cryptorand "crypto/rand"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/message"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// relayAuthDuration is how long an authorization to relay messages is
+	// valid for after it's signed
+	relayAuthDuration = 10 * time.Minute
+	// relayAuthRefreshFrequency is how often nodes in private mode renew the
+	// authorizations of their sentries, and how often expired authorizations
+	// are dropped
+	relayAuthRefreshFrequency = relayAuthDuration / 4
+)
+
+var errRelayAuthExpired = errors.New("relay authorization expired")
+
+// A validator in private mode that doesn't want to expose its IP connects only
+// to its static peers, its sentries, and sends each of them a RelayRequest
+// signed with its staking key. The request authorizes the sentry to relay
+// messages for [relayAuthDuration] after the time it was signed at, and is
+// renewed every [relayAuthRefreshFrequency] while the validator is connected
+// to the sentry. A sentry relays messages for the validators in
+// [relayedNodeIDs] and tells its other peers, with a RelayAuth, that it relays
+// messages to and from them. Peers verify the signature and expiry of the
+// RelayAuth, so that a sentry can only speak for nodes that authorized it
+// recently.
+//
+// Messages to a node that we aren't connected to are wrapped in a Relay
+// message and sent to one of the node's sentries, or to one of our sentries if
+// we're in private mode. The sentry forwards the Relay message, with the node
+// ID of the peer that sent it, to its destination.
+//
+// Peers tell the router that a node is connected while they're connected to it
+// or to one of its sentries, so that the uptime of nodes in private mode is
+// tracked.
+
+// relayAuth is the authorization, from a node we relay messages for, to relay
+// messages on its behalf
+type relayAuth struct {
+	cert *x509.Certificate
+	// Version the node runs
+	version string
+	// Unix time the authorization was signed at
+	time uint64
+	// Signature of our node ID and [time] by [cert]'s key
+	sig []byte
+}
+
+// expiry returns the time [auth] expires at
+func (auth relayAuth) expiry() time.Time {
+	return relayAuthExpiry(auth.time)
+}
+
+// relayAuthExpiry returns the time an authorization signed at [authTime]
+// expires at
+func relayAuthExpiry(authTime uint64) time.Time {
+	return time.Unix(int64(authTime), 0).Add(relayAuthDuration)
+}
+
+// relayAuthBytes returns what a node signs to authorize [relayerID] to relay
+// messages on its behalf at [authTime]
+func relayAuthBytes(relayerID ids.ShortID, authTime uint64) []byte {
+	p := wrappers.Packer{
+		Bytes: make([]byte, len(relayerID)+wrappers.LongLen),
+	}
+	p.PackFixedBytes(relayerID[:])
+	p.PackLong(authTime)
+	return p.Bytes
+}
+
+// verifyRelayAuth returns an error if [sig] isn't a valid authorization,
+// signed by [cert]'s key at [authTime], for [relayerID] to relay messages
+func (n *network) verifyRelayAuth(relayerID ids.ShortID, cert *x509.Certificate, authTime uint64, sig []byte) error {
+	now := n.clock.Time()
+	switch {
+	case !now.Before(relayAuthExpiry(authTime)):
+		return errRelayAuthExpired
+	case float64(authTime)-float64(now.Unix()) > n.maxClockDifference.Seconds():
+		return fmt.Errorf("relay authorization time %d is too far in the future", authTime)
+	}
+	return cert.CheckSignature(cert.SignatureAlgorithm, relayAuthBytes(relayerID, authTime), sig)
+}
+
+// send sends [msg] to [nodeID] through [p], the peer with ID [nodeID] or nil,
+// if we finished the handshake with it. Otherwise, [msg] is relayed through a
+// sentry, if there's one. Returns true if [msg] was sent.
+// If [canModifyMsg], [msg] may be modified by this method.
+// Assumes [n.stateLock] is not held.
+func (n *network) send(p *peer, nodeID ids.ShortID, msg message.Message, canModifyMsg bool) bool {
+	if p != nil && p.finishedHandshake.GetValue() {
+		return p.Send(msg, canModifyMsg)
+	}
+	return n.relayMsg(nodeID, msg)
+}
+
+// relayMsg sends [msg] to [nodeID] through a sentry. Relayed messages always
+// include the isCompressed flag if their op may be compressed. Returns true if
+// [msg] was sent.
+// Assumes [n.stateLock] is not held.
+func (n *network) relayMsg(nodeID ids.ShortID, msg message.Message) bool {
+	n.stateLock.RLock()
+	relayer := n.relayer(nodeID)
+	n.stateLock.RUnlock()
+
+	if relayer == nil {
+		return false
+	}
+
+	relayMsg, err := n.b.Relay(nodeID, msg.Bytes())
+	if err != nil {
+		n.log.Debug("failed to build Relay of %s to %s%s: %s", msg.Op(), constants.NodeIDPrefix, nodeID, err)
+		return false
+	}
+	return relayer.sendRelay(relayMsg)
+}
+
+// relayer returns a peer that relays messages to [nodeID], or nil if there
+// isn't one. If no peer told us that it relays messages to [nodeID] and we're
+// in private mode, one of our sentries is returned.
+// Assumes [n.stateLock] is held.
+func (n *network) relayer(nodeID ids.ShortID) *peer {
+	now := n.clock.Time()
+	relayers := []*peer(nil)
+	for relayerID, expiry := range n.relayers[nodeID] {
+		if !now.Before(expiry) {
+			continue
+		}
+		if p, ok := n.peers.getByID(relayerID); ok && p.finishedHandshake.GetValue() {
+			relayers = append(relayers, p)
+		}
+	}
+	if len(relayers) == 0 && n.privateMode {
+		for sentryID := range n.staticPeers {
+			if p, ok := n.peers.getByID(sentryID); ok && p.finishedHandshake.GetValue() && p.canRelay() {
+				relayers = append(relayers, p)
+			}
+		}
+	}
+	if len(relayers) == 0 {
+		return nil
+	}
+	return relayers[rand.Intn(len(relayers))] // #nosec G404
+}
+
+// isSentry returns true if [nodeID] relays messages on our behalf.
+// Assumes [n.stateLock] is held.
+func (n *network) isSentry(nodeID ids.ShortID) bool {
+	_, ok := n.staticPeers[nodeID]
+	return ok && n.privateMode
+}
+
+// relaysFor returns true if [relayerID] relays messages to and from [nodeID].
+// Assumes [n.stateLock] is held.
+func (n *network) relaysFor(relayerID, nodeID ids.ShortID) bool {
+	expiry, ok := n.relayers[nodeID][relayerID]
+	return ok && n.clock.Time().Before(expiry)
+}
+
+// hasRelayer returns true if a peer relays messages to and from [nodeID].
+// Assumes [n.stateLock] is held.
+func (n *network) hasRelayer(nodeID ids.ShortID) bool {
+	now := n.clock.Time()
+	for _, expiry := range n.relayers[nodeID] {
+		if now.Before(expiry) {
+			return true
+		}
+	}
+	return false
+}
+
+// relaysForUs returns true if we're authorized to relay messages for
+// [nodeID].
+// Assumes [n.stateLock] is held.
+func (n *network) relaysForUs(nodeID ids.ShortID) bool {
+	auth, ok := n.relayAuths[nodeID]
+	return ok && n.clock.Time().Before(auth.expiry())
+}
+
+// addRelayer records that [relayerID] relays messages to and from [nodeID],
+// which runs [nodeVersion], until [expiry]. If we aren't connected to
+// [nodeID], the router is told that it's connected.
+// Assumes [n.stateLock] is held.
+func (n *network) addRelayer(nodeID, relayerID ids.ShortID, nodeVersion version.Application, expiry time.Time) {
+	relayers, ok := n.relayers[nodeID]
+	if !ok {
+		relayers = make(map[ids.ShortID]time.Time)
+		n.relayers[nodeID] = relayers
+	}
+	relayers[relayerID] = expiry
+
+	if _, ok := n.relayedPeers[nodeID]; ok {
+		return
+	}
+	if p, ok := n.peers.getByID(nodeID); ok && p.finishedHandshake.GetValue() {
+		return
+	}
+	n.relayedPeers[nodeID] = nodeVersion
+	n.log.Debug("connected to %s%s through %s%s", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, relayerID)
+	n.router.Connected(nodeID, nodeVersion)
+}
+
+// removeRelayers stops relaying messages through the peers [shouldRemove]
+// returns true for. Nodes that we were only connected to through those peers
+// are disconnected from the router.
+// Assumes [n.stateLock] is held.
+func (n *network) removeRelayers(shouldRemove func(relayerID ids.ShortID, expiry time.Time) bool) {
+	for nodeID, relayers := range n.relayers {
+		for relayerID, expiry := range relayers {
+			if shouldRemove(relayerID, expiry) {
+				delete(relayers, relayerID)
+			}
+		}
+		if len(relayers) == 0 {
+			delete(n.relayers, nodeID)
+		}
+		if _, ok := n.relayedPeers[nodeID]; ok && !n.hasRelayer(nodeID) {
+			delete(n.relayedPeers, nodeID)
+			n.log.Debug("disconnected from %s%s, which was reached through relayers", constants.NodeIDPrefix, nodeID)
+			n.router.Disconnected(nodeID)
+		}
+	}
+}
+
+// refreshRelayAuths periodically renews the authorizations of our sentries,
+// if we're in private mode, and drops expired authorizations.
+// Assumes [n.stateLock] is not held. Only returns after the network is closed.
+func (n *network) refreshRelayAuths() {
+	t := time.NewTicker(relayAuthRefreshFrequency)
+	defer t.Stop()
+
+	for range t.C {
+		if n.closed.GetValue() {
+			return
+		}
+
+		n.stateLock.Lock()
+		now := n.clock.Time()
+		for nodeID, auth := range n.relayAuths {
+			if !now.Before(auth.expiry()) {
+				n.log.Debug("stopped relaying messages for %s%s because its authorization expired", constants.NodeIDPrefix, nodeID)
+				delete(n.relayAuths, nodeID)
+			}
+		}
+		n.removeRelayers(func(_ ids.ShortID, expiry time.Time) bool {
+			return !now.Before(expiry)
+		})
+		if n.privateMode {
+			for sentryID := range n.staticPeers {
+				if p, ok := n.peers.getByID(sentryID); ok && p.finishedHandshake.GetValue() && p.canRelay() {
+					p.sendRelayRequest()
+				}
+			}
+		}
+		n.stateLock.Unlock()
+	}
+}
+
+// forward sends [msgBytes], a message relayed from [fromID], to [toID].
+// Assumes [n.stateLock] is not held.
+func (n *network) forward(fromID, toID ids.ShortID, msgBytes []byte) {
+	p := n.getPeer(toID)
+	if p == nil || !p.finishedHandshake.GetValue() {
+		n.log.Debug("dropping Relay from %s%s to %s%s because we aren't connected to it", constants.NodeIDPrefix, fromID, constants.NodeIDPrefix, toID)
+		n.relay.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, n.clock.Time())
+		return
+	}
+
+	msg, err := n.b.Relay(fromID, msgBytes)
+	if err != nil {
+		n.log.Debug("failed to build Relay from %s%s to %s%s: %s", constants.NodeIDPrefix, fromID, constants.NodeIDPrefix, toID, err)
+		n.relay.numFailed.Inc()
+		n.sendFailRateCalculator.Observe(1, n.clock.Time())
+		return
+	}
+	p.sendRelay(msg)
+}
+
+// canRelay returns true if [p] can relay messages and handle relayed messages.
+// Should only be called after [p]'s version is known.
+func (p *peer) canRelay() bool {
+	peerVersion, ok := p.versionStruct.GetValue().(version.Application)
+	return ok && peerVersion.Compare(minVersionCanRelay) >= 0
+}
+
+// sendRelayRequest asks [p] to relay messages on our behalf.
+// Assumes the [stateLock] is held.
+func (p *peer) sendRelayRequest() {
+	authTime := p.net.clock.Unix()
+	sig, err := p.net.tlsKey.Sign(cryptorand.Reader, hashing.ComputeHash256(relayAuthBytes(p.nodeID, authTime)), crypto.SHA256)
+	if err != nil {
+		p.net.log.Error("failed to sign RelayRequest to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+	msg, err := p.net.b.RelayRequest(authTime, sig)
+	if err != nil {
+		p.net.log.Warn("failed to send RelayRequest to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.relayRequest.numSent.Inc()
+		p.net.relayRequest.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.relayRequest.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// sendRelayAuth tells [p] that we relay messages for the node that gave us
+// [auth].
+// Assumes the [stateLock] is held.
+func (p *peer) sendRelayAuth(auth relayAuth) {
+	msg, err := p.net.b.RelayAuth(auth.cert, auth.version, auth.time, auth.sig)
+	if err != nil {
+		p.net.log.Warn("failed to send RelayAuth to %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.relayAuth.numSent.Inc()
+		p.net.relayAuth.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.relayAuth.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+}
+
+// sendRelay sends [msg], a Relay message, to [p]. Returns true if [msg] was
+// sent.
+// Assumes the [stateLock] is not held.
+func (p *peer) sendRelay(msg message.Message) bool {
+	lenMsg := len(msg.Bytes())
+	sent := p.Send(msg, true)
+	if sent {
+		p.net.relay.numSent.Inc()
+		p.net.relay.sentBytes.Add(float64(lenMsg))
+		p.net.sendFailRateCalculator.Observe(0, p.net.clock.Time())
+	} else {
+		p.net.relay.numFailed.Inc()
+		p.net.sendFailRateCalculator.Observe(1, p.net.clock.Time())
+	}
+	return sent
+}
+
+// handleRelayRequest makes us relay messages for [p] if we're its sentry.
+// Assumes the [stateLock] is not held.
+func (p *peer) handleRelayRequest(msg message.Message) {
+	auth := relayAuth{
+		cert: p.cert,
+		time: msg.Get(message.VersionTime).(uint64),
+		sig:  msg.Get(message.SigBytes).([]byte),
+	}
+	auth.version, _ = p.versionStr.GetValue().(string)
+	if err := p.net.verifyRelayAuth(p.net.id, auth.cert, auth.time, auth.sig); err != nil {
+		p.net.log.Debug("dropping RelayRequest from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	if !p.net.relayedNodeIDs.Contains(p.nodeID) {
+		p.net.log.Debug("dropping RelayRequest from %s%s at %s because we don't relay messages for it", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+
+	if _, ok := p.net.relayAuths[p.nodeID]; !ok {
+		p.net.log.Debug("relaying messages for %s%s at %s", constants.NodeIDPrefix, p.nodeID, p.getIP())
+	}
+	p.net.relayAuths[p.nodeID] = auth
+
+	for _, peer := range p.net.peers.peersList {
+		if peer != p && peer.finishedHandshake.GetValue() && peer.canRelay() {
+			peer.sendRelayAuth(auth)
+		}
+	}
+}
+
+// handleRelayAuth makes us relay messages to the node in [msg] through [p].
+// Assumes the [stateLock] is not held.
+func (p *peer) handleRelayAuth(msg message.Message) {
+	cert, _ := msg.Get(message.Cert).(*x509.Certificate)
+	if cert == nil {
+		p.net.log.Debug("dropping RelayAuth from %s%s at %s without a certificate", constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+	nodeID := certToID(cert)
+
+	authTime := msg.Get(message.VersionTime).(uint64)
+	sig := msg.Get(message.SigBytes).([]byte)
+	if err := p.net.verifyRelayAuth(p.nodeID, cert, authTime, sig); err != nil {
+		p.net.log.Debug("dropping RelayAuth for %s%s from %s%s at %s: %s", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+	nodeVersion, err := p.net.parser.Parse(msg.Get(message.VersionStr).(string))
+	if err != nil {
+		p.net.log.Debug("dropping RelayAuth for %s%s from %s%s at %s because its version could not be parsed: %s", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		return
+	}
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	switch {
+	case nodeID == p.nodeID:
+		return
+	case !p.net.vdrs.Contains(nodeID):
+		// Only messages to and from validators are relayed
+		p.net.log.Debug("dropping RelayAuth for %s%s from %s%s at %s because it isn't a validator", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, p.nodeID, p.getIP())
+		return
+	}
+
+	p.net.addRelayer(nodeID, p.nodeID, nodeVersion, relayAuthExpiry(authTime))
+}
+
+// handleRelay handles a message relayed to us by [p], or forwards a message
+// that [p] asked us to relay.
+// Assumes the [stateLock] is not held.
+func (p *peer) handleRelay(msg message.Message, onFinishedHandling func()) {
+	nodeID, err := ids.ToShortID(msg.Get(message.RelayNodeID).([]byte))
+	if err != nil {
+		p.net.log.Debug("error parsing RelayNodeID from %s%s at %s: %s", constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+		onFinishedHandling()
+		p.net.metrics.failedToParse.Inc()
+		p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
+		return
+	}
+	relayedBytes := msg.Get(message.RelayedMsg).([]byte)
+
+	p.net.stateLock.RLock()
+	relayedToUs := p.net.isSentry(p.nodeID) || p.net.relaysFor(p.nodeID, nodeID)
+	relayedFromPeer := p.net.relaysForUs(p.nodeID)
+	relayedToNode := p.net.relaysForUs(nodeID)
+	p.net.stateLock.RUnlock()
+
+	switch {
+	case relayedToUs:
+		// [p] relayed a message from [nodeID] to us
+		relayedMsg, err := p.net.c.Parse(relayedBytes, true)
+		if err != nil {
+			p.net.log.Debug("failed to parse message relayed from %s%s by %s%s at %s: %s", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, p.nodeID, p.getIP(), err)
+			onFinishedHandling()
+			p.net.metrics.failedToParse.Inc()
+			p.net.benchlistManager.RegisterInvalidMessage(p.nodeID)
+			return
+		}
+		p.handleConsensus(nodeID, relayedMsg, onFinishedHandling)
+	case relayedFromPeer || relayedToNode:
+		// We're the sentry of [p] or of [nodeID]
+		p.net.forward(p.nodeID, nodeID, relayedBytes)
+		onFinishedHandling()
+	default:
+		p.net.log.Debug("dropping Relay to %s%s from %s%s at %s because we don't relay messages for either", constants.NodeIDPrefix, nodeID, constants.NodeIDPrefix, p.nodeID, p.getIP())
+		onFinishedHandling()
+	}
+}
@@ -0,0 +1,386 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/message"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Portion of each outbound byte budget that low priority messages can't
+	// use, so that there's bandwidth left for query responses when gossip
+	// would otherwise saturate the uplink.
+	lowPriorityReservedPortion = 0.5
+
+	// How often byte budgets of peers that haven't used bandwidth recently are
+	// dropped. A dropped budget is equivalent to a full one.
+	bandwidthPruneFrequency = time.Minute
+)
+
+var (
+	errNoBurst     = errors.New("max burst size must be positive when the refill rate is")
+	errNoPeerBurst = errors.New("peer max burst size must be positive when the peer refill rate is")
+
+	_ InboundBandwidthThrottler  = &inboundBandwidthThrottler{}
+	_ InboundBandwidthThrottler  = &noInboundBandwidthThrottler{}
+	_ OutboundBandwidthThrottler = &outboundBandwidthThrottler{}
+	_ OutboundBandwidthThrottler = &noOutboundBandwidthThrottler{}
+)
+
+// BandwidthThrottlerConfig limits the bandwidth used to send, or receive,
+// messages with token buckets. A bucket holds up to its max burst size in bytes
+// and refills at its refill rate. Messages larger than a bucket pass once the
+// bucket is full, after which the bucket must refill past 0 again.
+type BandwidthThrottlerConfig struct {
+	// Bytes per second across all peers. If 0, not limited.
+	RefillRate uint64
+	// Max bytes in a burst across all peers
+	MaxBurstSize uint64
+	// Bytes per second for each peer. If 0, not limited.
+	PeerRefillRate uint64
+	// Max bytes in a burst for each peer
+	PeerMaxBurstSize uint64
+}
+
+// Verify returns an error if a bucket would refill but could never hold a
+// byte, which would drop, or stall, every message
+func (c BandwidthThrottlerConfig) Verify() error {
+	switch {
+	case c.RefillRate != 0 && c.MaxBurstSize == 0:
+		return errNoBurst
+	case c.PeerRefillRate != 0 && c.PeerMaxBurstSize == 0:
+		return errNoPeerBurst
+	default:
+		return nil
+	}
+}
+
+// InboundBandwidthThrottler limits the bandwidth used to receive messages
+type InboundBandwidthThrottler interface {
+	// Blocks until a message of size [msgSize] can be read from [nodeID], or
+	// for at most [maxWait]
+	Acquire(msgSize uint64, nodeID ids.ShortID, maxWait time.Duration)
+}
+
+// OutboundBandwidthThrottler limits the bandwidth used to send messages
+type OutboundBandwidthThrottler interface {
+	// Returns true if a message of size [msgSize] with op [op] can be sent to
+	// [nodeID] now. Returns false if the message should be dropped.
+	// If [lowPriority], the message is dropped before using the bandwidth
+	// reserved for other messages.
+	Acquire(op message.Op, lowPriority bool, msgSize uint64, nodeID ids.ShortID) bool
+
+	// Like Acquire, but the message is only limited by the bandwidth across
+	// all peers, not by the bandwidth of [nodeID]
+	AcquireExempt(op message.Op, lowPriority bool, msgSize uint64, nodeID ids.ShortID) bool
+}
+
+// tokenBucket holds bytes that may be sent or received. Not safe for
+// concurrent use.
+type tokenBucket struct {
+	// Bytes added per second
+	refillRate float64
+	// Max bytes the bucket holds
+	size float64
+	// Bytes in the bucket. Negative if more bytes were taken than the
+	// bucket held.
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(refillRate, size uint64, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		refillRate: float64(refillRate),
+		size:       float64(size),
+		tokens:     float64(size),
+		lastRefill: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(b.size, b.tokens+elapsed.Seconds()*b.refillRate)
+	b.lastRefill = now
+}
+
+// canTake returns true if [n] bytes can be taken without taking from the
+// [reserved] bytes. More bytes than aren't reserved can be taken only if the
+// bucket is full.
+func (b *tokenBucket) canTake(n, reserved float64) bool {
+	return b.tokens-reserved >= math.Min(n, b.size-reserved)
+}
+
+func (b *tokenBucket) take(n float64) { b.tokens -= n }
+
+// wait returns how long until the bucket holds no less than 0 bytes
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.refillRate * float64(time.Second))
+}
+
+// bandwidthBuckets are the token buckets of all peers and of each peer
+type bandwidthBuckets struct {
+	config BandwidthThrottlerConfig
+	clock  timer.Clock
+
+	lock sync.Mutex
+	// nil if the bandwidth across all peers isn't limited
+	all *tokenBucket
+	// Node ID --> Its bucket. Empty if the bandwidth of each peer isn't
+	// limited.
+	peers     map[ids.ShortID]*tokenBucket
+	lastPrune time.Time
+}
+
+func newBandwidthBuckets(config BandwidthThrottlerConfig) *bandwidthBuckets {
+	b := &bandwidthBuckets{
+		config: config,
+		peers:  make(map[ids.ShortID]*tokenBucket),
+	}
+	now := b.clock.Time()
+	if config.RefillRate != 0 {
+		b.all = newTokenBucket(config.RefillRate, config.MaxBurstSize, now)
+	}
+	b.lastPrune = now
+	return b
+}
+
+// get returns the refilled buckets a message to or from [nodeID] takes bytes
+// from. If ![perPeer], the bucket of [nodeID] isn't returned. Assumes [b.lock]
+// is held.
+func (b *bandwidthBuckets) get(nodeID ids.ShortID, perPeer bool) []*tokenBucket {
+	now := b.clock.Time()
+	b.prune(now)
+
+	buckets := make([]*tokenBucket, 0, 2)
+	if b.all != nil {
+		b.all.refill(now)
+		buckets = append(buckets, b.all)
+	}
+	if perPeer && b.config.PeerRefillRate != 0 {
+		peer, ok := b.peers[nodeID]
+		if !ok {
+			peer = newTokenBucket(b.config.PeerRefillRate, b.config.PeerMaxBurstSize, now)
+			b.peers[nodeID] = peer
+		}
+		peer.refill(now)
+		buckets = append(buckets, peer)
+	}
+	return buckets
+}
+
+// prune drops the buckets of peers that are full. Assumes [b.lock] is held.
+func (b *bandwidthBuckets) prune(now time.Time) {
+	if now.Sub(b.lastPrune) < bandwidthPruneFrequency {
+		return
+	}
+	b.lastPrune = now
+	for nodeID, peer := range b.peers {
+		peer.refill(now)
+		if peer.tokens >= peer.size {
+			delete(b.peers, nodeID)
+		}
+	}
+}
+
+type inboundBandwidthThrottler struct {
+	*bandwidthBuckets
+	metrics inboundBandwidthThrottlerMetrics
+	// Replaced in tests
+	sleep func(time.Duration)
+}
+
+// NewInboundBandwidthThrottler returns a throttler that makes messages wait to
+// be read until they fit in the bandwidth given by [config]
+func NewInboundBandwidthThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	config BandwidthThrottlerConfig,
+) (InboundBandwidthThrottler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	t := &inboundBandwidthThrottler{
+		bandwidthBuckets: newBandwidthBuckets(config),
+		sleep:            time.Sleep,
+	}
+	return t, t.metrics.initialize(namespace, registerer)
+}
+
+// See InboundBandwidthThrottler. Messages take their bytes as soon as they
+// arrive, so they are read in the order they arrived in. A message that waits
+// for [maxWait] still takes all of its bytes, so the messages after it wait
+// longer.
+func (t *inboundBandwidthThrottler) Acquire(msgSize uint64, nodeID ids.ShortID, maxWait time.Duration) {
+	t.lock.Lock()
+	wait := time.Duration(0)
+	for _, bucket := range t.get(nodeID, true) {
+		bucket.take(float64(msgSize))
+		if bucketWait := bucket.wait(); bucketWait > wait {
+			wait = bucketWait
+		}
+	}
+	t.lock.Unlock()
+
+	if wait > maxWait {
+		wait = maxWait
+	}
+	if wait <= 0 {
+		return
+	}
+	t.metrics.throttledBytes.Add(float64(msgSize))
+	t.metrics.waitTime.Observe(float64(wait))
+	t.sleep(wait)
+}
+
+type inboundBandwidthThrottlerMetrics struct {
+	throttledBytes prometheus.Counter
+	waitTime       metric.Averager
+}
+
+func (m *inboundBandwidthThrottlerMetrics) initialize(namespace string, registerer prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+	m.throttledBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "throttler_inbound_bandwidth_throttled_bytes",
+		Help:      "Bytes of incoming messages that waited to be read due to bandwidth limits",
+	})
+	m.waitTime = metric.NewAveragerWithErrs(
+		namespace,
+		"throttler_inbound_bandwidth_wait_time",
+		"time (in ns) of an incoming message waiting to be read due to bandwidth limits",
+		registerer,
+		&errs,
+	)
+	errs.Add(registerer.Register(m.throttledBytes))
+	return errs.Err
+}
+
+type outboundBandwidthThrottler struct {
+	*bandwidthBuckets
+	metrics outboundBandwidthThrottlerMetrics
+}
+
+// NewOutboundBandwidthThrottler returns a throttler that drops messages that
+// don't fit in the bandwidth given by [config]
+func NewOutboundBandwidthThrottler(
+	namespace string,
+	registerer prometheus.Registerer,
+	config BandwidthThrottlerConfig,
+) (OutboundBandwidthThrottler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	t := &outboundBandwidthThrottler{
+		bandwidthBuckets: newBandwidthBuckets(config),
+	}
+	return t, t.metrics.initialize(namespace, registerer)
+}
+
+// See OutboundBandwidthThrottler
+func (t *outboundBandwidthThrottler) Acquire(op message.Op, lowPriority bool, msgSize uint64, nodeID ids.ShortID) bool {
+	return t.acquire(op, lowPriority, msgSize, nodeID, true)
+}
+
+// See OutboundBandwidthThrottler
+func (t *outboundBandwidthThrottler) AcquireExempt(op message.Op, lowPriority bool, msgSize uint64, nodeID ids.ShortID) bool {
+	return t.acquire(op, lowPriority, msgSize, nodeID, false)
+}
+
+// acquire takes [msgSize] bytes from the bandwidth across all peers and, if
+// [perPeer], from the bandwidth of [nodeID]
+func (t *outboundBandwidthThrottler) acquire(op message.Op, lowPriority bool, msgSize uint64, nodeID ids.ShortID, perPeer bool) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	reservedPortion := 0.
+	if lowPriority {
+		reservedPortion = lowPriorityReservedPortion
+	}
+	buckets := t.get(nodeID, perPeer)
+	for _, bucket := range buckets {
+		if !bucket.canTake(float64(msgSize), reservedPortion*bucket.size) {
+			opStr := op.String()
+			t.metrics.throttledBytes.WithLabelValues(opStr).Add(float64(msgSize))
+			t.metrics.throttledMsgs.WithLabelValues(opStr).Inc()
+			return false
+		}
+	}
+	for _, bucket := range buckets {
+		bucket.take(float64(msgSize))
+	}
+	return true
+}
+
+type outboundBandwidthThrottlerMetrics struct {
+	throttledBytes *prometheus.CounterVec
+	throttledMsgs  *prometheus.CounterVec
+}
+
+func (m *outboundBandwidthThrottlerMetrics) initialize(namespace string, registerer prometheus.Registerer) error {
+	m.throttledBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "throttler_outbound_bandwidth_throttled_bytes",
+			Help:      "Bytes of outbound messages dropped due to bandwidth limits",
+		},
+		[]string{"op"},
+	)
+	m.throttledMsgs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "throttler_outbound_bandwidth_throttled",
+			Help:      "Outbound messages dropped due to bandwidth limits",
+		},
+		[]string{"op"},
+	)
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.throttledBytes),
+		registerer.Register(m.throttledMsgs),
+	)
+	return errs.Err
+}
+
+func NewNoInboundBandwidthThrottler() InboundBandwidthThrottler {
+	return &noInboundBandwidthThrottler{}
+}
+
+// noInboundBandwidthThrottler implements InboundBandwidthThrottler.
+// [Acquire] always returns immediately.
+type noInboundBandwidthThrottler struct{}
+
+func (*noInboundBandwidthThrottler) Acquire(uint64, ids.ShortID, time.Duration) {}
+
+func NewNoOutboundBandwidthThrottler() OutboundBandwidthThrottler {
+	return &noOutboundBandwidthThrottler{}
+}
+
+// noOutboundBandwidthThrottler implements OutboundBandwidthThrottler.
+// [Acquire] always returns true.
+type noOutboundBandwidthThrottler struct{}
+
+func (*noOutboundBandwidthThrottler) Acquire(message.Op, bool, uint64, ids.ShortID) bool {
+	return true
+}
+
+func (*noOutboundBandwidthThrottler) AcquireExempt(message.Op, bool, uint64, ids.ShortID) bool {
+	return true
+}
@@ -0,0 +1,238 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package throttling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/message"
+)
+
+func TestOutboundBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		RefillRate:       1024,
+		MaxBurstSize:     2048,
+		PeerRefillRate:   512,
+		PeerMaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	node1ID := ids.GenerateTestShortID()
+	node2ID := ids.GenerateTestShortID()
+
+	// Take all of node 1's bytes
+	assert.True(throttler.Acquire(message.Chits, false, 1024, node1ID))
+	assert.False(throttler.Acquire(message.Chits, false, 1, node1ID))
+
+	// Node 2 has its own budget. Taking all of it leaves no bytes across all
+	// peers.
+	assert.True(throttler.Acquire(message.Chits, false, 1024, node2ID))
+	assert.False(throttler.Acquire(message.Chits, false, 1, node2ID))
+
+	node3ID := ids.GenerateTestShortID()
+	assert.False(throttler.Acquire(message.Chits, false, 1, node3ID))
+
+	// After a second, node 1 has 512 bytes and all peers have 1024 bytes
+	now = now.Add(time.Second)
+	throttler.clock.Set(now)
+	assert.False(throttler.Acquire(message.Chits, false, 513, node1ID))
+	assert.True(throttler.Acquire(message.Chits, false, 512, node1ID))
+
+	assert.Equal(float64(4), testutil.ToFloat64(throttler.metrics.throttledMsgs.WithLabelValues(message.Chits.String())))
+	assert.Equal(float64(516), testutil.ToFloat64(throttler.metrics.throttledBytes.WithLabelValues(message.Chits.String())))
+}
+
+func TestOutboundBandwidthThrottlerExempt(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		RefillRate:       1024,
+		MaxBurstSize:     2048,
+		PeerRefillRate:   512,
+		PeerMaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	throttler.clock.Set(time.Now())
+
+	nodeID := ids.GenerateTestShortID()
+
+	// An exempt message isn't limited by the budget of the peer
+	assert.True(throttler.AcquireExempt(message.Chits, false, 1536, nodeID))
+	assert.Empty(throttler.peers)
+
+	// But it is limited by the budget across all peers
+	assert.False(throttler.AcquireExempt(message.Chits, false, 1024, nodeID))
+	assert.True(throttler.AcquireExempt(message.Chits, false, 512, nodeID))
+	assert.False(throttler.Acquire(message.Chits, false, 1, nodeID))
+}
+
+func TestOutboundBandwidthThrottlerLowPriority(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		RefillRate:   1024,
+		MaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	throttler.clock.Set(time.Now())
+
+	nodeID := ids.GenerateTestShortID()
+
+	// Low priority messages can't take the reserved half of the bucket
+	assert.True(throttler.Acquire(message.PeerList, true, 256, nodeID))
+	assert.True(throttler.Acquire(message.PeerList, true, 256, nodeID))
+	assert.False(throttler.Acquire(message.PeerList, true, 1, nodeID))
+
+	// Other messages can
+	assert.True(throttler.Acquire(message.MultiPut, false, 512, nodeID))
+	assert.False(throttler.Acquire(message.MultiPut, false, 1, nodeID))
+
+	assert.Equal(float64(1), testutil.ToFloat64(throttler.metrics.throttledBytes.WithLabelValues(message.PeerList.String())))
+	assert.Equal(float64(1), testutil.ToFloat64(throttler.metrics.throttledBytes.WithLabelValues(message.MultiPut.String())))
+}
+
+func TestOutboundBandwidthThrottlerLargeMessage(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		RefillRate:   1024,
+		MaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+
+	// A message larger than the bucket can be sent when the bucket is full
+	assert.True(throttler.Acquire(message.MultiPut, false, 2048, nodeID))
+
+	// Then the bucket is in debt until it refills
+	now = now.Add(time.Second)
+	throttler.clock.Set(now)
+	assert.False(throttler.Acquire(message.Chits, false, 1, nodeID))
+	now = now.Add(time.Second)
+	throttler.clock.Set(now)
+	assert.True(throttler.Acquire(message.Chits, false, 1, nodeID))
+}
+
+func TestInboundBandwidthThrottler(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		RefillRate:       2048,
+		MaxBurstSize:     2048,
+		PeerRefillRate:   1024,
+		PeerMaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewInboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*inboundBandwidthThrottler)
+	throttler.clock.Set(time.Now())
+	var waits []time.Duration
+	throttler.sleep = func(wait time.Duration) { waits = append(waits, wait) }
+
+	node1ID := ids.GenerateTestShortID()
+	node2ID := ids.GenerateTestShortID()
+
+	// Messages that fit don't wait
+	throttler.Acquire(1024, node1ID, time.Minute)
+	assert.Empty(waits)
+
+	// Node 1 must wait for its bucket to refill
+	throttler.Acquire(512, node1ID, time.Minute)
+	assert.Equal([]time.Duration{time.Second / 2}, waits)
+
+	// Node 2 must wait for the bucket of all peers to refill
+	throttler.Acquire(1024, node2ID, time.Minute)
+	assert.Equal([]time.Duration{time.Second / 2, time.Second / 4}, waits)
+
+	assert.Equal(float64(1536), testutil.ToFloat64(throttler.metrics.throttledBytes))
+}
+
+func TestInboundBandwidthThrottlerMaxWait(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		PeerRefillRate:   1024,
+		PeerMaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewInboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*inboundBandwidthThrottler)
+	throttler.clock.Set(time.Now())
+	var waits []time.Duration
+	throttler.sleep = func(wait time.Duration) { waits = append(waits, wait) }
+
+	nodeID := ids.GenerateTestShortID()
+
+	// The message would need 4 seconds of refill but waits for at most 1
+	throttler.Acquire(5*1024, nodeID, time.Second)
+	assert.Equal([]time.Duration{time.Second}, waits)
+
+	// The bytes it didn't wait for are still owed
+	throttler.Acquire(1024, nodeID, time.Minute)
+	assert.Equal([]time.Duration{time.Second, 5 * time.Second}, waits)
+}
+
+func TestBandwidthThrottlerPrune(t *testing.T) {
+	assert := assert.New(t)
+	config := BandwidthThrottlerConfig{
+		PeerRefillRate:   1024,
+		PeerMaxBurstSize: 1024,
+	}
+	throttlerIntf, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), config)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundBandwidthThrottler)
+	now := time.Now()
+	throttler.clock.Set(now)
+
+	node1ID := ids.GenerateTestShortID()
+	node2ID := ids.GenerateTestShortID()
+	assert.True(throttler.Acquire(message.Chits, false, 1024, node1ID))
+	assert.Len(throttler.peers, 1)
+
+	// Node 1's bucket is full again, so it's dropped
+	throttler.clock.Set(now.Add(bandwidthPruneFrequency))
+	assert.True(throttler.Acquire(message.Chits, false, 1024, node2ID))
+	assert.Len(throttler.peers, 1)
+	assert.Contains(throttler.peers, node2ID)
+}
+
+func TestNoBandwidthThrottlers(t *testing.T) {
+	nodeID := ids.GenerateTestShortID()
+	NewNoInboundBandwidthThrottler().Acquire(1024, nodeID, time.Second)
+	assert.True(t, NewNoOutboundBandwidthThrottler().Acquire(message.Put, true, 1024, nodeID))
+}
+
+func TestBandwidthThrottlerConfigVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(BandwidthThrottlerConfig{}.Verify())
+	assert.NoError(BandwidthThrottlerConfig{
+		RefillRate:       1024,
+		MaxBurstSize:     2048,
+		PeerRefillRate:   512,
+		PeerMaxBurstSize: 1024,
+	}.Verify())
+	assert.ErrorIs(BandwidthThrottlerConfig{RefillRate: 1024}.Verify(), errNoBurst)
+	assert.ErrorIs(BandwidthThrottlerConfig{PeerRefillRate: 1024}.Verify(), errNoPeerBurst)
+
+	_, err := NewOutboundBandwidthThrottler("", prometheus.NewRegistry(), BandwidthThrottlerConfig{RefillRate: 1024})
+	assert.ErrorIs(err, errNoBurst)
+	_, err = NewInboundBandwidthThrottler("", prometheus.NewRegistry(), BandwidthThrottlerConfig{PeerRefillRate: 1024})
+	assert.ErrorIs(err, errNoPeerBurst)
+}
@@ -4,6 +4,8 @@
 package throttling
 
 import (
+	stdmath "math"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -26,6 +28,11 @@ type OutboundMsgThrottler interface {
 	// If this method returns false, do not make a corresponding call to Release.
 	Acquire(msgSize uint64, nodeID ids.ShortID) bool
 
+	// Like Acquire, but [nodeID] may take any of the at-large allocation
+	// rather than at most the bytes each node may take from it. The bytes
+	// must be released the same way.
+	AcquireExempt(msgSize uint64, nodeID ids.ShortID) bool
+
 	// Mark that a message of size [msgSize] has been sent to [nodeID] or we have
 	// given up sending the message. Must correspond to a previous call to
 	// Acquire([msgSize], [nodeID]) that returned true.
@@ -61,6 +68,17 @@ func NewSybilOutboundMsgThrottler(
 
 // See OutboundMsgThrottler
 func (t *outboundMsgThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) bool {
+	return t.acquire(msgSize, nodeID, t.nodeMaxAtLargeBytes)
+}
+
+// See OutboundMsgThrottler
+func (t *outboundMsgThrottler) AcquireExempt(msgSize uint64, nodeID ids.ShortID) bool {
+	return t.acquire(msgSize, nodeID, stdmath.MaxUint64)
+}
+
+// acquire takes [msgSize] bytes for [nodeID], which may use up to
+// [nodeMaxAtLargeBytes] of the at-large allocation
+func (t *outboundMsgThrottler) acquire(msgSize uint64, nodeID ids.ShortID, nodeMaxAtLargeBytes uint64) bool {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
@@ -70,7 +88,7 @@ func (t *outboundMsgThrottler) Acquire(msgSize uint64, nodeID ids.ShortID) bool
 		// only give as many bytes as needed
 		bytesNeeded,
 		// don't exceed per-node limit
-		t.nodeMaxAtLargeBytes-t.nodeToAtLargeBytesUsed[nodeID],
+		nodeMaxAtLargeBytes-t.nodeToAtLargeBytesUsed[nodeID],
 		// don't give more bytes than are in the allocation
 		t.remainingAtLargeBytes,
 	)
@@ -204,4 +222,6 @@ type noOutboundMsgThrottler struct{}
 
 func (*noOutboundMsgThrottler) Acquire(uint64, ids.ShortID) bool { return true }
 
+func (*noOutboundMsgThrottler) AcquireExempt(uint64, ids.ShortID) bool { return true }
+
 func (*noOutboundMsgThrottler) Release(uint64, ids.ShortID) {}
@@ -185,3 +185,37 @@ func TestSybilOutboundMsgThrottlerMaxNonVdr(t *testing.T) {
 	assert.EqualValues(config.NodeMaxAtLargeBytes, throttler.nodeToAtLargeBytesUsed[nonVdrNodeID2])
 	assert.EqualValues(config.AtLargeAllocSize-config.NodeMaxAtLargeBytes*3, throttler.remainingAtLargeBytes)
 }
+
+func TestSybilOutboundMsgThrottlerExempt(t *testing.T) {
+	assert := assert.New(t)
+	config := MsgThrottlerConfig{
+		VdrAllocSize:        100,
+		AtLargeAllocSize:    100,
+		NodeMaxAtLargeBytes: 10,
+	}
+	throttlerIntf, err := NewSybilOutboundMsgThrottler(
+		&logging.Log{},
+		"",
+		prometheus.NewRegistry(),
+		validators.NewSet(),
+		config,
+	)
+	assert.NoError(err)
+	throttler := throttlerIntf.(*outboundMsgThrottler)
+	nodeID := ids.GenerateTestShortID()
+
+	// An exempt node may take more than [NodeMaxAtLargeBytes]
+	assert.True(throttlerIntf.AcquireExempt(config.NodeMaxAtLargeBytes+1, nodeID))
+	assert.EqualValues(config.NodeMaxAtLargeBytes+1, throttler.nodeToAtLargeBytesUsed[nodeID])
+
+	// But not more than the at-large allocation
+	assert.False(throttlerIntf.AcquireExempt(config.AtLargeAllocSize, nodeID))
+	assert.True(throttlerIntf.AcquireExempt(config.AtLargeAllocSize-config.NodeMaxAtLargeBytes-1, nodeID))
+	assert.EqualValues(0, throttler.remainingAtLargeBytes)
+
+	// The bytes are released the same way
+	throttlerIntf.Release(config.NodeMaxAtLargeBytes+1, nodeID)
+	throttlerIntf.Release(config.AtLargeAllocSize-config.NodeMaxAtLargeBytes-1, nodeID)
+	assert.EqualValues(config.AtLargeAllocSize, throttler.remainingAtLargeBytes)
+	assert.Len(throttler.nodeToAtLargeBytesUsed, 0)
+}
@@ -7,17 +7,23 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/password"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 )
 
@@ -53,6 +59,21 @@ type Config struct {
 	// Name of the database type to use
 	DBName string
 
+	// If true and the database type is pebble, copy the existing leveldb
+	// databases into pebble before starting
+	DBCopyLevelDB bool
+
+	// If non-empty, export a snapshot of the database here and then end the
+	// node.
+	SnapshotExportDir string
+
+	// If non-empty, import the snapshot here into the empty database before
+	// starting the node.
+	SnapshotImportDir string
+
+	// If true, check the database for inconsistencies and then end the node.
+	VerifyDatabase bool
+
 	// Staking configuration
 	StakingIP             utils.DynamicIPDesc
 	EnableStaking         bool
@@ -68,14 +89,30 @@ type Config struct {
 	PeerListGossipSize uint32
 	PeerListGossipFreq time.Duration
 	CompressionEnabled bool
+	// Compression type to prefer with peers that support it
+	CompressionType compression.Type
+	// zstd dictionary to compress messages with, or nil if there isn't one
+	CompressionDictionary []byte
+	// If true, don't advertise our IP and only connect to static peers and
+	// bootstrap peers
+	PrivateMode bool
+	// IDs of the nodes in private mode we're a sentry for
+	RelayedNodeIDs ids.ShortSet
 
 	// Benchlist Configuration
 	BenchlistConfig benchlist.Config
 
+	// Peer Score Configuration
+	PeerScoreConfig score.Config
+
 	// Bootstrapping configuration
 	BootstrapIDs []ids.ShortID
 	BootstrapIPs []utils.IPDesc
 
+	// Peers to always stay connected to
+	StaticPeerIDs []ids.ShortID
+	StaticPeerIPs []utils.IPDesc
+
 	// HTTP configuration
 	HTTPHost string
 	HTTPPort uint16
@@ -86,6 +123,20 @@ type Config struct {
 	APIRequireAuthToken bool
 	APIAuthPassword     string
 	APIAllowedOrigins   []string
+	APIMaxBatchSize     int
+
+	// Client certificates that API clients may present over HTTPs
+	HTTPSClientCerts server.ClientCertConfig
+	// Permissions granted to API clients that present matching certificates
+	APIAuthClientCertPermissions []auth.ClientCertPermissions
+
+	// If true, calls to the API methods matching [APIAuditMethods] are
+	// recorded in the audit log
+	APIAuditEnabled bool
+	APIAuditMethods []string
+
+	// Rate limits of HTTP API requests
+	APIRateLimiterConfig server.RateLimiterConfig
 
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
@@ -95,6 +146,9 @@ type Config struct {
 	HealthAPIEnabled   bool
 	IndexAPIEnabled    bool
 
+	// Parameters that keystore keys and password hashes are derived with
+	KeystoreKDFParams password.KDFParams
+
 	// Profiling configurations
 	ProfilerConfig profiler.Config
 
@@ -104,6 +158,10 @@ type Config struct {
 	// Plugin directory
 	PluginDir string
 
+	// The cgroup that plugins with memory or CPU limits are moved into a
+	// cgroup under
+	PluginCgroupDir string
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
@@ -111,6 +169,7 @@ type Config struct {
 	IPCAPIEnabled      bool
 	IPCPath            string
 	IPCDefaultChainIDs []string
+	IPCTransport       ipcs.TransportConfig
 
 	// Metrics
 	MeterVMEnabled bool
@@ -151,6 +210,16 @@ type Config struct {
 	// containers in a multiput it receives.
 	BootstrapMultiputMaxContainersReceived int
 
+	// If true, a chain whose VM runs in a separate process is restarted when
+	// that process exits, at most [VMRestartMaxAttempts] times
+	VMRestartEnabled     bool
+	VMRestartMaxAttempts int
+
+	// Time to wait before the first restart of a chain's VM, and the max time
+	// to wait as the wait doubles after each restart
+	VMRestartBackoff    time.Duration
+	VMRestartMaxBackoff time.Duration
+
 	// Peer alias configuration
 	PeerAliasTimeout time.Duration
 
@@ -14,7 +14,10 @@ import (
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/api/audit"
 	"github.com/ava-labs/avalanchego/api/auth"
 	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/info"
@@ -36,6 +39,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -66,8 +70,15 @@ const (
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey     = []byte("genesisID")
+	peerScoresDBPrefix = []byte("peer scores")
+
+	// IndexerDBPrefix is the prefix of the indexer's database
+	IndexerDBPrefix = []byte{0x00}
+	// KeystoreDBPrefix is the prefix of the keystore's database
+	KeystoreDBPrefix = []byte("keystore")
+	// AuditDBPrefix is the prefix of the API auditor's database
+	AuditDBPrefix = []byte("audit")
 
 	errPrimarySubnetNotBootstrapped = errors.New("primary subnet has not finished bootstrapping")
 	errInvalidTLSKey                = errors.New("invalid TLS key")
@@ -111,6 +122,9 @@ type Node struct {
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
+	// Tracks the reputation of peers
+	peerScores score.Tracker
+
 	// dispatcher for events as they happen in consensus
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
@@ -129,6 +143,10 @@ type Node struct {
 	// Handles HTTP API calls
 	APIServer server.Server
 
+	// Registers the metrics of the node and serves them over the Metrics API
+	metricsRegistry *prometheus.Registry
+	metricsHandler  *common.HTTPHandler
+
 	// This node's configuration
 	Config *Config
 
@@ -186,7 +204,15 @@ func (n *Node) initNetworking() error {
 		return err
 	}
 
+	// Configure peer scores
+	peerScoresDB := prefixdb.New(peerScoresDBPrefix, n.DB)
+	n.peerScores, err = score.NewTracker(n.Config.PeerScoreConfig, n.Log, peerScoresDB)
+	if err != nil {
+		return fmt.Errorf("couldn't load peer scores: %w", err)
+	}
+
 	// Configure benchlist
+	n.Config.BenchlistConfig.Scores = n.peerScores
 	n.Config.BenchlistConfig.Validators = n.vdrs
 	n.Config.BenchlistConfig.Benchable = n.Config.ConsensusRouter
 	n.benchlistManager = benchlist.NewManager(&n.Config.BenchlistConfig)
@@ -254,6 +280,24 @@ func (n *Node) initNetworking() error {
 		return fmt.Errorf("initializing outbound message throttler failed with: %s", err)
 	}
 
+	inboundBandwidthThrottler, err := throttling.NewInboundBandwidthThrottler(
+		networkNamespace,
+		n.Config.NetworkConfig.MetricsRegisterer,
+		n.Config.NetworkConfig.InboundBandwidthConfig,
+	)
+	if err != nil {
+		return fmt.Errorf("initializing inbound bandwidth throttler failed with: %s", err)
+	}
+
+	outboundBandwidthThrottler, err := throttling.NewOutboundBandwidthThrottler(
+		networkNamespace,
+		n.Config.NetworkConfig.MetricsRegisterer,
+		n.Config.NetworkConfig.OutboundBandwidthConfig,
+	)
+	if err != nil {
+		return fmt.Errorf("initializing outbound bandwidth throttler failed with: %s", err)
+	}
+
 	n.Net, err = network.NewDefaultNetwork(
 		networkNamespace,
 		n.Config.ConsensusParams.Metrics,
@@ -282,8 +326,14 @@ func (n *Node) initNetworking() error {
 		n.Config.ConsensusGossipAcceptedFrontierSize,
 		n.Config.ConsensusGossipOnAcceptSize,
 		n.Config.CompressionEnabled,
+		n.Config.CompressionType,
+		n.Config.CompressionDictionary,
+		n.Config.PrivateMode,
+		n.Config.RelayedNodeIDs,
 		inboundMsgThrottler,
 		outboundMsgThrottler,
+		inboundBandwidthThrottler,
+		outboundBandwidthThrottler,
 	)
 	return err
 }
@@ -294,9 +344,9 @@ type insecureValidatorManager struct {
 	weight uint64
 }
 
-func (i *insecureValidatorManager) Connected(vdrID ids.ShortID) {
+func (i *insecureValidatorManager) Connected(vdrID ids.ShortID, nodeVersion version.Application) {
 	_ = i.vdrs.AddWeight(vdrID, i.weight)
-	i.Router.Connected(vdrID)
+	i.Router.Connected(vdrID, nodeVersion)
 }
 
 func (i *insecureValidatorManager) Disconnected(vdrID ids.ShortID) {
@@ -314,23 +364,23 @@ type beaconManager struct {
 	weight         uint64
 }
 
-func (b *beaconManager) Connected(vdrID ids.ShortID) {
+func (b *beaconManager) Connected(vdrID ids.ShortID, nodeVersion version.Application) {
 	weight, ok := b.beacons.GetWeight(vdrID)
 	if !ok {
-		b.Router.Connected(vdrID)
+		b.Router.Connected(vdrID, nodeVersion)
 		return
 	}
 	weight, err := math.Add64(weight, b.weight)
 	if err != nil {
 		b.timer.Cancel()
-		b.Router.Connected(vdrID)
+		b.Router.Connected(vdrID, nodeVersion)
 		return
 	}
 	b.weight = weight
 	if b.weight >= b.requiredWeight {
 		b.timer.Cancel()
 	}
-	b.Router.Connected(vdrID)
+	b.Router.Connected(vdrID, nodeVersion)
 }
 
 func (b *beaconManager) Disconnected(vdrID ids.ShortID) {
@@ -354,7 +404,7 @@ func (n *Node) Dispatch() error {
 		var err error
 		if n.Config.HTTPSEnabled {
 			n.Log.Debug("initializing API server with TLS")
-			err = n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
+			err = n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile, n.Config.HTTPSClientCerts)
 		} else {
 			n.Log.Debug("initializing API server without TLS")
 			err = n.APIServer.Dispatch()
@@ -377,6 +427,14 @@ func (n *Node) Dispatch() error {
 		}
 	}
 
+	// Add static peers to the peer network
+	for i, peerIP := range n.Config.StaticPeerIPs {
+		nodeID := n.Config.StaticPeerIDs[i]
+		if err := n.Net.AddStaticPeer(nodeID, peerIP); err != nil {
+			n.Log.Error("couldn't add static peer %s%s at %s: %s", constants.NodeIDPrefix, nodeID, peerIP, err)
+		}
+	}
+
 	// Start P2P connections
 	err := n.Net.Dispatch()
 
@@ -459,7 +517,7 @@ func (n *Node) initIPCs() error {
 	}
 
 	var err error
-	n.IPCs, err = ipcs.NewChainIPCs(n.Log, n.Config.IPCPath, n.Config.NetworkID, n.ConsensusDispatcher, n.DecisionDispatcher, chainIDs)
+	n.IPCs, err = ipcs.NewChainIPCs(n.Log, n.Config.IPCPath, n.Config.NetworkID, n.Config.IPCTransport, n.indexer, n.ConsensusDispatcher, n.DecisionDispatcher, chainIDs)
 	return err
 }
 
@@ -467,7 +525,7 @@ func (n *Node) initIPCs() error {
 // Should only be called after [n.DB], [n.DecisionDispatcher], [n.ConsensusDispatcher],
 // [n.Log], [n.APIServer], [n.chainManager] are initialized
 func (n *Node) initIndexer() error {
-	txIndexerDB := prefixdb.New(indexerDBPrefix, n.DB)
+	txIndexerDB := prefixdb.New(IndexerDBPrefix, n.DB)
 	var err error
 	n.indexer, err = indexer.NewIndexer(indexer.Config{
 		IndexingEnabled:      n.Config.IndexAPIEnabled,
@@ -504,25 +562,81 @@ func (n *Node) initChains(genesisBytes []byte) {
 	})
 }
 
+// initMetrics initializes the registry that the node's metrics are registered
+// with
+func (n *Node) initMetrics() {
+	n.metricsRegistry, n.metricsHandler = metrics.NewService()
+	// It is assumed by components of the system that the Metrics interface is
+	// non-nil. So, it is set regardless of if the metrics API is available or not.
+	n.Config.ConsensusParams.Metrics = n.metricsRegistry
+	n.Config.NetworkConfig.MetricsRegisterer = n.metricsRegistry
+}
+
 // initAPIServer initializes the server that handles HTTP calls
+// Assumes n.metricsRegistry is already set
 func (n *Node) initAPIServer() error {
 	n.Log.Info("initializing API server")
 
-	if !n.Config.APIRequireAuthToken {
-		n.APIServer.Initialize(
+	// Each wrapper wraps the ones passed before it. The limits of remote IPs
+	// are passed last so that they also apply to requests that don't
+	// authenticate. The auth wrapper is passed before them, and after the
+	// limits of authenticated clients, so that those limits know the client a
+	// request was authenticated as.
+	// The auditor is passed first so that it only records requests that were
+	// authorized and weren't rate limited.
+	var wrappers []server.Wrapper
+	var a auth.Auth
+	if n.Config.APIRequireAuthToken {
+		var err error
+		a, err = auth.New(n.Log, "auth", n.Config.APIAuthPassword)
+		if err != nil {
+			return err
+		}
+		if err := a.SetClientCertPermissions(n.Config.APIAuthClientCertPermissions); err != nil {
+			return fmt.Errorf("invalid client certificate permissions: %w", err)
+		}
+	}
+	if n.Config.APIAuditEnabled {
+		auditLog, err := n.LogFactory.Make("audit")
+		if err != nil {
+			return fmt.Errorf("couldn't create audit log: %w", err)
+		}
+		// Every entry is written to the audit log file, regardless of the
+		// configured log levels, but none are displayed.
+		auditLog.SetLogLevel(logging.Info)
+		auditLog.SetDisplayLevel(logging.Off)
+
+		var tokens audit.TokenIntrospector
+		if a != nil {
+			tokens = a
+		}
+		auditor, err := audit.New(auditLog, n.Config.APIAuditMethods, tokens, prefixdb.New(AuditDBPrefix, n.DB))
+		if err != nil {
+			return fmt.Errorf("couldn't create API auditor: %w", err)
+		}
+		wrappers = append(wrappers, auditor)
+		n.Log.Info("API auditing is enabled for methods %v", n.Config.APIAuditMethods)
+	}
+	var rateLimiter *server.RateLimiter
+	if rateLimiterConfig := n.Config.APIRateLimiterConfig; rateLimiterConfig.IPRate > 0 || rateLimiterConfig.TokenRate > 0 {
+		var err error
+		rateLimiter, err = server.NewRateLimiter(
+			rateLimiterConfig,
 			n.Log,
-			n.LogFactory,
-			n.Config.HTTPHost,
-			n.Config.HTTPPort,
-			n.Config.APIAllowedOrigins,
-			n.ID,
+			fmt.Sprintf("%s_api_rate_limiter", constants.PlatformName),
+			n.metricsRegistry,
 		)
-		return nil
+		if err != nil {
+			return err
+		}
+		wrappers = append(wrappers, rateLimiter.IdentityWrapper())
+		n.Log.Info("API rate limiting is enabled")
 	}
-
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
-	if err != nil {
-		return err
+	if a != nil {
+		wrappers = append(wrappers, a)
+	}
+	if rateLimiter != nil {
+		wrappers = append(wrappers, rateLimiter.IPWrapper())
 	}
 
 	n.APIServer.Initialize(
@@ -531,9 +645,13 @@ func (n *Node) initAPIServer() error {
 		n.Config.HTTPHost,
 		n.Config.HTTPPort,
 		n.Config.APIAllowedOrigins,
+		n.Config.APIMaxBatchSize,
 		n.ID,
-		a,
+		wrappers...,
 	)
+	if !n.Config.APIRequireAuthToken {
+		return nil
+	}
 
 	// only create auth service if token authorization is required
 	n.Log.Info("API authorization is enabled. Auth tokens must be passed in the header of API requests, except requests to the auth service.")
@@ -653,6 +771,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		EpochDuration:                          n.Config.EpochDuration,
 		Validators:                             n.vdrs,
 		NodeID:                                 n.ID,
+		StakingCert:                            n.Config.StakingTLSCert,
 		NetworkID:                              n.Config.NetworkID,
 		Server:                                 &n.APIServer,
 		Keystore:                               n.keystore,
@@ -667,10 +786,16 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		RetryBootstrapMaxAttempts:              n.Config.RetryBootstrapMaxAttempts,
 		ShutdownNodeFunc:                       n.Shutdown,
 		MeterVMEnabled:                         n.Config.MeterVMEnabled,
+		PeerScores:                             n.peerScores,
+		PeerScoreMinSamplingFactor:             n.Config.PeerScoreConfig.MinSamplingFactor,
 		ChainConfigs:                           n.Config.ChainConfigs,
 		BootstrapMaxTimeGetAncestors:           n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapMultiputMaxContainersSent:     n.Config.BootstrapMultiputMaxContainersSent,
 		BootstrapMultiputMaxContainersReceived: n.Config.BootstrapMultiputMaxContainersReceived,
+		VMRestartEnabled:                       n.Config.VMRestartEnabled,
+		VMRestartMaxAttempts:                   n.Config.VMRestartMaxAttempts,
+		VMRestartBackoff:                       n.Config.VMRestartBackoff,
+		VMRestartMaxBackoff:                    n.Config.VMRestartMaxBackoff,
 	})
 
 	vdrs := n.vdrs
@@ -748,7 +873,8 @@ func (n *Node) registerRPCVMs() error {
 		}
 
 		if err = n.vmManager.RegisterFactory(vmID, &rpcchainvm.Factory{
-			Path: filepath.Join(n.Config.PluginDir, file.Name()),
+			Path:      filepath.Join(n.Config.PluginDir, file.Name()),
+			CgroupDir: n.Config.PluginCgroupDir,
 		}); err != nil {
 			return err
 		}
@@ -768,8 +894,8 @@ func (n *Node) initSharedMemory() error {
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() error {
 	n.Log.Info("initializing keystore")
-	keystoreDB := n.DBManager.NewPrefixDBManager([]byte("keystore"))
-	ks, err := keystore.New(n.Log, keystoreDB)
+	keystoreDB := n.DBManager.NewPrefixDBManager(KeystoreDBPrefix)
+	ks, err := keystore.New(n.Log, keystoreDB, n.Config.KeystoreKDFParams)
 	if err != nil {
 		return err
 	}
@@ -791,14 +917,8 @@ func (n *Node) initKeystoreAPI() error {
 }
 
 // initMetricsAPI initializes the Metrics API
-// Assumes n.APIServer is already set
+// Assumes n.APIServer and n.metricsRegistry are already set
 func (n *Node) initMetricsAPI() error {
-	registry, handler := metrics.NewService()
-	// It is assumed by components of the system that the Metrics interface is
-	// non-nil. So, it is set regardless of if the metrics API is available or not.
-	n.Config.ConsensusParams.Metrics = registry
-	n.Config.NetworkConfig.MetricsRegisterer = registry
-
 	if !n.Config.MetricsAPIEnabled {
 		n.Log.Info("skipping metrics API initialization because it has been disabled")
 		return nil
@@ -807,24 +927,24 @@ func (n *Node) initMetricsAPI() error {
 	n.Log.Info("initializing metrics API")
 
 	dbNamespace := fmt.Sprintf("%s_db", constants.PlatformName)
-	meterDBManager, err := n.DBManager.NewMeterDBManager(dbNamespace, registry)
+	meterDBManager, err := n.DBManager.NewMeterDBManager(dbNamespace, n.metricsRegistry)
 	if err != nil {
 		return err
 	}
 	n.DBManager = meterDBManager
 
-	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
+	return n.APIServer.AddRoute(n.metricsHandler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
 }
 
 // initAdminAPI initializes the Admin API service
-// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+// Assumes n.log, n.chainManager, n.indexer, and n.ValidatorAPI already initialized
 func (n *Node) initAdminAPI() error {
 	if !n.Config.AdminAPIEnabled {
 		n.Log.Info("skipping admin API initialization because it has been disabled")
 		return nil
 	}
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer, n.Config.ProfilerConfig.Dir)
+	service, err := admin.NewService(n.Log, n.chainManager, n.indexer, &n.APIServer, n.Net, n.Config.ProfilerConfig.Dir)
 	if err != nil {
 		return err
 	}
@@ -867,6 +987,7 @@ func (n *Node) initInfoAPI() error {
 		n.chainManager,
 		n.vmManager,
 		n.Net,
+		n.peerScores,
 		n.Config.CreationTxFee,
 		n.Config.TxFee,
 	)
@@ -1030,6 +1151,8 @@ func (n *Node) Initialize(
 	if err = n.initBeacons(); err != nil { // Configure the beacons
 		return fmt.Errorf("problem initializing node beacons: %w", err)
 	}
+	n.initMetrics()
+
 	// Start HTTP APIs
 	if err := n.initAPIServer(); err != nil { // Start the API Server
 		return fmt.Errorf("couldn't initialize API server: %w", err)
@@ -1064,12 +1187,12 @@ func (n *Node) Initialize(
 	if err := n.initChainManager(n.Config.AvaxAssetID); err != nil { // Set up the chain manager
 		return fmt.Errorf("couldn't initialize chain manager: %w", err)
 	}
-	if err := n.initAdminAPI(); err != nil { // Start the Admin API
-		return fmt.Errorf("couldn't initialize admin API: %w", err)
-	}
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
+	if err := n.initIndexer(); err != nil {
+		return fmt.Errorf("couldn't initialize indexer: %w", err)
+	}
 	if err := n.initIPCs(); err != nil { // Start the IPCs
 		return fmt.Errorf("couldn't initialize IPCs: %w", err)
 	}
@@ -1082,8 +1205,8 @@ func (n *Node) Initialize(
 	if err := n.initAPIAliases(n.Config.GenesisBytes); err != nil {
 		return fmt.Errorf("couldn't initialize API aliases: %w", err)
 	}
-	if err := n.initIndexer(); err != nil {
-		return fmt.Errorf("couldn't initialize indexer: %w", err)
+	if err := n.initAdminAPI(); err != nil { // Start the Admin API
+		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
 
 	n.initProfiler()
@@ -1120,6 +1243,11 @@ func (n *Node) shutdown() {
 		// Close already logs its own error if one occurs, so the error is ignored here
 		_ = n.Net.Close()
 	}
+	if n.peerScores != nil {
+		if err := n.peerScores.Commit(); err != nil {
+			n.Log.Debug("error persisting peer scores: %s", err)
+		}
+	}
 	if err := n.APIServer.Shutdown(); err != nil {
 		n.Log.Debug("error during API shutdown: %s", err)
 	}
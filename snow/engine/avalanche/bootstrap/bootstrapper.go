@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 const (
@@ -496,12 +497,12 @@ func (b *Bootstrapper) finish() error {
 }
 
 // Connected implements the Engine interface.
-func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
-	err := b.VM.Connected(validatorID)
+func (b *Bootstrapper) Connected(validatorID ids.ShortID, nodeVersion version.Application) error {
+	err := b.VM.Connected(validatorID, nodeVersion)
 	if err != nil {
 		return err
 	}
-	return b.Bootstrapper.Connected(validatorID)
+	return b.Bootstrapper.Connected(validatorID, nodeVersion)
 }
 
 // Disconnected implements the Engine interface.
@@ -218,7 +218,10 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse vertex %s due to: %s", vtxID, err)
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
-		return t.GetFailed(vdr, requestID)
+		if err := t.GetFailed(vdr, requestID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: couldn't parse vertex %s: %s", common.ErrInvalidMessage, vtxID, err)
 	}
 	if _, err := t.issueFrom(vdr, vtx); err != nil {
 		return err
@@ -300,7 +303,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, vtxID ids.ID,
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse vertex %s due to: %s", vtxID, err)
 		t.Ctx.Log.Verbo("vertex:\n%s", formatting.DumpBytes{Bytes: vtxBytes})
-		return nil
+		return fmt.Errorf("%w: couldn't parse vertex %s: %s", common.ErrInvalidMessage, vtxID, err)
 	}
 
 	if _, err := t.issueFrom(vdr, vtx); err != nil {
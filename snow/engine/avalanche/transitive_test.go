@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 var (
@@ -137,8 +138,8 @@ func TestEngineAdd(t *testing.T) {
 
 	manager.ParseVtxF = func(b []byte) (avalanche.Vertex, error) { return nil, errFailedParsing }
 
-	if err := te.Put(vdr, *reqID, vtx.ParentsV[0].ID(), nil); err != nil {
-		t.Fatal(err)
+	if err := te.Put(vdr, *reqID, vtx.ParentsV[0].ID(), nil); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	manager.ParseVtxF = nil
@@ -2485,7 +2486,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	if err := te.Initialize(config); err != nil {
 		t.Fatal(err)
 	}
-	if err := te.Connected(vdr); err != nil {
+	if err := te.Connected(vdr, version.CurrentApp); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2890,7 +2891,7 @@ func TestEngineReBootstrappingIntoConsensus(t *testing.T) {
 	if err := te.Initialize(config); err != nil {
 		t.Fatal(err)
 	}
-	if err := te.Connected(vdr); err != nil {
+	if err := te.Connected(vdr, version.CurrentApp); err != nil {
 		t.Fatal(err)
 	}
 
@@ -3391,8 +3392,8 @@ func TestEngineInvalidVertexIgnoredFromUnexpectedPeer(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := te.Put(secondVdr, *reqID, vtx0.ID(), []byte{3}); err != nil {
-		t.Fatal(err)
+	if err := te.Put(secondVdr, *reqID, vtx0.ID(), []byte{3}); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	*parsed = false
@@ -3537,8 +3538,8 @@ func TestEnginePushQueryRequestIDConflict(t *testing.T) {
 	sender.GetF = nil
 	sender.CantGet = false
 
-	if err := te.PushQuery(vdr, *reqID, randomVtxID, []byte{3}); err != nil {
-		t.Fatal(err)
+	if err := te.PushQuery(vdr, *reqID, randomVtxID, []byte{3}); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	*parsed = false
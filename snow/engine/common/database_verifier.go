@@ -0,0 +1,15 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// DatabaseVerifier can be optionally implemented by a VM to allow the node to
+// check the VM's persisted state for inconsistencies.
+type DatabaseVerifier interface {
+	// VerifyDatabase returns a description of each inconsistency found in the
+	// VM's database. An error is only returned if the database couldn't be
+	// read. VerifyDatabase must not modify the database.
+	//
+	// Assumes the context lock is held.
+	VerifyDatabase() ([]string, error)
+}
@@ -4,11 +4,19 @@
 package common
 
 import (
+	"errors"
+
 	"github.com/ava-labs/avalanchego/health"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/version"
 )
 
+// ErrInvalidMessage is wrapped by the errors that an engine returns when it's
+// passed a message that is invalid, such as a container that doesn't parse.
+// Unlike other errors, these aren't fatal. They are counted against the sender.
+var ErrInvalidMessage = errors.New("invalid message")
+
 // Engine describes the standard interface of a consensus engine
 type Engine interface {
 	Handler
@@ -305,8 +313,8 @@ type InternalHandler interface {
 	// Notify this engine of a message from the virtual machine.
 	Notify(Message) error
 
-	// Notify this engine of a new peer.
-	Connected(validatorID ids.ShortID) error
+	// Notify this engine of a new peer running [nodeVersion].
+	Connected(validatorID ids.ShortID, nodeVersion version.Application) error
 
 	// Notify this engine of a removed peer.
 	Disconnected(validatorID ids.ShortID) error
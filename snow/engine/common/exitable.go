@@ -0,0 +1,12 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// Exitable can be optionally implemented by a VM that runs in a separate
+// process to allow the node to notice when that process exits.
+type Exitable interface {
+	// Exited returns a channel that is closed when the VM's process exits
+	// without the VM having been shut down.
+	Exited() <-chan struct{}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 // EngineTest is a test engine
@@ -64,10 +65,11 @@ type EngineTest struct {
 	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF func(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID) error
 	GetAcceptedFrontierF, GetFailedF, GetAncestorsFailedF,
 	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF func(validatorID ids.ShortID, requestID uint32) error
-	ConnectedF, DisconnectedF func(validatorID ids.ShortID) error
-	HealthF                   func() (interface{}, error)
-	GetVtxF                   func() (avalanche.Vertex, error)
-	GetVMF                    func() VM
+	ConnectedF    func(validatorID ids.ShortID, nodeVersion version.Application) error
+	DisconnectedF func(validatorID ids.ShortID) error
+	HealthF       func() (interface{}, error)
+	GetVtxF       func() (avalanche.Vertex, error)
+	GetVMF        func() VM
 }
 
 var _ Engine = &EngineTest{}
@@ -391,9 +393,9 @@ func (e *EngineTest) Chits(validatorID ids.ShortID, requestID uint32, containerI
 	return errors.New("unexpectedly called Chits")
 }
 
-func (e *EngineTest) Connected(validatorID ids.ShortID) error {
+func (e *EngineTest) Connected(validatorID ids.ShortID, nodeVersion version.Application) error {
 	if e.ConnectedF != nil {
-		return e.ConnectedF(validatorID)
+		return e.ConnectedF(validatorID, nodeVersion)
 	}
 	if !e.CantConnected {
 		return nil
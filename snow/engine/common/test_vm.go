@@ -11,6 +11,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 var (
@@ -40,7 +41,7 @@ type TestVM struct {
 	BootstrappingF, BootstrappedF, ShutdownF func() error
 	CreateHandlersF                          func() (map[string]*HTTPHandler, error)
 	CreateStaticHandlersF                    func() (map[string]*HTTPHandler, error)
-	ConnectedF                               func(ids.ShortID) error
+	ConnectedF                               func(ids.ShortID, version.Application) error
 	DisconnectedF                            func(ids.ShortID) error
 	HealthCheckF                             func() (interface{}, error)
 	VersionF                                 func() (string, error)
@@ -135,9 +136,9 @@ func (vm *TestVM) HealthCheck() (interface{}, error) {
 	return nil, errHealthCheck
 }
 
-func (vm *TestVM) Connected(id ids.ShortID) error {
+func (vm *TestVM) Connected(id ids.ShortID, nodeVersion version.Application) error {
 	if vm.ConnectedF != nil {
-		return vm.ConnectedF(id)
+		return vm.ConnectedF(id, nodeVersion)
 	}
 	if vm.CantConnected && vm.T != nil {
 		vm.T.Fatal(errConnected)
@@ -0,0 +1,33 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+)
+
+// TimedBlock is a block that knows the time it was produced at.
+//
+// Blocks of a chain that hasn't forked yet must implement TimedBlock for the
+// proposervm to stop accepting blocks without proposers as soon as a block
+// after the fork time is accepted.
+type TimedBlock interface {
+	snowman.Block
+
+	// Timestamp returns the time this block was produced at. The zero time is
+	// returned if it isn't known, such as when the block wasn't verified yet.
+	Timestamp() time.Time
+}
+
+// Timestamp returns the time [blk] was produced at, or the zero time if [blk]
+// doesn't know it.
+func Timestamp(blk snowman.Block) time.Time {
+	timedBlk, ok := blk.(TimedBlock)
+	if !ok {
+		return time.Time{}
+	}
+	return timedBlk.Timestamp()
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 // Parameters for delaying bootstrapping to avoid potential CPU burns
@@ -400,13 +401,13 @@ func (b *Bootstrapper) finish() error {
 }
 
 // Connected implements the Engine interface.
-func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
+func (b *Bootstrapper) Connected(validatorID ids.ShortID, nodeVersion version.Application) error {
 	if connector, ok := b.VM.(validators.Connector); ok {
-		if err := connector.Connected(validatorID); err != nil {
+		if err := connector.Connected(validatorID, nodeVersion); err != nil {
 			return err
 		}
 	}
-	return b.Bootstrapper.Connected(validatorID)
+	return b.Bootstrapper.Connected(validatorID, nodeVersion)
 }
 
 // Disconnected implements the Engine interface.
@@ -13,6 +13,8 @@ import (
 	snow "github.com/ava-labs/avalanchego/snow"
 
 	snowman "github.com/ava-labs/avalanchego/snow/engine/snowman"
+
+	version "github.com/ava-labs/avalanchego/version"
 )
 
 // Engine is an autogenerated mock type for the Engine type
@@ -62,13 +64,13 @@ func (_m *Engine) Chits(validatorID ids.ShortID, requestID uint32, containerIDs
 	return r0
 }
 
-// Connected provides a mock function with given fields: validatorID
-func (_m *Engine) Connected(validatorID ids.ShortID) error {
-	ret := _m.Called(validatorID)
+// Connected provides a mock function with given fields: validatorID, nodeVersion
+func (_m *Engine) Connected(validatorID ids.ShortID, nodeVersion version.Application) error {
+	ret := _m.Called(validatorID, nodeVersion)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(ids.ShortID) error); ok {
-		r0 = rf(validatorID)
+	if rf, ok := ret.Get(0).(func(ids.ShortID, version.Application) error); ok {
+		r0 = rf(validatorID, nodeVersion)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,9 +4,16 @@
 package snowman
 
 import (
+	"errors"
+
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 )
 
+// ErrNotOracle is returned by Options when a block that implements OracleBlock
+// doesn't have any options. This allows wrappers of blocks to implement
+// OracleBlock without requiring the wrapped block to be an oracle.
+var ErrNotOracle = errors.New("block isn't an oracle")
+
 // OracleBlock is a block that only has two valid children. The children should
 // be returned in preferential order.
 //
@@ -16,6 +23,7 @@ type OracleBlock interface {
 	snowman.Block
 
 	// Options returns the possible children of this block in the order this
-	// validator prefers the blocks.
+	// validator prefers the blocks. If this block doesn't have any options,
+	// ErrNotOracle is returned.
 	Options() ([2]snowman.Block, error)
 }
@@ -105,19 +105,27 @@ func (t *Transitive) finishBootstrapping() error {
 
 	// to maintain the invariant that oracle blocks are issued in the correct
 	// preferences, we need to handle the case that we are bootstrapping into an oracle block
-	switch blk := lastAccepted.(type) {
-	case OracleBlock:
-		options, err := blk.Options()
-		if err != nil {
+	options := [2]snowman.Block{}
+	isOracle := false
+	if blk, ok := lastAccepted.(OracleBlock); ok {
+		options, err = blk.Options()
+		switch err {
+		case nil:
+			isOracle = true
+		case ErrNotOracle:
+		default:
 			return err
 		}
+	}
+
+	if isOracle {
 		for _, blk := range options {
 			// note that deliver will set the VM's preference
 			if err := t.deliver(blk); err != nil {
 				return err
 			}
 		}
-	default:
+	} else {
 		// if there aren't blocks we need to deliver on startup, we need to set
 		// the preference to the last accepted block
 		if err := t.VM.SetPreference(lastAcceptedID); err != nil {
@@ -222,7 +230,10 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		// because GetFailed doesn't utilize the assumption that we actually
 		// sent a Get message, we can safely call GetFailed here to potentially
 		// abandon the request.
-		return t.GetFailed(vdr, requestID)
+		if err := t.GetFailed(vdr, requestID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: couldn't parse block %s: %s", common.ErrInvalidMessage, blkID, err)
 	}
 
 	// issue the block into consensus. If the block has already been issued,
@@ -303,7 +314,7 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID,
 	if err != nil {
 		t.Ctx.Log.Debug("failed to parse block %s: %s", blkID, err)
 		t.Ctx.Log.Verbo("block:\n%s", formatting.DumpBytes{Bytes: blkBytes})
-		return nil
+		return fmt.Errorf("%w: couldn't parse block %s: %s", common.ErrInvalidMessage, blkID, err)
 	}
 
 	// issue the block into consensus. If the block has already been issued,
@@ -669,18 +680,20 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	dropped := []snowman.Block{}
 	if blk, ok := blk.(OracleBlock); ok {
 		options, err := blk.Options()
-		if err != nil {
-			return err
-		}
-		for _, blk := range options {
-			if err := blk.Verify(); err != nil {
-				t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
-				dropped = append(dropped, blk)
-			} else {
-				if err := t.Consensus.Add(blk); err != nil {
-					return err
+		if err != ErrNotOracle {
+			if err != nil {
+				return err
+			}
+			for _, blk := range options {
+				if err := blk.Verify(); err != nil {
+					t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
+					dropped = append(dropped, blk)
+				} else {
+					if err := t.Consensus.Add(blk); err != nil {
+						return err
+					}
+					added = append(added, blk)
 				}
-				added = append(added, blk)
 			}
 		}
 	}
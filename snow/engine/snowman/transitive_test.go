@@ -157,8 +157,8 @@ func TestEngineAdd(t *testing.T) {
 
 	vm.ParseBlockF = func(b []byte) (snowman.Block, error) { return nil, errUnknownBytes }
 
-	if err := te.Put(vdr, *reqID, blk.Parent().ID(), nil); err != nil {
-		t.Fatal(err)
+	if err := te.Put(vdr, *reqID, blk.Parent().ID(), nil); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	vm.ParseBlockF = nil
@@ -1493,8 +1493,8 @@ func TestEngineInvalidBlockIgnoredFromUnexpectedPeer(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := te.Put(secondVdr, *reqID, missingBlk.ID(), []byte{3}); err != nil {
-		t.Fatal(err)
+	if err := te.Put(secondVdr, *reqID, missingBlk.ID(), []byte{3}); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	*parsed = false
@@ -1594,8 +1594,8 @@ func TestEnginePushQueryRequestIDConflict(t *testing.T) {
 	sender.GetF = nil
 	sender.CantGet = false
 
-	if err := te.PushQuery(vdr, *reqID, randomBlkID, []byte{3}); err != nil {
-		t.Fatal(err)
+	if err := te.PushQuery(vdr, *reqID, randomBlkID, []byte{3}); !errors.Is(err, common.ErrInvalidMessage) {
+		t.Fatalf("expected %s but got %s", common.ErrInvalidMessage, err)
 	}
 
 	*parsed = false
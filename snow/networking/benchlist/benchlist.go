@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -121,6 +122,11 @@ type benchlist struct {
 	// The maximum percentage of total network stake that may be benched
 	// Must be in [0,1)
 	maxPortion float64
+
+	// A benched validator whose score is below [minScore] is benched for the
+	// full [duration]
+	scores   score.Tracker
+	minScore float64
 }
 
 // NewBenchlist returns a new Benchlist
@@ -133,6 +139,8 @@ func NewBenchlist(
 	minimumFailingDuration,
 	duration time.Duration,
 	maxPortion float64,
+	scores score.Tracker,
+	minScore float64,
 	namespace string,
 	registerer prometheus.Registerer,
 ) (Benchlist, error) {
@@ -150,6 +158,8 @@ func NewBenchlist(
 		minimumFailingDuration: minimumFailingDuration,
 		duration:               duration,
 		maxPortion:             maxPortion,
+		scores:                 scores,
+		minScore:               minScore,
 	}
 	benchlist.timer = timer.NewTimer(benchlist.update)
 	go benchlist.timer.Dispatch()
@@ -271,14 +281,23 @@ func (b *benchlist) RegisterFailure(validatorID ids.ShortID) {
 	b.failureStreaks[validatorID] = failureStreak
 	b.streaklock.Unlock()
 
-	if failureStreak.consecutive >= b.threshold && now.After(failureStreak.firstFailure.Add(b.minimumFailingDuration)) {
-		b.bench(validatorID)
+	if failureStreak.consecutive < b.threshold || !now.After(failureStreak.firstFailure.Add(b.minimumFailingDuration)) {
+		return
 	}
+
+	validatorScore := b.scores.Score(validatorID)
+	lowScore := validatorScore < b.minScore
+	if lowScore {
+		b.log.Debug("validator %s has a score of %f, which is below the minimum of %f", validatorID, validatorScore, b.minScore)
+	}
+	b.bench(validatorID, lowScore)
 }
 
 // Assumes [b.lock] is held
 // Assumes [validatorID] is not already benched
-func (b *benchlist) bench(validatorID ids.ShortID) {
+// If [fullDuration], [validatorID] is benched for [b.duration]. Otherwise, it's
+// benched for a random duration in [b.duration/2, b.duration].
+func (b *benchlist) bench(validatorID ids.ShortID, fullDuration bool) {
 	benchedStake, err := b.vdrs.SubsetWeight(b.benchlistSet)
 	if err != nil {
 		// This should never happen
@@ -320,6 +339,9 @@ func (b *benchlist) bench(validatorID ids.ShortID) {
 	maxBenchedUntil := now.Add(b.duration)
 	diff := maxBenchedUntil.Sub(minBenchedUntil)
 	benchedUntil := minBenchedUntil.Add(time.Duration(rand.Float64() * float64(diff))) // #nosec G404
+	if fullDuration {
+		benchedUntil = maxBenchedUntil
+	}
 
 	// Add to benchlist times with randomized delay
 	b.benchlistSet.Add(validatorID)
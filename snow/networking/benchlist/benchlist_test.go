@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
@@ -52,6 +53,8 @@ func TestBenchlistAdd(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		score.NewNoTracker(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -196,6 +199,8 @@ func TestBenchlistMaxStake(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		score.NewNoTracker(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -337,6 +342,8 @@ func TestBenchlistRemove(t *testing.T) {
 		minimumFailingDuration,
 		duration,
 		maxPortion,
+		score.NewNoTracker(),
+		0,
 		"",
 		prometheus.NewRegistry(),
 	)
@@ -419,3 +426,78 @@ func TestBenchlistRemove(t *testing.T) {
 
 	assert.Equal(t, 3, count)
 }
+
+type testScores struct {
+	score.Tracker
+	score float64
+}
+
+func (s testScores) Score(ids.ShortID) float64 { return s.score }
+
+// Test that a validator with a low score is benched for the full duration, but
+// only after it fails as many queries as any other validator
+func TestBenchlistLowScore(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(50)
+	vdr1 := validators.GenerateRandomValidator(50)
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	benchable := &TestBenchable{T: t}
+	benchable.CantUnbenched = true
+
+	threshold := 3
+	duration := time.Minute
+	maxPortion := 0.5
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		vdrs,
+		threshold,
+		minimumFailingDuration,
+		duration,
+		maxPortion,
+		testScores{Tracker: score.NewNoTracker()},
+		0.5,
+		"",
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+	now := time.Now()
+	b.clock.Set(now)
+
+	// A single failure doesn't bench a validator with a low score
+	b.RegisterFailure(vdr0.ID())
+	b.lock.Lock()
+	assert.False(t, b.isBenched(vdr0.ID()))
+	b.lock.Unlock()
+
+	// Neither do [threshold] failures before [minimumFailingDuration] passes
+	for i := 1; i < threshold; i++ {
+		b.RegisterFailure(vdr0.ID())
+	}
+	b.lock.Lock()
+	assert.False(t, b.isBenched(vdr0.ID()))
+	now = now.Add(minimumFailingDuration).Add(time.Second)
+	b.clock.Set(now)
+	b.lock.Unlock()
+
+	b.RegisterFailure(vdr0.ID())
+
+	b.lock.Lock()
+	assert.True(t, b.isBenched(vdr0.ID()))
+	assert.Equal(t, 1, b.benchedQueue.Len())
+	assert.Equal(t, now.Add(duration), b.benchedQueue[0].benchedUntil)
+	b.lock.Unlock()
+}
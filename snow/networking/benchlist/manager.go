@@ -7,6 +7,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/validators"
 )
 
@@ -18,11 +19,17 @@ var errUnknownValidators = errors.New("unknown validator set for provided chain"
 // the full network timeout for their responses.
 type Manager interface {
 	// RegisterResponse registers that we receive a request response from [validatorID]
-	// regarding [chainID] within the timeout
-	RegisterResponse(chainID ids.ID, validatorID ids.ShortID)
+	// regarding [chainID] within the timeout after [latency]
+	RegisterResponse(chainID ids.ID, validatorID ids.ShortID, latency time.Duration)
 	// RegisterFailure registers that a request to [validatorID] regarding
 	// [chainID] timed out
 	RegisterFailure(chainID ids.ID, validatorID ids.ShortID)
+	// RegisterInvalidMessage registers that [validatorID] sent a message that
+	// couldn't be parsed
+	RegisterInvalidMessage(validatorID ids.ShortID)
+	// Score returns the reputation score of [validatorID] in [0, 1]. A higher
+	// score is better.
+	Score(validatorID ids.ShortID) float64
 	// RegisterChain registers a new chain with metrics under [namespace]
 	RegisterChain(ctx *snow.Context, namespace string) error
 	// IsBenched returns true if messages to [validatorID] regarding chain [chainID]
@@ -44,6 +51,10 @@ type Config struct {
 	Duration               time.Duration
 	MaxPortion             float64
 	PeerSummaryEnabled     bool
+	// Scores is informed of every response and failure. A benched validator
+	// whose score is below [MinScore] is benched for the full [Duration].
+	Scores   score.Tracker
+	MinScore float64
 }
 
 type manager struct {
@@ -62,6 +73,9 @@ func NewManager(config *Config) Manager {
 	if config.MaxPortion <= 0 {
 		return NewNoBenchlist()
 	}
+	if config.Scores == nil {
+		config.Scores = score.NewNoTracker()
+	}
 	return &manager{
 		config:          config,
 		chainBenchlists: make(map[ids.ID]Benchlist),
@@ -121,6 +135,8 @@ func (m *manager) RegisterChain(ctx *snow.Context, namespace string) error {
 		m.config.MinimumFailingDuration,
 		m.config.Duration,
 		m.config.MaxPortion,
+		m.config.Scores,
+		m.config.MinScore,
 		namespace,
 		ctx.Metrics,
 	)
@@ -133,7 +149,9 @@ func (m *manager) RegisterChain(ctx *snow.Context, namespace string) error {
 }
 
 // RegisterResponse implements the Manager interface
-func (m *manager) RegisterResponse(chainID ids.ID, validatorID ids.ShortID) {
+func (m *manager) RegisterResponse(chainID ids.ID, validatorID ids.ShortID, latency time.Duration) {
+	m.config.Scores.RegisterResponse(validatorID, latency)
+
 	m.lock.RLock()
 	benchlist, exists := m.chainBenchlists[chainID]
 	m.lock.RUnlock()
@@ -146,6 +164,8 @@ func (m *manager) RegisterResponse(chainID ids.ID, validatorID ids.ShortID) {
 
 // RegisterFailure implements the Manager interface
 func (m *manager) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
+	m.config.Scores.RegisterFailure(validatorID)
+
 	m.lock.RLock()
 	benchlist, exists := m.chainBenchlists[chainID]
 	m.lock.RUnlock()
@@ -156,13 +176,25 @@ func (m *manager) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
 	benchlist.RegisterFailure(validatorID)
 }
 
+// RegisterInvalidMessage implements the Manager interface
+func (m *manager) RegisterInvalidMessage(validatorID ids.ShortID) {
+	m.config.Scores.RegisterInvalidMessage(validatorID)
+}
+
+// Score implements the Manager interface
+func (m *manager) Score(validatorID ids.ShortID) float64 {
+	return m.config.Scores.Score(validatorID)
+}
+
 type noBenchlist struct{}
 
 // NewNoBenchlist returns an empty benchlist that will never stop any queries
 func NewNoBenchlist() Manager { return &noBenchlist{} }
 
-func (noBenchlist) RegisterChain(*snow.Context, string) error { return nil }
-func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)      {}
-func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)       {}
-func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool        { return false }
-func (noBenchlist) GetBenched(ids.ShortID) []ids.ID           { return nil }
+func (noBenchlist) RegisterChain(*snow.Context, string) error           { return nil }
+func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID, time.Duration) {}
+func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)                 {}
+func (noBenchlist) RegisterInvalidMessage(ids.ShortID)                  {}
+func (noBenchlist) Score(ids.ShortID) float64                           { return 1 }
+func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool                  { return false }
+func (noBenchlist) GetBenched(ids.ShortID) []ids.ID                     { return nil }
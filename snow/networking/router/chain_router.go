@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 const (
@@ -57,7 +58,7 @@ type ChainRouter struct {
 	gossiper         *timer.Repeater
 	intervalNotifier *timer.Repeater
 	closeTimeout     time.Duration
-	peers            ids.ShortSet
+	peers            map[ids.ShortID]version.Application
 	// node ID --> chains that node is benched on
 	// invariant: if a node is benched on any chain, it is treated as disconnected on all chains
 	benched        map[ids.ShortID]ids.Set
@@ -106,7 +107,7 @@ func (cr *ChainRouter) Initialize(
 	cr.criticalChains = criticalChains
 	cr.onFatal = onFatal
 	cr.timedRequests = linkedhashmap.New()
-	cr.peers.Add(nodeID)
+	cr.peers = map[ids.ShortID]version.Application{nodeID: version.CurrentApp}
 	cr.healthConfig = healthConfig
 	cr.requestIDBytes = make([]byte, 2*hashing.HashLen+wrappers.IntLen) // Validator ID, Chain ID, Request ID
 
@@ -216,10 +217,10 @@ func (cr *ChainRouter) AddChain(chain *Handler) {
 	chain.onCloseF = func() { cr.removeChain(chainID) }
 	cr.chains[chainID] = chain
 
-	for validatorID := range cr.peers {
+	for validatorID, nodeVersion := range cr.peers {
 		// If this validator is benched on any chain, treat them as disconnected on all chains
 		if _, benched := cr.benched[validatorID]; !benched {
-			chain.Connected(validatorID)
+			chain.Connected(validatorID, nodeVersion)
 		}
 	}
 }
@@ -789,18 +790,18 @@ func (cr *ChainRouter) QueryFailed(
 }
 
 // Connected routes an incoming notification that a validator was just connected
-func (cr *ChainRouter) Connected(validatorID ids.ShortID) {
+func (cr *ChainRouter) Connected(validatorID ids.ShortID, nodeVersion version.Application) {
 	cr.lock.Lock()
 	defer cr.lock.Unlock()
 
-	cr.peers.Add(validatorID)
+	cr.peers[validatorID] = nodeVersion
 	// If this validator is benched on any chain, treat them as disconnected on all chains
 	if _, benched := cr.benched[validatorID]; benched {
 		return
 	}
 
 	for _, chain := range cr.chains {
-		chain.Connected(validatorID)
+		chain.Connected(validatorID, nodeVersion)
 	}
 }
 
@@ -809,7 +810,7 @@ func (cr *ChainRouter) Disconnected(validatorID ids.ShortID) {
 	cr.lock.Lock()
 	defer cr.lock.Unlock()
 
-	cr.peers.Remove(validatorID)
+	delete(cr.peers, validatorID)
 	if _, benched := cr.benched[validatorID]; benched {
 		return
 	}
@@ -827,7 +828,7 @@ func (cr *ChainRouter) Benched(chainID ids.ID, validatorID ids.ShortID) {
 	benchedChains, exists := cr.benched[validatorID]
 	benchedChains.Add(chainID)
 	cr.benched[validatorID] = benchedChains
-	if exists || !cr.peers.Contains(validatorID) {
+	if _, connected := cr.peers[validatorID]; exists || !connected {
 		// If the set already existed, then the node was previously benched.
 		return
 	}
@@ -851,12 +852,13 @@ func (cr *ChainRouter) Unbenched(chainID ids.ID, validatorID ids.ShortID) {
 		return // This node is still benched
 	}
 
-	if !cr.peers.Contains(validatorID) {
+	nodeVersion, connected := cr.peers[validatorID]
+	if !connected {
 		return
 	}
 
 	for _, chain := range cr.chains {
-		chain.Connected(validatorID)
+		chain.Connected(validatorID, nodeVersion)
 	}
 }
 
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -61,6 +62,7 @@ func TestShutdown(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -136,6 +138,7 @@ func TestShutdownTimesOut(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -237,6 +240,7 @@ func TestRouterTimeout(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -306,6 +310,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -4,6 +4,7 @@
 package router
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -13,12 +14,14 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/uptime"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 // Handler passes incoming messages from the network to the consensus engine.
@@ -30,8 +33,12 @@ type Handler struct {
 	metrics handlerMetrics
 	// The validator set that validates this chain
 	validators validators.Set
-	// The consensus engine
-	engine common.Engine
+	// Informed of the nodes that send messages the engine finds invalid
+	scores score.Tracker
+	// The consensus engine. It's replaced with both [ctx.Lock] and
+	// [engineLock] held, so holding either is enough to read it.
+	engine     common.Engine
+	engineLock sync.RWMutex
 	// Closed when this handler and [engine] are done shutting down
 	closed chan struct{}
 	// Receives messages from the VM
@@ -45,7 +52,10 @@ type Handler struct {
 	// Holds messages that [engine] hasn't processed yet.
 	// [unprocessedMsgsCond.L] must be held while accessing [unprocessedMsgs].
 	unprocessedMsgs unprocessedMsgs
-	closing         utils.AtomicBool
+	// True while messages are held back from [engine]. [unprocessedMsgsCond.L]
+	// must be held while accessing [paused].
+	paused  bool
+	closing utils.AtomicBool
 }
 
 // Initialize this consensus handler
@@ -53,6 +63,7 @@ type Handler struct {
 func (h *Handler) Initialize(
 	engine common.Engine,
 	validators validators.Set,
+	scores score.Tracker,
 	msgFromVMChan <-chan common.Message,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
@@ -65,6 +76,7 @@ func (h *Handler) Initialize(
 	h.msgFromVMChan = msgFromVMChan
 	h.engine = engine
 	h.validators = validators
+	h.scores = scores
 	var lock sync.Mutex
 	h.unprocessedMsgsCond = sync.NewCond(&lock)
 	h.cpuTracker = tracker.NewCPUTracker(uptime.IntervalFactory{}, defaultCPUInterval)
@@ -74,13 +86,48 @@ func (h *Handler) Initialize(
 }
 
 // Context of this Handler
-func (h *Handler) Context() *snow.Context { return h.engine.Context() }
+func (h *Handler) Context() *snow.Context { return h.Engine().Context() }
+
+// CPUTracker returns the tracker of the CPU time this handler spends on the
+// messages of each node
+func (h *Handler) CPUTracker() tracker.TimeTracker { return h.cpuTracker }
 
 // Engine returns the engine this handler dispatches to
-func (h *Handler) Engine() common.Engine { return h.engine }
+func (h *Handler) Engine() common.Engine {
+	h.engineLock.RLock()
+	defer h.engineLock.RUnlock()
+
+	return h.engine
+}
+
+// SetEngine sets the engine for this handler to dispatch to.
+// Assumes [h.ctx.Lock] is locked.
+func (h *Handler) SetEngine(engine common.Engine) {
+	h.engineLock.Lock()
+	defer h.engineLock.Unlock()
+
+	h.engine = engine
+}
 
-// SetEngine sets the engine for this handler to dispatch to
-func (h *Handler) SetEngine(engine common.Engine) { h.engine = engine }
+// Pause stops passing messages to the engine until Resume is called. Messages
+// that arrive in the meantime are queued. A message that is being handled when
+// Pause is called is still handled.
+func (h *Handler) Pause() {
+	h.unprocessedMsgsCond.L.Lock()
+	h.paused = true
+	h.unprocessedMsgsCond.L.Unlock()
+}
+
+// Resume passes the queued messages, and the messages that arrive after, to
+// the engine again
+func (h *Handler) Resume() {
+	h.unprocessedMsgsCond.L.Lock()
+	h.paused = false
+	h.unprocessedMsgsCond.L.Unlock()
+
+	// If we're waiting in [Dispatch] wake up.
+	h.unprocessedMsgsCond.Signal()
+}
 
 // Dispatch waits for incoming messages from the router
 // and, when they arrive, sends them to the consensus engine
@@ -99,8 +146,8 @@ func (h *Handler) Dispatch() {
 				h.unprocessedMsgsCond.L.Unlock()
 				return
 			}
-			if h.unprocessedMsgs.Len() == 0 {
-				// Signalled in [h.push] and [h.StartShutdown]
+			if h.paused || h.unprocessedMsgs.Len() == 0 {
+				// Signalled in [h.push], [h.Resume] and [h.StartShutdown]
 				h.unprocessedMsgsCond.Wait()
 				continue
 			}
@@ -161,6 +208,12 @@ func (h *Handler) handleMsg(msg message) error {
 		histogram := h.metrics.getMSGHistogram(msg.messageType)
 		histogram.Observe(float64(handleDuration))
 		h.cpuTracker.UtilizeTime(msg.nodeID, startTime, endTime)
+
+		if errors.Is(err, common.ErrInvalidMessage) {
+			h.ctx.Log.Debug("dropping invalid message from %s%s: %s", constants.NodeIDPrefix, msg.nodeID, err)
+			h.scores.RegisterInvalidMessage(msg.nodeID)
+			err = nil
+		}
 	}
 
 	msg.doneHandling()
@@ -210,7 +263,7 @@ func (h *Handler) handleConsensusMsg(msg message) error {
 	case constants.ChitsMsg:
 		err = h.engine.Chits(msg.nodeID, msg.requestID, msg.containerIDs)
 	case constants.ConnectedMsg:
-		err = h.engine.Connected(msg.nodeID)
+		err = h.engine.Connected(msg.nodeID, msg.nodeVersion)
 	case constants.DisconnectedMsg:
 		err = h.engine.Disconnected(msg.nodeID)
 	}
@@ -473,10 +526,11 @@ func (h *Handler) QueryFailed(nodeID ids.ShortID, requestID uint32) {
 }
 
 // Connected passes a new connection notification to the consensus engine
-func (h *Handler) Connected(nodeID ids.ShortID) {
+func (h *Handler) Connected(nodeID ids.ShortID, nodeVersion version.Application) {
 	h.push(message{
 		messageType: constants.ConnectedMsg,
 		nodeID:      nodeID,
+		nodeVersion: nodeVersion,
 	})
 }
 
@@ -521,7 +575,7 @@ func (h *Handler) StartShutdown() {
 	// we wouldn't be able to grab [h.ctx.Lock] until the engine
 	// finished executing state transitions, which may take a long time.
 	// As a result, the router would time out on shutting down this chain.
-	h.engine.Halt()
+	h.Engine().Halt()
 }
 
 // Calls [h.engine.Shutdown] and [h.onCloseF]; closes [h.closed].
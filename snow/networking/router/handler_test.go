@@ -5,16 +5,20 @@ package router
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
 func TestHandlerDropsTimedOutMessages(t *testing.T) {
@@ -42,6 +46,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 	err := handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -84,6 +89,7 @@ func TestHandlerDoesntDrop(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -120,6 +126,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -143,6 +150,55 @@ func TestHandlerClosesOnError(t *testing.T) {
 	}
 }
 
+func TestHandlerCountsInvalidMessages(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+
+	called := make(chan struct{}, 1)
+
+	engine.ContextF = snow.DefaultContextTest
+	engine.PutF = func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) error {
+		return fmt.Errorf("%w: couldn't parse container", common.ErrInvalidMessage)
+	}
+	engine.GetAcceptedFrontierF = func(validatorID ids.ShortID, requestID uint32) error {
+		called <- struct{}{}
+		return nil
+	}
+
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	scores, err := score.NewTracker(score.Config{MaxInvalidMessages: 10, MaxPeers: 10}, logging.NoLog{}, memdb.New())
+	assert.NoError(t, err)
+	handler := &Handler{}
+	err = handler.Initialize(
+		&engine,
+		vdrs,
+		scores,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+
+	handler.clock.Set(time.Now())
+
+	go handler.Dispatch()
+
+	// The invalid message isn't fatal, so the next message is handled
+	nodeID := ids.GenerateTestShortID()
+	handler.Put(nodeID, 1, ids.GenerateTestID(), nil, func() {})
+	handler.GetAcceptedFrontier(nodeID, 2, time.Time{}, func() {})
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	select {
+	case <-ticker.C:
+		t.Fatalf("Calling engine function timed out")
+	case <-called:
+	}
+	assert.Equal(t, float64(1), scores.Scores()[nodeID].InvalidMessages)
+}
+
 func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 	engine := common.EngineTest{T: t}
 	engine.Default(false)
@@ -164,6 +220,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -204,6 +261,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		msgFromVMChan,
 		"",
 		prometheus.NewRegistry(),
@@ -219,3 +277,81 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+func TestHandlerSetEngineDuringShutdown(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	handler := &Handler{}
+	err := handler.Initialize(
+		&engine,
+		validators.NewSet(),
+		score.NewNoTracker(),
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+
+	newEngine := common.EngineTest{T: t}
+	newEngine.Default(false)
+	newEngine.ContextF = snow.DefaultContextTest
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		handler.ctx.Lock.Lock()
+		handler.SetEngine(&newEngine)
+		handler.ctx.Lock.Unlock()
+	}()
+	handler.StartShutdown()
+	_ = handler.Context()
+	<-done
+
+	assert.Equal(t, &newEngine, handler.Engine())
+}
+
+func TestHandlerPause(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	called := make(chan struct{}, 1)
+	engine.GetAcceptedFrontierF = func(validatorID ids.ShortID, requestID uint32) error {
+		called <- struct{}{}
+		return nil
+	}
+
+	handler := &Handler{}
+	vdrs := validators.NewSet()
+	err := vdrs.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	err = handler.Initialize(
+		&engine,
+		vdrs,
+		score.NewNoTracker(),
+		nil,
+		"",
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(t, err)
+
+	handler.Pause()
+	go handler.Dispatch()
+	handler.GetAcceptedFrontier(ids.ShortID{}, 1, time.Time{}, func() {})
+
+	select {
+	case <-time.After(20 * time.Millisecond):
+	case <-called:
+		t.Fatalf("paused handler shouldn't pass messages to the engine")
+	}
+
+	handler.Resume()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("resumed handler should pass queued messages to the engine")
+	case <-called:
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/version"
 )
 
 type message struct {
@@ -18,6 +19,7 @@ type message struct {
 	messageType constants.MsgType
 	// Must always be set
 	nodeID         ids.ShortID
+	nodeVersion    version.Application // Only set for Connected messages
 	requestID      uint32
 	containerID    ids.ID
 	container      []byte
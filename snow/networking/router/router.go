@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -141,7 +142,7 @@ type InternalRouter interface {
 	GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 
-	Connected(validatorID ids.ShortID)
+	Connected(validatorID ids.ShortID, nodeVersion version.Application)
 	Disconnected(validatorID ids.ShortID)
 
 	benchlist.Benchable
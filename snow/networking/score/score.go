@@ -0,0 +1,114 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package score
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/tracker"
+)
+
+// The relative importance of each of the inputs to a peer's score. They sum
+// to 1 so that a score is always in [0, 1].
+const (
+	latencyWeight        = 0.3
+	failureWeight        = 0.4
+	invalidMessageWeight = 0.2
+	cpuWeight            = 0.1
+)
+
+// Tracker assigns each peer a reputation score based on how well it has
+// behaved. A score is in [0, 1], where 1 is a perfectly behaved peer and 0
+// is a peer that should be avoided. Peers that haven't been observed have a
+// score of 1.
+//
+// Tracker is safe for concurrent access.
+type Tracker interface {
+	// RegisterResponse registers that [nodeID] responded to a request after
+	// [latency].
+	RegisterResponse(nodeID ids.ShortID, latency time.Duration)
+
+	// RegisterFailure registers that [nodeID] didn't respond to a request
+	// within the timeout.
+	RegisterFailure(nodeID ids.ShortID)
+
+	// RegisterInvalidMessage registers that [nodeID] sent a message that
+	// couldn't be parsed.
+	RegisterInvalidMessage(nodeID ids.ShortID)
+
+	// RegisterCPUTracker includes the CPU time that [cpuTracker] measures for
+	// chain [chainID] in the scores. If a tracker was already registered for
+	// [chainID], it is replaced.
+	RegisterCPUTracker(chainID ids.ID, cpuTracker tracker.TimeTracker)
+
+	// Score returns the current score of [nodeID].
+	Score(nodeID ids.ShortID) float64
+
+	// Scores returns the current score, and the inputs of that score, of
+	// every peer that has been observed.
+	Scores() map[ids.ShortID]PeerScore
+
+	// Commit persists the current scores so that they survive a restart.
+	Commit() error
+}
+
+// Config defines how the inputs of a score are weighed
+type Config struct {
+	// Halflife of the moving averages that a score is computed from
+	Halflife time.Duration
+	// A peer whose average response latency is at least [MaxLatency] gets no
+	// credit for its latency
+	MaxLatency time.Duration
+	// A peer that has sent at least [MaxInvalidMessages] invalid messages in
+	// the last halflife gets no credit for its messages
+	MaxInvalidMessages float64
+	// A peer that is using at least [MaxCPUUtilization] of this node's message
+	// handling time gets no credit for its CPU usage
+	MaxCPUUtilization float64
+	// When sampling validators, the weight of a validator is scaled by a
+	// factor in [MinSamplingFactor, 1] depending on its score. Must be in
+	// (0, 1].
+	MinSamplingFactor float64
+	// Maximum number of peers whose score inputs are kept. Once exceeded, the
+	// peer that was observed least recently is forgotten. Must be positive.
+	MaxPeers int
+	// The score inputs of a peer that hasn't been observed for [Expiry] are
+	// forgotten. If 0, they don't expire.
+	Expiry time.Duration
+}
+
+// PeerScore is a peer's score along with the inputs it was computed from
+type PeerScore struct {
+	Score           float64
+	Latency         time.Duration
+	FailureRate     float64
+	InvalidMessages float64
+	CPUUtilization  float64
+}
+
+// score calculates the score of a peer with the provided inputs.
+func (c *Config) score(latency time.Duration, failureRate, invalidMessages, cpuUtilization float64) float64 {
+	latencyScore := 1 - ratio(float64(latency), float64(c.MaxLatency))
+	failureScore := 1 - ratio(failureRate, 1)
+	invalidMessageScore := 1 - ratio(invalidMessages, c.MaxInvalidMessages)
+	cpuScore := 1 - ratio(cpuUtilization, c.MaxCPUUtilization)
+	return latencyWeight*latencyScore +
+		failureWeight*failureScore +
+		invalidMessageWeight*invalidMessageScore +
+		cpuWeight*cpuScore
+}
+
+// ratio returns [value]/[max] capped to [0, 1]. If [max] isn't positive, the
+// input is ignored.
+func ratio(value, max float64) float64 {
+	switch {
+	case max <= 0 || value <= 0:
+		return 0
+	case value >= max:
+		return 1
+	default:
+		return value / max
+	}
+}
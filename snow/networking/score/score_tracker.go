@@ -0,0 +1,348 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package score
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/tracker"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const codecVersion = 0
+
+var (
+	c codec.Manager
+
+	_ Tracker = &scoreTracker{}
+)
+
+func init() {
+	lc := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	c = codec.NewManager(math.MaxInt32)
+	if err := c.RegisterCodec(codecVersion, lc); err != nil {
+		panic(err)
+	}
+}
+
+// peerRecord is the persisted form of a peer's score inputs. The codec
+// doesn't support floats, so they are stored as their IEEE 754 bits.
+type peerRecord struct {
+	Latency         int64  `serialize:"true"`
+	FailureRate     uint64 `serialize:"true"`
+	InvalidMessages uint64 `serialize:"true"`
+	LastInvalid     int64  `serialize:"true"`
+	LastObserved    int64  `serialize:"true"`
+}
+
+type peer struct {
+	// Average response latency, in nanoseconds
+	latency safemath.Averager
+	// Average of 1 for each failed request and 0 for each response
+	failureRate safemath.Averager
+	// Number of invalid messages, decayed by the halflife, as of [lastInvalid]
+	invalidMessages float64
+	lastInvalid     time.Time
+	// Last time a response, failure or invalid message of this peer was
+	// registered
+	lastObserved time.Time
+}
+
+type scoreTracker struct {
+	lock   sync.Mutex
+	config Config
+	log    logging.Logger
+	db     database.Database
+
+	// Tells the time. Can be faked for testing.
+	clock timer.Clock
+
+	// Node ID --> score inputs of that peer, ordered from the least to the
+	// most recently observed peer
+	peers linkedhashmap.LinkedHashmap
+
+	// Chain ID --> CPU time spent on messages from each peer for that chain
+	cpuTrackers map[ids.ID]tracker.TimeTracker
+}
+
+// NewTracker returns a new Tracker that persists its scores in [db]. The
+// previously persisted scores are loaded from [db].
+func NewTracker(config Config, log logging.Logger, db database.Database) (Tracker, error) {
+	t := &scoreTracker{
+		config:      config,
+		log:         log,
+		db:          db,
+		peers:       linkedhashmap.New(),
+		cpuTrackers: make(map[ids.ID]tracker.TimeTracker),
+	}
+	return t, t.load()
+}
+
+// loadedPeer is a peer read from the database
+type loadedPeer struct {
+	nodeID ids.ShortID
+	peer   *peer
+}
+
+// load the persisted scores from the database
+func (t *scoreTracker) load() error {
+	it := t.db.NewIterator()
+	defer it.Release()
+
+	now := t.clock.Time()
+	loaded := []loadedPeer(nil)
+	for it.Next() {
+		nodeID, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return err
+		}
+
+		record := peerRecord{}
+		if _, err := c.Unmarshal(it.Value(), &record); err != nil {
+			return err
+		}
+
+		p := &peer{
+			latency:         safemath.NewAverager(float64(record.Latency), t.config.Halflife, now),
+			failureRate:     safemath.NewAverager(math.Float64frombits(record.FailureRate), t.config.Halflife, now),
+			invalidMessages: math.Float64frombits(record.InvalidMessages),
+		}
+		if record.LastInvalid != 0 {
+			p.lastInvalid = time.Unix(0, record.LastInvalid)
+		}
+		p.lastObserved = time.Unix(0, record.LastObserved)
+		loaded = append(loaded, loadedPeer{
+			nodeID: nodeID,
+			peer:   p,
+		})
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(loaded, func(i, j int) bool {
+		return loaded[i].peer.lastObserved.Before(loaded[j].peer.lastObserved)
+	})
+	for _, l := range loaded {
+		t.peers.Put(l.nodeID, l.peer)
+	}
+	t.prune(now)
+	return nil
+}
+
+// getPeer returns the score inputs of [nodeID], creating them if they don't
+// exist, and marks [nodeID] as observed at [now].
+// Assumes [t.lock] is held.
+func (t *scoreTracker) getPeer(nodeID ids.ShortID, now time.Time) *peer {
+	var p *peer
+	if pIntf, exists := t.peers.Get(nodeID); exists {
+		p = pIntf.(*peer)
+	} else {
+		p = &peer{
+			latency:     safemath.NewAverager(0, t.config.Halflife, now),
+			failureRate: safemath.NewAverager(0, t.config.Halflife, now),
+		}
+	}
+	p.lastObserved = now
+	t.peers.Put(nodeID, p)
+	t.prune(now)
+	return p
+}
+
+// prune forgets the peers that expired, and the least recently observed peers
+// while more than [MaxPeers] are kept.
+// Assumes [t.lock] is held.
+func (t *scoreTracker) prune(now time.Time) {
+	for {
+		nodeIDIntf, pIntf, exists := t.peers.Oldest()
+		if !exists {
+			return
+		}
+		expired := t.config.Expiry > 0 && now.Sub(pIntf.(*peer).lastObserved) >= t.config.Expiry
+		if !expired && t.peers.Len() <= t.config.MaxPeers {
+			return
+		}
+		t.peers.Delete(nodeIDIntf)
+	}
+}
+
+func (t *scoreTracker) RegisterResponse(nodeID ids.ShortID, latency time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getPeer(nodeID, now)
+	p.latency.Observe(float64(latency), now)
+	p.failureRate.Observe(0, now)
+}
+
+func (t *scoreTracker) RegisterFailure(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getPeer(nodeID, now)
+	p.failureRate.Observe(1, now)
+}
+
+func (t *scoreTracker) RegisterInvalidMessage(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getPeer(nodeID, now)
+	p.invalidMessages = t.decay(p.invalidMessages, p.lastInvalid, now) + 1
+	p.lastInvalid = now
+}
+
+func (t *scoreTracker) RegisterCPUTracker(chainID ids.ID, cpuTracker tracker.TimeTracker) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.cpuTrackers[chainID] = cpuTracker
+}
+
+func (t *scoreTracker) Score(nodeID ids.ShortID) float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.peerScore(nodeID, t.clock.Time()).Score
+}
+
+func (t *scoreTracker) Scores() map[ids.ShortID]PeerScore {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	t.prune(now)
+	scores := make(map[ids.ShortID]PeerScore, t.peers.Len())
+	it := t.peers.NewIterator()
+	for it.Next() {
+		nodeID := it.Key().(ids.ShortID)
+		scores[nodeID] = t.peerScore(nodeID, now)
+	}
+	return scores
+}
+
+func (t *scoreTracker) Commit() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.prune(t.clock.Time())
+
+	batch := t.db.NewBatch()
+
+	// Delete the persisted scores of the peers that were forgotten
+	if err := t.deleteForgotten(batch); err != nil {
+		return err
+	}
+
+	it := t.peers.NewIterator()
+	for it.Next() {
+		nodeID := it.Key().(ids.ShortID)
+		p := it.Value().(*peer)
+		record := peerRecord{
+			Latency:         int64(p.latency.Read()),
+			FailureRate:     math.Float64bits(p.failureRate.Read()),
+			InvalidMessages: math.Float64bits(p.invalidMessages),
+			LastObserved:    p.lastObserved.UnixNano(),
+		}
+		if !p.lastInvalid.IsZero() {
+			record.LastInvalid = p.lastInvalid.UnixNano()
+		}
+		recordBytes, err := c.Marshal(codecVersion, &record)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(nodeID[:], recordBytes); err != nil {
+			return err
+		}
+	}
+	t.log.Debug("persisting the scores of %d peers", t.peers.Len())
+	return batch.Write()
+}
+
+// deleteForgotten adds to [batch] the deletion of the persisted scores of the
+// peers that aren't kept anymore.
+// Assumes [t.lock] is held.
+func (t *scoreTracker) deleteForgotten(batch database.Batch) error {
+	it := t.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		nodeID, err := ids.ToShortID(key)
+		if err != nil {
+			return err
+		}
+		if _, exists := t.peers.Get(nodeID); exists {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// peerScore returns the score of [nodeID] at time [now].
+// Assumes [t.lock] is held.
+func (t *scoreTracker) peerScore(nodeID ids.ShortID, now time.Time) PeerScore {
+	cpuUtilization := 0.
+	for _, cpuTracker := range t.cpuTrackers {
+		cpuUtilization += cpuTracker.Utilization(nodeID, now)
+	}
+
+	peerScore := PeerScore{
+		CPUUtilization: cpuUtilization,
+	}
+	if pIntf, exists := t.peers.Get(nodeID); exists {
+		p := pIntf.(*peer)
+		peerScore.Latency = time.Duration(p.latency.Read())
+		peerScore.FailureRate = p.failureRate.Read()
+		peerScore.InvalidMessages = t.decay(p.invalidMessages, p.lastInvalid, now)
+	}
+	peerScore.Score = t.config.score(
+		peerScore.Latency,
+		peerScore.FailureRate,
+		peerScore.InvalidMessages,
+		peerScore.CPUUtilization,
+	)
+	return peerScore
+}
+
+// decay returns [value], which was measured at [lastUpdated], decayed by the
+// halflife until [now].
+func (t *scoreTracker) decay(value float64, lastUpdated, now time.Time) float64 {
+	elapsed := now.Sub(lastUpdated)
+	if value == 0 || elapsed <= 0 || t.config.Halflife <= 0 {
+		return value
+	}
+	return value * math.Exp2(-float64(elapsed)/float64(t.config.Halflife))
+}
+
+type noTracker struct{}
+
+// NewNoTracker returns a Tracker that gives every peer a perfect score
+func NewNoTracker() Tracker { return noTracker{} }
+
+func (noTracker) RegisterResponse(ids.ShortID, time.Duration)    {}
+func (noTracker) RegisterFailure(ids.ShortID)                    {}
+func (noTracker) RegisterInvalidMessage(ids.ShortID)             {}
+func (noTracker) RegisterCPUTracker(ids.ID, tracker.TimeTracker) {}
+func (noTracker) Score(ids.ShortID) float64                      { return 1 }
+func (noTracker) Scores() map[ids.ShortID]PeerScore              { return nil }
+func (noTracker) Commit() error                                  { return nil }
@@ -0,0 +1,173 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package score
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/tracker"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var testConfig = Config{
+	Halflife:           time.Minute,
+	MaxLatency:         time.Second,
+	MaxInvalidMessages: 10,
+	MaxCPUUtilization:  0.5,
+	MinSamplingFactor:  0.5,
+	MaxPeers:           2,
+	Expiry:             time.Hour,
+}
+
+func TestTrackerUnknownPeer(t *testing.T) {
+	assert := assert.New(t)
+
+	scores, err := NewTracker(testConfig, logging.NoLog{}, memdb.New())
+	assert.NoError(err)
+
+	assert.InDelta(1, scores.Score(ids.GenerateTestShortID()), 1e-9)
+	assert.Empty(scores.Scores())
+}
+
+func TestTrackerInputs(t *testing.T) {
+	assert := assert.New(t)
+
+	scoresIntf, err := NewTracker(testConfig, logging.NoLog{}, memdb.New())
+	assert.NoError(err)
+	scores := scoresIntf.(*scoreTracker)
+	now := time.Now()
+	scores.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+
+	// A fast response doesn't hurt the score
+	scores.RegisterResponse(nodeID, 0)
+	assert.InDelta(1, scores.Score(nodeID), 1e-9)
+
+	// Averaging the max latency removes the latency credit. The average
+	// includes the initial prediction of 0.
+	scores.RegisterResponse(nodeID, 3*testConfig.MaxLatency)
+	peerScore := scores.Scores()[nodeID]
+	assert.Equal(testConfig.MaxLatency, peerScore.Latency)
+	assert.InDelta(1-latencyWeight, peerScore.Score, 1e-9)
+
+	// Failures reduce the score
+	scores.RegisterFailure(nodeID)
+	peerScore = scores.Scores()[nodeID]
+	assert.InDelta(1./4, peerScore.FailureRate, 1e-9)
+	assert.InDelta(1-latencyWeight-failureWeight/4, peerScore.Score, 1e-9)
+
+	// Invalid messages reduce the score and decay over time
+	for i := 0; i < 5; i++ {
+		scores.RegisterInvalidMessage(nodeID)
+	}
+	assert.InDelta(5, scores.Scores()[nodeID].InvalidMessages, 1e-9)
+	scores.clock.Set(now.Add(testConfig.Halflife))
+	assert.InDelta(2.5, scores.Scores()[nodeID].InvalidMessages, 1e-9)
+
+	// CPU usage is summed across chains
+	cpuTracker := &tracker.MockTimeTracker{}
+	cpuTracker.On("Utilization", mock.Anything, mock.Anything).Return(0.25)
+	scores.RegisterCPUTracker(ids.GenerateTestID(), cpuTracker)
+	scores.RegisterCPUTracker(ids.GenerateTestID(), cpuTracker)
+	assert.Equal(0.5, scores.Scores()[nodeID].CPUUtilization)
+
+	// A peer that hasn't otherwise been observed is still scored by its CPU
+	// usage
+	assert.InDelta(1-cpuWeight, scores.Score(ids.GenerateTestShortID()), 1e-9)
+}
+
+func TestTrackerPersistence(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	scores, err := NewTracker(testConfig, logging.NoLog{}, db)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	scores.RegisterResponse(nodeID, testConfig.MaxLatency/2)
+	scores.RegisterFailure(nodeID)
+	scores.RegisterInvalidMessage(nodeID)
+	assert.NoError(scores.Commit())
+
+	expectedScores := scores.Scores()
+
+	reloadedScores, err := NewTracker(testConfig, logging.NoLog{}, db)
+	assert.NoError(err)
+
+	reloadedScore := reloadedScores.Scores()[nodeID]
+	expectedScore := expectedScores[nodeID]
+	assert.Equal(expectedScore.Latency, reloadedScore.Latency)
+	assert.InDelta(expectedScore.FailureRate, reloadedScore.FailureRate, 1e-9)
+	assert.InDelta(expectedScore.InvalidMessages, reloadedScore.InvalidMessages, 1e-3)
+	assert.InDelta(expectedScore.Score, reloadedScore.Score, 1e-3)
+}
+
+func TestTrackerMaxPeers(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	scoresIntf, err := NewTracker(testConfig, logging.NoLog{}, db)
+	assert.NoError(err)
+	scores := scoresIntf.(*scoreTracker)
+	scores.clock.Set(time.Now())
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeID2 := ids.GenerateTestShortID()
+	scores.RegisterFailure(nodeID0)
+	scores.RegisterFailure(nodeID1)
+	assert.NoError(scores.Commit())
+
+	// Observing a third peer forgets the least recently observed one
+	scores.RegisterFailure(nodeID0)
+	scores.RegisterFailure(nodeID2)
+	assert.Len(scores.Scores(), 2)
+	assert.Contains(scores.Scores(), nodeID0)
+	assert.NotContains(scores.Scores(), nodeID1)
+	assert.Contains(scores.Scores(), nodeID2)
+
+	// The persisted score of the forgotten peer is deleted
+	assert.NoError(scores.Commit())
+	has, err := db.Has(nodeID1[:])
+	assert.NoError(err)
+	assert.False(has)
+}
+
+func TestTrackerExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	scoresIntf, err := NewTracker(testConfig, logging.NoLog{}, db)
+	assert.NoError(err)
+	scores := scoresIntf.(*scoreTracker)
+	now := time.Now()
+	scores.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+	scores.RegisterFailure(nodeID)
+	assert.NoError(scores.Commit())
+
+	// A peer that wasn't observed for [Expiry] is forgotten, also when the
+	// scores are reloaded
+	reloadedScoresIntf, err := NewTracker(testConfig, logging.NoLog{}, db)
+	assert.NoError(err)
+	reloadedScores := reloadedScoresIntf.(*scoreTracker)
+	reloadedScores.clock.Set(now.Add(testConfig.Expiry))
+	assert.Empty(reloadedScores.Scores())
+	assert.InDelta(1, reloadedScores.Score(nodeID), 1e-9)
+
+	scores.clock.Set(now.Add(testConfig.Expiry))
+	assert.Empty(scores.Scores())
+	assert.NoError(scores.Commit())
+	has, err := db.Has(nodeID[:])
+	assert.NoError(err)
+	assert.False(has)
+}
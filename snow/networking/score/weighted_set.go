@@ -0,0 +1,78 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package score
+
+import (
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+)
+
+var _ validators.Set = &weightedSet{}
+
+// validatorSet allows validators.Set to be embedded without its Set method
+// being shadowed by the name of the embedded field.
+type validatorSet = validators.Set
+
+// weightedSet is a validator set whose samples are biased towards validators
+// with higher scores.
+type weightedSet struct {
+	validatorSet
+	scores            Tracker
+	minSamplingFactor float64
+}
+
+// NewWeightedSet returns a view of [vdrs] where the weight of each validator,
+// when sampling, is scaled by a factor in [minSamplingFactor, 1] based on the
+// validator's score. All other operations are passed through to [vdrs].
+func NewWeightedSet(vdrs validators.Set, scores Tracker, minSamplingFactor float64) validators.Set {
+	return &weightedSet{
+		validatorSet:      vdrs,
+		scores:            scores,
+		minSamplingFactor: minSamplingFactor,
+	}
+}
+
+func (s *weightedSet) Sample(size int) ([]validators.Validator, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	vdrs := s.validatorSet.List()
+	weights := make([]uint64, len(vdrs))
+	for i, vdr := range vdrs {
+		weights[i] = ScaleWeight(vdr.Weight(), s.scores.Score(vdr.ID()), s.minSamplingFactor)
+	}
+
+	weightedSampler := sampler.NewWeightedWithoutReplacement()
+	if err := weightedSampler.Initialize(weights); err != nil {
+		return nil, err
+	}
+	indices, err := weightedSampler.Sample(size)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled := make([]validators.Validator, size)
+	for i, index := range indices {
+		sampled[i] = vdrs[index]
+	}
+	return sampled, nil
+}
+
+// ScaleWeight returns [weight] scaled by a factor in [minFactor, 1] that
+// grows linearly with [score]. A non-zero weight is never scaled below 1.
+func ScaleWeight(weight uint64, score, minFactor float64) uint64 {
+	if weight == 0 {
+		return 0
+	}
+	factor := minFactor + (1-minFactor)*score
+	if factor >= 1 {
+		return weight
+	}
+	scaled := uint64(float64(weight) * factor)
+	if scaled == 0 {
+		return 1
+	}
+	return scaled
+}
@@ -0,0 +1,56 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestScaleWeight(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint64(0), ScaleWeight(0, 1, 0.5))
+	assert.Equal(uint64(100), ScaleWeight(100, 1, 0.5))
+	assert.Equal(uint64(50), ScaleWeight(100, 0, 0.5))
+	assert.Equal(uint64(75), ScaleWeight(100, 0.5, 0.5))
+	assert.Equal(uint64(1), ScaleWeight(1, 0, 0.5))
+}
+
+func TestWeightedSetSample(t *testing.T) {
+	assert := assert.New(t)
+
+	scores, err := NewTracker(testConfig, logging.NoLog{}, memdb.New())
+	assert.NoError(err)
+
+	goodID := ids.GenerateTestShortID()
+	badID := ids.GenerateTestShortID()
+
+	vdrs := validators.NewSet()
+	assert.NoError(vdrs.AddWeight(goodID, 1))
+	assert.NoError(vdrs.AddWeight(badID, 1))
+
+	// The validator with the bad score is only sampled if the sample must
+	// include every unit of weight.
+	scores.RegisterFailure(badID)
+	weightedVdrs := NewWeightedSet(vdrs, scores, 0.5)
+
+	sampled, err := weightedVdrs.Sample(1)
+	assert.NoError(err)
+	assert.Len(sampled, 1)
+
+	sampled, err = weightedVdrs.Sample(2)
+	assert.NoError(err)
+	assert.Len(sampled, 2)
+
+	// Everything else is passed through to the underlying set
+	assert.Equal(uint64(2), weightedVdrs.Weight())
+	assert.True(weightedVdrs.Contains(badID))
+}
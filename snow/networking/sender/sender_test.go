@@ -19,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -93,6 +94,7 @@ func TestTimeout(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -169,6 +171,7 @@ func TestReliableMessages(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
@@ -254,6 +257,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		nil,
 		"",
 		prometheus.NewRegistry(),
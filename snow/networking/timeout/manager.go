@@ -90,7 +90,7 @@ func (m *Manager) RegisterResponse(
 	m.lock.Lock()
 	m.metrics.observe(chainID, msgType, latency)
 	m.lock.Unlock()
-	m.benchlistMgr.RegisterResponse(chainID, validatorID)
+	m.benchlistMgr.RegisterResponse(chainID, validatorID, latency)
 	m.tm.Remove(uniqueRequestID)
 }
 
@@ -0,0 +1,60 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var _ State = &lockedState{}
+
+// State allows the lookup of validator sets on specified subnets at the
+// requested P-chain height, and of the subnets that validate chains.
+type State interface {
+	// GetCurrentHeight returns the current height of the P-chain.
+	GetCurrentHeight() (uint64, error)
+
+	// GetValidatorSet returns the weights of the nodeIDs for the provided
+	// subnet at the requested P-chain height.
+	GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error)
+
+	// GetSubnetID returns the ID of the subnet that validates [chainID].
+	GetSubnetID(chainID ids.ID) (ids.ID, error)
+}
+
+type lockedState struct {
+	lock sync.Locker
+	s    State
+}
+
+// NewLockedState returns a State that grabs [lock] before every call to [s].
+func NewLockedState(lock sync.Locker, s State) State {
+	return &lockedState{
+		lock: lock,
+		s:    s,
+	}
+}
+
+func (s *lockedState) GetCurrentHeight() (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetCurrentHeight()
+}
+
+func (s *lockedState) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetValidatorSet(height, subnetID)
+}
+
+func (s *lockedState) GetSubnetID(chainID ids.ID) (ids.ID, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.GetSubnetID(chainID)
+}
@@ -3,6 +3,10 @@
 
 package compression
 
+import (
+	"fmt"
+)
+
 // Compressor compresss and decompresses messages.
 // Decompress is the inverse of Compress.
 // Decompress(Compress(msg)) == msg.
@@ -10,3 +14,41 @@ type Compressor interface {
 	Compress([]byte) ([]byte, error)
 	Decompress([]byte) ([]byte, error)
 }
+
+// Type is the algorithm a message is compressed with. Its value is sent over
+// the wire, so existing values must not change.
+type Type byte
+
+const (
+	NoCompression Type = iota
+	Gzip
+	Zstd
+	// ZstdWithDictionary is zstd with a dictionary that both the sender and
+	// the receiver have
+	ZstdWithDictionary
+)
+
+// TypeFromString returns the compression type named [s]
+func TypeFromString(s string) (Type, error) {
+	for _, t := range []Type{NoCompression, Gzip, Zstd, ZstdWithDictionary} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return NoCompression, fmt.Errorf("unknown compression type %q", s)
+}
+
+func (t Type) String() string {
+	switch t {
+	case NoCompression:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	case ZstdWithDictionary:
+		return "zstd-dictionary"
+	default:
+		return "unknown"
+	}
+}
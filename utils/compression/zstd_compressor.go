@@ -0,0 +1,103 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// frameMagic starts every zstd frame
+	frameMagic = 0xFD2FB528
+	// dictionaryMagic starts every zstd dictionary
+	dictionaryMagic = 0xEC30A437
+)
+
+var (
+	errInvalidFrame      = errors.New("invalid zstd frame")
+	errInvalidDictionary = errors.New("invalid zstd dictionary")
+)
+
+// zstdCompressor implements Compressor
+type zstdCompressor struct {
+	maxSize int64
+
+	// The encoder and decoder are safe for concurrent use
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// Compress [msg] and returns the compressed bytes.
+func (z *zstdCompressor) Compress(msg []byte) ([]byte, error) {
+	if int64(len(msg)) > z.maxSize {
+		return nil, fmt.Errorf("msg length (%d) > maximum msg length (%d)", len(msg), z.maxSize)
+	}
+	return z.encoder.EncodeAll(msg, nil), nil
+}
+
+// Decompress decompresses [msg].
+func (z *zstdCompressor) Decompress(msg []byte) ([]byte, error) {
+	// The decoder skips input too short to be a frame rather than erroring
+	if len(msg) < 4 || binary.LittleEndian.Uint32(msg) != frameMagic {
+		return nil, errInvalidFrame
+	}
+	decompressed, err := z.decoder.DecodeAll(msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > z.maxSize {
+		return nil, fmt.Errorf("msg length > maximum msg length (%d)", z.maxSize)
+	}
+	return decompressed, nil
+}
+
+// NewZstdCompressor returns a new zstd Compressor. If [dictionary] isn't nil,
+// messages are compressed with it and messages compressed with it can be
+// decompressed. Messages compressed without a dictionary can always be
+// decompressed.
+func NewZstdCompressor(maxSize int64, dictionary []byte) (Compressor, error) {
+	encoderOpts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+	// The decoder doesn't allocate more than [maxSize] bytes for a message,
+	// so that small messages can't decompress into huge ones
+	decoderOpts := []zstd.DOption{
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(uint64(maxSize)),
+	}
+	if dictionary != nil {
+		encoderOpts = append(encoderOpts, zstd.WithEncoderDict(dictionary))
+		decoderOpts = append(decoderOpts, zstd.WithDecoderDicts(dictionary))
+	}
+
+	encoder, err := zstd.NewWriter(nil, encoderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidDictionary, err)
+	}
+	decoder, err := zstd.NewReader(nil, decoderOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidDictionary, err)
+	}
+	return &zstdCompressor{
+		maxSize: maxSize,
+		encoder: encoder,
+		decoder: decoder,
+	}, nil
+}
+
+// DictionaryID returns the ID of the zstd [dictionary], which identifies the
+// dictionary a message was compressed with. The ID is never 0, which marks
+// messages compressed without a dictionary.
+func DictionaryID(dictionary []byte) (uint32, error) {
+	if len(dictionary) < 8 || binary.LittleEndian.Uint32(dictionary) != dictionaryMagic {
+		return 0, errInvalidDictionary
+	}
+	id := binary.LittleEndian.Uint32(dictionary[4:])
+	if id == 0 {
+		return 0, fmt.Errorf("%w: dictionary ID is 0", errInvalidDictionary)
+	}
+	return id, nil
+}
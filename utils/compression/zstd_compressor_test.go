@@ -0,0 +1,125 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// testdata/zstd.dict was trained on byte strings that look like transactions
+// and has ID 1
+func readTestDictionary(t *testing.T) []byte {
+	dictionary, err := ioutil.ReadFile("testdata/zstd.dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dictionary
+}
+
+func TestZstdCompressDecompress(t *testing.T) {
+	data := make([]byte, 4096)
+	for i := 0; i < len(data); i++ {
+		data[i] = byte(rand.Intn(256)) // #nosec G404
+	}
+
+	data2 := make([]byte, 4096)
+	for i := 0; i < len(data); i++ {
+		data2[i] = byte(rand.Intn(256)) // #nosec G404
+	}
+
+	compressor, err := NewZstdCompressor(2*units.MiB, nil)
+	assert.NoError(t, err)
+
+	dataCompressed, err := compressor.Compress(data)
+	assert.NoError(t, err)
+
+	data2Compressed, err := compressor.Compress(data2)
+	assert.NoError(t, err)
+
+	dataDecompressed, err := compressor.Decompress(dataCompressed)
+	assert.NoError(t, err)
+	assert.EqualValues(t, data, dataDecompressed)
+
+	data2Decompressed, err := compressor.Decompress(data2Compressed)
+	assert.NoError(t, err)
+	assert.EqualValues(t, data2, data2Decompressed)
+
+	nonZstdData := []byte{1, 2, 3}
+	_, err = compressor.Decompress(nonZstdData)
+	assert.Error(t, err)
+}
+
+func TestZstdDictionary(t *testing.T) {
+	assert := assert.New(t)
+
+	dictionary := readTestDictionary(t)
+	id, err := DictionaryID(dictionary)
+	assert.NoError(err)
+	assert.EqualValues(1, id)
+
+	withDictionary, err := NewZstdCompressor(2*units.MiB, dictionary)
+	assert.NoError(err)
+	withoutDictionary, err := NewZstdCompressor(2*units.MiB, nil)
+	assert.NoError(err)
+
+	data := bytes.Repeat([]byte("\x00\x00\x00\x07transfer\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x03\xe8"), 4)
+	compressed, err := withDictionary.Compress(data)
+	assert.NoError(err)
+	compressedWithoutDictionary, err := withoutDictionary.Compress(data)
+	assert.NoError(err)
+	assert.Less(len(compressed), len(compressedWithoutDictionary))
+
+	decompressed, err := withDictionary.Decompress(compressed)
+	assert.NoError(err)
+	assert.Equal(data, decompressed)
+
+	// Messages compressed with a dictionary can't be decompressed without it
+	_, err = withoutDictionary.Decompress(compressed)
+	assert.Error(err)
+
+	// Messages compressed without a dictionary can always be decompressed
+	decompressed, err = withDictionary.Decompress(compressedWithoutDictionary)
+	assert.NoError(err)
+	assert.Equal(data, decompressed)
+
+	_, err = DictionaryID([]byte{1, 2, 3})
+	assert.ErrorIs(err, errInvalidDictionary)
+	_, err = NewZstdCompressor(2*units.MiB, []byte{1, 2, 3})
+	assert.ErrorIs(err, errInvalidDictionary)
+}
+
+func TestZstdMaxSize(t *testing.T) {
+	assert := assert.New(t)
+
+	compressor, err := NewZstdCompressor(2*units.MiB, nil)
+	assert.NoError(err)
+	smallCompressor, err := NewZstdCompressor(units.KiB, nil)
+	assert.NoError(err)
+
+	_, err = smallCompressor.Compress(make([]byte, units.KiB+1))
+	assert.Error(err)
+
+	// A message that decompresses into more than the max size is rejected
+	compressed, err := compressor.Compress(make([]byte, units.MiB))
+	assert.NoError(err)
+	_, err = smallCompressor.Decompress(compressed)
+	assert.Error(err)
+}
+
+func TestTypeFromString(t *testing.T) {
+	for _, typ := range []Type{NoCompression, Gzip, Zstd, ZstdWithDictionary} {
+		parsed, err := TypeFromString(typ.String())
+		assert.NoError(t, err)
+		assert.Equal(t, typ, parsed)
+	}
+	_, err := TypeFromString("lz4")
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package hdwallet derives secp256k1 keys from BIP-39 mnemonics along BIP-44
+// derivation paths, as described in BIP-32.
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+
+	secp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v3"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+const (
+	// HardenedOffset is added to the index of hardened children
+	HardenedOffset uint32 = 1 << 31
+
+	// AvalancheCoinType is the BIP-44 coin type of the X-Chain and the P-Chain
+	AvalancheCoinType uint32 = 9000
+
+	// MinSeedLen and MaxSeedLen bound the length, in bytes, of seeds. Seeds
+	// derived from mnemonics are [MaxSeedLen] bytes.
+	MinSeedLen = 16
+	MaxSeedLen = 64
+
+	// mnemonicEntropy is the number of bits of entropy of new mnemonics, which
+	// have 24 words
+	mnemonicEntropy = 256
+
+	purpose = 44
+)
+
+var (
+	masterKeyHMACKey = []byte("Bitcoin seed")
+
+	errInvalidMnemonic = errors.New("invalid mnemonic")
+	errInvalidSeedLen  = fmt.Errorf("seed must be between %d and %d bytes", MinSeedLen, MaxSeedLen)
+	errInvalidChild    = errors.New("derived key is invalid")
+)
+
+// NewMnemonic returns a new 24 word mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropy)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// Seed returns the seed [mnemonic] encodes. Mnemonic passphrases aren't
+// supported.
+func Seed(mnemonic string) ([]byte, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errInvalidMnemonic
+	}
+	return bip39.NewSeedWithErrorChecking(mnemonic, "")
+}
+
+// ExternalChainPath returns the BIP-44 derivation path of the parent of the
+// external addresses of the first account of [coinType]: m/44'/coinType'/0'/0
+func ExternalChainPath(coinType uint32) []uint32 {
+	return []uint32{
+		purpose + HardenedOffset,
+		coinType + HardenedOffset,
+		HardenedOffset,
+		0,
+	}
+}
+
+// Path returns the BIP-44 derivation path of the [index]th external address
+// of the first account of [coinType]: m/44'/coinType'/0'/0/index
+func Path(coinType, index uint32) []uint32 {
+	return append(ExternalChainPath(coinType), index)
+}
+
+// ExtendedKey is a private key that children can be derived from
+type ExtendedKey struct {
+	key       [32]byte
+	chainCode [32]byte
+}
+
+// NewMaster returns the master key of the tree derived from [seed]
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, errInvalidSeedLen
+	}
+	return newExtendedKey(masterKeyHMACKey, seed)
+}
+
+// Child returns the [index]th child of [k]. If [index] is at least
+// [HardenedOffset], the child is hardened.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		data = append(data, 0)
+		data = append(data, k.key[:]...)
+	} else {
+		pk := secp256k1.PrivKeyFromBytes(k.key[:]).PubKey()
+		data = append(data, pk.SerializeCompressed()...)
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	child, err := newExtendedKey(k.chainCode[:], data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The child's key is the sum of the tweak and the parent's key
+	tweak := secp256k1.ModNScalar{}
+	tweak.SetBytes(&child.key)
+	parent := secp256k1.ModNScalar{}
+	parent.SetBytes(&k.key)
+	tweak.Add(&parent)
+	if tweak.IsZero() {
+		return nil, errInvalidChild
+	}
+	child.key = tweak.Bytes()
+	return child, nil
+}
+
+// Derive returns the descendant of [k] at [path]
+func (k *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// PrivateKey returns the private key of [k]
+func (k *ExtendedKey) PrivateKey() *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	// ToPrivateKey never errors for keys of the right length
+	sk, _ := factory.ToPrivateKey(k.key[:])
+	return sk.(*crypto.PrivateKeySECP256K1R)
+}
+
+// newExtendedKey returns the extended key whose key and chain code are the
+// halves of HMAC-SHA512(hmacKey, data)
+func newExtendedKey(hmacKey, data []byte) (*ExtendedKey, error) {
+	mac := hmac.New(sha512.New, hmacKey)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	k := &ExtendedKey{}
+	copy(k.key[:], sum[:32])
+	copy(k.chainCode[:], sum[32:])
+
+	key := secp256k1.ModNScalar{}
+	if overflow := key.SetBytes(&k.key); overflow != 0 || key.IsZero() {
+		return nil, errInvalidChild
+	}
+	return k, nil
+}
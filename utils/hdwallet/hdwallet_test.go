@@ -0,0 +1,70 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hdwallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector 1 of BIP-32
+func TestDerive(t *testing.T) {
+	assert := assert.New(t)
+
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	assert.NoError(err)
+	master, err := NewMaster(seed)
+	assert.NoError(err)
+	assert.Equal("e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", hex.EncodeToString(master.key[:]))
+	assert.Equal("873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", hex.EncodeToString(master.chainCode[:]))
+
+	tests := []struct {
+		path []uint32
+		key  string
+	}{
+		{
+			path: []uint32{HardenedOffset},
+			key:  "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		},
+		{
+			path: []uint32{HardenedOffset, 1},
+			key:  "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		},
+		{
+			path: []uint32{HardenedOffset, 1, 2 + HardenedOffset},
+			key:  "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+		},
+		{
+			path: []uint32{HardenedOffset, 1, 2 + HardenedOffset, 2},
+			key:  "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+		},
+		{
+			path: []uint32{HardenedOffset, 1, 2 + HardenedOffset, 2, 1000000000},
+			key:  "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+	}
+	for _, test := range tests {
+		key, err := master.Derive(test.path)
+		assert.NoError(err)
+		assert.Equal(test.key, hex.EncodeToString(key.PrivateKey().Bytes()))
+	}
+}
+
+func TestSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	mnemonic, err := NewMnemonic()
+	assert.NoError(err)
+	seed, err := Seed(mnemonic)
+	assert.NoError(err)
+	assert.Len(seed, MaxSeedLen)
+
+	_, err = Seed("not a mnemonic")
+	assert.Error(err)
+
+	_, err = NewMaster(seed[:MinSeedLen-1])
+	assert.Error(err)
+}
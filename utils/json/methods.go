@@ -0,0 +1,55 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrMethodNotParsable is returned by RequestMethods if the request isn't a
+// JSON-RPC request
+var ErrMethodNotParsable = errors.New("couldn't parse the JSON-RPC method of the request")
+
+// rpcRequest is the part of a JSON-RPC request that names the called method
+type rpcRequest struct {
+	Method string `json:"method"`
+}
+
+// RequestMethods returns the JSON-RPC methods called by [r]. A batch request
+// returns the method of each of its calls. The body of [r] is replaced so that
+// it can be read again.
+func RequestMethods(r *http.Request) ([]string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// The body is decoded the same way the codec decodes it, so that bytes
+	// after the first JSON value are ignored here too.
+	var requests []rpcRequest
+	decoder := stdjson.NewDecoder(bytes.NewReader(body))
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = decoder.Decode(&requests)
+	} else {
+		requests = make([]rpcRequest, 1)
+		err = decoder.Decode(&requests[0])
+	}
+	if err != nil || len(requests) == 0 {
+		return nil, ErrMethodNotParsable
+	}
+
+	methods := make([]string, len(requests))
+	for i, request := range requests {
+		if request.Method == "" {
+			return nil, ErrMethodNotParsable
+		}
+		methods[i] = request.Method
+	}
+	return methods, nil
+}
@@ -0,0 +1,45 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMethods(t *testing.T) {
+	tests := []struct {
+		body    string
+		methods []string
+		err     error
+	}{
+		{`{"jsonrpc":"2.0","method":"info.getNodeID","id":1}`, []string{"info.getNodeID"}, nil},
+		{`[{"method":"info.peers"},{"method":"health.health"}]`, []string{"info.peers", "health.health"}, nil},
+		// The codec ignores the bytes after the first JSON value
+		{`{"method":"info.peers"} {"method":"admin.stopCPUProfiler"}`, []string{"info.peers"}, nil},
+		{`[{"method":"info.peers"}] garbage`, []string{"info.peers"}, nil},
+		{`{"method":`, nil, ErrMethodNotParsable},
+		{`[]`, nil, ErrMethodNotParsable},
+		{`{"id":1}`, nil, ErrMethodNotParsable},
+	}
+	for _, test := range tests {
+		t.Run(test.body, func(t *testing.T) {
+			assert := assert.New(t)
+			req := httptest.NewRequest(http.MethodPost, "/ext/info", strings.NewReader(test.body))
+
+			methods, err := RequestMethods(req)
+			assert.ErrorIs(err, test.err)
+			assert.Equal(test.methods, methods)
+
+			body, err := ioutil.ReadAll(req.Body)
+			assert.NoError(err)
+			assert.Equal(test.body, string(body))
+		})
+	}
+}
@@ -6,8 +6,6 @@ package password
 import (
 	"bytes"
 	"crypto/rand"
-
-	"golang.org/x/crypto/argon2"
 )
 
 // Hash of a password
@@ -18,18 +16,33 @@ type Hash struct {
 
 // Set updates the password hash to be of the provided password
 func (h *Hash) Set(password string) error {
+	return h.SetWithParams(password, HashParams)
+}
+
+// SetWithParams updates the password hash to be of the provided password,
+// hashed with [params]
+func (h *Hash) SetWithParams(password string, params KDFParams) error {
 	if _, err := rand.Read(h.Salt[:]); err != nil {
 		return err
 	}
 	// pw is the salted, hashed password
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
-	copy(h.Password[:], pw[:32])
+	pw, err := params.DeriveKey(password, h.Salt[:], uint32(len(h.Password)))
+	if err != nil {
+		return err
+	}
+	copy(h.Password[:], pw)
 	return nil
 }
 
 // Check returns true iff the provided password was the same as the last
 // password set.
 func (h *Hash) Check(password string) bool {
-	pw := argon2.IDKey([]byte(password), h.Salt[:], 1, 64*1024, 4, 32)
-	return bytes.Equal(pw, h.Password[:])
+	return h.CheckWithParams(password, HashParams)
+}
+
+// CheckWithParams returns true iff the provided password was the same as the
+// last password set, assuming it was hashed with [params].
+func (h *Hash) CheckWithParams(password string, params KDFParams) bool {
+	pw, err := params.DeriveKey(password, h.Salt[:], uint32(len(h.Password)))
+	return err == nil && bytes.Equal(pw, h.Password[:])
 }
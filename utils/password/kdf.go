@@ -0,0 +1,114 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package password
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// maxTime is the maximum number of passes an Argon2id derivation may make
+	maxTime = 16
+	// maxMemory is the maximum memory, in KiB, an Argon2id derivation may use
+	maxMemory = units.GiB / units.KiB
+)
+
+var (
+	// DefaultKDFParams are the parameters keys are derived with by default
+	DefaultKDFParams = KDFParams{
+		KDF:     Argon2id,
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	// HashParams are the parameters Set and Check compute a Hash with. They
+	// were used to hash every password before the parameters were stored.
+	HashParams = KDFParams{
+		KDF:     Argon2id,
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	errNoThreads = errors.New("argon2id must use at least 1 thread")
+)
+
+// KDF is a function that derives keys from passwords
+type KDF byte
+
+const (
+	// SHA256 derives a key by hashing the password, ignoring the salt. It's
+	// only supported to decrypt data that was encrypted before keys were
+	// derived with Argon2id.
+	SHA256 KDF = iota
+	// Argon2id derives a key with the Argon2id function
+	Argon2id
+)
+
+func (kdf KDF) String() string {
+	switch kdf {
+	case SHA256:
+		return "sha256"
+	case Argon2id:
+		return "argon2id"
+	default:
+		return fmt.Sprintf("unknown KDF %d", byte(kdf))
+	}
+}
+
+// KDFParams are the algorithm and cost of a key derivation
+type KDFParams struct {
+	KDF KDF `serialize:"true"`
+	// Number of passes over the memory. Only used by Argon2id.
+	Time uint32 `serialize:"true"`
+	// Memory used, in KiB. Only used by Argon2id.
+	Memory uint32 `serialize:"true"`
+	// Degree of parallelism. Only used by Argon2id.
+	Threads uint8 `serialize:"true"`
+}
+
+// Verify returns an error if keys can't be derived with [p], or if deriving
+// them would be unreasonably expensive
+func (p KDFParams) Verify() error {
+	switch p.KDF {
+	case SHA256:
+		return nil
+	case Argon2id:
+		switch {
+		case p.Threads == 0:
+			return errNoThreads
+		case p.Time == 0 || p.Time > maxTime:
+			return fmt.Errorf("argon2id time %d must be in [1, %d]", p.Time, maxTime)
+		case p.Memory < 8*uint32(p.Threads) || p.Memory > maxMemory:
+			return fmt.Errorf("argon2id memory %d KiB must be in [%d, %d]", p.Memory, 8*uint32(p.Threads), maxMemory)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown KDF %d", byte(p.KDF))
+	}
+}
+
+// DeriveKey returns a key of [keyLen] bytes derived from [password] and [salt]
+func (p KDFParams) DeriveKey(password string, salt []byte, keyLen uint32) ([]byte, error) {
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+	switch p.KDF {
+	case SHA256:
+		if keyLen != sha256.Size {
+			return nil, fmt.Errorf("sha256 derives keys of %d bytes but %d were requested", sha256.Size, keyLen)
+		}
+		key := sha256.Sum256([]byte(password))
+		return key[:], nil
+	default:
+		return argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyLen), nil
+	}
+}
@@ -0,0 +1,74 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package password
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestKDFParamsVerify(t *testing.T) {
+	tests := map[string]struct {
+		params KDFParams
+		valid  bool
+	}{
+		"default":        {params: DefaultKDFParams, valid: true},
+		"sha256":         {params: KDFParams{KDF: SHA256}, valid: true},
+		"unknown KDF":    {params: KDFParams{KDF: Argon2id + 1}},
+		"no threads":     {params: KDFParams{KDF: Argon2id, Time: 1, Memory: 64 * 1024}},
+		"no time":        {params: KDFParams{KDF: Argon2id, Memory: 64 * 1024, Threads: 4}},
+		"too much time":  {params: KDFParams{KDF: Argon2id, Time: maxTime + 1, Memory: 64 * 1024, Threads: 4}},
+		"too little mem": {params: KDFParams{KDF: Argon2id, Time: 1, Memory: 31, Threads: 4}},
+		"too much mem":   {params: KDFParams{KDF: Argon2id, Time: 1, Memory: maxMemory + 1, Threads: 4}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.params.Verify()
+			if test.valid && err != nil {
+				t.Fatalf("Should have been valid but got: %s", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatalf("Should have been invalid")
+			}
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	salt := []byte("salt")
+	key, err := DefaultKDFParams.DeriveKey("password", salt, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameKey, err := DefaultKDFParams.DeriveKey("password", salt, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, sameKey) {
+		t.Fatalf("Should have derived the same key")
+	}
+
+	otherParams := DefaultKDFParams
+	otherParams.Time++
+	otherKey, err := otherParams.DeriveKey("password", salt, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(key, otherKey) {
+		t.Fatalf("Should have derived a different key")
+	}
+
+	legacyKey, err := KDFParams{KDF: SHA256}.DeriveKey("password", salt, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKey := sha256.Sum256([]byte("password"))
+	if !bytes.Equal(legacyKey, expectedKey[:]) {
+		t.Fatalf("Should have hashed the password")
+	}
+	if _, err := (KDFParams{KDF: SHA256}).DeriveKey("password", salt, 16); err == nil {
+		t.Fatalf("Should have errored due to the key length")
+	}
+}
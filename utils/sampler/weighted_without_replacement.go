@@ -9,6 +9,9 @@ package sampler
 type WeightedWithoutReplacement interface {
 	Initialize(weights []uint64) error
 	Sample(count int) ([]int, error)
+
+	Seed(int64)
+	ClearSeed()
 }
 
 // NewWeightedWithoutReplacement returns a new sampler
@@ -28,3 +31,12 @@ func NewBestWeightedWithoutReplacement(
 		w: NewWeighted(),
 	}
 }
+
+// NewDeterministicWeightedWithoutReplacement returns a new sampler that will
+// always return the same samples when provided the same weights and seed.
+func NewDeterministicWeightedWithoutReplacement() WeightedWithoutReplacement {
+	return &weightedWithoutReplacementGeneric{
+		u: NewUniform(),
+		w: &weightedHeap{},
+	}
+}
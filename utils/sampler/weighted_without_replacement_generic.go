@@ -27,6 +27,10 @@ func (s *weightedWithoutReplacementGeneric) Initialize(weights []uint64) error {
 	return s.w.Initialize(weights)
 }
 
+func (s *weightedWithoutReplacementGeneric) Seed(seed int64) { s.u.Seed(seed) }
+
+func (s *weightedWithoutReplacementGeneric) ClearSeed() { s.u.ClearSeed() }
+
 func (s *weightedWithoutReplacementGeneric) Sample(count int) ([]int, error) {
 	s.u.Reset()
 
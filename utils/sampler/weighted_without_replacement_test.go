@@ -33,6 +33,13 @@ var (
 				},
 			},
 		},
+		{
+			name: "generic with replacer and heap",
+			sampler: &weightedWithoutReplacementGeneric{
+				u: &uniformReplacer{},
+				w: &weightedHeap{},
+			},
+		},
 	}
 	weightedWithoutReplacementTests = []struct {
 		name string
@@ -66,6 +73,10 @@ var (
 			name: "distribution",
 			test: WeightedWithoutReplacementDistributionTest,
 		},
+		{
+			name: "seeded",
+			test: WeightedWithoutReplacementSeededTest,
+		},
 	}
 )
 
@@ -169,3 +180,25 @@ func WeightedWithoutReplacementDistributionTest(
 		"should have selected all the elements",
 	)
 }
+
+func WeightedWithoutReplacementSeededTest(
+	t *testing.T,
+	s WeightedWithoutReplacement,
+) {
+	weights := []uint64{1, 1, 2, 0, 4}
+	err := s.Initialize(weights)
+	assert.NoError(t, err)
+
+	s.Seed(0)
+	expectedIndices, err := s.Sample(5)
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		s.Seed(0)
+		indices, err := s.Sample(5)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedIndices, indices, "should have sampled the same indices with the same seed")
+	}
+
+	s.ClearSeed()
+}
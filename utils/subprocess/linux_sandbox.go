@@ -0,0 +1,315 @@
+// +build linux
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subprocess
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// cpuPeriod is the period, in microseconds, over which the CPU time of a
+	// subprocess is limited
+	cpuPeriod = 100000
+
+	// userHZ is the number of clock ticks per second CPU times are reported
+	// in by /proc. It's 100 on all the architectures Linux supports.
+	userHZ = 100
+
+	// Landlock's syscalls have the same number on all architectures
+	sysLandlockCreateRuleset = 444
+	sysLandlockAddRule       = 445
+	sysLandlockRestrictSelf  = 446
+
+	landlockRulePathBeneath = 1
+
+	// landlockWriteFile is the only right to modify the file system that
+	// applies to files rather than directories
+	landlockWriteFile = 1 << 1
+	// landlockWriteAccess are the rights to modify the file system that
+	// Landlock handles. Reading and executing files isn't restricted.
+	landlockWriteAccess = landlockWriteFile |
+		1<<4 | // remove directories
+		1<<5 | // remove files
+		1<<6 | // create character devices
+		1<<7 | // create directories
+		1<<8 | // create regular files
+		1<<9 | // create sockets
+		1<<10 | // create named pipes
+		1<<11 | // create block devices
+		1<<12 // create symbolic links
+)
+
+var (
+	errLandlockUnsupported = errors.New("landlock isn't supported by the kernel")
+	errNoCgroupDir         = errors.New("no cgroup to create the subprocess's cgroup under")
+)
+
+type landlockRulesetAttr struct {
+	handledAccessFS uint64
+}
+
+// landlockPathBeneathAttr is packed by the kernel, which only reads its first
+// 12 bytes
+type landlockPathBeneathAttr struct {
+	allowedAccess uint64
+	parentFD      int32
+}
+
+type sandbox struct {
+	name      string
+	limits    Limits
+	cgroupDir string
+	log       logging.Logger
+
+	pid int
+	// cgroup is the cgroup the subprocess was moved to, if any
+	cgroup string
+
+	// closed is closed when the subprocess has exited
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSandbox returns a sandbox that applies [limits] to the subprocess
+// [name]. Memory and CPU limits are applied by moving the subprocess to a new
+// cgroup under [cgroupDir], which must be managed by cgroups v2 and delegated
+// to this process. cgroups v2 doesn't allow limiting the children of a cgroup
+// that contains processes, so the cgroup of this process can't be used.
+func NewSandbox(name string, limits Limits, cgroupDir string, log logging.Logger) Sandbox {
+	return &sandbox{
+		name:      name,
+		limits:    limits,
+		cgroupDir: cgroupDir,
+		log:       log,
+		closed:    make(chan struct{}),
+	}
+}
+
+// Start calls [start] on a thread that's restricted to modifying files under
+// the allowed directories, as the subprocess inherits the restrictions of the
+// thread that starts it.
+func (s *sandbox) Start(start func() error) error {
+	if len(s.limits.AllowedDirs) == 0 {
+		return start()
+	}
+
+	// The null device is opened for writing when the subprocess's output
+	// isn't redirected
+	dirs := append([]string{os.DevNull, os.TempDir()}, s.limits.AllowedDirs...)
+	errs := make(chan error, 1)
+	go func() {
+		// The thread is never unlocked, so that it exits instead of running
+		// other goroutines with its restrictions. It's kept alive until the
+		// subprocess exits as the subprocess is signalled when the thread
+		// that started it exits.
+		runtime.LockOSThread()
+
+		switch err := restrictThread(dirs); {
+		case errors.Is(err, errLandlockUnsupported):
+			s.log.Warn("%s isn't restricted to its allowed directories: %s", s.name, err)
+		case err != nil:
+			errs <- fmt.Errorf("couldn't restrict %s to its allowed directories: %w", s.name, err)
+			return
+		}
+		errs <- start()
+		<-s.closed
+	}()
+	return <-errs
+}
+
+// Limit applies the limits with prlimit and by moving the subprocess to a new
+// cgroup. Neither can be done to the child between fork and exec without
+// also limiting this process, so the subprocess is unlimited from the time
+// it's started until Limit returns.
+func (s *sandbox) Limit(pid int) error {
+	s.pid = pid
+
+	if s.limits.MaxOpenFiles != 0 {
+		rlimit := unix.Rlimit{
+			Cur: s.limits.MaxOpenFiles,
+			Max: s.limits.MaxOpenFiles,
+		}
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &rlimit, nil); err != nil {
+			return fmt.Errorf("couldn't limit the open files of %s: %w", s.name, err)
+		}
+	}
+
+	if s.limits.MaxMemory == 0 && s.limits.CPUShare == 0 {
+		return nil
+	}
+	if err := s.limitCgroup(); err != nil {
+		return fmt.Errorf("couldn't limit the memory and CPU usage of %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// limitCgroup moves the subprocess to a new cgroup that limits its memory and
+// CPU usage
+func (s *sandbox) limitCgroup() error {
+	parent := s.cgroupDir
+	if parent == "" {
+		return errNoCgroupDir
+	}
+
+	cgroup := filepath.Join(parent, fmt.Sprintf("%s-%d", s.name, s.pid))
+	if err := os.Mkdir(cgroup, 0o755); err != nil {
+		return err
+	}
+	s.cgroup = cgroup
+
+	// The controllers may already be enabled, in which case this fails
+	_ = ioutil.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("+memory +cpu"), 0)
+
+	if s.limits.MaxMemory != 0 {
+		maxMemory := strconv.FormatUint(s.limits.MaxMemory, 10)
+		if err := ioutil.WriteFile(filepath.Join(cgroup, "memory.max"), []byte(maxMemory), 0); err != nil {
+			return err
+		}
+	}
+	if s.limits.CPUShare != 0 {
+		cpuMax := fmt.Sprintf("%d %d", int64(s.limits.CPUShare*cpuPeriod), cpuPeriod)
+		if err := ioutil.WriteFile(filepath.Join(cgroup, "cpu.max"), []byte(cpuMax), 0); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(filepath.Join(cgroup, "cgroup.procs"), []byte(strconv.Itoa(s.pid)), 0)
+}
+
+func (s *sandbox) Usage() (Usage, error) {
+	if s.pid == 0 {
+		return Usage{}, errUnsupported
+	}
+
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", s.pid))
+	if err != nil {
+		return Usage{}, err
+	}
+	// The name of the executable, in parentheses, may contain spaces, so
+	// fields are counted after it. The first of these fields is the 3rd.
+	end := bytes.LastIndexByte(stat, ')')
+	if end < 0 {
+		return Usage{}, fmt.Errorf("malformed stat of process %d", s.pid)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return Usage{}, fmt.Errorf("malformed stat of process %d", s.pid)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return Usage{}, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return Usage{}, err
+	}
+	rss, err := strconv.ParseUint(fields[21], 10, 64)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	fdDir, err := os.Open(fmt.Sprintf("/proc/%d/fd", s.pid))
+	if err != nil {
+		return Usage{}, err
+	}
+	fds, err := fdDir.Readdirnames(-1)
+	_ = fdDir.Close()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		Memory:    rss * uint64(os.Getpagesize()),
+		CPUTime:   time.Duration(utime+stime) * time.Second / userHZ,
+		OpenFiles: len(fds),
+	}, nil
+}
+
+func (s *sandbox) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		if s.cgroup != "" {
+			if err := os.Remove(s.cgroup); err != nil {
+				s.log.Debug("couldn't remove the cgroup of %s: %s", s.name, err)
+			}
+		}
+	})
+}
+
+// restrictThread restricts the calling thread, and the processes it starts,
+// to modifying files under [dirs]. Files may also be passed, in which case
+// they may only be written to.
+func restrictThread(dirs []string) error {
+	rulesetAttr := landlockRulesetAttr{
+		handledAccessFS: landlockWriteAccess,
+	}
+	rulesetFD, _, errno := unix.Syscall(
+		sysLandlockCreateRuleset,
+		uintptr(unsafe.Pointer(&rulesetAttr)),
+		unsafe.Sizeof(rulesetAttr),
+		0,
+	)
+	switch errno {
+	case 0:
+	case unix.ENOSYS, unix.EOPNOTSUPP:
+		return errLandlockUnsupported
+	default:
+		return errno
+	}
+	defer unix.Close(int(rulesetFD))
+
+	for _, dir := range dirs {
+		dirFD, err := unix.Open(dir, unix.O_PATH|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return fmt.Errorf("couldn't open %s: %w", dir, err)
+		}
+		var stat unix.Stat_t
+		if err := unix.Fstat(dirFD, &stat); err != nil {
+			_ = unix.Close(dirFD)
+			return fmt.Errorf("couldn't stat %s: %w", dir, err)
+		}
+		pathAttr := landlockPathBeneathAttr{
+			allowedAccess: landlockWriteAccess,
+			parentFD:      int32(dirFD),
+		}
+		if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+			pathAttr.allowedAccess = landlockWriteFile
+		}
+		_, _, errno := unix.Syscall6(
+			sysLandlockAddRule,
+			rulesetFD,
+			landlockRulePathBeneath,
+			uintptr(unsafe.Pointer(&pathAttr)),
+			0, 0, 0,
+		)
+		_ = unix.Close(dirFD)
+		if errno != 0 {
+			return fmt.Errorf("couldn't allow %s: %w", dir, errno)
+		}
+	}
+
+	// Required to restrict a thread without privileges
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return err
+	}
+	if _, _, errno := unix.Syscall(sysLandlockRestrictSelf, rulesetFD, 0, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// +build linux
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subprocess
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestSandboxLimitMemoryWithoutCgroupDir(t *testing.T) {
+	assert := assert.New(t)
+
+	sb := NewSandbox("test", Limits{MaxMemory: 1 << 30}, "", logging.NoLog{})
+	defer sb.Close()
+
+	cmd := New("sleep", "10")
+	assert.NoError(sb.Start(cmd.Start))
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	assert.ErrorIs(sb.Limit(cmd.Process.Pid), errNoCgroupDir)
+}
+
+func TestSandboxLimitOpenFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	sb := NewSandbox("test", Limits{MaxOpenFiles: 64}, "", logging.NoLog{})
+	defer sb.Close()
+
+	cmd := New("sleep", "10")
+	assert.NoError(sb.Start(cmd.Start))
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	assert.NoError(sb.Limit(cmd.Process.Pid))
+
+	limits, err := ioutil.ReadFile(filepath.Join("/proc", strconv.Itoa(cmd.Process.Pid), "limits"))
+	assert.NoError(err)
+	for _, line := range strings.Split(string(limits), "\n") {
+		if strings.HasPrefix(line, "Max open files") {
+			assert.Equal([]string{"Max", "open", "files", "64", "64", "files"}, strings.Fields(line))
+		}
+	}
+
+	usage, err := sb.Usage()
+	assert.NoError(err)
+	assert.NotZero(usage.OpenFiles)
+}
+
+func TestSandboxAllowedDirs(t *testing.T) {
+	assert := assert.New(t)
+
+	if !landlockSupported() {
+		t.Skip("landlock isn't supported")
+	}
+
+	// The directories are created outside of the temporary directory, which
+	// the subprocess may always modify
+	allowedDir, err := ioutil.TempDir(".", "allowed")
+	assert.NoError(err)
+	defer os.RemoveAll(allowedDir)
+	deniedDir, err := ioutil.TempDir(".", "denied")
+	assert.NoError(err)
+	defer os.RemoveAll(deniedDir)
+
+	absAllowedDir, err := filepath.Abs(allowedDir)
+	assert.NoError(err)
+	sb := NewSandbox("test", Limits{AllowedDirs: []string{absAllowedDir}}, "", logging.NoLog{})
+	defer sb.Close()
+
+	touch := func(dir string) error {
+		cmd := New("touch", filepath.Join(dir, "file"))
+		if err := sb.Start(cmd.Start); err != nil {
+			return err
+		}
+		return cmd.Wait()
+	}
+	assert.NoError(touch(allowedDir))
+	assert.Error(touch(deniedDir))
+
+	// This process isn't restricted
+	assert.NoError(ioutil.WriteFile(filepath.Join(deniedDir, "file"), nil, 0o600))
+}
+
+// landlockSupported restricts a throwaway thread to report whether landlock
+// is supported
+func landlockSupported() bool {
+	supported := make(chan bool)
+	go func() {
+		runtime.LockOSThread()
+		err := restrictThread([]string{os.TempDir()})
+		supported <- err == nil
+	}()
+	return <-supported
+}
@@ -0,0 +1,44 @@
+// +build !linux
+
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subprocess
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type sandbox struct {
+	limits Limits
+	log    logging.Logger
+}
+
+// NewSandbox returns a sandbox that applies [limits] to the subprocess
+// [name]. Resource limits are only supported on Linux, so subprocesses with
+// resource limits fail to be limited.
+func NewSandbox(name string, limits Limits, _ string, log logging.Logger) Sandbox {
+	return &sandbox{
+		limits: limits,
+		log:    log,
+	}
+}
+
+func (s *sandbox) Start(start func() error) error {
+	if len(s.limits.AllowedDirs) != 0 {
+		s.log.Warn("allowed directories aren't supported on this platform")
+	}
+	return start()
+}
+
+func (s *sandbox) Limit(int) error {
+	if s.limits.MaxMemory != 0 || s.limits.CPUShare != 0 || s.limits.MaxOpenFiles != 0 {
+		return fmt.Errorf("couldn't apply resource limits: %w", errUnsupported)
+	}
+	return nil
+}
+
+func (s *sandbox) Usage() (Usage, error) { return Usage{}, errUnsupported }
+
+func (s *sandbox) Close() {}
@@ -0,0 +1,61 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subprocess
+
+import (
+	"errors"
+	"time"
+)
+
+var errUnsupported = errors.New("not supported on this platform")
+
+// Limits are the resources a subprocess may use. Zero values mean no limit.
+type Limits struct {
+	// Max bytes of memory the subprocess may use
+	MaxMemory uint64 `json:"maxMemory"`
+	// Number of CPUs the subprocess may use. e.g. 0.5 allows the subprocess
+	// to use half of a CPU.
+	CPUShare float64 `json:"cpuShare"`
+	// Max number of files the subprocess may have open
+	MaxOpenFiles uint64 `json:"maxOpenFiles"`
+	// If non-empty, the subprocess may only modify files under these
+	// directories and the temporary directory
+	AllowedDirs []string `json:"allowedDirs"`
+}
+
+// IsEmpty returns true if no limit is set
+func (l Limits) IsEmpty() bool {
+	return l.MaxMemory == 0 && l.CPUShare == 0 && l.MaxOpenFiles == 0 && len(l.AllowedDirs) == 0
+}
+
+// Usage is the resources a subprocess is using
+type Usage struct {
+	// Bytes of memory resident in RAM
+	Memory uint64
+	// Time spent running on CPUs, in user and kernel mode
+	CPUTime time.Duration
+	// Number of open files
+	OpenFiles int
+}
+
+// Sandbox applies limits to a subprocess and reports its resource usage.
+// Limits that can't be applied on this platform are logged and skipped.
+type Sandbox interface {
+	// Start calls [start], which must start the subprocess. Restrictions on
+	// the files the subprocess may modify are applied when it's started.
+	Start(start func() error) error
+
+	// Limit applies the limits on memory, CPU and open files to the started
+	// subprocess with ID [pid]. The subprocess isn't limited until then, so
+	// it should be called as soon as the subprocess is started and before
+	// it's given work.
+	Limit(pid int) error
+
+	// Usage returns the resources the subprocess is using
+	Usage() (Usage, error)
+
+	// Close releases what's held to limit the subprocess. It must be called
+	// once the subprocess has exited.
+	Close()
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"math"
+	"net"
 
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -291,7 +292,12 @@ func (p *Packer) UnpackStr() string {
 
 // PackIP packs an ip port pair to the byte array
 func (p *Packer) PackIP(ip utils.IPDesc) {
-	p.PackFixedBytes(ip.IP.To16())
+	ipBytes := ip.IP.To16()
+	if ipBytes == nil {
+		// An IP that isn't set is packed as the unspecified address
+		ipBytes = net.IPv6unspecified
+	}
+	p.PackFixedBytes(ipBytes)
 	p.PackShort(ip.Port)
 }
 
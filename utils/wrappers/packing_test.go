@@ -629,6 +629,18 @@ func TestPackIPCert(t *testing.T) {
 	assert.Equal(t, ipCert.Signature, resolvedUnpackedIPCert.Signature)
 }
 
+func TestPackEmptyIP(t *testing.T) {
+	p := Packer{MaxSize: IPLen}
+	p.PackIP(utils.IPDesc{})
+	assert.NoError(t, p.Err)
+	assert.Len(t, p.Bytes, IPLen)
+
+	p.Offset = 0
+	ip := p.UnpackIP()
+	assert.NoError(t, p.Err)
+	assert.True(t, ip.IsZero())
+}
+
 func TestPackIPCertList(t *testing.T) {
 	cert, err := staking.NewTLSCert()
 	assert.NoError(t, err)
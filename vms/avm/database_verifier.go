@@ -0,0 +1,35 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var _ common.DatabaseVerifier = &VM{}
+
+// VerifyDatabase implements the common.DatabaseVerifier interface
+func (vm *VM) VerifyDatabase() ([]string, error) {
+	return avax.VerifyUTXOState(newUTXODB(vm.db), vm.codec)
+}
+
+// VerifyDatabase checks the state of an AVM that isn't running. [db] is the
+// database the VM was given and [fxs] are the fxs the VM was created with.
+// Returns a description of each inconsistency found. [db] isn't modified.
+func VerifyDatabase(db database.Database, fxs []*common.Fx) ([]string, error) {
+	// The state is stored under a versiondb, as it is by Initialize
+	vm := &VM{
+		ctx: &snow.Context{Log: logging.NoLog{}},
+		db:  versiondb.New(db),
+	}
+	if err := vm.initCodecs(fxs); err != nil {
+		return nil, err
+	}
+	return vm.VerifyDatabase()
+}
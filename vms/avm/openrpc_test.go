@@ -0,0 +1,23 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api/openrpc"
+)
+
+func TestOpenRPCDocument(t *testing.T) {
+	openrpc.VerifyGolden(t, filepath.Join("testdata", "openrpc.json"), openrpc.Service{Receiver: &Service{}, Name: "avm"})
+}
+
+func TestWalletOpenRPCDocument(t *testing.T) {
+	openrpc.VerifyGolden(t, filepath.Join("testdata", "wallet_openrpc.json"), openrpc.Service{Receiver: &WalletService{}, Name: "wallet"})
+}
+
+func TestStaticOpenRPCDocument(t *testing.T) {
+	openrpc.VerifyGolden(t, filepath.Join("testdata", "static_openrpc.json"), openrpc.Service{Receiver: &StaticService{}, Name: "avm"})
+}
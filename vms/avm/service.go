@@ -11,13 +11,16 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/hdkeys"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -722,12 +725,10 @@ func (service *Service) CreateAddress(r *http.Request, args *api.UserPass, reply
 		return fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
 	}
 
-	factory := crypto.FactorySECP256K1R{}
-	skIntf, err := factory.NewPrivateKey()
+	sk, err := hdkeys.NewKey(service.vm.ctx.Keystore, db, args.Username, args.Password, hdwallet.AvalancheCoinType)
 	if err != nil {
 		return fmt.Errorf("problem generating private key: %w", err)
 	}
-	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
 	if err := user.SetKey(db, sk); err != nil {
 		return fmt.Errorf("problem saving private key: %w", err)
@@ -748,6 +749,78 @@ func (service *Service) CreateAddress(r *http.Request, args *api.UserPass, reply
 	return db.Close()
 }
 
+// DiscoverAddressesArgs are arguments for DiscoverAddresses
+type DiscoverAddressesArgs struct {
+	api.UserPass
+	// Number of consecutive addresses without UTXOs after which discovery
+	// stops. Defaults to 20.
+	GapLimit json.Uint32 `json:"gapLimit"`
+}
+
+// DiscoverAddresses adds to user [args.Username] the addresses derived from
+// their mnemonic that hold UTXOs, along with the unused addresses before the
+// last of them. The next address created for the user follows them.
+func (service *Service) DiscoverAddresses(_ *http.Request, args *DiscoverAddressesArgs, reply *api.JSONAddresses) error {
+	service.vm.ctx.Log.Debug("AVM: DiscoverAddresses called for user '%s'", args.Username)
+
+	seed, err := service.vm.ctx.Keystore.GetSeed(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving seed: %w", err)
+	}
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	kc, err := hdkeys.New(db, seed, hdwallet.AvalancheCoinType)
+	if err != nil {
+		return err
+	}
+
+	user := userState{vm: service.vm}
+	addresses, err := user.Addresses(db)
+	if err != nil && err != database.ErrNotFound {
+		return fmt.Errorf("problem retrieving addresses: %w", err)
+	}
+	addressSet := ids.ShortSet{}
+	addressSet.Add(addresses...)
+
+	gapLimit := int(args.GapLimit)
+	if gapLimit == 0 {
+		gapLimit = hdkeys.DefaultGapLimit
+	}
+	keys, err := kc.Discover(gapLimit, maxKeystoreAddresses-len(addresses), func(addr ids.ShortID) (bool, error) {
+		utxoIDs, err := service.vm.state.UTXOIDs(addr.Bytes(), ids.Empty, 1)
+		return len(utxoIDs) > 0, err
+	})
+	if err != nil {
+		return fmt.Errorf("problem discovering addresses: %w", err)
+	}
+
+	reply.Addresses = make([]string, 0, len(keys))
+	for _, sk := range keys {
+		addr := sk.PublicKey().Address()
+		addrStr, err := service.vm.FormatLocalAddress(addr)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		reply.Addresses = append(reply.Addresses, addrStr)
+		if addressSet.Contains(addr) {
+			continue
+		}
+		if err := user.SetKey(db, sk); err != nil {
+			return fmt.Errorf("problem saving private key: %w", err)
+		}
+		addresses = append(addresses, addr)
+		addressSet.Add(addr)
+	}
+	if err := user.SetAddresses(db, addresses); err != nil {
+		return fmt.Errorf("problem saving addresses: %w", err)
+	}
+	return db.Close()
+}
+
 // ListAddresses returns all of the addresses controlled by user [args.Username]
 func (service *Service) ListAddresses(_ *http.Request, args *api.UserPass, response *api.JSONAddresses) error {
 	service.vm.ctx.Log.Debug("AVM: ListAddresses called for user '%s'", args.Username)
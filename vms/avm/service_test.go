@@ -16,18 +16,22 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/hdkeys"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
@@ -2630,6 +2634,76 @@ func TestCreateAndListAddresses(t *testing.T) {
 	t.Fatalf("Failed to find newly created address among %d addresses", len(listReply.Addresses))
 }
 
+func TestCreateAndDiscoverHDAddresses(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, vm, s, _, _ := setup(t, true)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	mnemonic, err := hdwallet.NewMnemonic()
+	assert.NoError(err)
+	seed, err := hdwallet.Seed(mnemonic)
+	assert.NoError(err)
+
+	userKeystore, err := keystore.CreateTestKeystore()
+	assert.NoError(err)
+	assert.NoError(userKeystore.CreateUser(username, password))
+	assert.NoError(userKeystore.ImportMnemonic(username, password, mnemonic))
+	vm.ctx.Keystore = userKeystore.NewBlockchainKeyStore(vm.ctx.ChainID)
+
+	kc, err := hdkeys.New(memdb.New(), seed, hdwallet.AvalancheCoinType)
+	assert.NoError(err)
+	hdAddrs := make([]string, 4)
+	for i := range hdAddrs {
+		key, err := kc.Key(uint32(i))
+		assert.NoError(err)
+		hdAddrs[i], err = vm.FormatLocalAddress(key.PublicKey().Address())
+		assert.NoError(err)
+	}
+
+	user := &api.UserPass{
+		Username: username,
+		Password: password,
+	}
+	createReply := &api.JSONAddress{}
+	assert.NoError(s.CreateAddress(nil, user, createReply))
+	assert.Equal(hdAddrs[0], createReply.Address)
+
+	// Give the address at index 2 a UTXO, as if it were used on another node
+	key, err := kc.Key(2)
+	assert.NoError(err)
+	genesisTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: genesisTx.ID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{key.PublicKey().Address()},
+			},
+		},
+	}
+	assert.NoError(vm.state.PutUTXO(utxo.InputID(), utxo))
+
+	discoverReply := &api.JSONAddresses{}
+	assert.NoError(s.DiscoverAddresses(nil, &DiscoverAddressesArgs{UserPass: *user}, discoverReply))
+	assert.Equal(hdAddrs[:3], discoverReply.Addresses)
+
+	listReply := &api.JSONAddresses{}
+	assert.NoError(s.ListAddresses(nil, user, listReply))
+	assert.Equal(hdAddrs[:3], listReply.Addresses)
+
+	// The next address follows the discovered ones
+	assert.NoError(s.CreateAddress(nil, user, createReply))
+	assert.Equal(hdAddrs[3], createReply.Address)
+}
+
 func TestImport(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
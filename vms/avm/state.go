@@ -45,8 +45,14 @@ type state struct {
 	uniqueTxs cache.Deduplicator
 }
 
+// newUTXODB returns the database that the UTXOs of a State created with [db]
+// are stored in
+func newUTXODB(db database.Database) database.Database {
+	return prefixdb.New(utxoStatePrefix, db)
+}
+
 func NewState(db database.Database, genesisCodec, codec codec.Manager) State {
-	utxoDB := prefixdb.New(utxoStatePrefix, db)
+	utxoDB := newUTXODB(db)
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
@@ -64,7 +70,7 @@ func NewState(db database.Database, genesisCodec, codec codec.Manager) State {
 }
 
 func NewMeteredState(db database.Database, genesisCodec, codec codec.Manager, namespace string, metrics prometheus.Registerer) (State, error) {
-	utxoDB := prefixdb.New(utxoStatePrefix, db)
+	utxoDB := newUTXODB(db)
 	statusDB := prefixdb.New(statusStatePrefix, db)
 	singletonDB := prefixdb.New(singletonStatePrefix, db)
 	txDB := prefixdb.New(txStatePrefix, db)
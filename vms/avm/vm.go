@@ -14,6 +14,7 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
@@ -113,7 +114,7 @@ type VM struct {
 	walletService WalletService
 }
 
-func (vm *VM) Connected(id ids.ShortID) error {
+func (vm *VM) Connected(id ids.ShortID, nodeVersion version.Application) error {
 	return nil // noop
 }
 
@@ -148,17 +149,51 @@ func (vm *VM) Initialize(
 	vm.toEngine = toEngine
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
-	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.assetToFxCache = &cache.LRU{Size: assetToFxCacheSize}
 
 	vm.pubsub = pubsub.New(ctx.NetworkID, ctx.Log)
 
+	if err := vm.initCodecs(fxs); err != nil {
+		return err
+	}
+	vm.AtomicUTXOManager = avax.NewAtomicUTXOManager(ctx.SharedMemory, vm.codec)
+
+	state, err := NewMeteredState(vm.db, vm.genesisCodec, vm.codec, ctx.Namespace, ctx.Metrics)
+	if err != nil {
+		return err
+	}
+	vm.state = state
+
+	if err := vm.initGenesis(genesisBytes); err != nil {
+		return err
+	}
+
+	vm.timer = timer.NewTimer(func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		vm.FlushTxs()
+	})
+	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
+	vm.batchTimeout = batchTimeout
+
+	vm.walletService.vm = vm
+	vm.walletService.pendingTxMap = make(map[ids.ID]*list.Element)
+	vm.walletService.pendingTxOrdering = list.New()
+
+	return vm.db.Commit()
+}
+
+// initCodecs registers the VM's transaction types and the types of [fxs] with
+// the VM's codecs and initializes [fxs].
+func (vm *VM) initCodecs(fxs []*common.Fx) error {
+	vm.typeToFxIndex = map[reflect.Type]int{}
+
 	genesisCodec := linearcodec.New(reflectcodec.DefaultTagName, 1<<20)
 	c := linearcodec.NewDefault()
 
 	vm.genesisCodec = codec.NewManager(math.MaxInt32)
 	vm.codec = codec.NewDefaultManager()
-	vm.AtomicUTXOManager = avax.NewAtomicUTXOManager(ctx.SharedMemory, vm.codec)
 
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -202,31 +237,7 @@ func (vm *VM) Initialize(
 			return err
 		}
 	}
-
-	state, err := NewMeteredState(vm.db, vm.genesisCodec, vm.codec, ctx.Namespace, ctx.Metrics)
-	if err != nil {
-		return err
-	}
-	vm.state = state
-
-	if err := vm.initGenesis(genesisBytes); err != nil {
-		return err
-	}
-
-	vm.timer = timer.NewTimer(func() {
-		ctx.Lock.Lock()
-		defer ctx.Lock.Unlock()
-
-		vm.FlushTxs()
-	})
-	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
-	vm.batchTimeout = batchTimeout
-
-	vm.walletService.vm = vm
-	vm.walletService.pendingTxMap = make(map[ids.ID]*list.Element)
-	vm.walletService.pendingTxOrdering = list.New()
-
-	return vm.db.Commit()
+	return nil
 }
 
 // Bootstrapping is called by the consensus engine when it starts bootstrapping
@@ -282,7 +293,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	rpcServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
 	rpcServer.RegisterAfterFunc(vm.metrics.apiRequestMetric.AfterRequest)
 	// name this service "avm"
-	if err := rpcServer.RegisterService(&Service{vm: vm}, "avm"); err != nil {
+	if err := openrpc.RegisterServices(rpcServer, openrpc.Service{Receiver: &Service{vm: vm}, Name: "avm"}); err != nil {
 		return nil, err
 	}
 
@@ -292,7 +303,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	walletServer.RegisterInterceptFunc(vm.metrics.apiRequestMetric.InterceptRequest)
 	walletServer.RegisterAfterFunc(vm.metrics.apiRequestMetric.AfterRequest)
 	// name this service "wallet"
-	err := walletServer.RegisterService(&vm.walletService, "wallet")
+	err := openrpc.RegisterServices(walletServer, openrpc.Service{Receiver: &vm.walletService, Name: "wallet"})
 
 	return map[string]*common.HTTPHandler{
 		"":        {Handler: rpcServer},
@@ -312,7 +323,7 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 	staticService := CreateStaticService()
 	return map[string]*common.HTTPHandler{
 		"": {LockOptions: common.WriteLock, Handler: newServer},
-	}, newServer.RegisterService(staticService, "avm")
+	}, openrpc.RegisterServices(newServer, openrpc.Service{Receiver: staticService, Name: "avm"})
 }
 
 // Pending implements the avalanche.DAGVM interface
@@ -1562,3 +1562,75 @@ func TestTxVerifyAfterVerifyAncestorTx(t *testing.T) {
 		t.Fatalf("Should have errored due to a missing UTXO")
 	}
 }
+
+func TestVerifyDatabase(t *testing.T) {
+	_, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	findings, err := vm.VerifyDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+
+	fxs := []*common.Fx{
+		{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		},
+		{
+			ID: nftfx.ID,
+			Fx: &nftfx.Fx{},
+		},
+	}
+	findings, err = VerifyDatabase(vm.baseDB, fxs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+
+	// Storing a UTXO under the wrong ID should be reported
+	addrsSet := ids.ShortSet{}
+	addrsSet.Add(addrs[0])
+	utxos, err := vm.getAllUTXOs(addrsSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) == 0 {
+		t.Fatal("expected the genesis to contain UTXOs")
+	}
+	if err := vm.state.PutUTXO(ids.GenerateTestID(), utxos[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err = vm.VerifyDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding but got: %v", findings)
+	}
+
+	// Once committed, the inconsistency is found in the database of a VM that
+	// isn't running
+	if err := vm.db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	findings, err = VerifyDatabase(vm.baseDB, fxs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding but got: %v", findings)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -95,3 +96,61 @@ func TestUTXOState(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal([]ids.ID{utxoID}, utxoIDs)
 }
+
+func TestVerifyUTXOState(t *testing.T) {
+	assert := assert.New(t)
+	addr := ids.GenerateTestShortID()
+	utxo := &UTXO{
+		UTXOID: UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 0,
+		},
+		Asset: Asset{ID: ids.GenerateTestID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 12345,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	utxoID := utxo.InputID()
+
+	c := linearcodec.NewDefault()
+	manager := codec.NewDefaultManager()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&secp256k1fx.MintOutput{}),
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		manager.RegisterCodec(codecVersion, c),
+	)
+	assert.NoError(errs.Err)
+
+	db := memdb.New()
+	s := NewUTXOState(db, manager)
+	assert.NoError(s.PutUTXO(utxoID, utxo))
+
+	findings, err := VerifyUTXOState(db, manager)
+	assert.NoError(err)
+	assert.Empty(findings)
+
+	// Removing the UTXO without removing it from the index should be reported
+	utxoDB := prefixdb.New(utxoPrefix, db)
+	utxoBytes, err := utxoDB.Get(utxoID[:])
+	assert.NoError(err)
+	assert.NoError(utxoDB.Delete(utxoID[:]))
+
+	findings, err = VerifyUTXOState(db, manager)
+	assert.NoError(err)
+	assert.Len(findings, 1)
+
+	// Removing the UTXO from the index should be reported
+	assert.NoError(utxoDB.Put(utxoID[:], utxoBytes))
+	indexDB := prefixdb.New(indexPrefix, db)
+	assert.NoError(getIndexList(indexDB, addr[:]).Delete(utxoID[:]))
+
+	findings, err = VerifyUTXOState(db, manager)
+	assert.NoError(err)
+	assert.Len(findings, 1)
+}
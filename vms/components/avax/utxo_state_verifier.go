@@ -0,0 +1,160 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avax
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/linkeddb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// VerifyUTXOState checks that the address indices of the UTXO state stored in
+// [db] match the UTXOs it contains. [db] is the database that was passed to
+// NewUTXOState. Returns a description of each inconsistency found. [db] isn't
+// modified.
+func VerifyUTXOState(db database.Database, codec codec.Manager) ([]string, error) {
+	utxoDB := prefixdb.New(utxoPrefix, db)
+	indexDB := prefixdb.New(indexPrefix, db)
+
+	var findings []string
+	// addresses that own at least one UTXO
+	addresses := make(map[string]struct{})
+
+	utxoIt := utxoDB.NewIterator()
+	defer utxoIt.Release()
+	for utxoIt.Next() {
+		utxoID, err := ids.ToID(utxoIt.Key())
+		if err != nil {
+			findings = append(findings, fmt.Sprintf("UTXO has malformed ID 0x%x", utxoIt.Key()))
+			continue
+		}
+		utxo := &UTXO{}
+		if _, err := codec.Unmarshal(utxoIt.Value(), utxo); err != nil {
+			findings = append(findings, fmt.Sprintf("UTXO %s couldn't be parsed: %s", utxoID, err))
+			continue
+		}
+		if inputID := utxo.InputID(); inputID != utxoID {
+			findings = append(findings, fmt.Sprintf("UTXO %s is stored under the wrong ID %s", inputID, utxoID))
+		}
+
+		addressable, ok := utxo.Out.(Addressable)
+		if !ok {
+			continue
+		}
+		for _, addr := range addressable.Addresses() {
+			addresses[string(addr)] = struct{}{}
+			has, err := getIndexList(indexDB, addr).Has(utxoID[:])
+			if err != nil {
+				return nil, err
+			}
+			if !has {
+				findings = append(findings, fmt.Sprintf("UTXO %s is missing from the index of address %s", utxoID, formatAddress(addr)))
+			}
+		}
+	}
+	if err := utxoIt.Error(); err != nil {
+		return nil, err
+	}
+
+	// The number of keys in [indexDB] that are accounted for by the addresses
+	// that own UTXOs
+	expectedIndexKeys := 0
+	for addrStr := range addresses {
+		addr := []byte(addrStr)
+		numEntries := 0
+
+		it := getIndexList(indexDB, addr).NewIterator()
+		for it.Next() {
+			numEntries++
+
+			utxoID, err := ids.ToID(it.Key())
+			if err != nil {
+				findings = append(findings, fmt.Sprintf("index of address %s contains malformed UTXO ID 0x%x", formatAddress(addr), it.Key()))
+				continue
+			}
+			owned, err := isOwnedBy(utxoDB, codec, utxoID, addr)
+			if err != nil {
+				it.Release()
+				return nil, err
+			}
+			if !owned {
+				findings = append(findings, fmt.Sprintf("index of address %s contains UTXO %s that the address doesn't own", formatAddress(addr), utxoID))
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return nil, err
+		}
+
+		if numEntries > 0 {
+			// Each non-empty list also stores its head key.
+			expectedIndexKeys += numEntries + 1
+		}
+	}
+
+	indexKeys, err := countKeys(indexDB)
+	if err != nil {
+		return nil, err
+	}
+	if indexKeys > expectedIndexKeys {
+		findings = append(findings, fmt.Sprintf("index contains %d entries for addresses that don't own any UTXOs", indexKeys-expectedIndexKeys))
+	}
+	return findings, nil
+}
+
+func getIndexList(indexDB database.Database, addr []byte) linkeddb.LinkedDB {
+	return linkeddb.NewDefault(prefixdb.NewNested(addr, indexDB))
+}
+
+// isOwnedBy returns true if the UTXO [utxoID] exists in [utxoDB] and [addr] is
+// one of its addresses
+func isOwnedBy(utxoDB database.Database, codec codec.Manager, utxoID ids.ID, addr []byte) (bool, error) {
+	utxoBytes, err := utxoDB.Get(utxoID[:])
+	if err == database.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	utxo := &UTXO{}
+	if _, err := codec.Unmarshal(utxoBytes, utxo); err != nil {
+		// The parsing error was already reported when iterating over the
+		// UTXOs.
+		return true, nil
+	}
+	addressable, ok := utxo.Out.(Addressable)
+	if !ok {
+		return false, nil
+	}
+	for _, owner := range addressable.Addresses() {
+		if bytes.Equal(owner, addr) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func countKeys(db database.Iteratee) (int, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	numKeys := 0
+	for it.Next() {
+		numKeys++
+	}
+	return numKeys, it.Error()
+}
+
+func formatAddress(addr []byte) string {
+	if shortID, err := ids.ToShortID(addr); err == nil {
+		return shortID.String()
+	}
+	return fmt.Sprintf("0x%x", addr)
+}
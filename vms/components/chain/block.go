@@ -4,10 +4,15 @@
 package chain
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/vms/components/missing"
 )
 
+var _ block.TimedBlock = &BlockWrapper{}
+
 // BlockWrapper wraps a snowman Block while adding a smart caching layer to improve
 // VM performance.
 type BlockWrapper struct {
@@ -66,3 +71,7 @@ func (bw *BlockWrapper) Parent() snowman.Block {
 	}
 	return &missing.Block{BlkID: parentID}
 }
+
+// Timestamp returns the time the underlying block was produced at, or the zero
+// time if the underlying block doesn't know it.
+func (bw *BlockWrapper) Timestamp() time.Time { return block.Timestamp(bw.Block) }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -141,7 +142,7 @@ func (svm *SnowmanVM) NewHandler(name string, service interface{}, lockOption ..
 	server := rpc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
-	if err := server.RegisterService(service, name); err != nil {
+	if err := openrpc.RegisterServices(server, openrpc.Service{Receiver: service, Name: name}); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,170 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hdkeys
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
+)
+
+// DefaultGapLimit is the number of consecutive unused addresses after which
+// discovery stops, as recommended by BIP-44
+const DefaultGapLimit = 20
+
+var (
+	// nextIndexKey is the key, in a user's database on a chain, of the index
+	// of the next key to derive for the user
+	nextIndexKey = []byte("hdNextIndex")
+
+	errIndexExhausted = errors.New("all non-hardened indices were derived")
+)
+
+// Keychain derives the keys of a keystore user on a chain from their seed,
+// along the BIP-44 derivation path of the chain's coin type, and tracks the
+// index of the next key to derive
+type Keychain struct {
+	db       database.Database
+	external *hdwallet.ExtendedKey
+}
+
+// New returns the keychain of the user whose seed is [seed] and whose database
+// on the chain is [db]
+func New(db database.Database, seed []byte, coinType uint32) (*Keychain, error) {
+	master, err := hdwallet.NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+	external, err := master.Derive(hdwallet.ExternalChainPath(coinType))
+	if err != nil {
+		return nil, err
+	}
+	return &Keychain{
+		db:       db,
+		external: external,
+	}, nil
+}
+
+// NewKey returns a key for a new address of [username], whose database on the
+// chain is [db]. If the user was seeded from a mnemonic, the key is the next
+// one derived from their seed. Otherwise, it's random.
+func NewKey(
+	ks keystore.BlockchainKeystore,
+	db database.Database,
+	username string,
+	password string,
+	coinType uint32,
+) (*crypto.PrivateKeySECP256K1R, error) {
+	seed, err := ks.GetSeed(username, password)
+	if errors.Is(err, keystore.ErrNoSeed) {
+		factory := crypto.FactorySECP256K1R{}
+		sk, err := factory.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return sk.(*crypto.PrivateKeySECP256K1R), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	kc, err := New(db, seed, coinType)
+	if err != nil {
+		return nil, err
+	}
+	return kc.NextKey()
+}
+
+// NextIndex returns the index of the next key to derive
+func (kc *Keychain) NextIndex() (uint32, error) {
+	index, err := database.GetUInt64(kc.db, nextIndexKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return uint32(index), err
+}
+
+// Key returns the key at [index]
+func (kc *Keychain) Key(index uint32) (*crypto.PrivateKeySECP256K1R, error) {
+	if index >= hdwallet.HardenedOffset {
+		return nil, errIndexExhausted
+	}
+	key, err := kc.external.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey(), nil
+}
+
+// NextKey returns the key at the next index and advances the next index
+func (kc *Keychain) NextKey() (*crypto.PrivateKeySECP256K1R, error) {
+	index, err := kc.NextIndex()
+	if err != nil {
+		return nil, err
+	}
+	// BIP-32 skips indices whose key is invalid
+	for {
+		key, err := kc.Key(index)
+		index++
+		if err == nil {
+			return key, database.PutUInt64(kc.db, nextIndexKey, uint64(index))
+		}
+		if err == errIndexExhausted {
+			return nil, err
+		}
+	}
+}
+
+// Discover derives keys from index 0 until [gapLimit] consecutive keys whose
+// addresses aren't [used], or until [maxKeys] keys were derived. Returns the
+// keys up to the last used one, and advances the next index past it.
+func (kc *Keychain) Discover(
+	gapLimit int,
+	maxKeys int,
+	used func(ids.ShortID) (bool, error),
+) ([]*crypto.PrivateKeySECP256K1R, error) {
+	var (
+		keys []*crypto.PrivateKeySECP256K1R
+		// Number of keys up to, and including, the last used one
+		numUsed int
+		// Index after the last used key
+		nextIndex uint32
+		// Number of consecutive unused keys
+		gap int
+	)
+	for index := uint32(0); gap < gapLimit && len(keys) < maxKeys; index++ {
+		key, err := kc.Key(index)
+		if err == errIndexExhausted {
+			break
+		}
+		if err != nil {
+			// BIP-32 skips indices whose key is invalid
+			continue
+		}
+		keys = append(keys, key)
+
+		isUsed, err := used(key.PublicKey().Address())
+		if err != nil {
+			return nil, err
+		}
+		if isUsed {
+			numUsed = len(keys)
+			nextIndex = index + 1
+			gap = 0
+		} else {
+			gap++
+		}
+	}
+	keys = keys[:numUsed]
+
+	// Don't derive the discovered keys again
+	currentNextIndex, err := kc.NextIndex()
+	if err != nil || nextIndex <= currentNextIndex {
+		return keys, err
+	}
+	return keys, database.PutUInt64(kc.db, nextIndexKey, uint64(nextIndex))
+}
@@ -0,0 +1,91 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hdkeys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func newTestKeychain(t *testing.T) *Keychain {
+	seed, err := hdwallet.Seed(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc, err := New(memdb.New(), seed, hdwallet.AvalancheCoinType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kc
+}
+
+func TestNextKey(t *testing.T) {
+	assert := assert.New(t)
+
+	kc := newTestKeychain(t)
+	for i := uint32(0); i < 3; i++ {
+		index, err := kc.NextIndex()
+		assert.NoError(err)
+		assert.Equal(i, index)
+
+		expected, err := kc.Key(i)
+		assert.NoError(err)
+		key, err := kc.NextKey()
+		assert.NoError(err)
+		assert.Equal(expected.Bytes(), key.Bytes())
+	}
+
+	// Keys are derived deterministically
+	otherKC := newTestKeychain(t)
+	key, err := otherKC.NextKey()
+	assert.NoError(err)
+	expected, err := kc.Key(0)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), key.Bytes())
+}
+
+func TestDiscover(t *testing.T) {
+	assert := assert.New(t)
+
+	kc := newTestKeychain(t)
+	usedAddrs := ids.ShortSet{}
+	for _, index := range []uint32{1, 4} {
+		key, err := kc.Key(index)
+		assert.NoError(err)
+		usedAddrs.Add(key.PublicKey().Address())
+	}
+	used := func(addr ids.ShortID) (bool, error) {
+		return usedAddrs.Contains(addr), nil
+	}
+
+	// The gap between the used keys is too large to be crossed
+	keys, err := kc.Discover(2, 100, used)
+	assert.NoError(err)
+	assert.Len(keys, 2)
+	index, err := kc.NextIndex()
+	assert.NoError(err)
+	assert.EqualValues(2, index)
+
+	keys, err = kc.Discover(3, 100, used)
+	assert.NoError(err)
+	assert.Len(keys, 5)
+	index, err = kc.NextIndex()
+	assert.NoError(err)
+	assert.EqualValues(5, index)
+
+	// Discovery doesn't move the next index backwards
+	keys, err = kc.Discover(3, 3, used)
+	assert.NoError(err)
+	assert.Len(keys, 2)
+	index, err = kc.NextIndex()
+	assert.NoError(err)
+	assert.EqualValues(5, index)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/subprocess"
 )
 
 // A Factory creates new instances of a VM
@@ -20,6 +21,14 @@ type Factory interface {
 	New(*snow.Context) (interface{}, error)
 }
 
+// A LimitedFactory creates new instances of a VM that runs in a separate
+// process whose resource usage is limited
+type LimitedFactory interface {
+	Factory
+
+	NewWithLimits(*snow.Context, subprocess.Limits) (interface{}, error)
+}
+
 // Manager is a VM manager.
 // It has the following functionality:
 //   1) Register a VM factory. To register a VM is to associate its ID with a
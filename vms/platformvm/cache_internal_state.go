@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -33,6 +34,7 @@ var (
 	validatorPrefix       = []byte("validator")
 	delegatorPrefix       = []byte("delegator")
 	subnetValidatorPrefix = []byte("subnetValidator")
+	validatorDiffsPrefix  = []byte("validatorDiffs")
 	blockPrefix           = []byte("block")
 	txPrefix              = []byte("tx")
 	rewardUTXOsPrefix     = []byte("rewardUTXOs")
@@ -46,6 +48,10 @@ var (
 	lastAcceptedKey  = []byte("last accepted")
 	initializedKey   = []byte("initialized")
 	migratedKey      = []byte("migrated")
+	// validatorDiffsHeightKey is the lowest height whose validator sets can be
+	// reconstructed from the validator weight diffs. Databases written before
+	// the diffs were recorded only have the diffs of later heights.
+	validatorDiffsHeightKey = []byte("validator diffs height")
 
 	errWrongNetworkID = errors.New("tx has wrong network ID")
 
@@ -60,11 +66,12 @@ const (
 	mediumPriority
 	topPriority
 
-	blockCacheSize       = 2048
-	txCacheSize          = 2048
-	rewardUTXOsCacheSize = 2048
-	chainCacheSize       = 2048
-	chainDBCacheSize     = 2048
+	blockCacheSize          = 2048
+	txCacheSize             = 2048
+	rewardUTXOsCacheSize    = 2048
+	chainCacheSize          = 2048
+	chainDBCacheSize        = 2048
+	validatorDiffsCacheSize = 2048
 )
 
 type InternalState interface {
@@ -88,6 +95,16 @@ type InternalState interface {
 
 	UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error)
 
+	// GetValidatorWeightDiffs returns the changes to the weights of the
+	// validators of [subnetID] that were applied when the block at [height]
+	// was accepted.
+	GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error)
+
+	// GetValidatorDiffsHeight returns the lowest height whose validator sets
+	// can be reconstructed from the validator weight diffs of the blocks
+	// accepted after it.
+	GetValidatorDiffsHeight() uint64
+
 	Abort()
 	Commit() error
 	CommitBatch() (database.Batch, error)
@@ -110,16 +127,19 @@ type InternalState interface {
  * | | '-. subnetValidator
  * | |   '-. list
  * | |     '-- txID -> nil
- * | '-. pending
- * |   |-. validator
- * |   | '-. list
- * |   |   '-- txID -> nil
- * |   |-. delegator
- * |   | '-. list
- * |   |   '-- txID -> nil
- * |   '-. subnetValidator
- * |     '-. list
- * |       '-- txID -> nil
+ * | |-. pending
+ * | | |-. validator
+ * | | | '-. list
+ * | | |   '-- txID -> nil
+ * | | |-. delegator
+ * | | | '-. list
+ * | | |   '-- txID -> nil
+ * | | '-. subnetValidator
+ * | |   '-. list
+ * | |     '-- txID -> nil
+ * | '-. diffs
+ * |   '-. height + subnetID
+ * |     '-- nodeID -> weight change
  * |-. blocks
  * | '-- blockID -> block bytes
  * |-. txs
@@ -174,6 +194,8 @@ type internalStateImpl struct {
 	pendingDelegatorList         linkeddb.LinkedDB
 	pendingSubnetValidatorBaseDB database.Database
 	pendingSubnetValidatorList   linkeddb.LinkedDB
+	validatorDiffsCache          cache.Cacher // cache of height + subnetID -> map[ids.ShortID]*ValidatorWeightDiff
+	validatorDiffsDB             database.Database
 
 	addedBlocks map[ids.ID]Block // map of blockID -> Block
 	blockCache  cache.Cacher     // cache of blockID -> Block, if the entry is nil, it is not in the database
@@ -204,6 +226,7 @@ type internalStateImpl struct {
 	originalTimestamp, timestamp         time.Time
 	originalCurrentSupply, currentSupply uint64
 	originalLastAccepted, lastAccepted   ids.ID
+	validatorDiffsHeight                 uint64
 	singletonDB                          database.Database
 }
 
@@ -232,6 +255,8 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 	pendingDelegatorBaseDB := prefixdb.New(delegatorPrefix, pendingValidatorsDB)
 	pendingSubnetValidatorBaseDB := prefixdb.New(subnetValidatorPrefix, pendingValidatorsDB)
 
+	validatorDiffsDB := prefixdb.New(validatorDiffsPrefix, validatorsDB)
+
 	rewardUTXODB := prefixdb.New(rewardUTXOsPrefix, baseDB)
 	utxoDB := prefixdb.New(utxoPrefix, baseDB)
 	subnetBaseDB := prefixdb.New(subnetPrefix, baseDB)
@@ -258,6 +283,7 @@ func newInternalStateDatabases(vm *VM, db database.Database) *internalStateImpl
 		pendingDelegatorList:         linkeddb.NewDefault(pendingDelegatorBaseDB),
 		pendingSubnetValidatorBaseDB: pendingSubnetValidatorBaseDB,
 		pendingSubnetValidatorList:   linkeddb.NewDefault(pendingSubnetValidatorBaseDB),
+		validatorDiffsDB:             validatorDiffsDB,
 
 		addedBlocks: make(map[ids.ID]Block),
 		blockDB:     prefixdb.New(blockPrefix, baseDB),
@@ -288,6 +314,7 @@ func (st *internalStateImpl) initCaches() {
 	st.utxoState = avax.NewUTXOState(st.utxoDB, GenesisCodec)
 	st.chainCache = &cache.LRU{Size: chainCacheSize}
 	st.chainDBCache = &cache.LRU{Size: chainDBCacheSize}
+	st.validatorDiffsCache = &cache.LRU{Size: validatorDiffsCacheSize}
 }
 
 func (st *internalStateImpl) initMeteredCaches(namespace string, metrics prometheus.Registerer) error {
@@ -337,12 +364,22 @@ func (st *internalStateImpl) initMeteredCaches(namespace string, metrics prometh
 		metrics,
 		&cache.LRU{Size: chainDBCacheSize},
 	)
+	if err != nil {
+		return err
+	}
+
+	validatorDiffsCache, err := metercacher.New(
+		fmt.Sprintf("%s_validator_diffs_cache", namespace),
+		metrics,
+		&cache.LRU{Size: validatorDiffsCacheSize},
+	)
 	st.blockCache = blockCache
 	st.txCache = txCache
 	st.rewardUTXOsCache = rewardUTXOsCache
 	st.utxoState = utxoState
 	st.chainCache = chainCache
 	st.chainDBCache = chainDBCache
+	st.validatorDiffsCache = validatorDiffsCache
 	return err
 }
 
@@ -372,9 +409,37 @@ func (st *internalStateImpl) sync(genesis []byte) error {
 			err,
 		)
 	}
+
+	if err := st.loadValidatorDiffsHeight(); err != nil {
+		return fmt.Errorf(
+			"failed to load the height of the first validator weight diffs: %w",
+			err,
+		)
+	}
 	return nil
 }
 
+// loadValidatorDiffsHeight loads the lowest height whose validator sets can be
+// reconstructed. If it was never written, the validator weight diffs are only
+// known from the last accepted block onwards.
+func (st *internalStateImpl) loadValidatorDiffsHeight() error {
+	height, err := database.GetUInt64(st.singletonDB, validatorDiffsHeightKey)
+	if err == nil {
+		st.validatorDiffsHeight = height
+		return nil
+	}
+	if err != database.ErrNotFound {
+		return err
+	}
+
+	lastAccepted, err := st.GetBlock(st.lastAccepted)
+	if err != nil {
+		return err
+	}
+	st.validatorDiffsHeight = lastAccepted.Height()
+	return database.PutUInt64(st.singletonDB, validatorDiffsHeightKey, st.validatorDiffsHeight)
+}
+
 func NewInternalState(vm *VM, db database.Database, genesis []byte) (InternalState, error) {
 	is := newInternalStateDatabases(vm, db)
 	is.initCaches()
@@ -644,6 +709,49 @@ func (st *internalStateImpl) UTXOIDs(addr []byte, start ids.ID, limit int) ([]id
 	return st.utxoState.UTXOIDs(addr, start, limit)
 }
 
+func (st *internalStateImpl) GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error) {
+	prefixStruct := heightWithSubnet{
+		Height:   height,
+		SubnetID: subnetID,
+	}
+	prefixBytes, err := GenesisCodec.Marshal(codecVersion, prefixStruct)
+	if err != nil {
+		return nil, err
+	}
+	prefixStr := string(prefixBytes)
+
+	if weightDiffsIntf, ok := st.validatorDiffsCache.Get(prefixStr); ok {
+		return weightDiffsIntf.(map[ids.ShortID]*ValidatorWeightDiff), nil
+	}
+
+	diffDB := prefixdb.New(prefixBytes, st.validatorDiffsDB)
+	diffIter := diffDB.NewIterator()
+	defer diffIter.Release()
+
+	weightDiffs := make(map[ids.ShortID]*ValidatorWeightDiff)
+	for diffIter.Next() {
+		nodeID, err := ids.ToShortID(diffIter.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		weightDiff := ValidatorWeightDiff{}
+		if _, err := GenesisCodec.Unmarshal(diffIter.Value(), &weightDiff); err != nil {
+			return nil, err
+		}
+
+		weightDiffs[nodeID] = &weightDiff
+	}
+	if err := diffIter.Error(); err != nil {
+		return nil, err
+	}
+
+	st.validatorDiffsCache.Put(prefixStr, weightDiffs)
+	return weightDiffs, nil
+}
+
+func (st *internalStateImpl) GetValidatorDiffsHeight() uint64 { return st.validatorDiffsHeight }
+
 func (st *internalStateImpl) CurrentStakerChainState() currentStakerChainState {
 	return st.currentStakerChainState
 }
@@ -752,6 +860,7 @@ func (st *internalStateImpl) Close() error {
 		st.pendingDelegatorBaseDB.Close(),
 		st.pendingValidatorBaseDB.Close(),
 		st.pendingValidatorsDB.Close(),
+		st.validatorDiffsDB.Close(),
 		st.currentSubnetValidatorBaseDB.Close(),
 		st.currentDelegatorBaseDB.Close(),
 		st.currentValidatorBaseDB.Close(),
@@ -778,7 +887,51 @@ type currentValidatorState struct {
 	PotentialReward uint64        `serialize:"true"`
 }
 
+type heightWithSubnet struct {
+	Height   uint64 `serialize:"true"`
+	SubnetID ids.ID `serialize:"true"`
+}
+
+// ValidatorWeightDiff describes how the weight of a validator changed when a
+// block was accepted.
+type ValidatorWeightDiff struct {
+	Decrease bool   `serialize:"true"`
+	Amount   uint64 `serialize:"true"`
+}
+
+// Add the weight change described by [decrease] and [amount] to this diff.
+func (v *ValidatorWeightDiff) Add(decrease bool, amount uint64) error {
+	if v.Decrease == decrease {
+		var err error
+		v.Amount, err = safemath.Add64(v.Amount, amount)
+		return err
+	}
+
+	if v.Amount > amount {
+		v.Amount -= amount
+	} else {
+		v.Amount = amount - v.Amount
+		v.Decrease = decrease
+	}
+	return nil
+}
+
 func (st *internalStateImpl) writeCurrentStakers() error {
+	weightDiffs := make(map[ids.ID]map[ids.ShortID]*ValidatorWeightDiff) // subnetID -> nodeID -> weightDiff
+	addWeightDiff := func(subnetID ids.ID, nodeID ids.ShortID, decrease bool, amount uint64) error {
+		subnetDiffs, ok := weightDiffs[subnetID]
+		if !ok {
+			subnetDiffs = make(map[ids.ShortID]*ValidatorWeightDiff)
+			weightDiffs[subnetID] = subnetDiffs
+		}
+		nodeDiff, ok := subnetDiffs[nodeID]
+		if !ok {
+			nodeDiff = &ValidatorWeightDiff{}
+			subnetDiffs[nodeID] = nodeDiff
+		}
+		return nodeDiff.Add(decrease, amount)
+	}
+
 	for _, currentStaker := range st.addedCurrentStakers {
 		txID := currentStaker.addStakerTx.ID()
 		potentialReward := currentStaker.potentialReward
@@ -804,14 +957,26 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 				return err
 			}
 			st.uptimes[tx.Validator.NodeID] = vdr
+
+			if err := addWeightDiff(constants.PrimaryNetworkID, tx.Validator.NodeID, false, tx.Validator.Wght); err != nil {
+				return err
+			}
 		case *UnsignedAddDelegatorTx:
 			if err := database.PutUInt64(st.currentDelegatorList, txID[:], potentialReward); err != nil {
 				return err
 			}
+
+			if err := addWeightDiff(constants.PrimaryNetworkID, tx.Validator.NodeID, false, tx.Validator.Wght); err != nil {
+				return err
+			}
 		case *UnsignedAddSubnetValidatorTx:
 			if err := st.currentSubnetValidatorList.Put(txID[:], nil); err != nil {
 				return err
 			}
+
+			if err := addWeightDiff(tx.Validator.Subnet, tx.Validator.NodeID, false, tx.Validator.Wght); err != nil {
+				return err
+			}
 		default:
 			return errWrongTxType
 		}
@@ -819,16 +984,29 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 	st.addedCurrentStakers = nil
 
 	for _, tx := range st.deletedCurrentStakers {
-		var db database.KeyValueWriter
+		var (
+			db       database.KeyValueWriter
+			subnetID ids.ID
+			vdr      *Validator
+		)
 		switch tx := tx.UnsignedTx.(type) {
 		case *UnsignedAddValidatorTx:
 			db = st.currentValidatorList
 			delete(st.uptimes, tx.Validator.NodeID)
 			delete(st.updatedUptimes, tx.Validator.NodeID)
+
+			subnetID = constants.PrimaryNetworkID
+			vdr = &tx.Validator
 		case *UnsignedAddDelegatorTx:
 			db = st.currentDelegatorList
+
+			subnetID = constants.PrimaryNetworkID
+			vdr = &tx.Validator
 		case *UnsignedAddSubnetValidatorTx:
 			db = st.currentSubnetValidatorList
+
+			subnetID = tx.Validator.Subnet
+			vdr = &tx.Validator.Validator
 		default:
 			return errWrongTxType
 		}
@@ -837,8 +1015,66 @@ func (st *internalStateImpl) writeCurrentStakers() error {
 		if err := db.Delete(txID[:]); err != nil {
 			return err
 		}
+		if err := addWeightDiff(subnetID, vdr.NodeID, true, vdr.Wght); err != nil {
+			return err
+		}
 	}
 	st.deletedCurrentStakers = nil
+
+	if len(weightDiffs) == 0 {
+		return nil
+	}
+	return st.writeValidatorDiffs(weightDiffs)
+}
+
+// writeValidatorDiffs records the validator weight changes made by the last
+// accepted block so that historical validator sets can be re-constructed.
+func (st *internalStateImpl) writeValidatorDiffs(weightDiffs map[ids.ID]map[ids.ShortID]*ValidatorWeightDiff) error {
+	lastAccepted, err := st.GetBlock(st.lastAccepted)
+	if err != nil {
+		return err
+	}
+	height := lastAccepted.Height()
+
+	for subnetID, nodeDiffs := range weightDiffs {
+		prefixStruct := heightWithSubnet{
+			Height:   height,
+			SubnetID: subnetID,
+		}
+		prefixBytes, err := GenesisCodec.Marshal(codecVersion, prefixStruct)
+		if err != nil {
+			return err
+		}
+		diffDB := prefixdb.New(prefixBytes, st.validatorDiffsDB)
+		for nodeID, nodeDiff := range nodeDiffs {
+			// Merge with any changes that were previously written at this
+			// height.
+			nodeDiffBytes, err := diffDB.Get(nodeID[:])
+			switch err {
+			case nil:
+				previousDiff := ValidatorWeightDiff{}
+				if _, err := GenesisCodec.Unmarshal(nodeDiffBytes, &previousDiff); err != nil {
+					return err
+				}
+				if err := previousDiff.Add(nodeDiff.Decrease, nodeDiff.Amount); err != nil {
+					return err
+				}
+				nodeDiff = &previousDiff
+			case database.ErrNotFound:
+			default:
+				return err
+			}
+
+			nodeDiffBytes, err = GenesisCodec.Marshal(codecVersion, nodeDiff)
+			if err != nil {
+				return err
+			}
+			if err := diffDB.Put(nodeID[:], nodeDiffBytes); err != nil {
+				return err
+			}
+		}
+		st.validatorDiffsCache.Evict(string(prefixBytes))
+	}
 	return nil
 }
 
@@ -67,6 +67,18 @@ func (c *Client) CreateAddress(user api.UserPass) (string, error) {
 	return res.Address, err
 }
 
+// DiscoverAddresses adds to [user] the addresses derived from their mnemonic
+// that hold UTXOs, stopping after [gapLimit] consecutive unused addresses, and
+// returns them
+func (c *Client) DiscoverAddresses(user api.UserPass, gapLimit uint32) ([]string, error) {
+	res := &api.JSONAddresses{}
+	err := c.requester.SendRequest("discoverAddresses", &DiscoverAddressesArgs{
+		UserPass: user,
+		GapLimit: cjson.Uint32(gapLimit),
+	}, res)
+	return res.Addresses, err
+}
+
 // ListAddresses returns an array of platform addresses controlled by [user]
 func (c *Client) ListAddresses(user api.UserPass) ([]string, error) {
 	res := &api.JSONAddresses{}
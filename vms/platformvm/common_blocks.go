@@ -6,6 +6,7 @@ package platformvm
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -81,6 +82,10 @@ var (
 type Block interface {
 	snowman.Block
 
+	// Timestamp returns the chain time once this block is accepted, or the
+	// zero time if it isn't known yet.
+	Timestamp() time.Time
+
 	// initialize this block's non-serialized fields.
 	//
 	// This method should be called when a block is unmarshaled from bytes.
@@ -165,6 +170,16 @@ func (b *CommonBlock) ParentID() ids.ID { return b.PrntID }
 // Height returns this block's height. The genesis block has height 0.
 func (b *CommonBlock) Height() uint64 { return b.Hght }
 
+// Timestamp returns the chain time once this block is accepted. The chain time
+// isn't kept once a block is decided, so it's only known for the last accepted
+// block.
+func (b *CommonBlock) Timestamp() time.Time {
+	if b.id == b.vm.lastAcceptedID {
+		return b.vm.internalState.GetTimestamp()
+	}
+	return time.Time{}
+}
+
 // Parent returns [b]'s parent
 func (b *CommonBlock) Parent() snowman.Block {
 	// TODO: This should properly propegate the error.
@@ -263,6 +278,15 @@ func (cdb *CommonDecisionBlock) onAccept() MutableState {
 	return cdb.onAcceptState
 }
 
+// Timestamp returns the chain time once this block is accepted. It's known once
+// this block was verified.
+func (cdb *CommonDecisionBlock) Timestamp() time.Time {
+	if cdb.Status().Decided() || cdb.onAcceptState == nil {
+		return cdb.CommonBlock.Timestamp()
+	}
+	return cdb.onAcceptState.GetTimestamp()
+}
+
 func (cdb *CommonDecisionBlock) Reject() error {
 	defer cdb.free()
 
@@ -0,0 +1,145 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ common.DatabaseVerifier = &VM{}
+
+// VerifyDatabase implements the common.DatabaseVerifier interface.
+//
+// In addition to the checks performed by the exported VerifyDatabase function,
+// the stakers and supply persisted on disk are compared against the state
+// held in memory.
+func (vm *VM) VerifyDatabase() ([]string, error) {
+	st, findings, err := loadInternalStateForVerification(vm.dbManager.Current().Database)
+	if st == nil {
+		return findings, err
+	}
+
+	findings = append(findings, compareStakers(
+		"current",
+		st.CurrentStakerChainState().Stakers(),
+		vm.internalState.CurrentStakerChainState().Stakers(),
+	)...)
+	findings = append(findings, compareStakers(
+		"pending",
+		st.PendingStakerChainState().Stakers(),
+		vm.internalState.PendingStakerChainState().Stakers(),
+	)...)
+	if diskSupply, memorySupply := st.GetCurrentSupply(), vm.internalState.GetCurrentSupply(); diskSupply != memorySupply {
+		findings = append(findings, fmt.Sprintf("persisted current supply %d doesn't match in-memory current supply %d", diskSupply, memorySupply))
+	}
+	return findings, nil
+}
+
+// VerifyDatabase checks that the staker sets and the supply of the platform
+// chain state stored in [db] are self-consistent. [db] is the database the VM
+// was given. Returns a description of each inconsistency found. [db] isn't
+// modified.
+func VerifyDatabase(db database.Database) ([]string, error) {
+	_, findings, err := loadInternalStateForVerification(db)
+	return findings, err
+}
+
+// loadInternalStateForVerification loads the internal state stored in [db] and
+// verifies it. If the state couldn't be loaded, the returned state is nil and
+// the failure is reported as a finding.
+func loadInternalStateForVerification(db database.Database) (*internalStateImpl, []string, error) {
+	st := newInternalStateDatabases(nil, db)
+	st.initCaches()
+
+	initialized, err := st.singletonDB.Has(initializedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !initialized {
+		return nil, []string{"platform chain state was never initialized"}, nil
+	}
+	if err := st.load(); err != nil {
+		return nil, []string{fmt.Sprintf("platform chain state couldn't be loaded: %s", err)}, nil
+	}
+
+	findings, err := verifyInternalState(st)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, findings, nil
+}
+
+// verifyInternalState checks that every staker was committed and that the
+// current supply accounts for the potential rewards of the current stakers.
+func verifyInternalState(st *internalStateImpl) ([]string, error) {
+	var findings []string
+
+	currentStakers := st.CurrentStakerChainState()
+	stakers := append([]*Tx(nil), currentStakers.Stakers()...)
+	stakers = append(stakers, st.PendingStakerChainState().Stakers()...)
+	for _, staker := range stakers {
+		txID := staker.ID()
+		_, status, err := st.GetTx(txID)
+		if err != nil {
+			return nil, err
+		}
+		if status != Committed {
+			findings = append(findings, fmt.Sprintf("staker %s has status %s rather than %s", txID, status, Committed))
+		}
+	}
+
+	currentSupply := st.GetCurrentSupply()
+	if currentSupply > SupplyCap {
+		findings = append(findings, fmt.Sprintf("current supply %d exceeds the supply cap %d", currentSupply, SupplyCap))
+	}
+
+	potentialRewards := uint64(0)
+	for _, staker := range currentStakers.Stakers() {
+		_, potentialReward, err := currentStakers.GetStaker(staker.ID())
+		if err != nil {
+			return nil, err
+		}
+		potentialRewards, err = safemath.Add64(potentialRewards, potentialReward)
+		if err != nil {
+			findings = append(findings, "potential rewards of the current stakers overflow")
+			return findings, nil
+		}
+	}
+	if potentialRewards > currentSupply {
+		findings = append(findings, fmt.Sprintf("potential rewards %d of the current stakers exceed the current supply %d", potentialRewards, currentSupply))
+	}
+	return findings, nil
+}
+
+// compareStakers reports the differences between the [kind] stakers persisted
+// on disk and the ones held in memory.
+func compareStakers(kind string, diskStakers, memoryStakers []*Tx) []string {
+	diskIDs := ids.Set{}
+	for _, staker := range diskStakers {
+		diskIDs.Add(staker.ID())
+	}
+	memoryIDs := ids.Set{}
+	for _, staker := range memoryStakers {
+		memoryIDs.Add(staker.ID())
+	}
+
+	var findings []string
+	for txID := range diskIDs {
+		if !memoryIDs.Contains(txID) {
+			findings = append(findings, fmt.Sprintf("%s staker %s is persisted but isn't in memory", kind, txID))
+		}
+	}
+	for txID := range memoryIDs {
+		if !diskIDs.Contains(txID) {
+			findings = append(findings, fmt.Sprintf("%s staker %s is in memory but isn't persisted", kind, txID))
+		}
+	}
+	return findings
+}
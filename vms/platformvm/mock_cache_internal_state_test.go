@@ -207,6 +207,43 @@ func (_m *MockInternalState) GetCurrentSupply() uint64 {
 	return r0
 }
 
+// GetValidatorDiffsHeight provides a mock function with given fields:
+func (_m *MockInternalState) GetValidatorDiffsHeight() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetValidatorWeightDiffs provides a mock function with given fields: height, subnetID
+func (_m *MockInternalState) GetValidatorWeightDiffs(height uint64, subnetID ids.ID) (map[ids.ShortID]*ValidatorWeightDiff, error) {
+	ret := _m.Called(height, subnetID)
+
+	var r0 map[ids.ShortID]*ValidatorWeightDiff
+	if rf, ok := ret.Get(0).(func(uint64, ids.ID) map[ids.ShortID]*ValidatorWeightDiff); ok {
+		r0 = rf(height, subnetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[ids.ShortID]*ValidatorWeightDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, ids.ID) error); ok {
+		r1 = rf(height, subnetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastAccepted provides a mock function with given fields:
 func (_m *MockInternalState) GetLastAccepted() ids.ID {
 	ret := _m.Called()
@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -80,6 +81,20 @@ func (pb *ProposalBlock) Reject() error {
 	return pb.CommonBlock.Reject()
 }
 
+// Timestamp returns the chain time the proposal of this block is made at, which
+// is the chain time once its parent is accepted. The chain time only changes
+// once the proposal is committed.
+func (pb *ProposalBlock) Timestamp() time.Time {
+	if pb.Status().Decided() {
+		return pb.CommonBlock.Timestamp()
+	}
+	parent, err := pb.parent()
+	if err != nil {
+		return time.Time{}
+	}
+	return parent.Timestamp()
+}
+
 func (pb *ProposalBlock) initialize(vm *VM, bytes []byte, status choices.Status, self Block) error {
 	if err := pb.CommonBlock.initialize(vm, bytes, status, self); err != nil {
 		return err
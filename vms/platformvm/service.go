@@ -16,11 +16,13 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hdwallet"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/hdkeys"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
@@ -288,7 +290,7 @@ func (service *Service) CreateAddress(_ *http.Request, args *api.UserPass, respo
 		return fmt.Errorf("keystore user has reached its limit of %d addresses", maxKeystoreAddresses)
 	}
 
-	key, err := service.vm.factory.NewPrivateKey()
+	key, err := hdkeys.NewKey(service.vm.ctx.Keystore, db, args.Username, args.Password, hdwallet.AvalancheCoinType)
 	if err != nil {
 		return fmt.Errorf("couldn't create key: %w", err)
 	}
@@ -298,12 +300,72 @@ func (service *Service) CreateAddress(_ *http.Request, args *api.UserPass, respo
 		return fmt.Errorf("problem formatting address: %w", err)
 	}
 
-	if err := user.putAddress(key.(*crypto.PrivateKeySECP256K1R)); err != nil {
+	if err := user.putAddress(key); err != nil {
 		return fmt.Errorf("problem saving key %w", err)
 	}
 	return db.Close()
 }
 
+// DiscoverAddressesArgs are the arguments for DiscoverAddresses
+type DiscoverAddressesArgs struct {
+	api.UserPass
+	// Number of consecutive addresses without UTXOs after which discovery
+	// stops. Defaults to 20.
+	GapLimit json.Uint32 `json:"gapLimit"`
+}
+
+// DiscoverAddresses adds to [args.Username] the addresses derived from their
+// mnemonic that hold UTXOs, along with the unused addresses before the last of
+// them. The next address created for the user follows them.
+func (service *Service) DiscoverAddresses(_ *http.Request, args *DiscoverAddressesArgs, response *api.JSONAddresses) error {
+	service.vm.ctx.Log.Debug("Platform: DiscoverAddresses called")
+
+	seed, err := service.vm.ctx.Keystore.GetSeed(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving seed: %w", err)
+	}
+	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem retrieving user %q: %w", args.Username, err)
+	}
+	defer db.Close()
+
+	kc, err := hdkeys.New(db, seed, hdwallet.AvalancheCoinType)
+	if err != nil {
+		return err
+	}
+
+	user := user{db: db}
+	addrs, err := user.getAddresses()
+	if err != nil {
+		return fmt.Errorf("couldn't get addresses: %w", err)
+	}
+
+	gapLimit := int(args.GapLimit)
+	if gapLimit == 0 {
+		gapLimit = hdkeys.DefaultGapLimit
+	}
+	keys, err := kc.Discover(gapLimit, maxKeystoreAddresses-len(addrs), func(addr ids.ShortID) (bool, error) {
+		utxoIDs, err := service.vm.internalState.UTXOIDs(addr.Bytes(), ids.Empty, 1)
+		return len(utxoIDs) > 0, err
+	})
+	if err != nil {
+		return fmt.Errorf("problem discovering addresses: %w", err)
+	}
+
+	response.Addresses = make([]string, len(keys))
+	for i, key := range keys {
+		response.Addresses[i], err = service.vm.FormatLocalAddress(key.PublicKey().Address())
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+		if err := user.putAddress(key); err != nil {
+			return fmt.Errorf("problem saving key %w", err)
+		}
+	}
+	return db.Close()
+}
+
 // ListAddresses returns the addresses controlled by [args.Username]
 func (service *Service) ListAddresses(_ *http.Request, args *api.UserPass, response *api.JSONAddresses) error {
 	service.vm.ctx.Log.Debug("Platform: ListAddresses called")
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/codec"
@@ -43,6 +44,12 @@ const (
 
 	droppedTxCacheSize = 50
 
+	validatorSetCacheSize = 64
+
+	// maxValidatorSetLookback is the number of blocks that GetValidatorSet may
+	// revert the validator weight diffs of to reconstruct a validator set
+	maxValidatorSetLookback = 1 << 14
+
 	maxUTXOsToFetch = 1024
 
 	// TODO: Turn these constants into governable parameters
@@ -70,9 +77,14 @@ var (
 	errDSCantValidate    = errors.New("new blockchain can't be validated by primary network")
 	errStartTimeTooEarly = errors.New("start time is before the current chain time")
 	errStartAfterEndTime = errors.New("start time is after the end time")
+	errUnfinalizedHeight = errors.New("failed to fetch validator set at unfinalized height")
+	errUnrecordedHeight  = errors.New("failed to fetch validator set at a height before the validator weight diffs were recorded")
+	errHeightTooOld      = errors.New("failed to fetch validator set at a height too far below the last accepted height")
+	errNotBlockchain     = errors.New("not a committed blockchain")
 
 	_ block.ChainVM        = &VM{}
 	_ validators.Connector = &VM{}
+	_ validators.State     = &VM{}
 	_ secp256k1fx.VM       = &VM{}
 	_ Fx                   = &secp256k1fx.Fx{}
 )
@@ -122,6 +134,10 @@ type VM struct {
 	// Value: String repr. of the verification error
 	droppedTxCache cache.LRU
 
+	// Key: validatorSetKey
+	// Value: the validator set at that height, as returned by GetValidatorSet
+	validatorSetCache cache.LRU
+
 	// Key: block ID
 	// Value: the block
 	currentBlocks map[ids.ID]Block
@@ -167,6 +183,7 @@ func (vm *VM) Initialize(
 	}
 
 	vm.droppedTxCache = cache.LRU{Size: droppedTxCacheSize}
+	vm.validatorSetCache = cache.LRU{Size: validatorSetCacheSize}
 	vm.currentBlocks = make(map[ids.ID]Block)
 
 	vm.mempool.Initialize(vm)
@@ -417,7 +434,7 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	server.RegisterInterceptFunc(vm.metrics.apiRequestMetrics.InterceptRequest)
 	server.RegisterAfterFunc(vm.metrics.apiRequestMetrics.AfterRequest)
-	if err := server.RegisterService(&Service{vm: vm}, "platform"); err != nil {
+	if err := openrpc.RegisterServices(server, openrpc.Service{Receiver: &Service{vm: vm}, Name: "platform"}); err != nil {
 		return nil, err
 	}
 
@@ -435,7 +452,7 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 	server := rpc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
-	if err := server.RegisterService(&StaticService{}, "platform"); err != nil {
+	if err := openrpc.RegisterServices(server, openrpc.Service{Receiver: &StaticService{}, Name: "platform"}); err != nil {
 		return nil, err
 	}
 
@@ -448,7 +465,7 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 }
 
 // Connected implements validators.Connector
-func (vm *VM) Connected(vdrID ids.ShortID) error {
+func (vm *VM) Connected(vdrID ids.ShortID, nodeVersion version.Application) error {
 	return vm.Connect(vdrID)
 }
 
@@ -460,6 +477,120 @@ func (vm *VM) Disconnected(vdrID ids.ShortID) error {
 	return vm.internalState.Commit()
 }
 
+// GetCurrentHeight implements validators.State
+func (vm *VM) GetCurrentHeight() (uint64, error) {
+	lastAccepted, err := vm.getBlock(vm.lastAcceptedID)
+	if err != nil {
+		return 0, err
+	}
+	return lastAccepted.Height(), nil
+}
+
+// validatorSetKey identifies a validator set in the validator set cache
+type validatorSetKey struct {
+	height   uint64
+	subnetID ids.ID
+}
+
+// GetValidatorSet implements validators.State. The validator set at [height]
+// is re-constructed by reverting the validator weight diffs of every block
+// accepted after [height] from the current validator set. At most
+// [maxValidatorSetLookback] blocks are reverted.
+func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	lastAcceptedHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case lastAcceptedHeight < height:
+		return nil, errUnfinalizedHeight
+	case height < vm.internalState.GetValidatorDiffsHeight():
+		return nil, errUnrecordedHeight
+	case lastAcceptedHeight-height > maxValidatorSetLookback:
+		return nil, errHeightTooOld
+	}
+
+	// The validator set at a finalized height never changes
+	key := validatorSetKey{
+		height:   height,
+		subnetID: subnetID,
+	}
+	if vdrSetIntf, ok := vm.validatorSetCache.Get(key); ok {
+		return copyValidatorSet(vdrSetIntf.(map[ids.ShortID]uint64)), nil
+	}
+
+	currentValidators, err := vm.internalState.CurrentStakerChainState().ValidatorSet(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	currentValidatorList := currentValidators.List()
+
+	vdrSet := make(map[ids.ShortID]uint64, len(currentValidatorList))
+	for _, vdr := range currentValidatorList {
+		vdrSet[vdr.ID()] = vdr.Weight()
+	}
+
+	for i := lastAcceptedHeight; i > height; i-- {
+		diffs, err := vm.internalState.GetValidatorWeightDiffs(i, subnetID)
+		if err != nil {
+			return nil, err
+		}
+
+		for nodeID, diff := range diffs {
+			var op func(uint64, uint64) (uint64, error)
+			if diff.Decrease {
+				// The validator's weight was decreased at this block, so in the
+				// prior block it was higher.
+				op = safemath.Add64
+			} else {
+				// The validator's weight was increased at this block, so in the
+				// prior block it was lower.
+				op = safemath.Sub64
+			}
+
+			newWeight, err := op(vdrSet[nodeID], diff.Amount)
+			if err != nil {
+				return nil, err
+			}
+			if newWeight == 0 {
+				delete(vdrSet, nodeID)
+			} else {
+				vdrSet[nodeID] = newWeight
+			}
+		}
+	}
+
+	vm.validatorSetCache.Put(key, vdrSet)
+	return copyValidatorSet(vdrSet), nil
+}
+
+// copyValidatorSet returns a copy of [vdrSet], so that callers can't modify
+// the cached validator sets
+func copyValidatorSet(vdrSet map[ids.ShortID]uint64) map[ids.ShortID]uint64 {
+	vdrSetCopy := make(map[ids.ShortID]uint64, len(vdrSet))
+	for nodeID, weight := range vdrSet {
+		vdrSetCopy[nodeID] = weight
+	}
+	return vdrSetCopy
+}
+
+// GetSubnetID implements validators.State
+func (vm *VM) GetSubnetID(chainID ids.ID) (ids.ID, error) {
+	if chainID == constants.PlatformChainID {
+		return constants.PrimaryNetworkID, nil
+	}
+
+	chainTx, status, err := vm.internalState.GetTx(chainID)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	chain, ok := chainTx.UnsignedTx.(*UnsignedCreateChainTx)
+	if !ok || status != Committed {
+		return ids.ID{}, errNotBlockchain
+	}
+	return chain.SubnetID, nil
+}
+
 func (vm *VM) updateValidators(force bool) error {
 	now := vm.clock.Time()
 	if !force && !vm.bootstrapped && now.Sub(vm.lastVdrUpdate) < 5*time.Second {
@@ -28,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/score"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -1357,6 +1358,20 @@ func TestCreateChain(t *testing.T) {
 	if !foundNewChain {
 		t.Fatal("should've created new chain but didn't")
 	}
+
+	if subnetID, err := vm.GetSubnetID(tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if subnetID != testSubnet1.ID() {
+		t.Fatalf("expected chain to be validated by %s but got %s", testSubnet1.ID(), subnetID)
+	}
+	if subnetID, err := vm.GetSubnetID(constants.PlatformChainID); err != nil {
+		t.Fatal(err)
+	} else if subnetID != constants.PrimaryNetworkID {
+		t.Fatalf("expected P-chain to be validated by %s but got %s", constants.PrimaryNetworkID, subnetID)
+	}
+	if _, err := vm.GetSubnetID(testSubnet1.ID()); err != errNotBlockchain {
+		t.Fatalf("expected %s but got %v", errNotBlockchain, err)
+	}
 }
 
 // test where we:
@@ -2158,6 +2173,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 	err = handler.Initialize(
 		&engine,
 		vdrs,
+		score.NewNoTracker(),
 		msgChan,
 		"",
 		prometheus.NewRegistry(),
@@ -2168,7 +2184,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 	chainRouter.AddChain(handler)
 	go ctx.Log.RecoverAndPanic(handler.Dispatch)
 
-	if err := engine.Connected(peerID); err != nil {
+	if err := engine.Connected(peerID, version.CurrentApp); err != nil {
 		t.Fatal(err)
 	}
 
@@ -2461,3 +2477,237 @@ func TestUnverifiedParentPanic(t *testing.T) {
 	// Doesn't matter what verify returns as long as it's not panicking.
 	_ = addSubnetBlk2.Verify()
 }
+
+func TestGetValidatorSet(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	genesisHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisValidators, err := vm.GetValidatorSet(genesisHeight, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genesisValidators) != len(keys) {
+		t.Fatalf("expected %d genesis validators but got %d", len(keys), len(genesisValidators))
+	}
+
+	if _, err := vm.GetValidatorSet(genesisHeight+1, constants.PrimaryNetworkID); err != errUnfinalizedHeight {
+		t.Fatalf("expected %s but got %v", errUnfinalizedHeight, err)
+	}
+
+	if err := vm.SetPreference(vm.lastAcceptedID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fast forward clock to time for genesis validators to leave
+	vm.clock.Set(defaultValidateEndTime)
+
+	for i := 0; i < 2; i++ {
+		blk, err := vm.BuildBlock() // advance time, then reward a genesis validator
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+
+		block := blk.(*ProposalBlock)
+		options, err := block.Options()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit := options[0].(*CommitBlock)
+		if err := block.Accept(); err != nil {
+			t.Fatal(err)
+		}
+		if err := commit.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := commit.Accept(); err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.SetPreference(commit.ID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	currentHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedHeight := genesisHeight + 4; currentHeight != expectedHeight {
+		t.Fatalf("expected height %d but got %d", expectedHeight, currentHeight)
+	}
+
+	currentValidators, err := vm.GetValidatorSet(currentHeight, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(currentValidators) != len(keys)-1 {
+		t.Fatalf("expected %d current validators but got %d", len(keys)-1, len(currentValidators))
+	}
+
+	// The validator set before the reward block was accepted should still
+	// contain the removed validator.
+	for height := genesisHeight; height < currentHeight; height++ {
+		historicalValidators, err := vm.GetValidatorSet(height, constants.PrimaryNetworkID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(historicalValidators) != len(genesisValidators) {
+			t.Fatalf("expected %d validators at height %d but got %d", len(genesisValidators), height, len(historicalValidators))
+		}
+		for nodeID, weight := range genesisValidators {
+			if historicalWeight := historicalValidators[nodeID]; historicalWeight != weight {
+				t.Fatalf("expected weight %d for %s at height %d but got %d", weight, nodeID, height, historicalWeight)
+			}
+		}
+	}
+
+	// Modifying a returned validator set doesn't modify the cached one
+	for nodeID := range genesisValidators {
+		genesisValidators[nodeID] = 0
+	}
+	cachedValidators, err := vm.GetValidatorSet(genesisHeight, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nodeID, weight := range cachedValidators {
+		if weight == 0 {
+			t.Fatalf("expected the cached weight of %s to be unmodified", nodeID)
+		}
+	}
+
+	// The validator sets before the validator weight diffs were recorded
+	// can't be reconstructed
+	vm.internalState.(*internalStateImpl).validatorDiffsHeight = genesisHeight + 1
+	vm.validatorSetCache.Flush()
+	if _, err := vm.GetValidatorSet(genesisHeight, constants.PrimaryNetworkID); err != errUnrecordedHeight {
+		t.Fatalf("expected %s but got %v", errUnrecordedHeight, err)
+	}
+	if _, err := vm.GetValidatorSet(genesisHeight+1, constants.PrimaryNetworkID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyDatabase(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	db := vm.dbManager.Current().Database
+
+	findings, err := vm.VerifyDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+	findings, err = VerifyDatabase(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+
+	// An in-memory supply that wasn't persisted should be reported
+	vm.internalState.SetCurrentSupply(SupplyCap + 1)
+	findings, err = vm.VerifyDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding but got: %v", findings)
+	}
+
+	// A persisted supply above the supply cap should be reported
+	if err := vm.internalState.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	findings, err = VerifyDatabase(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding but got: %v", findings)
+	}
+}
+
+func TestBlockTimestamp(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	if err := vm.SetPreference(vm.lastAcceptedID); err != nil {
+		t.Fatal(err)
+	}
+	vm.clock.Set(defaultValidateEndTime)
+
+	blk, err := vm.BuildBlock() // should contain proposal to advance time
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The proposal is made at the chain time of its parent
+	block := blk.(*ProposalBlock)
+	if timestamp := block.Timestamp(); !timestamp.Equal(defaultGenesisTime) {
+		t.Fatalf("expected proposal timestamp %s but got %s", defaultGenesisTime, timestamp)
+	}
+
+	options, err := block.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := options[0].(*CommitBlock)
+	abort := options[1].(*AbortBlock)
+	if timestamp := commit.Timestamp(); !timestamp.IsZero() {
+		t.Fatalf("expected unverified block to have no timestamp but got %s", timestamp)
+	}
+	if err := commit.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := abort.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only committing the proposal advances the chain time
+	if timestamp := commit.Timestamp(); !timestamp.Equal(defaultValidateEndTime) {
+		t.Fatalf("expected commit timestamp %s but got %s", defaultValidateEndTime, timestamp)
+	}
+	if timestamp := abort.Timestamp(); !timestamp.Equal(defaultGenesisTime) {
+		t.Fatalf("expected abort timestamp %s but got %s", defaultGenesisTime, timestamp)
+	}
+
+	if err := block.Accept(); err != nil {
+		t.Fatal(err)
+	} else if err := commit.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last accepted block reports the chain time
+	if timestamp := commit.Timestamp(); !timestamp.Equal(defaultValidateEndTime) {
+		t.Fatalf("expected accepted commit timestamp %s but got %s", defaultValidateEndTime, timestamp)
+	}
+}
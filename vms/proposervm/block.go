@@ -0,0 +1,262 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/components/missing"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+const (
+	// maxSkew is the maximum amount of time that a block's timestamp may be
+	// ahead of the local clock.
+	maxSkew = 10 * time.Second
+)
+
+var (
+	errInnerParentMismatch      = errors.New("inner parentID didn't match expected parent")
+	errTimeNotMonotonic         = errors.New("time must monotonically increase")
+	errPChainHeightNotMonotonic = errors.New("non monotonically increasing P-chain height")
+	errPChainHeightNotReached   = errors.New("block P-chain height larger than current P-chain height")
+	errTimeTooAdvanced          = errors.New("time is too far advanced")
+	errProposerWindowNotStarted = errors.New("proposer window hasn't started")
+	errProposersNotActivated    = errors.New("proposers haven't been activated yet")
+	errProposersActivated       = errors.New("proposers have been activated")
+	errUnexpectedBlockType      = errors.New("unexpected proposer block type")
+	errUnexpectedPreForkChild   = errors.New("post-fork block can't have a pre-fork child")
+)
+
+// Block is a snowman block that is exposed to the consensus engine by the
+// proposervm.
+type Block interface {
+	snowman.Block
+
+	getInnerBlk() snowman.Block
+	setInnerBlk(snowman.Block)
+
+	// After the fork is activated, the engine may only verify children of
+	// oracle blocks that are options of the oracle block.
+	verifyPreForkChild(child *preForkBlock) error
+	verifyPostForkChild(child *postForkBlock) error
+	verifyPostForkOption(child *postForkOption) error
+
+	buildChild() (Block, error)
+
+	pChainHeight() (uint64, error)
+}
+
+// PostForkBlock is a Block that was issued after the fork was activated.
+type PostForkBlock interface {
+	Block
+
+	setStatus(status choices.Status)
+	getStatelessBlk() block.Block
+	timestamp() (time.Time, error)
+}
+
+// postForkCommonComponents contains the fields and logic that are shared by
+// postForkBlock and postForkOption.
+type postForkCommonComponents struct {
+	vm       *VM
+	innerBlk snowman.Block
+	status   choices.Status
+}
+
+func (p *postForkCommonComponents) getInnerBlk() snowman.Block { return p.innerBlk }
+
+func (p *postForkCommonComponents) setInnerBlk(innerBlk snowman.Block) { p.innerBlk = innerBlk }
+
+func (p *postForkCommonComponents) Height() uint64 { return p.innerBlk.Height() }
+
+func (p *postForkCommonComponents) Status() choices.Status { return p.status }
+
+func (p *postForkCommonComponents) setStatus(status choices.Status) { p.status = status }
+
+// Verify that [child] is a valid child of a post-fork block with the provided
+// [parentTimestamp] and [parentPChainHeight].
+func (p *postForkCommonComponents) Verify(
+	parentTimestamp time.Time,
+	parentPChainHeight uint64,
+	child *postForkBlock,
+) error {
+	if err := verifyIsNotOracleBlock(p.innerBlk); err != nil {
+		return err
+	}
+
+	childPChainHeight := child.PChainHeight()
+	if childPChainHeight < parentPChainHeight {
+		return errPChainHeightNotMonotonic
+	}
+
+	expectedInnerParentID := p.innerBlk.ID()
+	innerParentID := child.innerBlk.Parent().ID()
+	if innerParentID != expectedInnerParentID {
+		return errInnerParentMismatch
+	}
+
+	childTimestamp := child.Timestamp()
+	if childTimestamp.Before(parentTimestamp) {
+		return errTimeNotMonotonic
+	}
+
+	maxTimestamp := p.vm.clock.Time().Add(maxSkew)
+	if childTimestamp.After(maxTimestamp) {
+		return errTimeTooAdvanced
+	}
+
+	// The P-chain height is checked after the timestamp so that blocks that
+	// are invalid regardless of the local P-chain state are reported as such.
+	currentPChainHeight, err := p.vm.vdrState.GetCurrentHeight()
+	if err != nil {
+		return err
+	}
+	if childPChainHeight > currentPChainHeight {
+		return errPChainHeightNotReached
+	}
+
+	childHeight := child.Height()
+	proposerID := child.Proposer()
+	minDelay, err := p.vm.windower.Delay(childHeight, parentPChainHeight, proposerID)
+	if err != nil {
+		return err
+	}
+
+	delay := childTimestamp.Sub(parentTimestamp)
+	if delay < minDelay {
+		return errProposerWindowNotStarted
+	}
+
+	// Verify the signature of the node
+	shouldHaveProposer := delay < proposer.MaxDelay
+	if err := child.SignedBlock.Verify(shouldHaveProposer, p.vm.ctx.ChainID); err != nil {
+		return err
+	}
+
+	return p.vm.verifyAndRecordInnerBlk(child)
+}
+
+// buildChild builds a post-fork child of a post-fork block with the provided
+// [parentID], [parentTimestamp] and [parentPChainHeight].
+func (p *postForkCommonComponents) buildChild(
+	parentID ids.ID,
+	parentTimestamp time.Time,
+	parentPChainHeight uint64,
+) (Block, error) {
+	// Child's timestamp is the later of now and this block's timestamp
+	newTimestamp := p.vm.clock.Time().Truncate(time.Second)
+	if newTimestamp.Before(parentTimestamp) {
+		newTimestamp = parentTimestamp
+	}
+
+	// The child's P-Chain height is proposed as the current P-Chain height
+	// unless it would go backwards.
+	pChainHeight, err := p.vm.vdrState.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	if pChainHeight < parentPChainHeight {
+		pChainHeight = parentPChainHeight
+	}
+
+	delay := newTimestamp.Sub(parentTimestamp)
+	if delay < proposer.MaxDelay {
+		minDelay := proposer.MaxDelay
+		if p.vm.stakingCert != nil {
+			childHeight := p.innerBlk.Height() + 1
+			minDelay, err = p.vm.windower.Delay(childHeight, parentPChainHeight, p.vm.ctx.NodeID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if delay < minDelay {
+			return nil, errProposerWindowNotStarted
+		}
+	}
+
+	innerBlock, err := p.vm.ChainVM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var statelessBlock block.SignedBlock
+	if delay >= proposer.MaxDelay {
+		statelessBlock, err = block.BuildUnsigned(
+			parentID,
+			newTimestamp,
+			pChainHeight,
+			innerBlock.Bytes(),
+		)
+	} else {
+		statelessBlock, err = block.Build(
+			parentID,
+			newTimestamp,
+			pChainHeight,
+			p.vm.stakingCert,
+			innerBlock.Bytes(),
+			p.vm.ctx.ChainID,
+			p.vm.stakingKey,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	child := &postForkBlock{
+		SignedBlock: statelessBlock,
+		postForkCommonComponents: postForkCommonComponents{
+			vm:       p.vm,
+			innerBlk: innerBlock,
+			status:   choices.Processing,
+		},
+	}
+
+	p.vm.ctx.Log.Debug("built post-fork block %s with inner block %s at height %d",
+		child.ID(), innerBlock.ID(), child.Height())
+	return child, nil
+}
+
+// parent returns the block with [parentID] or a missing block if it isn't
+// known.
+func (p *postForkCommonComponents) parent(parentID ids.ID) snowman.Block {
+	parent, err := p.vm.getBlock(parentID)
+	if err != nil {
+		return &missing.Block{BlkID: parentID}
+	}
+	return parent
+}
+
+func verifyIsOracleBlock(b snowman.Block) error {
+	oracle, ok := b.(smeng.OracleBlock)
+	if !ok {
+		return errUnexpectedBlockType
+	}
+	_, err := oracle.Options()
+	return err
+}
+
+func verifyIsNotOracleBlock(b snowman.Block) error {
+	oracle, ok := b.(smeng.OracleBlock)
+	if !ok {
+		return nil
+	}
+	_, err := oracle.Options()
+	switch err {
+	case nil:
+		return errUnexpectedBlockType
+	case smeng.ErrNotOracle:
+		return nil
+	default:
+		return err
+	}
+}
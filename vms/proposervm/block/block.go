@@ -0,0 +1,116 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+var (
+	errUnexpectedProposer = errors.New("expected no proposer but one was provided")
+	errMissingProposer    = errors.New("expected proposer but none was provided")
+
+	_ SignedBlock = &statelessBlock{}
+)
+
+// Block is the header that the proposervm wraps around the bytes of an inner
+// block.
+type Block interface {
+	ID() ids.ID
+	ParentID() ids.ID
+	Block() []byte
+	Bytes() []byte
+
+	initialize(bytes []byte) error
+}
+
+// SignedBlock is a Block that was issued during a proposer window. If the
+// block was issued by a specific proposer, it is signed by the proposer's
+// staking key.
+type SignedBlock interface {
+	Block
+
+	PChainHeight() uint64
+	Timestamp() time.Time
+	Proposer() ids.ShortID
+
+	// Verify that the signature of the block is valid for the provided
+	// [chainID]. If [shouldHaveProposer] is false, the block must not be
+	// signed.
+	Verify(shouldHaveProposer bool, chainID ids.ID) error
+}
+
+type statelessUnsignedBlock struct {
+	ParentID     ids.ID `serialize:"true"`
+	Timestamp    int64  `serialize:"true"`
+	PChainHeight uint64 `serialize:"true"`
+	Certificate  []byte `serialize:"true"`
+	Block        []byte `serialize:"true"`
+}
+
+type statelessBlock struct {
+	StatelessBlock statelessUnsignedBlock `serialize:"true"`
+	Signature      []byte                 `serialize:"true"`
+
+	id        ids.ID
+	timestamp time.Time
+	cert      *x509.Certificate
+	proposer  ids.ShortID
+	bytes     []byte
+}
+
+func (b *statelessBlock) ID() ids.ID            { return b.id }
+func (b *statelessBlock) ParentID() ids.ID      { return b.StatelessBlock.ParentID }
+func (b *statelessBlock) Block() []byte         { return b.StatelessBlock.Block }
+func (b *statelessBlock) Bytes() []byte         { return b.bytes }
+func (b *statelessBlock) PChainHeight() uint64  { return b.StatelessBlock.PChainHeight }
+func (b *statelessBlock) Timestamp() time.Time  { return b.timestamp }
+func (b *statelessBlock) Proposer() ids.ShortID { return b.proposer }
+
+func (b *statelessBlock) initialize(bytes []byte) error {
+	b.bytes = bytes
+	b.id = hashing.ComputeHash256Array(bytes)
+	b.timestamp = time.Unix(b.StatelessBlock.Timestamp, 0)
+	if len(b.StatelessBlock.Certificate) == 0 {
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(b.StatelessBlock.Certificate)
+	if err != nil {
+		return err
+	}
+	b.cert = cert
+	b.proposer, err = ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+	return err
+}
+
+func (b *statelessBlock) Verify(shouldHaveProposer bool, chainID ids.ID) error {
+	if !shouldHaveProposer {
+		if len(b.Signature) > 0 || len(b.StatelessBlock.Certificate) > 0 {
+			return errUnexpectedProposer
+		}
+		return nil
+	}
+	if b.cert == nil {
+		return errMissingProposer
+	}
+
+	unsignedBytes := b.bytes[:len(b.bytes)-len(b.Signature)-signatureLenSize]
+	signedBytes := headerBytes(chainID, unsignedBytes)
+	return b.cert.CheckSignature(b.cert.SignatureAlgorithm, signedBytes, b.Signature)
+}
+
+// headerBytes returns the bytes that a proposer signs. The chainID is included
+// to prevent a signed block from being replayed on a different chain.
+func headerBytes(chainID ids.ID, unsignedBytes []byte) []byte {
+	signedBytes := make([]byte, len(chainID)+len(unsignedBytes))
+	copy(signedBytes, chainID[:])
+	copy(signedBytes[len(chainID):], unsignedBytes)
+	return signedBytes
+}
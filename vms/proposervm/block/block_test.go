@@ -0,0 +1,113 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+func TestBuildParseSigned(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.ID{1}
+	timestamp := time.Unix(123, 0)
+	pChainHeight := uint64(2)
+	innerBlockBytes := []byte{3}
+	chainID := ids.ID{4}
+
+	tlsCert, err := staking.NewTLSCert()
+	assert.NoError(err)
+
+	cert := tlsCert.Leaf
+	key := tlsCert.PrivateKey.(crypto.Signer)
+
+	builtBlock, err := Build(parentID, timestamp, pChainHeight, cert, innerBlockBytes, chainID, key)
+	assert.NoError(err)
+
+	assert.Equal(parentID, builtBlock.ParentID())
+	assert.Equal(pChainHeight, builtBlock.PChainHeight())
+	assert.Equal(timestamp, builtBlock.Timestamp())
+	assert.Equal(innerBlockBytes, builtBlock.Block())
+
+	expectedProposer, err := ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+	assert.NoError(err)
+	assert.Equal(expectedProposer, builtBlock.Proposer())
+
+	assert.NoError(builtBlock.Verify(true, chainID))
+	assert.Error(builtBlock.Verify(false, chainID))
+	assert.Error(builtBlock.Verify(true, ids.ID{5}), "should have failed verification with the wrong chainID")
+
+	parsedBlockIntf, err := Parse(builtBlock.Bytes())
+	assert.NoError(err)
+
+	parsedBlock, ok := parsedBlockIntf.(SignedBlock)
+	assert.True(ok)
+
+	assert.Equal(builtBlock.ID(), parsedBlock.ID())
+	assert.Equal(builtBlock.Bytes(), parsedBlock.Bytes())
+	assert.Equal(builtBlock.Proposer(), parsedBlock.Proposer())
+	assert.NoError(parsedBlock.Verify(true, chainID))
+}
+
+func TestBuildParseUnsigned(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.ID{1}
+	timestamp := time.Unix(123, 0)
+	pChainHeight := uint64(2)
+	innerBlockBytes := []byte{3}
+	chainID := ids.ID{4}
+
+	builtBlock, err := BuildUnsigned(parentID, timestamp, pChainHeight, innerBlockBytes)
+	assert.NoError(err)
+
+	assert.Equal(ids.ShortEmpty, builtBlock.Proposer())
+	assert.NoError(builtBlock.Verify(false, chainID))
+	assert.Error(builtBlock.Verify(true, chainID))
+
+	parsedBlockIntf, err := Parse(builtBlock.Bytes())
+	assert.NoError(err)
+
+	parsedBlock, ok := parsedBlockIntf.(SignedBlock)
+	assert.True(ok)
+
+	assert.Equal(builtBlock.ID(), parsedBlock.ID())
+	assert.Equal(timestamp, parsedBlock.Timestamp())
+	assert.NoError(parsedBlock.Verify(false, chainID))
+}
+
+func TestBuildParseOption(t *testing.T) {
+	assert := assert.New(t)
+
+	parentID := ids.ID{1}
+	innerBlockBytes := []byte{3}
+
+	builtOption, err := BuildOption(parentID, innerBlockBytes)
+	assert.NoError(err)
+
+	assert.Equal(parentID, builtOption.ParentID())
+	assert.Equal(innerBlockBytes, builtOption.Block())
+
+	parsedOption, err := Parse(builtOption.Bytes())
+	assert.NoError(err)
+
+	_, ok := parsedOption.(SignedBlock)
+	assert.False(ok)
+
+	assert.Equal(builtOption.ID(), parsedOption.ID())
+	assert.Equal(builtOption.Bytes(), parsedOption.Bytes())
+}
+
+func TestParseGibberish(t *testing.T) {
+	_, err := Parse([]byte{0, 1, 2, 3, 4, 5})
+	assert.Error(t, err)
+}
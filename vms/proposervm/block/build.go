@@ -0,0 +1,106 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// signatureLenSize is the number of bytes used to encode the length of the
+// signature, which is the last field of a serialized block.
+const signatureLenSize = wrappers.IntLen
+
+// BuildUnsigned returns a block that wasn't issued by a specific proposer.
+func BuildUnsigned(
+	parentID ids.ID,
+	timestamp time.Time,
+	pChainHeight uint64,
+	blockBytes []byte,
+) (SignedBlock, error) {
+	var blockIntf SignedBlock = &statelessBlock{
+		StatelessBlock: statelessUnsignedBlock{
+			ParentID:     parentID,
+			Timestamp:    timestamp.Unix(),
+			PChainHeight: pChainHeight,
+			Block:        blockBytes,
+		},
+		timestamp: timestamp,
+	}
+
+	bytes, err := c.Marshal(version, &blockIntf)
+	if err != nil {
+		return nil, err
+	}
+	return blockIntf, blockIntf.initialize(bytes)
+}
+
+// Build returns a block that was issued by the owner of [cert] and is signed
+// by [key].
+func Build(
+	parentID ids.ID,
+	timestamp time.Time,
+	pChainHeight uint64,
+	cert *x509.Certificate,
+	blockBytes []byte,
+	chainID ids.ID,
+	key crypto.Signer,
+) (SignedBlock, error) {
+	block := &statelessBlock{
+		StatelessBlock: statelessUnsignedBlock{
+			ParentID:     parentID,
+			Timestamp:    timestamp.Unix(),
+			PChainHeight: pChainHeight,
+			Certificate:  cert.Raw,
+			Block:        blockBytes,
+		},
+		timestamp: timestamp,
+	}
+	var blockIntf SignedBlock = block
+
+	unsignedBytesWithEmptySignature, err := c.Marshal(version, &blockIntf)
+	if err != nil {
+		return nil, err
+	}
+
+	// The serialized form of the block is the unsigned bytes followed by the
+	// signature, which is prefixed by its length. Because the signature is
+	// empty, we remove the length prefix to get the unsigned bytes.
+	unsignedBytes := unsignedBytesWithEmptySignature[:len(unsignedBytesWithEmptySignature)-signatureLenSize]
+
+	signedHash := hashing.ComputeHash256(headerBytes(chainID, unsignedBytes))
+	block.Signature, err = key.Sign(rand.Reader, signedHash, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := c.Marshal(version, &blockIntf)
+	if err != nil {
+		return nil, err
+	}
+	return block, block.initialize(bytes)
+}
+
+// BuildOption returns the wrapper of the option of an oracle block.
+func BuildOption(
+	parentID ids.ID,
+	innerBytes []byte,
+) (Block, error) {
+	var block Block = &option{
+		PrntID:     parentID,
+		InnerBytes: innerBytes,
+	}
+
+	bytes, err := c.Marshal(version, &block)
+	if err != nil {
+		return nil, err
+	}
+	return block, block.initialize(bytes)
+}
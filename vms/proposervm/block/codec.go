@@ -0,0 +1,32 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const version = 0
+
+var c codec.Manager
+
+func init() {
+	lc := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	c = codec.NewManager(math.MaxInt32)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		lc.RegisterType(&statelessBlock{}),
+		lc.RegisterType(&option{}),
+		c.RegisterCodec(version, lc),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
@@ -0,0 +1,21 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"fmt"
+)
+
+// Parse a block from its serialized form.
+func Parse(bytes []byte) (Block, error) {
+	var block Block
+	parsedVersion, err := c.Unmarshal(bytes, &block)
+	if err != nil {
+		return nil, err
+	}
+	if parsedVersion != version {
+		return nil, fmt.Errorf("expected codec version %d but got %d", version, parsedVersion)
+	}
+	return block, block.initialize(bytes)
+}
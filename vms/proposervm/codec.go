@@ -0,0 +1,26 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+)
+
+const codecVersion = 0
+
+// c is used to serialize the proposervm's on-disk state.
+var c codec.Manager
+
+func init() {
+	lc := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	c = codec.NewManager(math.MaxInt32)
+
+	if err := c.RegisterCodec(codecVersion, lc); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,96 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	_ PostForkBlock     = &postForkBlock{}
+	_ smeng.OracleBlock = &postForkBlock{}
+)
+
+// postForkBlock is a block that was issued after the fork was activated. It
+// wraps the bytes of an inner block with a header that specifies when, and by
+// whom, the block was proposed.
+type postForkBlock struct {
+	block.SignedBlock
+	postForkCommonComponents
+}
+
+// Accept persists the block, marks the inner block as accepted and rejects
+// all the inner blocks that conflict with it.
+func (b *postForkBlock) Accept() error {
+	return b.vm.acceptPostForkBlock(b)
+}
+
+// Reject persists the rejection of the block. The inner block isn't rejected
+// here, as it may be wrapped by another post-fork block that will be
+// accepted. The inner block will be rejected when a conflicting inner block
+// is accepted.
+func (b *postForkBlock) Reject() error {
+	return b.vm.rejectPostForkBlock(b)
+}
+
+func (b *postForkBlock) Parent() snowman.Block { return b.parent(b.ParentID()) }
+
+func (b *postForkBlock) Verify() error {
+	parent, err := b.vm.getBlock(b.ParentID())
+	if err != nil {
+		return err
+	}
+	return parent.verifyPostForkChild(b)
+}
+
+func (b *postForkBlock) Options() ([2]snowman.Block, error) {
+	return b.vm.getPostForkOptions(b)
+}
+
+func (b *postForkBlock) getStatelessBlk() block.Block { return b.SignedBlock }
+
+func (b *postForkBlock) timestamp() (time.Time, error) { return b.Timestamp(), nil }
+
+func (b *postForkBlock) pChainHeight() (uint64, error) { return b.PChainHeight(), nil }
+
+// A post-fork block can never have a pre-fork child.
+func (b *postForkBlock) verifyPreForkChild(child *preForkBlock) error {
+	return errUnexpectedPreForkChild
+}
+
+func (b *postForkBlock) verifyPostForkChild(child *postForkBlock) error {
+	return b.postForkCommonComponents.Verify(
+		b.Timestamp(),
+		b.PChainHeight(),
+		child,
+	)
+}
+
+func (b *postForkBlock) verifyPostForkOption(child *postForkOption) error {
+	if err := verifyIsOracleBlock(b.innerBlk); err != nil {
+		return err
+	}
+
+	// Make sure [b]'s inner block is the parent of [child]'s inner block
+	expectedInnerParentID := b.innerBlk.ID()
+	innerParentID := child.innerBlk.Parent().ID()
+	if innerParentID != expectedInnerParentID {
+		return errInnerParentMismatch
+	}
+
+	return b.vm.verifyAndRecordInnerBlk(child)
+}
+
+func (b *postForkBlock) buildChild() (Block, error) {
+	return b.postForkCommonComponents.buildChild(
+		b.ID(),
+		b.Timestamp(),
+		b.PChainHeight(),
+	)
+}
@@ -0,0 +1,107 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	_ PostForkBlock     = &postForkOption{}
+	_ smeng.OracleBlock = &postForkOption{}
+)
+
+// postForkOption wraps an option of a post-fork oracle block. An option
+// inherits the timestamp and P-chain height of its parent.
+type postForkOption struct {
+	block.Block
+	postForkCommonComponents
+}
+
+func (b *postForkOption) Accept() error {
+	return b.vm.acceptPostForkBlock(b)
+}
+
+func (b *postForkOption) Reject() error {
+	return b.vm.rejectPostForkBlock(b)
+}
+
+func (b *postForkOption) Parent() snowman.Block { return b.parent(b.ParentID()) }
+
+func (b *postForkOption) Verify() error {
+	parent, err := b.vm.getPostForkBlock(b.ParentID())
+	if err != nil {
+		return err
+	}
+	return parent.verifyPostForkOption(b)
+}
+
+func (b *postForkOption) Options() ([2]snowman.Block, error) {
+	return b.vm.getPostForkOptions(b)
+}
+
+func (b *postForkOption) getStatelessBlk() block.Block { return b.Block }
+
+func (b *postForkOption) timestamp() (time.Time, error) {
+	parent, err := b.vm.getPostForkBlock(b.ParentID())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parent.timestamp()
+}
+
+func (b *postForkOption) pChainHeight() (uint64, error) {
+	parent, err := b.vm.getPostForkBlock(b.ParentID())
+	if err != nil {
+		return 0, err
+	}
+	return parent.pChainHeight()
+}
+
+// A post-fork block can never have a pre-fork child.
+func (b *postForkOption) verifyPreForkChild(child *preForkBlock) error {
+	return errUnexpectedPreForkChild
+}
+
+func (b *postForkOption) verifyPostForkChild(child *postForkBlock) error {
+	parentTimestamp, err := b.timestamp()
+	if err != nil {
+		return err
+	}
+	parentPChainHeight, err := b.pChainHeight()
+	if err != nil {
+		return err
+	}
+	return b.postForkCommonComponents.Verify(
+		parentTimestamp,
+		parentPChainHeight,
+		child,
+	)
+}
+
+// An option can never have an option as its child.
+func (b *postForkOption) verifyPostForkOption(child *postForkOption) error {
+	return errUnexpectedBlockType
+}
+
+func (b *postForkOption) buildChild() (Block, error) {
+	parentTimestamp, err := b.timestamp()
+	if err != nil {
+		return nil, err
+	}
+	parentPChainHeight, err := b.pChainHeight()
+	if err != nil {
+		return nil, err
+	}
+	return b.postForkCommonComponents.buildChild(
+		b.ID(),
+		parentTimestamp,
+		parentPChainHeight,
+	)
+}
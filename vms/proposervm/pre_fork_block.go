@@ -0,0 +1,207 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/vms/components/missing"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var (
+	_ Block             = &preForkBlock{}
+	_ smeng.OracleBlock = &preForkBlock{}
+)
+
+// preForkBlock wraps a block of the inner VM that was issued before the fork
+// was activated. A preForkBlock has the same ID and bytes as the block it
+// wraps.
+type preForkBlock struct {
+	snowman.Block
+	vm *VM
+}
+
+func (b *preForkBlock) getInnerBlk() snowman.Block { return b.Block }
+
+func (b *preForkBlock) setInnerBlk(innerBlk snowman.Block) { b.Block = innerBlk }
+
+func (b *preForkBlock) Parent() snowman.Block {
+	parentID := b.Block.Parent().ID()
+	parent, err := b.vm.getBlock(parentID)
+	if err != nil {
+		return &missing.Block{BlkID: parentID}
+	}
+	return parent
+}
+
+func (b *preForkBlock) Verify() error {
+	parent, err := b.vm.getPreForkBlock(b.Block.Parent().ID())
+	if err != nil {
+		return err
+	}
+	return parent.verifyPreForkChild(b)
+}
+
+// Accept the inner block and reject all the inner blocks that conflict with
+// it.
+func (b *preForkBlock) Accept() error {
+	return b.vm.tree.Accept(b.Block)
+}
+
+// Reject is a no-op. The inner block will be rejected when a conflicting
+// block is accepted. Rejecting it here could reject an inner block that is
+// also wrapped by an accepted post-fork block.
+func (b *preForkBlock) Reject() error { return nil }
+
+func (b *preForkBlock) Options() ([2]snowman.Block, error) {
+	oracleBlk, ok := b.Block.(smeng.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, smeng.ErrNotOracle
+	}
+
+	options, err := oracleBlk.Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	// A pre-fork oracle block may only have pre-fork options
+	return [2]snowman.Block{
+		&preForkBlock{
+			Block: options[0],
+			vm:    b.vm,
+		},
+		&preForkBlock{
+			Block: options[1],
+			vm:    b.vm,
+		},
+	}, nil
+}
+
+func (b *preForkBlock) pChainHeight() (uint64, error) { return 0, nil }
+
+// Timestamp returns the time the inner block was produced at, or the zero time
+// if the inner block doesn't know it.
+func (b *preForkBlock) Timestamp() time.Time { return smblock.Timestamp(b.Block) }
+
+func (b *preForkBlock) verifyPreForkChild(child *preForkBlock) error {
+	if parentTimestamp := b.Timestamp(); !parentTimestamp.Before(b.vm.activationTime) {
+		// The parent was produced once the fork was activated, so its children
+		// must be post-fork blocks.
+		return errProposersActivated
+	}
+	if _, err := b.vm.state.getLastAccepted(); err == nil {
+		// A post-fork block has already been accepted, so no new pre-fork
+		// blocks may be accepted. This is the only check for inner blocks
+		// that don't know when they were produced.
+		return errProposersActivated
+	}
+
+	return b.vm.verifyAndRecordInnerBlk(child)
+}
+
+// A post-fork block can never have a pre-fork option as its child.
+func (b *preForkBlock) verifyPostForkOption(child *postForkOption) error {
+	return errUnexpectedBlockType
+}
+
+// This method only returns nil once, when the fork is activated.
+func (b *preForkBlock) verifyPostForkChild(child *postForkBlock) error {
+	if err := verifyIsNotOracleBlock(b.Block); err != nil {
+		return err
+	}
+
+	childTimestamp := child.Timestamp()
+	if childTimestamp.Before(b.vm.activationTime) {
+		return errProposersNotActivated
+	}
+
+	maxTimestamp := b.vm.clock.Time().Add(maxSkew)
+	if childTimestamp.After(maxTimestamp) {
+		return errTimeTooAdvanced
+	}
+
+	expectedInnerParentID := b.Block.ID()
+	innerParentID := child.innerBlk.Parent().ID()
+	if innerParentID != expectedInnerParentID {
+		return errInnerParentMismatch
+	}
+
+	currentPChainHeight, err := b.vm.vdrState.GetCurrentHeight()
+	if err != nil {
+		return err
+	}
+	if child.PChainHeight() > currentPChainHeight {
+		return errPChainHeightNotReached
+	}
+
+	// The first post-fork block must not be signed, as there is no parent
+	// post-fork block to derive the proposer windows from.
+	if err := child.SignedBlock.Verify(false, b.vm.ctx.ChainID); err != nil {
+		return err
+	}
+
+	return b.vm.verifyAndRecordInnerBlk(child)
+}
+
+func (b *preForkBlock) buildChild() (Block, error) {
+	parentTimestamp := b.vm.activationTime
+	newTimestamp := b.vm.clock.Time()
+	if newTimestamp.Before(parentTimestamp) && b.Timestamp().Before(parentTimestamp) {
+		// The fork hasn't been activated yet, so a pre-fork block is built.
+		innerBlock, err := b.vm.ChainVM.BuildBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		b.vm.ctx.Log.Debug("built pre-fork block %s", innerBlock.ID())
+		return &preForkBlock{
+			Block: innerBlock,
+			vm:    b.vm,
+		}, nil
+	}
+
+	// The fork has been activated, so an unsigned post-fork block is built.
+	newTimestamp = newTimestamp.Truncate(time.Second)
+	if newTimestamp.Before(parentTimestamp) {
+		newTimestamp = parentTimestamp
+	}
+
+	pChainHeight, err := b.vm.vdrState.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	innerBlock, err := b.vm.ChainVM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	statelessBlock, err := block.BuildUnsigned(
+		b.ID(),
+		newTimestamp,
+		pChainHeight,
+		innerBlock.Bytes(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := &postForkBlock{
+		SignedBlock: statelessBlock,
+		postForkCommonComponents: postForkCommonComponents{
+			vm:       b.vm,
+			innerBlk: innerBlock,
+			status:   choices.Processing,
+		},
+	}
+
+	b.vm.ctx.Log.Info("built first post-fork block %s with inner block %s at height %d",
+		blk.ID(), innerBlock.ID(), blk.Height())
+	return blk, nil
+}
@@ -0,0 +1,149 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposer
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// MaxWindows is the number of proposers that are given a dedicated window
+	// to build a block on top of any given parent.
+	MaxWindows = 6
+
+	// WindowDuration is the amount of time each proposer has to build a block
+	// before the next proposer is allowed to.
+	WindowDuration = 5 * time.Second
+
+	// MaxDelay is the amount of time after which any node is allowed to build
+	// a block.
+	MaxDelay = MaxWindows * WindowDuration
+)
+
+var _ Windower = &windower{}
+
+// Windower calculates when each validator is allowed to propose a block.
+type Windower interface {
+	// Delay returns the amount of time that [validatorID] must wait after the
+	// parent's timestamp before it is allowed to propose a block at
+	// [chainHeight]. The proposers are sampled from the validator set at
+	// [pChainHeight].
+	Delay(
+		chainHeight,
+		pChainHeight uint64,
+		validatorID ids.ShortID,
+	) (time.Duration, error)
+}
+
+// windower interfaces with P-Chain and it is responsible for calculating the
+// delay for the block submission window of a given validator
+type windower struct {
+	state       validators.State
+	subnetID    ids.ID
+	chainSource uint64
+	sampler     sampler.WeightedWithoutReplacement
+}
+
+// New returns a Windower that samples proposers from the validators of
+// [subnetID] to build blocks on the chain [chainID].
+func New(state validators.State, subnetID, chainID ids.ID) Windower {
+	w := wrappers.Packer{Bytes: chainID[:]}
+	return &windower{
+		state:       state,
+		subnetID:    subnetID,
+		chainSource: w.UnpackLong(),
+		sampler:     sampler.NewDeterministicWeightedWithoutReplacement(),
+	}
+}
+
+func (w *windower) Delay(chainHeight, pChainHeight uint64, validatorID ids.ShortID) (time.Duration, error) {
+	if validatorID == ids.ShortEmpty {
+		return MaxDelay, nil
+	}
+
+	// get the validator set by the p-chain height
+	validatorsMap, err := w.state.GetValidatorSet(pChainHeight, w.subnetID)
+	if err != nil {
+		return 0, err
+	}
+
+	// convert the map of validators to a slice
+	validators := make(validatorsSlice, 0, len(validatorsMap))
+	weight := uint64(0)
+	for k, v := range validatorsMap {
+		validators = append(validators, validatorData{
+			id:     k,
+			weight: v,
+		})
+		newWeight, err := math.Add64(weight, v)
+		if err != nil {
+			return 0, err
+		}
+		weight = newWeight
+	}
+
+	// canonically sort validators
+	// Note: validators are sorted by ID, sorting by weight would not create a
+	// canonically sorted list
+	sort.Sort(validators)
+
+	// convert the slice of validators to a slice of weights
+	validatorWeights := make([]uint64, len(validators))
+	for i, v := range validators {
+		validatorWeights[i] = v.weight
+	}
+
+	if err := w.sampler.Initialize(validatorWeights); err != nil {
+		return 0, err
+	}
+
+	numToSample := MaxWindows
+	if weight < uint64(numToSample) {
+		numToSample = int(weight)
+	}
+
+	seed := chainHeight ^ w.chainSource
+	w.sampler.Seed(int64(seed))
+
+	indices, err := w.sampler.Sample(numToSample)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := time.Duration(0)
+	for _, index := range indices {
+		nodeID := validators[index].id
+		if nodeID == validatorID {
+			return delay, nil
+		}
+		delay += WindowDuration
+	}
+	// [validatorID] wasn't sampled for a dedicated window, so it must wait
+	// until anyone is allowed to propose a block.
+	return MaxDelay, nil
+}
+
+type validatorData struct {
+	id     ids.ShortID
+	weight uint64
+}
+
+type validatorsSlice []validatorData
+
+func (d validatorsSlice) Len() int      { return len(d) }
+func (d validatorsSlice) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+func (d validatorsSlice) Less(i, j int) bool {
+	iID := d[i].id
+	jID := d[j].id
+	return bytes.Compare(iID[:], jID[:]) == -1
+}
@@ -0,0 +1,146 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestWindowerNoValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestShortID()
+	vdrState := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+			return nil, nil
+		},
+	}
+
+	w := New(vdrState, subnetID, chainID)
+
+	delay, err := w.Delay(1, 0, nodeID)
+	assert.NoError(err)
+	assert.EqualValues(MaxDelay, delay)
+}
+
+func TestWindowerRepeatedValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	validatorID := ids.GenerateTestShortID()
+	nonValidatorID := ids.GenerateTestShortID()
+	vdrState := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+			return map[ids.ShortID]uint64{
+				validatorID: 10,
+			}, nil
+		},
+	}
+
+	w := New(vdrState, subnetID, chainID)
+
+	validatorDelay, err := w.Delay(1, 0, validatorID)
+	assert.NoError(err)
+	assert.EqualValues(0, validatorDelay)
+
+	nonValidatorDelay, err := w.Delay(1, 0, nonValidatorID)
+	assert.NoError(err)
+	assert.EqualValues(MaxDelay, nonValidatorDelay)
+
+	emptyDelay, err := w.Delay(1, 0, ids.ShortEmpty)
+	assert.NoError(err)
+	assert.EqualValues(MaxDelay, emptyDelay)
+}
+
+func TestWindowerChangeByHeight(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.ID{0, 1}
+	chainID := ids.ID{0, 2}
+	validatorIDs := make([]ids.ShortID, MaxWindows)
+	for i := range validatorIDs {
+		validatorIDs[i] = ids.ShortID{byte(i + 1)}
+	}
+	vdrState := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+			vdrs := make(map[ids.ShortID]uint64, MaxWindows)
+			for _, id := range validatorIDs {
+				vdrs[id] = 1
+			}
+			return vdrs, nil
+		},
+	}
+
+	w := New(vdrState, subnetID, chainID)
+
+	// Every validator should be given a unique window at every height, and
+	// the windows should be the same when re-calculated.
+	for chainHeight := uint64(1); chainHeight < 10; chainHeight++ {
+		seenDelays := make(map[time.Duration]struct{})
+		for _, validatorID := range validatorIDs {
+			delay, err := w.Delay(chainHeight, 0, validatorID)
+			assert.NoError(err)
+			assert.Less(int64(delay), int64(MaxDelay))
+			assert.Zero(delay % WindowDuration)
+
+			_, seen := seenDelays[delay]
+			assert.False(seen, "validators should have unique windows")
+			seenDelays[delay] = struct{}{}
+
+			sameDelay, err := w.Delay(chainHeight, 0, validatorID)
+			assert.NoError(err)
+			assert.Equal(delay, sameDelay, "windows should be deterministic")
+		}
+	}
+}
+
+func TestWindowerDifferentChains(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	validatorIDs := make([]ids.ShortID, MaxWindows)
+	for i := range validatorIDs {
+		validatorIDs[i] = ids.GenerateTestShortID()
+	}
+	vdrState := &validators.TestState{
+		T: t,
+		GetValidatorSetF: func(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+			vdrs := make(map[ids.ShortID]uint64, MaxWindows)
+			for _, id := range validatorIDs {
+				vdrs[id] = 1
+			}
+			return vdrs, nil
+		},
+	}
+
+	// The proposer order should eventually differ between chains at the same
+	// height.
+	differs := false
+	for i := 0; i < 10 && !differs; i++ {
+		w0 := New(vdrState, subnetID, ids.GenerateTestID())
+		w1 := New(vdrState, subnetID, ids.GenerateTestID())
+		for _, validatorID := range validatorIDs {
+			delay0, err := w0.Delay(1, 0, validatorID)
+			assert.NoError(err)
+			delay1, err := w1.Delay(1, 0, validatorID)
+			assert.NoError(err)
+			if delay0 != delay1 {
+				differs = true
+			}
+		}
+	}
+	assert.True(differs, "proposer windows shouldn't depend only on the height")
+}
@@ -0,0 +1,115 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scheduler
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Scheduler forwards the messages of the inner VM to the consensus engine once
+// this node is allowed to propose a block.
+type Scheduler interface {
+	// Dispatch forwards messages until Close is called. [startTime] is the
+	// earliest time that a message may be forwarded.
+	Dispatch(startTime time.Time)
+
+	// SetBuildBlockTime notifies the scheduler that the earliest time this
+	// node is allowed to propose a block has changed.
+	SetBuildBlockTime(t time.Time)
+
+	Close()
+}
+
+// If the scheduler is told to dispatch a message at [buildBlockTime], it will
+// do so at the earliest after [buildBlockTime].
+type scheduler struct {
+	log logging.Logger
+
+	// The VM sends a message on this channel when it wants to tell the engine
+	// that the engine should call the VM's BuildBlock method
+	fromVM <-chan common.Message
+
+	// The scheduler sends a message on this channel to notify the engine that
+	// it should call its VM's BuildBlock method
+	toEngine chan<- common.Message
+
+	// When we receive a message on this channel, it means that we must refrain
+	// from telling the engine to call its VM's BuildBlock method until the
+	// given time
+	newBuildBlockTime chan time.Time
+}
+
+// New returns a Scheduler and the channel that the inner VM should send its
+// messages on.
+func New(log logging.Logger, toEngine chan<- common.Message) (Scheduler, chan<- common.Message) {
+	vmToEngine := make(chan common.Message, cap(toEngine))
+	return &scheduler{
+		log:               log,
+		fromVM:            vmToEngine,
+		toEngine:          toEngine,
+		newBuildBlockTime: make(chan time.Time),
+	}, vmToEngine
+}
+
+func (s *scheduler) Dispatch(buildBlockTime time.Time) {
+	timer := time.NewTimer(time.Until(buildBlockTime))
+waitloop:
+	for {
+		select {
+		case <-timer.C: // It's time to tell the engine to try to build a block
+		case buildBlockTime, ok := <-s.newBuildBlockTime:
+			// Stop the timer and clear [timer.C] if needed
+			if !timer.Stop() {
+				<-timer.C
+			}
+
+			if !ok {
+				// s.Close() was called
+				return
+			}
+
+			// The time at which we should notify the engine that it should try
+			// to build a block has changed
+			timer.Reset(time.Until(buildBlockTime))
+			continue waitloop
+		}
+
+		for {
+			select {
+			case msg := <-s.fromVM:
+				// Give the engine the message from the VM asking the engine to
+				// build a block
+				select {
+				case s.toEngine <- msg:
+				default:
+					// If the channel to the engine is full, drop the message
+					// from the VM to avoid deadlock
+					s.log.Debug("dropping message %s from VM because channel to engine is full", msg)
+				}
+			case buildBlockTime, ok := <-s.newBuildBlockTime:
+				// The time at which we should notify the engine that it should
+				// try to build a block has changed
+				if !ok {
+					// s.Close() was called
+					return
+				}
+				// We know [timer.C] was drained in the first select statement
+				// so its safe to call [timer.Reset]
+				timer.Reset(time.Until(buildBlockTime))
+				continue waitloop
+			}
+		}
+	}
+}
+
+func (s *scheduler) SetBuildBlockTime(t time.Time) {
+	s.newBuildBlockTime <- t
+}
+
+func (s *scheduler) Close() {
+	close(s.newBuildBlockTime)
+}
@@ -0,0 +1,121 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+const blockCacheSize = 2048
+
+var (
+	blockPrefix     = []byte("block")
+	singletonPrefix = []byte("singleton")
+
+	lastAcceptedKey = []byte("lastAccepted")
+)
+
+// stateBlk is the on-disk representation of a post-fork block.
+type stateBlk struct {
+	Bytes  []byte         `serialize:"true"`
+	Status choices.Status `serialize:"true"`
+}
+
+type cachedBlk struct {
+	blk    block.Block
+	status choices.Status
+}
+
+// state persists the post-fork blocks that have been decided as well as the
+// last accepted post-fork block.
+type state struct {
+	blkCache cache.Cacher
+	blkDB    database.Database
+
+	singletonDB database.Database
+}
+
+func newState(db database.Database) *state {
+	return &state{
+		blkCache:    &cache.LRU{Size: blockCacheSize},
+		blkDB:       prefixdb.New(blockPrefix, db),
+		singletonDB: prefixdb.New(singletonPrefix, db),
+	}
+}
+
+func (s *state) getBlock(blkID ids.ID) (block.Block, choices.Status, error) {
+	if blkIntf, found := s.blkCache.Get(blkID); found {
+		if blkIntf == nil {
+			return nil, choices.Unknown, database.ErrNotFound
+		}
+		blk := blkIntf.(*cachedBlk)
+		return blk.blk, blk.status, nil
+	}
+
+	blkBytes, err := s.blkDB.Get(blkID[:])
+	if err == database.ErrNotFound {
+		s.blkCache.Put(blkID, nil)
+		return nil, choices.Unknown, database.ErrNotFound
+	}
+	if err != nil {
+		return nil, choices.Unknown, err
+	}
+
+	sblk := stateBlk{}
+	parsedVersion, err := c.Unmarshal(blkBytes, &sblk)
+	if err != nil {
+		return nil, choices.Unknown, err
+	}
+	if parsedVersion != codecVersion {
+		return nil, choices.Unknown, fmt.Errorf("expected codec version %d but got %d", codecVersion, parsedVersion)
+	}
+
+	blk, err := block.Parse(sblk.Bytes)
+	if err != nil {
+		return nil, choices.Unknown, err
+	}
+
+	s.blkCache.Put(blkID, &cachedBlk{
+		blk:    blk,
+		status: sblk.Status,
+	})
+	return blk, sblk.Status, nil
+}
+
+func (s *state) putBlock(blk block.Block, status choices.Status) error {
+	sblk := stateBlk{
+		Bytes:  blk.Bytes(),
+		Status: status,
+	}
+	bytes, err := c.Marshal(codecVersion, &sblk)
+	if err != nil {
+		return err
+	}
+
+	blkID := blk.ID()
+	s.blkCache.Put(blkID, &cachedBlk{
+		blk:    blk,
+		status: status,
+	})
+	return s.blkDB.Put(blkID[:], bytes)
+}
+
+func (s *state) getLastAccepted() (ids.ID, error) {
+	lastAcceptedBytes, err := s.singletonDB.Get(lastAcceptedKey)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(lastAcceptedBytes)
+}
+
+func (s *state) setLastAccepted(blkID ids.ID) error {
+	return s.singletonDB.Put(lastAcceptedKey, blkID[:])
+}
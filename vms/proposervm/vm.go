@@ -0,0 +1,412 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"crypto"
+	"crypto/x509"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+	"github.com/ava-labs/avalanchego/vms/proposervm/scheduler"
+	"github.com/ava-labs/avalanchego/vms/proposervm/tree"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+	statelessblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+var (
+	dbPrefix = []byte("proposervm")
+
+	_ block.ChainVM = &VM{}
+)
+
+// VM wraps a snowman VM. After [activationTime], the wrapped blocks include a
+// header that restricts which validators may propose a block at any given
+// time. This reduces the number of conflicting blocks that are issued.
+type VM struct {
+	block.ChainVM
+	activationTime time.Time
+	vdrState       validators.State
+	stakingCert    *x509.Certificate
+	stakingKey     crypto.Signer
+
+	ctx       *snow.Context
+	db        *versiondb.Database
+	state     *state
+	windower  proposer.Windower
+	tree      tree.Tree
+	scheduler scheduler.Scheduler
+	clock     timer.Clock
+
+	// Block ID --> Block
+	// Each element is a block that passed verification but hasn't yet been
+	// decided.
+	verifiedBlocks map[ids.ID]PostForkBlock
+	preferred      ids.ID
+}
+
+// New returns a VM that wraps [vm]. [vdrState] is used to look up the
+// validator sets that the proposer windows are sampled from. If [stakingCert]
+// is nil, this node will only issue blocks once every proposer window has
+// passed.
+func New(
+	vm block.ChainVM,
+	activationTime time.Time,
+	vdrState validators.State,
+	stakingCert *x509.Certificate,
+	stakingKey crypto.Signer,
+) *VM {
+	return &VM{
+		ChainVM:        vm,
+		activationTime: activationTime,
+		vdrState:       vdrState,
+		stakingCert:    stakingCert,
+		stakingKey:     stakingKey,
+	}
+}
+
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisBytes,
+	upgradeBytes,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) error {
+	vm.ctx = ctx
+	rawDB := dbManager.Current().Database
+	prefixDB := prefixdb.New(dbPrefix, rawDB)
+	vm.db = versiondb.New(prefixDB)
+	vm.state = newState(vm.db)
+	vm.windower = proposer.New(vm.vdrState, ctx.SubnetID, ctx.ChainID)
+	vm.tree = tree.New()
+
+	scheduler, vmToEngine := scheduler.New(ctx.Log, toEngine)
+	vm.scheduler = scheduler
+	vm.verifiedBlocks = make(map[ids.ID]PostForkBlock)
+
+	go ctx.Log.RecoverAndPanic(func() {
+		scheduler.Dispatch(vm.clock.Time())
+	})
+
+	if err := vm.ChainVM.Initialize(
+		ctx,
+		dbManager,
+		genesisBytes,
+		upgradeBytes,
+		configBytes,
+		vmToEngine,
+		fxs,
+	); err != nil {
+		return err
+	}
+
+	return vm.repairAcceptedChain()
+}
+
+func (vm *VM) Shutdown() error {
+	vm.scheduler.Close()
+
+	if err := vm.ChainVM.Shutdown(); err != nil {
+		return err
+	}
+	return vm.db.Close()
+}
+
+func (vm *VM) BuildBlock() (snowman.Block, error) {
+	preferredBlock, err := vm.getBlock(vm.preferred)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferredBlock.buildChild()
+}
+
+func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
+	if blk, err := vm.parsePostForkBlock(b); err == nil {
+		return blk, nil
+	}
+	return vm.parsePreForkBlock(b)
+}
+
+func (vm *VM) GetBlock(id ids.ID) (snowman.Block, error) {
+	return vm.getBlock(id)
+}
+
+func (vm *VM) SetPreference(preferred ids.ID) error {
+	if vm.preferred == preferred {
+		return nil
+	}
+	vm.preferred = preferred
+
+	blk, err := vm.getBlock(preferred)
+	if err != nil {
+		return err
+	}
+
+	if err := vm.ChainVM.SetPreference(blk.getInnerBlk().ID()); err != nil {
+		return err
+	}
+
+	postForkBlk, ok := blk.(PostForkBlock)
+	if !ok {
+		// A child of a pre-fork block can be built immediately.
+		vm.scheduler.SetBuildBlockTime(vm.clock.Time())
+		return nil
+	}
+
+	parentTimestamp, err := postForkBlk.timestamp()
+	if err != nil {
+		return err
+	}
+	pChainHeight, err := postForkBlk.pChainHeight()
+	if err != nil {
+		return err
+	}
+
+	minDelay := proposer.MaxDelay
+	if vm.stakingCert != nil {
+		minDelay, err = vm.windower.Delay(blk.Height()+1, pChainHeight, vm.ctx.NodeID)
+		if err != nil {
+			vm.ctx.Log.Warn("failed to calculate the proposer window of block %s: %s", preferred, err)
+			minDelay = proposer.MaxDelay
+		}
+	}
+
+	// Note: The P-chain height is only used to sample the proposers of this
+	// block's children, so it doesn't need to be re-evaluated here.
+	nextStartTime := parentTimestamp.Add(minDelay)
+	vm.scheduler.SetBuildBlockTime(nextStartTime)
+
+	vm.ctx.Log.Debug("set preference to %s with timestamp %v; build time scheduled at %v",
+		preferred, parentTimestamp, nextStartTime)
+	return nil
+}
+
+func (vm *VM) LastAccepted() (ids.ID, error) {
+	lastAccepted, err := vm.state.getLastAccepted()
+	if err == database.ErrNotFound {
+		return vm.ChainVM.LastAccepted()
+	}
+	return lastAccepted, err
+}
+
+// repairAcceptedChain makes sure that the inner VM has accepted the inner
+// block of the last accepted post-fork block. The post-fork block is persisted
+// before the inner block is accepted, so if the node crashed in between the
+// two, the inner block is accepted here.
+func (vm *VM) repairAcceptedChain() error {
+	lastAcceptedID, err := vm.state.getLastAccepted()
+	if err == database.ErrNotFound {
+		// The fork hasn't been accepted yet, so there is nothing to repair.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	lastAccepted, err := vm.getPostForkBlock(lastAcceptedID)
+	if err != nil {
+		return err
+	}
+
+	innerBlk := lastAccepted.getInnerBlk()
+	if innerBlk.Status() == choices.Accepted {
+		return nil
+	}
+
+	vm.ctx.Log.Info("accepting inner block %s of the last accepted block %s",
+		innerBlk.ID(), lastAcceptedID)
+	if err := innerBlk.Verify(); err != nil {
+		return err
+	}
+	return innerBlk.Accept()
+}
+
+func (vm *VM) parsePostForkBlock(b []byte) (PostForkBlock, error) {
+	statelessBlock, err := statelessblock.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the block already exists, then make sure the status is set correctly
+	blkID := statelessBlock.ID()
+	blk, err := vm.getPostForkBlock(blkID)
+	if err == nil {
+		return blk, nil
+	}
+	if err != database.ErrNotFound {
+		return nil, err
+	}
+
+	innerBlk, err := vm.ChainVM.ParseBlock(statelessBlock.Block())
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.wrapPostForkBlock(statelessBlock, innerBlk, choices.Processing), nil
+}
+
+func (vm *VM) parsePreForkBlock(b []byte) (*preForkBlock, error) {
+	blk, err := vm.ChainVM.ParseBlock(b)
+	return &preForkBlock{
+		Block: blk,
+		vm:    vm,
+	}, err
+}
+
+func (vm *VM) getBlock(id ids.ID) (Block, error) {
+	if blk, err := vm.getPostForkBlock(id); err == nil {
+		return blk, nil
+	}
+	return vm.getPreForkBlock(id)
+}
+
+func (vm *VM) getPostForkBlock(blkID ids.ID) (PostForkBlock, error) {
+	if blk, exists := vm.verifiedBlocks[blkID]; exists {
+		return blk, nil
+	}
+
+	statelessBlock, status, err := vm.state.getBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+
+	innerBlk, err := vm.ChainVM.ParseBlock(statelessBlock.Block())
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.wrapPostForkBlock(statelessBlock, innerBlk, status), nil
+}
+
+func (vm *VM) getPreForkBlock(blkID ids.ID) (*preForkBlock, error) {
+	blk, err := vm.ChainVM.GetBlock(blkID)
+	return &preForkBlock{
+		Block: blk,
+		vm:    vm,
+	}, err
+}
+
+func (vm *VM) wrapPostForkBlock(
+	statelessBlock statelessblock.Block,
+	innerBlk snowman.Block,
+	status choices.Status,
+) PostForkBlock {
+	common := postForkCommonComponents{
+		vm:       vm,
+		innerBlk: innerBlk,
+		status:   status,
+	}
+	if signedBlock, ok := statelessBlock.(statelessblock.SignedBlock); ok {
+		return &postForkBlock{
+			SignedBlock:              signedBlock,
+			postForkCommonComponents: common,
+		}
+	}
+	return &postForkOption{
+		Block:                    statelessBlock,
+		postForkCommonComponents: common,
+	}
+}
+
+// getPostForkOptions wraps the options of the inner block of [blk], if the
+// inner block is an oracle block.
+func (vm *VM) getPostForkOptions(blk PostForkBlock) ([2]snowman.Block, error) {
+	innerOracleBlk, ok := blk.getInnerBlk().(smeng.OracleBlock)
+	if !ok {
+		return [2]snowman.Block{}, smeng.ErrNotOracle
+	}
+
+	innerOptions, err := innerOracleBlk.Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+
+	parentID := blk.ID()
+	outerOptions := [2]snowman.Block{}
+	for i, innerOption := range innerOptions {
+		statelessBlock, err := statelessblock.BuildOption(parentID, innerOption.Bytes())
+		if err != nil {
+			return [2]snowman.Block{}, err
+		}
+
+		// If the option is already known, its status must be preserved.
+		if option, err := vm.getPostForkBlock(statelessBlock.ID()); err == nil {
+			outerOptions[i] = option
+			continue
+		}
+
+		outerOptions[i] = vm.wrapPostForkBlock(statelessBlock, innerOption, choices.Processing)
+	}
+	return outerOptions, nil
+}
+
+// verifyAndRecordInnerBlk verifies the inner block of [blk], if it hasn't
+// already been verified, and tracks [blk] as processing.
+func (vm *VM) verifyAndRecordInnerBlk(blk Block) error {
+	// If the inner block has already been verified, then it shouldn't be
+	// verified again. This can happen if the same inner block is wrapped by
+	// multiple blocks.
+	innerBlk := blk.getInnerBlk()
+	if originalInnerBlk, contains := vm.tree.Get(innerBlk); contains {
+		blk.setInnerBlk(originalInnerBlk)
+	} else {
+		if err := innerBlk.Verify(); err != nil {
+			return err
+		}
+		vm.tree.Add(innerBlk)
+	}
+
+	if postForkBlk, ok := blk.(PostForkBlock); ok {
+		vm.verifiedBlocks[postForkBlk.ID()] = postForkBlk
+	}
+	return nil
+}
+
+func (vm *VM) acceptPostForkBlock(blk PostForkBlock) error {
+	blkID := blk.ID()
+	if err := vm.state.setLastAccepted(blkID); err != nil {
+		return err
+	}
+
+	blk.setStatus(choices.Accepted)
+	if err := vm.state.putBlock(blk.getStatelessBlk(), choices.Accepted); err != nil {
+		return err
+	}
+	if err := vm.db.Commit(); err != nil {
+		return err
+	}
+
+	delete(vm.verifiedBlocks, blkID)
+
+	// mark the inner block as accepted and all the conflicting inner blocks as
+	// rejected
+	return vm.tree.Accept(blk.getInnerBlk())
+}
+
+func (vm *VM) rejectPostForkBlock(blk PostForkBlock) error {
+	blkID := blk.ID()
+	delete(vm.verifiedBlocks, blkID)
+
+	blk.setStatus(choices.Rejected)
+	if err := vm.state.putBlock(blk.getStatelessBlk(), choices.Rejected); err != nil {
+		return err
+	}
+	return vm.db.Commit()
+}
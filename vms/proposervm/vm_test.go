@@ -0,0 +1,514 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	pChainHeight uint64 = 2000
+
+	errUnknownBlock = errors.New("unknown block")
+)
+
+// testOracleBlock is an inner oracle block with two options.
+type testOracleBlock struct {
+	*snowman.TestBlock
+	opts [2]snowman.Block
+}
+
+func (b *testOracleBlock) Options() ([2]snowman.Block, error) { return b.opts, nil }
+
+type testInnerVM struct {
+	*block.TestVM
+	blocks []snowman.Block
+}
+
+func (vm *testInnerVM) add(blks ...snowman.Block) { vm.blocks = append(vm.blocks, blks...) }
+
+func (vm *testInnerVM) newBlock(parent snowman.Block) *snowman.TestBlock {
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: parent,
+		HeightV: parent.Height() + 1,
+	}
+	blk.BytesV = blk.IDV[:]
+	vm.add(blk)
+	return blk
+}
+
+func initTestProposerVM(t *testing.T, activationTime time.Time) (*testInnerVM, *validators.TestState, *VM, *snowman.TestBlock) {
+	coreGenBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  []byte{0},
+	}
+
+	coreVM := &testInnerVM{
+		TestVM: &block.TestVM{
+			TestVM: common.TestVM{
+				T: t,
+			},
+		},
+		blocks: []snowman.Block{coreGenBlk},
+	}
+	coreVM.InitializeF = func(*snow.Context, manager.Manager, []byte, []byte, []byte, chan<- common.Message, []*common.Fx) error {
+		return nil
+	}
+	coreVM.ShutdownF = func() error { return nil }
+	coreVM.LastAcceptedF = func() (ids.ID, error) { return coreGenBlk.ID(), nil }
+	coreVM.SetPreferenceF = func(ids.ID) error { return nil }
+	coreVM.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range coreVM.blocks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range coreVM.blocks {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	tlsCert, err := staking.NewTLSCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := tlsCert.Leaf
+	key := tlsCert.PrivateKey.(crypto.Signer)
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valState := &validators.TestState{T: t}
+	valState.GetCurrentHeightF = func() (uint64, error) { return pChainHeight, nil }
+	valState.GetValidatorSetF = func(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+		return map[ids.ShortID]uint64{
+			nodeID: 10,
+		}, nil
+	}
+
+	proVM := New(coreVM, activationTime, valState, cert, key)
+
+	ctx := snow.DefaultContextTest()
+	ctx.NodeID = nodeID
+
+	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
+	if err := proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("failed to initialize proposerVM with %s", err)
+	}
+
+	if err := proVM.SetPreference(coreGenBlk.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	return coreVM, valState, proVM, coreGenBlk
+}
+
+func TestBuildPreForkBlockBeforeActivation(t *testing.T) {
+	coreVM, _, proVM, coreGenBlk := initTestProposerVM(t, time.Now().Add(time.Hour))
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreBlk := coreVM.newBlock(coreGenBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+
+	blk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blk.(*preForkBlock); !ok {
+		t.Fatalf("expected a pre-fork block but got %T", blk)
+	}
+	if blk.ID() != coreBlk.ID() {
+		t.Fatalf("pre-fork block should have the ID of the inner block")
+	}
+	if !bytes.Equal(blk.Bytes(), coreBlk.Bytes()) {
+		t.Fatalf("pre-fork block should have the bytes of the inner block")
+	}
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if status := coreBlk.Status(); status != choices.Accepted {
+		t.Fatalf("inner block should have been accepted but is %s", status)
+	}
+
+	lastAccepted, err := proVM.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coreLastAccepted, err := coreVM.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted != coreLastAccepted {
+		t.Fatalf("last accepted should be reported by the inner VM before the fork")
+	}
+}
+
+func TestPreForkChildOfBlockAfterActivation(t *testing.T) {
+	activationTime := time.Unix(1632326400, 0)
+	coreVM, _, proVM, coreGenBlk := initTestProposerVM(t, activationTime)
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	proVM.clock.Set(activationTime.Add(-time.Second))
+
+	// The parent was produced once the fork was activated, even though the
+	// local clock hasn't reached the activation time yet.
+	coreParent := coreVM.newBlock(coreGenBlk)
+	coreParent.TimestampV = activationTime
+	parent, err := proVM.ParseBlock(coreParent.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parent.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A pre-fork child of the parent can't be verified.
+	coreChild := coreVM.newBlock(coreParent)
+	child, err := proVM.ParseBlock(coreChild.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := child.Verify(); err != errProposersActivated {
+		t.Fatalf("expected %s but got %v", errProposersActivated, err)
+	}
+
+	// A post-fork child of the parent is built instead.
+	if err := proVM.SetPreference(parent.ID()); err != nil {
+		t.Fatal(err)
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreChild, nil }
+	blk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blk.(*postForkBlock); !ok {
+		t.Fatalf("expected a post-fork block but got %T", blk)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildFirstPostForkBlock(t *testing.T) {
+	coreVM, _, proVM, coreGenBlk := initTestProposerVM(t, time.Time{})
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreBlk := coreVM.newBlock(coreGenBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+
+	blkIntf, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk, ok := blkIntf.(*postForkBlock)
+	if !ok {
+		t.Fatalf("expected a post-fork block but got %T", blkIntf)
+	}
+	if blk.ParentID() != coreGenBlk.ID() {
+		t.Fatalf("first post-fork block should point to the pre-fork genesis")
+	}
+	if blk.Proposer() != ids.ShortEmpty {
+		t.Fatalf("first post-fork block shouldn't be signed")
+	}
+	if blk.PChainHeight() != pChainHeight {
+		t.Fatalf("wrong P-chain height %d", blk.PChainHeight())
+	}
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if status := coreBlk.Status(); status != choices.Accepted {
+		t.Fatalf("inner block should have been accepted but is %s", status)
+	}
+
+	lastAccepted, err := proVM.LastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted != blk.ID() {
+		t.Fatalf("wrong last accepted block")
+	}
+
+	// A pre-fork block can no longer be verified.
+	conflictingCoreBlk := coreVM.newBlock(coreGenBlk)
+	conflictingBlk, err := proVM.ParseBlock(conflictingCoreBlk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conflictingBlk.Verify(); err != errProposersActivated {
+		t.Fatalf("expected %s but got %v", errProposersActivated, err)
+	}
+}
+
+func TestBuildSignedBlockInProposerWindow(t *testing.T) {
+	coreVM, valState, proVM, coreGenBlk := initTestProposerVM(t, time.Time{})
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreBlk0 := coreVM.newBlock(coreGenBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk0, nil }
+	blk0, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk0.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := proVM.SetPreference(blk0.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// This node is the only validator, so it may build immediately.
+	proVM.clock.Set(blk0.(*postForkBlock).Timestamp())
+
+	coreBlk1 := coreVM.newBlock(coreBlk0)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk1, nil }
+	blk1Intf, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk1 := blk1Intf.(*postForkBlock)
+	if blk1.Proposer() != proVM.ctx.NodeID {
+		t.Fatalf("block should have been signed by this node")
+	}
+	if err := blk1.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The block should round trip through parsing.
+	parsedBlk, err := proVM.ParseBlock(blk1.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedBlk.ID() != blk1.ID() {
+		t.Fatalf("parsed block should have the same ID")
+	}
+	if err := proVM.SetPreference(blk1.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// If this node isn't a validator, it must wait for every proposer window
+	// to pass.
+	valState.GetValidatorSetF = func(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+		return map[ids.ShortID]uint64{
+			ids.GenerateTestShortID(): 10,
+		}, nil
+	}
+
+	coreBlk2 := coreVM.newBlock(coreBlk1)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk2, nil }
+	if _, err := proVM.BuildBlock(); err != errProposerWindowNotStarted {
+		t.Fatalf("expected %s but got %v", errProposerWindowNotStarted, err)
+	}
+
+	proVM.clock.Set(blk1.Timestamp().Add(proposer.MaxDelay))
+	blk2Intf, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk2 := blk2Intf.(*postForkBlock)
+	if blk2.Proposer() != ids.ShortEmpty {
+		t.Fatalf("block built after the proposer windows shouldn't be signed")
+	}
+	if err := blk2.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostForkBlockVerifyPChainHeight(t *testing.T) {
+	coreVM, valState, proVM, coreGenBlk := initTestProposerVM(t, time.Time{})
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreBlk := coreVM.newBlock(coreGenBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	blk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valState.GetCurrentHeightF = func() (uint64, error) { return pChainHeight - 1, nil }
+	if err := blk.Verify(); err != errPChainHeightNotReached {
+		t.Fatalf("expected %s but got %v", errPChainHeightNotReached, err)
+	}
+
+	valState.GetCurrentHeightF = func() (uint64, error) { return pChainHeight, nil }
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostForkOptions(t *testing.T) {
+	coreVM, _, proVM, coreGenBlk := initTestProposerVM(t, time.Time{})
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreOracleBlk := &testOracleBlock{
+		TestBlock: coreVM.newBlock(coreGenBlk),
+	}
+	coreVM.blocks[len(coreVM.blocks)-1] = coreOracleBlk
+	coreOracleBlk.opts = [2]snowman.Block{
+		coreVM.newBlock(coreOracleBlk),
+		coreVM.newBlock(coreOracleBlk),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreOracleBlk, nil }
+
+	oracleBlkIntf, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := oracleBlkIntf.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	oracleBlk, ok := oracleBlkIntf.(smeng.OracleBlock)
+	if !ok {
+		t.Fatalf("expected an oracle block")
+	}
+	opts, err := oracleBlk.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opt := range opts {
+		if opt.Parent().ID() != oracleBlkIntf.ID() {
+			t.Fatalf("option should point to the oracle block")
+		}
+		if err := opt.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := oracleBlkIntf.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := opts[0].Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := opts[1].Reject(); err != nil {
+		t.Fatal(err)
+	}
+	if status := coreOracleBlk.opts[0].Status(); status != choices.Accepted {
+		t.Fatalf("inner option should have been accepted but is %s", status)
+	}
+	if status := coreOracleBlk.opts[1].Status(); status != choices.Rejected {
+		t.Fatalf("inner option should have been rejected but is %s", status)
+	}
+
+	// The options of an oracle block can't be verified as regular children.
+	coreBlk := coreVM.newBlock(coreOracleBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	if err := proVM.SetPreference(oracleBlkIntf.ID()); err != nil {
+		t.Fatal(err)
+	}
+	proVM.clock.Set(time.Now().Add(proposer.MaxDelay))
+	childBlk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := childBlk.Verify(); err != errUnexpectedBlockType {
+		t.Fatalf("expected %s but got %v", errUnexpectedBlockType, err)
+	}
+}
+
+func TestAcceptedBlocksArePersisted(t *testing.T) {
+	coreVM, _, proVM, coreGenBlk := initTestProposerVM(t, time.Time{})
+	defer func() {
+		if err := proVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	coreBlk := coreVM.newBlock(coreGenBlk)
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	blk, err := proVM.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unverified blocks aren't tracked
+	if _, err := proVM.getPostForkBlock(blk.ID()); err != database.ErrNotFound {
+		t.Fatalf("expected %s but got %v", database.ErrNotFound, err)
+	}
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the verified blocks so the block must be read from the database
+	proVM.verifiedBlocks = make(map[ids.ID]PostForkBlock)
+	fetchedBlk, err := proVM.GetBlock(blk.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := fetchedBlk.Status(); status != choices.Accepted {
+		t.Fatalf("persisted block should be accepted but is %s", status)
+	}
+	if !bytes.Equal(fetchedBlk.Bytes(), blk.Bytes()) {
+		t.Fatalf("persisted block has the wrong bytes")
+	}
+}
@@ -0,0 +1,308 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/dagvmproto"
+)
+
+const (
+	decidedTxCacheSize    = 2048
+	unverifiedTxCacheSize = 2048
+	txBytesToIDCacheSize  = 2048
+)
+
+var (
+	_ vertex.DAGVM    = &DAGVMClient{}
+	_ common.Exitable = &DAGVMClient{}
+	_ snowstorm.Tx    = &TxClient{}
+)
+
+// DAGVMClient is an implementation of a DAG VM that talks over RPC.
+type DAGVMClient struct {
+	// vm implements the methods that aren't specific to DAG VMs
+	vm     *VMClient
+	client dagvmproto.DAGVMClient
+
+	// Transactions are cached so that the same transaction is always
+	// represented by the same object, and so that transactions don't need to
+	// be fetched from the plugin again.
+	// Verified transactions are kept until they're decided.
+	verifiedTxs   map[ids.ID]*TxClient
+	unverifiedTxs cache.Cacher
+	decidedTxs    cache.Cacher
+	// string([byte repr. of tx]) --> the tx's ID
+	bytesToIDCache cache.Cacher
+}
+
+// NewDAGClient returns a DAG VM connected to a remote DAG VM
+func NewDAGClient(vm *VMClient, client dagvmproto.DAGVMClient) *DAGVMClient {
+	return &DAGVMClient{
+		vm:             vm,
+		client:         client,
+		verifiedTxs:    make(map[ids.ID]*TxClient),
+		unverifiedTxs:  &cache.LRU{Size: unverifiedTxCacheSize},
+		decidedTxs:     &cache.LRU{Size: decidedTxCacheSize},
+		bytesToIDCache: &cache.LRU{Size: txBytesToIDCacheSize},
+	}
+}
+
+// SetProcess gives ownership of the server process to the client.
+func (vm *DAGVMClient) SetProcess(proc *plugin.Client) {
+	vm.vm.SetProcess(proc)
+}
+
+// Exited returns a channel that is closed when the plugin's process exits
+// without the VM having been shut down
+func (vm *DAGVMClient) Exited() <-chan struct{} { return vm.vm.Exited() }
+
+func (vm *DAGVMClient) Initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisBytes []byte,
+	upgradeBytes []byte,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) error {
+	_, err := vm.vm.initialize(ctx, dbManager, genesisBytes, upgradeBytes, configBytes, toEngine, fxs)
+	return err
+}
+
+func (vm *DAGVMClient) Bootstrapping() error { return vm.vm.Bootstrapping() }
+
+func (vm *DAGVMClient) Bootstrapped() error { return vm.vm.Bootstrapped() }
+
+func (vm *DAGVMClient) Shutdown() error { return vm.vm.Shutdown() }
+
+func (vm *DAGVMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	return vm.vm.CreateHandlers()
+}
+
+func (vm *DAGVMClient) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
+	return vm.vm.CreateStaticHandlers()
+}
+
+func (vm *DAGVMClient) HealthCheck() (interface{}, error) { return vm.vm.HealthCheck() }
+
+func (vm *DAGVMClient) Version() (string, error) { return vm.vm.Version() }
+
+func (vm *DAGVMClient) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
+	return vm.vm.Connected(nodeID, nodeVersion)
+}
+
+func (vm *DAGVMClient) Disconnected(nodeID ids.ShortID) error { return vm.vm.Disconnected(nodeID) }
+
+func (vm *DAGVMClient) PendingTxs() []snowstorm.Tx {
+	resp, err := vm.client.PendingTxs(context.Background(), &dagvmproto.PendingTxsRequest{})
+	if err != nil {
+		vm.vm.ctx.Log.Error("failed to fetch the pending txs: %s", err)
+		return nil
+	}
+
+	txs := make([]snowstorm.Tx, 0, len(resp.Txs))
+	for _, txResp := range resp.Txs {
+		tx, err := vm.newTx(txResp)
+		if err != nil {
+			vm.vm.ctx.Log.Error("failed to parse a pending tx: %s", err)
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func (vm *DAGVMClient) ParseTx(b []byte) (snowstorm.Tx, error) {
+	// See if we've cached this tx's ID by its byte repr.
+	if txIDIntf, ok := vm.bytesToIDCache.Get(string(b)); ok {
+		if tx, ok := vm.getCachedTx(txIDIntf.(ids.ID)); ok {
+			return tx, nil
+		}
+	}
+
+	resp, err := vm.client.ParseTx(context.Background(), &dagvmproto.ParseTxRequest{
+		Bytes: b,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx, err := vm.newTx(resp.Tx)
+	if err != nil {
+		return nil, err
+	}
+	vm.bytesToIDCache.Put(string(b), tx.id)
+	return tx, nil
+}
+
+func (vm *DAGVMClient) GetTx(txID ids.ID) (snowstorm.Tx, error) {
+	return vm.getTx(txID)
+}
+
+func (vm *DAGVMClient) getTx(txID ids.ID) (*TxClient, error) {
+	if tx, ok := vm.getCachedTx(txID); ok {
+		return tx, nil
+	}
+
+	resp, err := vm.client.GetTx(context.Background(), &dagvmproto.GetTxRequest{
+		Id: txID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vm.newTx(resp.Tx)
+}
+
+func (vm *DAGVMClient) getCachedTx(txID ids.ID) (*TxClient, bool) {
+	if tx, ok := vm.verifiedTxs[txID]; ok {
+		return tx, true
+	}
+	if txIntf, ok := vm.unverifiedTxs.Get(txID); ok {
+		return txIntf.(*TxClient), true
+	}
+	if txIntf, ok := vm.decidedTxs.Get(txID); ok {
+		return txIntf.(*TxClient), true
+	}
+	return nil, false
+}
+
+// newTx returns the tx described by [txResp]. If the tx is already cached,
+// the cached tx is returned so that each tx is represented by one object.
+func (vm *DAGVMClient) newTx(txResp *dagvmproto.Tx) (*TxClient, error) {
+	txID, err := ids.ToID(txResp.Id)
+	if err != nil {
+		return nil, err
+	}
+	if tx, ok := vm.getCachedTx(txID); ok {
+		return tx, nil
+	}
+
+	status := choices.Status(txResp.Status)
+	if err := status.Valid(); err != nil {
+		return nil, err
+	}
+	dependencyIDs, err := toIDs(txResp.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	inputIDs, err := toIDs(txResp.InputIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &TxClient{
+		vm:            vm,
+		id:            txID,
+		status:        status,
+		bytes:         txResp.Bytes,
+		dependencyIDs: dependencyIDs,
+		inputIDs:      inputIDs,
+	}
+	if status.Decided() {
+		vm.decidedTxs.Put(txID, tx)
+	} else {
+		vm.unverifiedTxs.Put(txID, tx)
+	}
+	return tx, nil
+}
+
+func toIDs(idsBytes [][]byte) ([]ids.ID, error) {
+	result := make([]ids.ID, len(idsBytes))
+	for i, idBytes := range idsBytes {
+		id, err := ids.ToID(idBytes)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = id
+	}
+	return result, nil
+}
+
+// TxClient is an implementation of Tx that talks over RPC.
+type TxClient struct {
+	vm *DAGVMClient
+
+	id            ids.ID
+	status        choices.Status
+	bytes         []byte
+	dependencyIDs []ids.ID
+	inputIDs      []ids.ID
+}
+
+func (tx *TxClient) ID() ids.ID { return tx.id }
+
+func (tx *TxClient) Accept() error {
+	tx.status = choices.Accepted
+	tx.decided()
+	_, err := tx.vm.client.TxAccept(context.Background(), &dagvmproto.TxAcceptRequest{
+		Id: tx.id[:],
+	})
+	return err
+}
+
+func (tx *TxClient) Reject() error {
+	tx.status = choices.Rejected
+	tx.decided()
+	_, err := tx.vm.client.TxReject(context.Background(), &dagvmproto.TxRejectRequest{
+		Id: tx.id[:],
+	})
+	return err
+}
+
+// decided moves [tx] to the decided cache
+func (tx *TxClient) decided() {
+	delete(tx.vm.verifiedTxs, tx.id)
+	tx.vm.unverifiedTxs.Evict(tx.id)
+	tx.vm.decidedTxs.Put(tx.id, tx)
+}
+
+func (tx *TxClient) Status() choices.Status { return tx.status }
+
+// Dependencies returns the dependencies of [tx]. Dependencies that can't be
+// fetched from the plugin are returned with an unknown status.
+func (tx *TxClient) Dependencies() []snowstorm.Tx {
+	deps := make([]snowstorm.Tx, len(tx.dependencyIDs))
+	for i, depID := range tx.dependencyIDs {
+		dep, err := tx.vm.getTx(depID)
+		if err != nil {
+			dep = &TxClient{
+				vm:     tx.vm,
+				id:     depID,
+				status: choices.Unknown,
+			}
+		}
+		deps[i] = dep
+	}
+	return deps
+}
+
+func (tx *TxClient) InputIDs() []ids.ID { return tx.inputIDs }
+
+func (tx *TxClient) Verify() error {
+	if _, err := tx.vm.client.TxVerify(context.Background(), &dagvmproto.TxVerifyRequest{
+		Id: tx.id[:],
+	}); err != nil {
+		return err
+	}
+
+	if !tx.status.Decided() {
+		tx.vm.unverifiedTxs.Evict(tx.id)
+		tx.vm.verifiedTxs[tx.id] = tx
+	}
+	return nil
+}
+
+func (tx *TxClient) Bytes() []byte { return tx.bytes }
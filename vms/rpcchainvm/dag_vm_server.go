@@ -0,0 +1,148 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/dagvmproto"
+)
+
+const sentTxCacheSize = 2048
+
+var _ dagvmproto.DAGVMServer = &DAGVMServer{}
+
+// DAGVMServer is a DAG VM that is managed over RPC.
+type DAGVMServer struct {
+	dagvmproto.UnimplementedDAGVMServer
+	vm vertex.DAGVM
+
+	// Transactions sent to the client are cached so that the client's calls
+	// on them are made on the same objects, even if the VM can't fetch them
+	// with GetTx. Verified transactions are kept until they're decided.
+	verifiedTxs map[ids.ID]snowstorm.Tx
+	sentTxs     cache.Cacher
+}
+
+// NewDAGServer returns a DAG VM server that serves [vm]
+func NewDAGServer(vm vertex.DAGVM) *DAGVMServer {
+	return &DAGVMServer{
+		vm:          vm,
+		verifiedTxs: make(map[ids.ID]snowstorm.Tx),
+		sentTxs:     &cache.LRU{Size: sentTxCacheSize},
+	}
+}
+
+func (vm *DAGVMServer) PendingTxs(context.Context, *dagvmproto.PendingTxsRequest) (*dagvmproto.PendingTxsResponse, error) {
+	txs := vm.vm.PendingTxs()
+	resp := &dagvmproto.PendingTxsResponse{
+		Txs: make([]*dagvmproto.Tx, len(txs)),
+	}
+	for i, tx := range txs {
+		resp.Txs[i] = vm.sendTx(tx)
+	}
+	return resp, nil
+}
+
+func (vm *DAGVMServer) ParseTx(_ context.Context, req *dagvmproto.ParseTxRequest) (*dagvmproto.ParseTxResponse, error) {
+	tx, err := vm.vm.ParseTx(req.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &dagvmproto.ParseTxResponse{
+		Tx: vm.sendTx(tx),
+	}, nil
+}
+
+func (vm *DAGVMServer) GetTx(_ context.Context, req *dagvmproto.GetTxRequest) (*dagvmproto.GetTxResponse, error) {
+	tx, err := vm.getTx(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &dagvmproto.GetTxResponse{
+		Tx: vm.sendTx(tx),
+	}, nil
+}
+
+func (vm *DAGVMServer) TxVerify(_ context.Context, req *dagvmproto.TxVerifyRequest) (*dagvmproto.TxVerifyResponse, error) {
+	tx, err := vm.getTx(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Verify(); err != nil {
+		return nil, err
+	}
+	vm.verifiedTxs[tx.ID()] = tx
+	return &dagvmproto.TxVerifyResponse{}, nil
+}
+
+func (vm *DAGVMServer) TxAccept(_ context.Context, req *dagvmproto.TxAcceptRequest) (*dagvmproto.TxAcceptResponse, error) {
+	tx, err := vm.getTx(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	delete(vm.verifiedTxs, tx.ID())
+	if err := tx.Accept(); err != nil {
+		return nil, err
+	}
+	return &dagvmproto.TxAcceptResponse{}, nil
+}
+
+func (vm *DAGVMServer) TxReject(_ context.Context, req *dagvmproto.TxRejectRequest) (*dagvmproto.TxRejectResponse, error) {
+	tx, err := vm.getTx(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	delete(vm.verifiedTxs, tx.ID())
+	if err := tx.Reject(); err != nil {
+		return nil, err
+	}
+	return &dagvmproto.TxRejectResponse{}, nil
+}
+
+// getTx returns the tx with ID [txIDBytes], preferring the txs that were sent
+// to the client
+func (vm *DAGVMServer) getTx(txIDBytes []byte) (snowstorm.Tx, error) {
+	txID, err := ids.ToID(txIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	if tx, ok := vm.verifiedTxs[txID]; ok {
+		return tx, nil
+	}
+	if txIntf, ok := vm.sentTxs.Get(txID); ok {
+		return txIntf.(snowstorm.Tx), nil
+	}
+	return vm.vm.GetTx(txID)
+}
+
+// sendTx caches [tx] and returns its wire representation
+func (vm *DAGVMServer) sendTx(tx snowstorm.Tx) *dagvmproto.Tx {
+	txID := tx.ID()
+	vm.sentTxs.Put(txID, tx)
+
+	deps := tx.Dependencies()
+	depIDs := make([][]byte, len(deps))
+	for i, dep := range deps {
+		depID := dep.ID()
+		depIDs[i] = depID[:]
+	}
+	inputIDs := tx.InputIDs()
+	inputIDsBytes := make([][]byte, len(inputIDs))
+	for i, inputID := range inputIDs {
+		inputID := inputID
+		inputIDsBytes[i] = inputID[:]
+	}
+	return &dagvmproto.Tx{
+		Id:           txID[:],
+		Bytes:        tx.Bytes(),
+		Status:       uint32(tx.Status()),
+		Dependencies: depIDs,
+		InputIDs:     inputIDsBytes,
+	}
+}
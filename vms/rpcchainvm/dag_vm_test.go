@@ -0,0 +1,129 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/dagvmproto"
+)
+
+// newTestDAGClient returns a client of a DAGVMServer that serves [vm] in
+// memory, and a function that closes both of them
+func newTestDAGClient(t *testing.T, vm vertex.DAGVM) (*DAGVMClient, func()) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	dagvmproto.RegisterDAGVMServer(server, NewDAGServer(vm))
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vmClient := NewClient(nil, nil)
+	vmClient.ctx = snow.DefaultContextTest()
+	return NewDAGClient(vmClient, dagvmproto.NewDAGVMClient(conn)), func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+func TestDAGVMTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	errUnknownTx := errors.New("unknown tx")
+	errInvalidTx := errors.New("invalid tx")
+
+	dep := &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		BytesV: []byte{0},
+	}
+	tx := &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []snowstorm.Tx{dep},
+		InputIDsV:     []ids.ID{ids.GenerateTestID()},
+		VerifyV:       errInvalidTx,
+		BytesV:        []byte{1},
+	}
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{tx} }
+	vm.ParseTxF = func(b []byte) (snowstorm.Tx, error) {
+		if !bytes.Equal(b, tx.Bytes()) {
+			return nil, errUnknownTx
+		}
+		return tx, nil
+	}
+	vm.GetTxF = func(txID ids.ID) (snowstorm.Tx, error) {
+		if txID != dep.ID() {
+			return nil, errUnknownTx
+		}
+		return dep, nil
+	}
+
+	client, closeFn := newTestDAGClient(t, vm)
+	defer closeFn()
+
+	pendingTxs := client.PendingTxs()
+	assert.Len(pendingTxs, 1)
+	clientTx := pendingTxs[0]
+	assert.Equal(tx.ID(), clientTx.ID())
+	assert.Equal(tx.Bytes(), clientTx.Bytes())
+	assert.Equal(choices.Processing, clientTx.Status())
+	assert.Equal(tx.InputIDs(), clientTx.InputIDs())
+
+	// The same tx is represented by the same object
+	parsedTx, err := client.ParseTx(tx.Bytes())
+	assert.NoError(err)
+	assert.Same(clientTx, parsedTx)
+
+	deps := clientTx.Dependencies()
+	assert.Len(deps, 1)
+	assert.Equal(dep.ID(), deps[0].ID())
+	assert.Equal(choices.Accepted, deps[0].Status())
+
+	assert.Error(clientTx.Verify())
+	tx.VerifyV = nil
+	assert.NoError(clientTx.Verify())
+
+	assert.NoError(clientTx.Accept())
+	assert.Equal(choices.Accepted, clientTx.Status())
+	assert.Equal(choices.Accepted, tx.Status())
+
+	// The server decided the tx it sent, even though the VM can't fetch it
+	fetchedTx, err := client.GetTx(tx.ID())
+	assert.NoError(err)
+	assert.Same(clientTx, fetchedTx)
+
+	_, err = client.GetTx(ids.GenerateTestID())
+	assert.Error(err)
+}
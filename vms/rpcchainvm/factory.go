@@ -5,29 +5,48 @@ package rpcchainvm
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"path/filepath"
 
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/subprocess"
+	"github.com/ava-labs/avalanchego/vms"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 )
 
-var errWrongVM = errors.New("wrong vm type")
+var (
+	_ vms.LimitedFactory = &Factory{}
+
+	errWrongVM     = errors.New("wrong vm type")
+	errUnknownKind = errors.New("unknown vm kind")
+)
 
 type Factory struct {
 	Path string
+	// CgroupDir is the cgroups v2 cgroup that plugins with memory or CPU
+	// limits are moved into a cgroup under. Plugins with memory or CPU limits
+	// fail to start if it's empty.
+	CgroupDir string
 }
 
 func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
+	return f.NewWithLimits(ctx, subprocess.Limits{})
+}
+
+// NewWithLimits starts the plugin with [limits] on the resources it may use
+func (f *Factory) NewWithLimits(ctx *snow.Context, limits subprocess.Limits) (interface{}, error) {
 	// Ignore warning from launching an executable with a variable command
 	// because the command is a controlled and required input
+	cmd := subprocess.New(f.Path)
 
 	config := &plugin.ClientConfig{
-		HandshakeConfig: Handshake,
-		Plugins:         PluginMap,
-		Cmd:             subprocess.New(f.Path),
+		HandshakeConfig:  Handshake,
+		VersionedPlugins: versionedPluginMap(),
+		Cmd:              cmd,
 		AllowedProtocols: []plugin.Protocol{
 			plugin.ProtocolNetRPC,
 			plugin.ProtocolGRPC,
@@ -44,7 +63,9 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 		// node shutdown to ensure every plugin subprocess is killed.
 		Managed: true,
 	}
+	var pluginLog logging.Logger = logging.NoLog{}
 	if ctx != nil {
+		pluginLog = ctx.Log
 		log.SetOutput(ctx.Log)
 		config.Stderr = ctx.Log
 		config.Logger = hclog.New(&hclog.LoggerOptions{
@@ -59,26 +80,93 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 		})
 	}
 	client := plugin.NewClient(config)
+	sandbox := subprocess.NewSandbox(filepath.Base(f.Path), limits, f.CgroupDir, pluginLog)
+	kill := func() {
+		client.Kill()
+		sandbox.Close()
+	}
 
-	rpcClient, err := client.Client()
+	var rpcClient plugin.ClientProtocol
+	err := sandbox.Start(func() error {
+		var err error
+		rpcClient, err = client.Client()
+		return err
+	})
 	if err != nil {
-		client.Kill()
+		kill()
+		return nil, fmt.Errorf("couldn't start plugin %s, which must speak one of protocol versions %s: %w",
+			f.Path, protocolVersionsString(), err)
+	}
+	// The plugin is unlimited until [sandbox.Limit] returns, so it's limited
+	// before anything else is done with it
+	if err := sandbox.Limit(cmd.Process.Pid); err != nil {
+		kill()
 		return nil, err
 	}
 
+	protocol, err := checkProtocol(client.NegotiatedVersion())
+	if err != nil {
+		kill()
+		return nil, fmt.Errorf("couldn't start plugin %s: %w", f.Path, err)
+	}
+	pluginLog.Debug("plugin %s speaks protocol version %d", f.Path, client.NegotiatedVersion())
+
 	raw, err := rpcClient.Dispense("vm")
 	if err != nil {
-		client.Kill()
+		kill()
 		return nil, err
 	}
 
 	vm, ok := raw.(*VMClient)
 	if !ok {
-		client.Kill()
+		kill()
 		return nil, errWrongVM
 	}
 
+	vm.protocol = protocol
+
+	kind := chainVMKind
+	if protocol.dagVMs {
+		kind, err = vm.kind()
+		if err != nil {
+			kill()
+			return nil, err
+		}
+	}
+
+	if ctx != nil {
+		collector := newUsageCollector(ctx.Namespace, sandbox)
+		if err := ctx.Metrics.Register(collector); err != nil {
+			kill()
+			return nil, err
+		}
+	}
+
+	vm.sandbox = sandbox
 	vm.SetProcess(client)
 	vm.ctx = ctx
-	return vm, nil
+	switch kind {
+	case chainVMKind:
+		return vm, nil
+	case dagVMKind:
+		raw, err := rpcClient.Dispense("dagvm")
+		if err != nil {
+			kill()
+			return nil, err
+		}
+
+		dagVM, ok := raw.(*DAGVMClient)
+		if !ok {
+			kill()
+			return nil, errWrongVM
+		}
+
+		dagVM.vm.sandbox = sandbox
+		dagVM.SetProcess(client)
+		dagVM.vm.ctx = ctx
+		return dagVM, nil
+	default:
+		kill()
+		return nil, errUnknownKind
+	}
 }
@@ -0,0 +1,62 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators/gvalidatorsproto"
+)
+
+var _ validators.State = &Client{}
+
+// Client is a validator state that talks over RPC.
+type Client struct {
+	client gvalidatorsproto.ValidatorStateClient
+}
+
+// NewClient returns a validator state connected to a remote validator state
+func NewClient(client gvalidatorsproto.ValidatorStateClient) *Client {
+	return &Client{client: client}
+}
+
+func (c *Client) GetCurrentHeight() (uint64, error) {
+	resp, err := c.client.GetCurrentHeight(context.Background(), &gvalidatorsproto.GetCurrentHeightRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Height, nil
+}
+
+func (c *Client) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	resp, err := c.client.GetValidatorSet(context.Background(), &gvalidatorsproto.GetValidatorSetRequest{
+		Height:   height,
+		SubnetID: subnetID[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vdrs := make(map[ids.ShortID]uint64, len(resp.Validators))
+	for _, vdr := range resp.Validators {
+		nodeID, err := ids.ToShortID(vdr.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		vdrs[nodeID] = vdr.Weight
+	}
+	return vdrs, nil
+}
+
+func (c *Client) GetSubnetID(chainID ids.ID) (ids.ID, error) {
+	resp, err := c.client.GetSubnetID(context.Background(), &gvalidatorsproto.GetSubnetIDRequest{
+		ChainID: chainID[:],
+	})
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(resp.SubnetID)
+}
@@ -0,0 +1,81 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators/gvalidatorsproto"
+)
+
+var _ gvalidatorsproto.ValidatorStateServer = &Server{}
+
+// Server is a validator state that is managed over RPC.
+type Server struct {
+	gvalidatorsproto.UnimplementedValidatorStateServer
+	state validators.State
+}
+
+// NewServer returns a validator state server that serves [state]
+func NewServer(state validators.State) *Server {
+	return &Server{state: state}
+}
+
+func (s *Server) GetCurrentHeight(
+	context.Context,
+	*gvalidatorsproto.GetCurrentHeightRequest,
+) (*gvalidatorsproto.GetCurrentHeightResponse, error) {
+	height, err := s.state.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	return &gvalidatorsproto.GetCurrentHeightResponse{
+		Height: height,
+	}, nil
+}
+
+func (s *Server) GetValidatorSet(
+	_ context.Context,
+	req *gvalidatorsproto.GetValidatorSetRequest,
+) (*gvalidatorsproto.GetValidatorSetResponse, error) {
+	subnetID, err := ids.ToID(req.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	vdrs, err := s.state.GetValidatorSet(req.Height, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &gvalidatorsproto.GetValidatorSetResponse{
+		Validators: make([]*gvalidatorsproto.Validator, 0, len(vdrs)),
+	}
+	for nodeID, weight := range vdrs {
+		nodeID := nodeID
+		resp.Validators = append(resp.Validators, &gvalidatorsproto.Validator{
+			NodeID: nodeID[:],
+			Weight: weight,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetSubnetID(
+	_ context.Context,
+	req *gvalidatorsproto.GetSubnetIDRequest,
+) (*gvalidatorsproto.GetSubnetIDResponse, error) {
+	chainID, err := ids.ToID(req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, err := s.state.GetSubnetID(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &gvalidatorsproto.GetSubnetIDResponse{
+		SubnetID: subnetID[:],
+	}, nil
+}
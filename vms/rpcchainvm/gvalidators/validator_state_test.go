@@ -0,0 +1,83 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators/gvalidatorsproto"
+)
+
+const bufSize = 1024 * 1024
+
+func TestValidatorState(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	vdrs := map[ids.ShortID]uint64{
+		ids.GenerateTestShortID(): 1,
+		ids.GenerateTestShortID(): 2,
+	}
+	errUnknownChain := errors.New("unknown chain")
+
+	state := &validators.TestState{T: t}
+	state.GetCurrentHeightF = func() (uint64, error) { return 5, nil }
+	state.GetValidatorSetF = func(height uint64, requestedSubnetID ids.ID) (map[ids.ShortID]uint64, error) {
+		assert.Equal(uint64(3), height)
+		assert.Equal(subnetID, requestedSubnetID)
+		return vdrs, nil
+	}
+	state.GetSubnetIDF = func(requestedChainID ids.ID) (ids.ID, error) {
+		if requestedChainID != chainID {
+			return ids.ID{}, errUnknownChain
+		}
+		return subnetID, nil
+	}
+
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	gvalidatorsproto.RegisterValidatorStateServer(server, NewServer(state))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := NewClient(gvalidatorsproto.NewValidatorStateClient(conn))
+
+	height, err := client.GetCurrentHeight()
+	assert.NoError(err)
+	assert.Equal(uint64(5), height)
+
+	vdrSet, err := client.GetValidatorSet(3, subnetID)
+	assert.NoError(err)
+	assert.Equal(vdrs, vdrSet)
+
+	returnedSubnetID, err := client.GetSubnetID(chainID)
+	assert.NoError(err)
+	assert.Equal(subnetID, returnedSubnetID)
+
+	_, err = client.GetSubnetID(subnetID)
+	assert.Error(err)
+}
@@ -0,0 +1,62 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/subprocess"
+)
+
+var _ prometheus.Collector = &usageCollector{}
+
+// usageCollector reports the resources a plugin's process is using each time
+// metrics are gathered
+type usageCollector struct {
+	sandbox subprocess.Sandbox
+
+	memory, cpuTime, openFiles *prometheus.Desc
+}
+
+func newUsageCollector(namespace string, sandbox subprocess.Sandbox) *usageCollector {
+	return &usageCollector{
+		sandbox: sandbox,
+		memory: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "plugin", "memory_bytes"),
+			"Bytes of memory the plugin's process has resident in RAM",
+			nil,
+			nil,
+		),
+		cpuTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "plugin", "cpu_seconds_total"),
+			"Seconds the plugin's process spent running on CPUs",
+			nil,
+			nil,
+		),
+		openFiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "plugin", "open_files"),
+			"Number of files the plugin's process has open",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (c *usageCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.memory
+	descs <- c.cpuTime
+	descs <- c.openFiles
+}
+
+// Collect reports nothing if the usage can't be measured, which is the case
+// once the process has exited or on platforms other than Linux
+func (c *usageCollector) Collect(metrics chan<- prometheus.Metric) {
+	usage, err := c.sandbox.Usage()
+	if err != nil {
+		return
+	}
+	metrics <- prometheus.MustNewConstMetric(c.memory, prometheus.GaugeValue, float64(usage.Memory))
+	metrics <- prometheus.MustNewConstMetric(c.cpuTime, prometheus.CounterValue, usage.CPUTime.Seconds())
+	metrics <- prometheus.MustNewConstMetric(c.openFiles, prometheus.GaugeValue, float64(usage.OpenFiles))
+}
@@ -0,0 +1,136 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+// ProtocolVersion is the latest version of the protocol the node and plugins
+// talk over. It must be bumped on every change to the protocol.
+const ProtocolVersion = 8
+
+var errIncompatibleProtocol = errors.New("incompatible protocol version")
+
+// protocol is what a version of the protocol supports
+type protocol struct {
+	// dagVMs is true if plugins may serve DAG VMs. Otherwise, plugins can't
+	// be asked which kind of VM they serve and only serve chain VMs.
+	dagVMs bool
+	// peerEvents is true if plugins are told about connected peers
+	peerEvents bool
+	// userKeys is true if the keystore gives plugins the key a user's data is
+	// encrypted with. Otherwise, plugins derive the key from the user's
+	// password, which doesn't decrypt the data of users whose keys are
+	// derived with a KDF and salt, so plugins can't use the keystore.
+	userKeys bool
+}
+
+// protocols is the compatibility matrix of the protocol. It maps each version
+// of the protocol the node talks to plugins over to what it supports. Older
+// versions are kept so that plugins that weren't rebuilt yet still run while
+// the node is upgraded.
+//
+// Versions 6, 7 and 8 only added to the version before them, so VMServer
+// serves all of them. Version 8 added the time blocks were produced at, which
+// plugins that speak older versions don't report. Plugins that speak version 5, such as plugins built before
+// version 6, only run as chain VMs, aren't told about peers and are given a
+// keystore that reports it's unsupported.
+var protocols = map[int]protocol{
+	5: {},
+	6: {
+		dagVMs:     true,
+		peerEvents: true,
+	},
+	7: {
+		dagVMs:     true,
+		peerEvents: true,
+		userKeys:   true,
+	},
+	8: {
+		dagVMs:     true,
+		peerEvents: true,
+		userKeys:   true,
+	},
+}
+
+// protocolVersions returns the versions of the protocol in [protocols],
+// oldest first
+func protocolVersions() []int {
+	versions := make([]int, 0, len(protocols))
+	for version := range protocols {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// protocolVersionsString returns the versions of the protocol the node talks
+// to plugins over, formatted to be read by users
+func protocolVersionsString() string {
+	versions := protocolVersions()
+	strs := make([]string, len(versions))
+	for i, version := range versions {
+		strs[i] = fmt.Sprint(version)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// versionedPluginMap returns the plugins that are dispensed for each version
+// of the protocol. The plugin picks the latest version both it and the node
+// speak when it's started.
+func versionedPluginMap() map[int]plugin.PluginSet {
+	versionedPlugins := make(map[int]plugin.PluginSet, len(protocols))
+	for version := range protocols {
+		versionedPlugins[version] = PluginMap
+	}
+	return versionedPlugins
+}
+
+// checkProtocol returns what the version of the protocol a plugin negotiated
+// supports, or an error naming the versions the node speaks if it isn't
+// compatible
+func checkProtocol(version int) (protocol, error) {
+	protocol, ok := protocols[version]
+	if !ok {
+		return protocol, fmt.Errorf("%w: plugin speaks protocol version %d but the node requires one of versions %s",
+			errIncompatibleProtocol, version, protocolVersionsString())
+	}
+	return protocol, nil
+}
+
+// Serve serves [vm] as a plugin over every version of the protocol that
+// supports chain VMs, so that it runs on nodes that speak older versions. It
+// returns once the plugin is killed.
+func Serve(vm block.ChainVM) {
+	serve(plugin.PluginSet{"vm": New(vm)}, func(protocol) bool { return true })
+}
+
+// ServeDAG serves [vm] as a plugin over every version of the protocol that
+// supports DAG VMs. It returns once the plugin is killed.
+func ServeDAG(vm vertex.DAGVM) {
+	serve(plugin.PluginSet{"dagvm": NewDAG(vm)}, func(p protocol) bool { return p.dagVMs })
+}
+
+func serve(plugins plugin.PluginSet, supported func(protocol) bool) {
+	versionedPlugins := make(map[int]plugin.PluginSet)
+	for version, protocol := range protocols {
+		if supported(protocol) {
+			versionedPlugins[version] = plugins
+		}
+	}
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig:  Handshake,
+		VersionedPlugins: versionedPlugins,
+		GRPCServer:       plugin.DefaultGRPCServer,
+	})
+}
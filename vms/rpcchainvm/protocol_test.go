@@ -0,0 +1,46 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	protocol, err := checkProtocol(ProtocolVersion)
+	assert.NoError(err)
+	assert.Equal(protocols[ProtocolVersion], protocol)
+
+	protocol, err = checkProtocol(5)
+	assert.NoError(err)
+	assert.False(protocol.dagVMs)
+	assert.False(protocol.peerEvents)
+	assert.False(protocol.userKeys)
+
+	protocol, err = checkProtocol(6)
+	assert.NoError(err)
+	assert.True(protocol.dagVMs)
+	assert.False(protocol.userKeys)
+
+	_, err = checkProtocol(ProtocolVersion + 1)
+	assert.ErrorIs(err, errIncompatibleProtocol)
+	assert.Contains(err.Error(), "requires one of versions 5, 6, 7, 8")
+}
+
+func TestVersionedPluginMap(t *testing.T) {
+	assert := assert.New(t)
+
+	versionedPlugins := versionedPluginMap()
+	assert.Len(versionedPlugins, len(protocols))
+	for _, version := range protocolVersions() {
+		assert.Contains(versionedPlugins, version)
+	}
+	// The handshake advertises the latest version
+	assert.Equal([]int{5, 6, 7, ProtocolVersion}, protocolVersions())
+	assert.EqualValues(ProtocolVersion, Handshake.ProtocolVersion)
+}
@@ -10,20 +10,32 @@ import (
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/dagvmproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
-// Handshake is a common handshake that is shared by plugin and host.
+// Kinds of VMs that plugins can serve
+const (
+	chainVMKind uint32 = iota
+	dagVMKind
+)
+
+// Handshake is a common handshake that is shared by plugin and host. The
+// version of the protocol is negotiated during the handshake, see
+// [protocols].
 var Handshake = plugin.HandshakeConfig{
-	ProtocolVersion:  5,
+	ProtocolVersion:  ProtocolVersion,
 	MagicCookieKey:   "VM_PLUGIN",
 	MagicCookieValue: "dynamic",
 }
 
-// PluginMap is the map of plugins we can dispense.
+// PluginMap is the map of plugins we can dispense. Plugins serve a chain VM
+// as "vm" and a DAG VM as "dagvm".
 var PluginMap = map[string]plugin.Plugin{
-	"vm": &Plugin{},
+	"vm":    &Plugin{},
+	"dagvm": &DAGPlugin{},
 }
 
 // Plugin is the implementation of plugin.Plugin so we can serve/consume this.
@@ -48,3 +60,26 @@ func (p *Plugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
 func (p *Plugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
 	return NewClient(vmproto.NewVMClient(c), broker), nil
 }
+
+// DAGPlugin is the implementation of plugin.Plugin for DAG VMs.
+type DAGPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	// Concrete implementation, written in Go. This is only used for plugins
+	// that are written in Go.
+	vm vertex.DAGVM
+}
+
+// NewDAG creates a new plugin from the provided DAG VM
+func NewDAG(vm vertex.DAGVM) *DAGPlugin { return &DAGPlugin{vm: vm} }
+
+// GRPCServer registers a new GRPC server.
+func (p *DAGPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	vmproto.RegisterVMServer(s, newDAGServer(p.vm, broker))
+	dagvmproto.RegisterDAGVMServer(s, NewDAGServer(p.vm))
+	return nil
+}
+
+// GRPCClient returns a new GRPC client
+func (p *DAGPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return NewDAGClient(NewClient(vmproto.NewVMClient(c), broker), dagvmproto.NewDAGVMClient(c)), nil
+}
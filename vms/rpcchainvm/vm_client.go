@@ -7,15 +7,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore"
 	"github.com/ava-labs/avalanchego/api/keystore/gkeystore/gkeystoreproto"
 	"github.com/ava-labs/avalanchego/chains/atomic/gsharedmemory"
 	"github.com/ava-labs/avalanchego/chains/atomic/gsharedmemory/gsharedmemoryproto"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/encdb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/rpcdb"
 	"github.com/ava-labs/avalanchego/database/rpcdb/rpcdbproto"
@@ -25,7 +30,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/subprocess"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/chain"
 	"github.com/ava-labs/avalanchego/vms/components/missing"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup"
@@ -35,15 +42,21 @@ import (
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup/gsubnetlookupproto"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators/gvalidatorsproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger/messengerproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
 var (
-	errUnsupportedFXs = errors.New("unsupported feature extensions")
+	errUnsupportedFXs      = errors.New("unsupported feature extensions")
+	errUnsupportedKeystore = errors.New("the plugin's protocol version doesn't support the keystore")
 
-	_ block.ChainVM = &VMClient{}
+	_ block.ChainVM               = &VMClient{}
+	_ block.TimedBlock            = &BlockClient{}
+	_ common.Exitable             = &VMClient{}
+	_ keystore.BlockchainKeystore = unsupportedKeystore{}
 )
 
 const (
@@ -51,6 +64,18 @@ const (
 	missingCacheSize    = 256
 	unverifiedCacheSize = 256
 	bytesToIDCacheSize  = 512
+
+	// maxPeerEventsBatchSize is the most peer events sent to the plugin in a
+	// single request. Once this many events are pending, they're sent
+	// immediately.
+	maxPeerEventsBatchSize = 256
+	// peerEventsFlushDelay is the longest a peer event waits for others to be
+	// batched with before it's sent to the plugin.
+	peerEventsFlushDelay = 100 * time.Millisecond
+
+	// processPollFrequency is how often the plugin's process is checked for
+	// having exited
+	processPollFrequency = 100 * time.Millisecond
 )
 
 // VMClient is an implementation of VM that talks over RPC.
@@ -59,6 +84,11 @@ type VMClient struct {
 	client vmproto.VMClient
 	broker *plugin.GRPCBroker
 	proc   *plugin.Client
+	// sandbox limits the resources the plugin's process may use
+	sandbox subprocess.Sandbox
+	// protocol is what the version of the protocol the plugin speaks
+	// supports
+	protocol protocol
 
 	db           *rpcdb.DatabaseServer
 	messenger    *messenger.Server
@@ -66,24 +96,73 @@ type VMClient struct {
 	sharedMemory *gsharedmemory.Server
 	bcLookup     *galiaslookup.Server
 	snLookup     *gsubnetlookup.Server
+	vdrState     *gvalidators.Server
 
 	serverCloser grpcutils.ServerCloser
 	conns        []*grpc.ClientConn
 
+	// peerEventsLock protects peerEvents and peerEventsTimer
+	peerEventsLock sync.Mutex
+	// peerEvents are the Connected and Disconnected calls, in the order they
+	// were made, that haven't been sent to the plugin yet
+	peerEvents []*vmproto.PeerEvent
+	// peerEventsTimer, if non-nil, will send the pending peer events
+	peerEventsTimer *time.Timer
+
+	// exited is closed when the plugin's process exits before Shutdown is
+	// called. closed is closed when Shutdown is called.
+	exited chan struct{}
+	closed chan struct{}
+
 	ctx *snow.Context
 }
 
 // NewClient returns a VM connected to a remote VM
 func NewClient(client vmproto.VMClient, broker *plugin.GRPCBroker) *VMClient {
 	return &VMClient{
-		client: client,
-		broker: broker,
+		client:   client,
+		broker:   broker,
+		protocol: protocols[ProtocolVersion],
+		exited:   make(chan struct{}),
+		closed:   make(chan struct{}),
 	}
 }
 
 // SetProcess gives ownership of the server process to the client.
 func (vm *VMClient) SetProcess(proc *plugin.Client) {
 	vm.proc = proc
+	go vm.monitorProcess()
+}
+
+// Exited returns a channel that is closed when the plugin's process exits
+// without the VM having been shut down
+func (vm *VMClient) Exited() <-chan struct{} { return vm.exited }
+
+// monitorProcess closes [vm.exited] once the plugin's process exits, unless
+// the VM was shut down first
+func (vm *VMClient) monitorProcess() {
+	ticker := time.NewTicker(processPollFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-vm.closed:
+			return
+		case <-ticker.C:
+			// The process is killed when the VM is shut down, so [vm.closed]
+			// must be checked again before blaming the process for exiting.
+			select {
+			case <-vm.closed:
+				return
+			default:
+			}
+			if vm.proc.Exited() {
+				vm.closeSandbox()
+				close(vm.exited)
+				return
+			}
+		}
+	}
 }
 
 func (vm *VMClient) Initialize(
@@ -95,13 +174,78 @@ func (vm *VMClient) Initialize(
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
+	resp, err := vm.initialize(ctx, dbManager, genesisBytes, upgradeBytes, configBytes, toEngine, fxs)
+	if err != nil {
+		return err
+	}
+
+	id, err := ids.ToID(resp.LastAcceptedID)
+	if err != nil {
+		return err
+	}
+	parentID, err := ids.ToID(resp.LastAcceptedParentID)
+	if err != nil {
+		return err
+	}
+
+	status := choices.Status(resp.Status)
+	vm.ctx.Log.AssertDeferredNoError(status.Valid)
+
+	timestamp, err := parseTimestamp(resp.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	lastAcceptedBlk := &BlockClient{
+		vm:        vm,
+		id:        id,
+		parentID:  parentID,
+		status:    status,
+		bytes:     resp.Bytes,
+		height:    resp.Height,
+		timestamp: timestamp,
+	}
+
+	chainState, err := chain.NewMeteredState(
+		ctx.Metrics,
+		fmt.Sprintf("%s_rpcchainvm", ctx.Namespace),
+		&chain.Config{
+			DecidedCacheSize:    decidedCacheSize,
+			MissingCacheSize:    missingCacheSize,
+			UnverifiedCacheSize: unverifiedCacheSize,
+			BytesToIDCacheSize:  bytesToIDCacheSize,
+			LastAcceptedBlock:   lastAcceptedBlk,
+			GetBlock:            vm.getBlock,
+			UnmarshalBlock:      vm.parseBlock,
+			BuildBlock:          vm.buildBlock,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	vm.State = chainState
+
+	return nil
+}
+
+// initialize serves the resources of [ctx] to the plugin and initializes the
+// plugin's VM
+func (vm *VMClient) initialize(
+	ctx *snow.Context,
+	dbManager manager.Manager,
+	genesisBytes []byte,
+	upgradeBytes []byte,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) (*vmproto.InitializeResponse, error) {
 	if len(fxs) != 0 {
-		return errUnsupportedFXs
+		return nil, errUnsupportedFXs
 	}
 
 	epochFirstTransitionBytes, err := ctx.EpochFirstTransition.MarshalBinary()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	vm.ctx = ctx
@@ -121,7 +265,11 @@ func (vm *VMClient) Initialize(
 	}
 
 	vm.messenger = messenger.NewServer(toEngine)
-	vm.keystore = gkeystore.NewServer(ctx.Keystore, vm.broker)
+	var ks keystore.BlockchainKeystore = unsupportedKeystore{}
+	if vm.protocol.userKeys {
+		ks = ctx.Keystore
+	}
+	vm.keystore = gkeystore.NewServer(ks, vm.broker)
 	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, dbManager.Current().Database)
 	vm.bcLookup = galiaslookup.NewServer(ctx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(ctx.SNLookup)
@@ -150,7 +298,15 @@ func (vm *VMClient) Initialize(
 	snLookupBrokerID := vm.broker.NextId()
 	go vm.broker.AcceptAndServe(snLookupBrokerID, vm.startSNLookupServer)
 
-	resp, err := vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
+	// start the validator state server, if there is a validator state
+	var vdrStateBrokerID uint32
+	if ctx.ValidatorState != nil {
+		vm.vdrState = gvalidators.NewServer(ctx.ValidatorState)
+		vdrStateBrokerID = vm.broker.NextId()
+		go vm.broker.AcceptAndServe(vdrStateBrokerID, vm.startValidatorStateServer)
+	}
+
+	return vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
 		NetworkID:            ctx.NetworkID,
 		SubnetID:             ctx.SubnetID[:],
 		ChainID:              ctx.ChainID[:],
@@ -168,52 +324,8 @@ func (vm *VMClient) Initialize(
 		SnLookupServer:       snLookupBrokerID,
 		EpochFirstTransition: epochFirstTransitionBytes,
 		EpochDuration:        uint64(ctx.EpochDuration),
+		ValidatorStateServer: vdrStateBrokerID,
 	})
-	if err != nil {
-		return err
-	}
-
-	id, err := ids.ToID(resp.LastAcceptedID)
-	if err != nil {
-		return err
-	}
-	parentID, err := ids.ToID(resp.LastAcceptedParentID)
-	if err != nil {
-		return err
-	}
-
-	status := choices.Status(resp.Status)
-	vm.ctx.Log.AssertDeferredNoError(status.Valid)
-
-	lastAcceptedBlk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
-	}
-
-	chainState, err := chain.NewMeteredState(
-		ctx.Metrics,
-		fmt.Sprintf("%s_rpcchainvm", ctx.Namespace),
-		&chain.Config{
-			DecidedCacheSize:    decidedCacheSize,
-			MissingCacheSize:    missingCacheSize,
-			UnverifiedCacheSize: unverifiedCacheSize,
-			BytesToIDCacheSize:  bytesToIDCacheSize,
-			LastAcceptedBlock:   lastAcceptedBlk,
-			GetBlock:            vm.getBlock,
-			UnmarshalBlock:      vm.parseBlock,
-			BuildBlock:          vm.buildBlock,
-		},
-	)
-	if err != nil {
-		return err
-	}
-	vm.State = chainState
-
-	return nil
 }
 
 func (vm *VMClient) startDBServer(opts []grpc.ServerOption) *grpc.Server {
@@ -267,6 +379,13 @@ func (vm *VMClient) startSNLookupServer(opts []grpc.ServerOption) *grpc.Server {
 	return server
 }
 
+func (vm *VMClient) startValidatorStateServer(opts []grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	vm.serverCloser.Add(server)
+	gvalidatorsproto.RegisterValidatorStateServer(server, vm.vdrState)
+	return server
+}
+
 func (vm *VMClient) Bootstrapping() error {
 	_, err := vm.client.Bootstrapping(context.Background(), &vmproto.BootstrappingRequest{})
 	return err
@@ -278,6 +397,22 @@ func (vm *VMClient) Bootstrapped() error {
 }
 
 func (vm *VMClient) Shutdown() error {
+	// Pending peer events are dropped as the plugin is going away
+	vm.peerEventsLock.Lock()
+	if vm.peerEventsTimer != nil {
+		vm.peerEventsTimer.Stop()
+		vm.peerEventsTimer = nil
+	}
+	vm.peerEvents = nil
+	vm.peerEventsLock.Unlock()
+
+	// The VM is shut down again by its chain if restarting it failed
+	select {
+	case <-vm.closed:
+	default:
+		close(vm.closed)
+	}
+
 	errs := wrappers.Errs{}
 	_, err := vm.client.Shutdown(context.Background(), &vmproto.ShutdownRequest{})
 	errs.Add(err)
@@ -288,9 +423,17 @@ func (vm *VMClient) Shutdown() error {
 	}
 
 	vm.proc.Kill()
+	vm.closeSandbox()
 	return errs.Err
 }
 
+// closeSandbox releases the sandbox of the plugin's exited process
+func (vm *VMClient) closeSandbox() {
+	if vm.sandbox != nil {
+		vm.sandbox.Close()
+	}
+}
+
 func (vm *VMClient) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	resp, err := vm.client.CreateHandlers(context.Background(), &vmproto.CreateHandlersRequest{})
 	if err != nil {
@@ -335,6 +478,15 @@ func (vm *VMClient) CreateStaticHandlers() (map[string]*common.HTTPHandler, erro
 	return handlers, nil
 }
 
+// kind returns the kind of VM the plugin serves
+func (vm *VMClient) kind() (uint32, error) {
+	resp, err := vm.client.Kind(context.Background(), &vmproto.KindRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Kind, nil
+}
+
 func (vm *VMClient) buildBlock() (snowman.Block, error) {
 	resp, err := vm.client.BuildBlock(context.Background(), &vmproto.BuildBlockRequest{})
 	if err != nil {
@@ -347,13 +499,19 @@ func (vm *VMClient) buildBlock() (snowman.Block, error) {
 	parentID, err := ids.ToID(resp.ParentID)
 	vm.ctx.Log.AssertNoError(err)
 
+	timestamp, err := parseTimestamp(resp.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
 	return &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   choices.Processing,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
+		vm:        vm,
+		id:        id,
+		parentID:  parentID,
+		status:    choices.Processing,
+		bytes:     resp.Bytes,
+		height:    resp.Height,
+		timestamp: timestamp,
 	}, nil
 }
 
@@ -374,13 +532,19 @@ func (vm *VMClient) parseBlock(bytes []byte) (snowman.Block, error) {
 	status := choices.Status(resp.Status)
 	vm.ctx.Log.AssertDeferredNoError(status.Valid)
 
+	timestamp, err := parseTimestamp(resp.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
 	blk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    bytes,
-		height:   resp.Height,
+		vm:        vm,
+		id:        id,
+		parentID:  parentID,
+		status:    status,
+		bytes:     bytes,
+		height:    resp.Height,
+		timestamp: timestamp,
 	}
 
 	return blk, nil
@@ -399,13 +563,19 @@ func (vm *VMClient) getBlock(id ids.ID) (snowman.Block, error) {
 	status := choices.Status(resp.Status)
 	vm.ctx.Log.AssertDeferredNoError(status.Valid)
 
+	timestamp, err := parseTimestamp(resp.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
 	blk := &BlockClient{
-		vm:       vm,
-		id:       id,
-		parentID: parentID,
-		status:   status,
-		bytes:    resp.Bytes,
-		height:   resp.Height,
+		vm:        vm,
+		id:        id,
+		parentID:  parentID,
+		status:    status,
+		bytes:     resp.Bytes,
+		height:    resp.Height,
+		timestamp: timestamp,
 	}
 
 	return blk, nil
@@ -445,6 +615,9 @@ type BlockClient struct {
 	status   choices.Status
 	bytes    []byte
 	height   uint64
+	// timestamp is the zero time if the plugin doesn't know when the block
+	// was produced
+	timestamp time.Time
 }
 
 func (b *BlockClient) ID() ids.ID { return b.id }
@@ -475,21 +648,122 @@ func (b *BlockClient) Parent() snowman.Block {
 }
 
 func (b *BlockClient) Verify() error {
-	_, err := b.vm.client.BlockVerify(context.Background(), &vmproto.BlockVerifyRequest{
+	resp, err := b.vm.client.BlockVerify(context.Background(), &vmproto.BlockVerifyRequest{
 		Bytes: b.bytes,
 	})
+	if err != nil {
+		return err
+	}
+
+	// Some blocks only know when they were produced once they're verified
+	if len(resp.Timestamp) != 0 {
+		b.timestamp, err = parseTimestamp(resp.Timestamp)
+	}
+	return err
+}
+
+func (b *BlockClient) Bytes() []byte        { return b.bytes }
+func (b *BlockClient) Height() uint64       { return b.height }
+func (b *BlockClient) Timestamp() time.Time { return b.timestamp }
+
+// parseTimestamp returns the time serialized in [bytes] by the plugin, or the
+// zero time if [bytes] is empty
+func parseTimestamp(bytes []byte) (time.Time, error) {
+	timestamp := time.Time{}
+	if len(bytes) == 0 {
+		return timestamp, nil
+	}
+	err := timestamp.UnmarshalBinary(bytes)
+	return timestamp, err
+}
+
+// Connected and Disconnected are batched to amortize the cost of the RPC over
+// the bursts of peer events that happen when the node starts or loses its
+// connectivity. Events are sent once [maxPeerEventsBatchSize] are pending or
+// [peerEventsFlushDelay] after the first pending event, whichever is first.
+// Events are always sent in the order they happened.
+
+func (vm *VMClient) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
+	return vm.addPeerEvent(&vmproto.PeerEvent{
+		NodeID:    nodeID.Bytes(),
+		Connected: true,
+		Version:   nodeVersion.String(),
+	})
+}
+
+func (vm *VMClient) Disconnected(nodeID ids.ShortID) error {
+	return vm.addPeerEvent(&vmproto.PeerEvent{
+		NodeID: nodeID.Bytes(),
+	})
+}
+
+// addPeerEvent queues [event] to be sent to the plugin.
+// Assumes [vm.ctx.Lock] is held.
+func (vm *VMClient) addPeerEvent(event *vmproto.PeerEvent) error {
+	if !vm.protocol.peerEvents {
+		return nil
+	}
+
+	vm.peerEventsLock.Lock()
+	vm.peerEvents = append(vm.peerEvents, event)
+	if len(vm.peerEvents) < maxPeerEventsBatchSize {
+		if vm.peerEventsTimer == nil {
+			vm.peerEventsTimer = time.AfterFunc(peerEventsFlushDelay, vm.flushPeerEvents)
+		}
+		vm.peerEventsLock.Unlock()
+		return nil
+	}
+	events := vm.peerEvents
+	vm.peerEvents = nil
+	vm.peerEventsLock.Unlock()
+
+	return vm.sendPeerEvents(events)
+}
+
+// flushPeerEvents sends the pending peer events to the plugin. It grabs
+// [vm.ctx.Lock] so that the events are serialized with the other calls into
+// the VM.
+func (vm *VMClient) flushPeerEvents() {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.peerEventsLock.Lock()
+	events := vm.peerEvents
+	vm.peerEvents = nil
+	vm.peerEventsTimer = nil
+	vm.peerEventsLock.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+	if err := vm.sendPeerEvents(events); err != nil {
+		vm.ctx.Log.Error("failed to send %d peer events to the plugin: %s", len(events), err)
+	}
+}
+
+func (vm *VMClient) sendPeerEvents(events []*vmproto.PeerEvent) error {
+	_, err := vm.client.PeerEvents(context.Background(), &vmproto.PeerEventsRequest{
+		Events: events,
+	})
 	return err
 }
 
-func (b *BlockClient) Bytes() []byte  { return b.bytes }
-func (b *BlockClient) Height() uint64 { return b.height }
+// unsupportedKeystore is the keystore of plugins whose protocol version
+// doesn't support the keystore
+type unsupportedKeystore struct{}
 
-// AV-590, quantify overhead of passing these over RPC
+func (unsupportedKeystore) GetDatabase(string, string) (*encdb.Database, error) {
+	return nil, errUnsupportedKeystore
+}
+
+func (unsupportedKeystore) GetRawDatabase(string, string) (database.Database, error) {
+	return nil, errUnsupportedKeystore
+}
 
-func (vm *VMClient) Connected(id ids.ShortID) error {
-	return nil // noop
+func (unsupportedKeystore) GetRawDatabaseWithKey(string, string) (database.Database, []byte, error) {
+	return nil, nil, errUnsupportedKeystore
 }
 
-func (vm *VMClient) Disconnected(id ids.ShortID) error {
-	return nil // noop
+func (unsupportedKeystore) GetSeed(string, string) ([]byte, error) {
+	return nil, errUnsupportedKeystore
 }
@@ -0,0 +1,224 @@
+// (c) 2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
+)
+
+const bufSize = 1024 * 1024
+
+// newTestClient returns a client of a VMServer that serves [vm] in memory,
+// and a function that closes both of them
+func newTestClient(tb testing.TB, vm block.ChainVM) (*VMClient, func()) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	vmproto.RegisterVMServer(server, NewServer(vm, nil))
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	client := NewClient(vmproto.NewVMClient(conn), nil)
+	client.ctx = snow.DefaultContextTest()
+	return client, func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+type peerEvent struct {
+	nodeID      ids.ShortID
+	nodeVersion version.Application
+}
+
+func TestPeerEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		lock   sync.Mutex
+		events []peerEvent
+	)
+	numEvents := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(events)
+	}
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.ConnectedF = func(nodeID ids.ShortID, nodeVersion version.Application) error {
+		lock.Lock()
+		defer lock.Unlock()
+		events = append(events, peerEvent{nodeID: nodeID, nodeVersion: nodeVersion})
+		return nil
+	}
+	vm.DisconnectedF = func(nodeID ids.ShortID) error {
+		lock.Lock()
+		defer lock.Unlock()
+		events = append(events, peerEvent{nodeID: nodeID})
+		return nil
+	}
+
+	client, closeFn := newTestClient(t, vm)
+	defer closeFn()
+
+	nodeIDs := make([]ids.ShortID, maxPeerEventsBatchSize)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestShortID()
+	}
+
+	// Events are held back until the batch is full
+	client.ctx.Lock.Lock()
+	for _, nodeID := range nodeIDs[:maxPeerEventsBatchSize-1] {
+		assert.NoError(client.Connected(nodeID, version.CurrentApp))
+	}
+	assert.Zero(numEvents())
+	assert.NoError(client.Connected(nodeIDs[maxPeerEventsBatchSize-1], version.CurrentApp))
+	client.ctx.Lock.Unlock()
+
+	assert.Equal(maxPeerEventsBatchSize, numEvents())
+	lock.Lock()
+	for i, event := range events {
+		assert.Equal(nodeIDs[i], event.nodeID)
+		assert.Equal(version.CurrentApp.String(), event.nodeVersion.String())
+	}
+	lock.Unlock()
+
+	// A partial batch is sent after a delay
+	client.ctx.Lock.Lock()
+	assert.NoError(client.Disconnected(nodeIDs[0]))
+	client.ctx.Lock.Unlock()
+
+	assert.Eventually(func() bool {
+		return numEvents() == maxPeerEventsBatchSize+1
+	}, 10*peerEventsFlushDelay, peerEventsFlushDelay/10)
+	lock.Lock()
+	last := events[len(events)-1]
+	lock.Unlock()
+	assert.Equal(nodeIDs[0], last.nodeID)
+	assert.Nil(last.nodeVersion)
+}
+
+func TestPeerEventsUnsupportedProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.ConnectedF = func(ids.ShortID, version.Application) error {
+		t.Fatal("plugin was told about a connected peer")
+		return nil
+	}
+
+	client, closeFn := newTestClient(t, vm)
+	defer closeFn()
+	client.protocol = protocols[5]
+
+	// A full batch would be sent immediately if the plugin was told about
+	// connected peers
+	client.ctx.Lock.Lock()
+	for i := 0; i < maxPeerEventsBatchSize; i++ {
+		assert.NoError(client.Connected(ids.GenerateTestShortID(), version.CurrentApp))
+	}
+	client.ctx.Lock.Unlock()
+	assert.Empty(client.peerEvents)
+}
+
+func TestBlockTimestamp(t *testing.T) {
+	assert := assert.New(t)
+
+	parent := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	timestamp := time.Unix(1632326400, 0)
+	blks := []*snowman.TestBlock{
+		{
+			TestDecidable: choices.TestDecidable{IDV: ids.GenerateTestID()},
+			ParentV:       parent,
+			TimestampV:    timestamp,
+		},
+		{
+			TestDecidable: choices.TestDecidable{IDV: ids.GenerateTestID()},
+			ParentV:       parent,
+		},
+	}
+
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.CantBuildBlock = true
+	vm.BuildBlockF = func() (snowman.Block, error) {
+		blk := blks[0]
+		blks = blks[1:]
+		return blk, nil
+	}
+
+	client, closeFn := newTestClient(t, vm)
+	defer closeFn()
+
+	blk, err := client.buildBlock()
+	assert.NoError(err)
+	assert.True(timestamp.Equal(block.Timestamp(blk)))
+
+	// Blocks that don't know when they were produced report the zero time
+	blk, err = client.buildBlock()
+	assert.NoError(err)
+	assert.True(block.Timestamp(blk).IsZero())
+}
+
+// BenchmarkPeerEvents measures the cost of forwarding a peer event to the
+// plugin, per event, depending on how many events are batched together
+func BenchmarkPeerEvents(b *testing.B) {
+	vm := &block.TestVM{}
+	vm.ConnectedF = func(ids.ShortID, version.Application) error { return nil }
+
+	client, closeFn := newTestClient(b, vm)
+	defer closeFn()
+
+	event := &vmproto.PeerEvent{
+		NodeID:    ids.GenerateTestShortID().Bytes(),
+		Connected: true,
+		Version:   version.CurrentApp.String(),
+	}
+	for _, batchSize := range []int{1, 16, maxPeerEventsBatchSize} {
+		events := make([]*vmproto.PeerEvent, batchSize)
+		for i := range events {
+			events[i] = event
+		}
+
+		b.Run(fmt.Sprintf("batch size %d", batchSize), func(b *testing.B) {
+			for sent := 0; sent < b.N; sent += batchSize {
+				if err := client.sendPeerEvents(events); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
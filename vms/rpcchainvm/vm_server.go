@@ -6,6 +6,7 @@ package rpcchainvm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"google.golang.org/grpc"
@@ -22,8 +23,11 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
@@ -34,18 +38,26 @@ import (
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gsubnetlookup/gsubnetlookupproto"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gvalidators/gvalidatorsproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/messenger/messengerproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
-var _ vmproto.VMServer = &VMServer{}
+var (
+	errNotChainVM = errors.New("vm isn't a chain vm")
+
+	_ vmproto.VMServer = &VMServer{}
+)
 
 // VMServer is a VM that is managed over RPC.
 type VMServer struct {
 	vmproto.UnimplementedVMServer
-	vm     block.ChainVM
-	broker *plugin.GRPCBroker
+	vm common.VM
+	// chainVM is [vm] if it's a chain VM, and nil otherwise
+	chainVM block.ChainVM
+	broker  *plugin.GRPCBroker
 
 	serverCloser grpcutils.ServerCloser
 	connCloser   wrappers.Closer
@@ -56,6 +68,16 @@ type VMServer struct {
 
 // NewServer returns a vm instance connected to a remote vm instance
 func NewServer(vm block.ChainVM, broker *plugin.GRPCBroker) *VMServer {
+	return &VMServer{
+		vm:      vm,
+		chainVM: vm,
+		broker:  broker,
+	}
+}
+
+// newDAGServer returns a server of the methods of [vm] that aren't specific to
+// DAG VMs
+func newDAGServer(vm vertex.DAGVM, broker *plugin.GRPCBroker) *VMServer {
 	return &VMServer{
 		vm:     vm,
 		broker: broker,
@@ -158,6 +180,19 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		_ = vm.connCloser.Close()
 		return nil, err
 	}
+	vm.connCloser.Add(snLookupConn)
+
+	var vdrStateClient validators.State
+	if req.ValidatorStateServer != 0 {
+		vdrStateConn, err := vm.broker.Dial(req.ValidatorStateServer)
+		if err != nil {
+			// Ignore closing error to return the original error
+			_ = vm.connCloser.Close()
+			return nil, err
+		}
+		vm.connCloser.Add(vdrStateConn)
+		vdrStateClient = gvalidators.NewClient(gvalidatorsproto.NewValidatorStateClient(vdrStateConn))
+	}
 
 	msgClient := messenger.NewClient(messengerproto.NewMessengerClient(msgConn))
 	keystoreClient := gkeystore.NewClient(gkeystoreproto.NewKeystoreClient(keystoreConn), vm.broker)
@@ -187,6 +222,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		SharedMemory:         sharedMemoryClient,
 		BCLookup:             bcLookupClient,
 		SNLookup:             snLookupClient,
+		ValidatorState:       vdrStateClient,
 		EpochFirstTransition: epochFirstTransition,
 		EpochDuration:        time.Duration(req.EpochDuration),
 	}
@@ -199,22 +235,31 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 	}
 
 	vm.toEngine = toEngine
-	lastAccepted, err := vm.vm.LastAccepted()
+	if vm.chainVM == nil {
+		return &vmproto.InitializeResponse{}, nil
+	}
+
+	lastAccepted, err := vm.chainVM.LastAccepted()
 	if err != nil {
 		return nil, err
 	}
-	blk, err := vm.vm.GetBlock(lastAccepted)
+	blk, err := vm.chainVM.GetBlock(lastAccepted)
 	if err != nil {
 		return nil, err
 	}
 	parentID := blk.Parent().ID()
+	timestamp, err := timestampBytes(blk)
+	if err != nil {
+		return nil, err
+	}
 	return &vmproto.InitializeResponse{
 		LastAcceptedID:       lastAccepted[:],
 		LastAcceptedParentID: parentID[:],
 		Status:               uint32(choices.Accepted),
 		Height:               blk.Height(),
 		Bytes:                blk.Bytes(),
-	}, err
+		Timestamp:            timestamp,
+	}, nil
 }
 
 func (vm *VMServer) Bootstrapping(context.Context, *vmproto.BootstrappingRequest) (*vmproto.BootstrappingResponse, error) {
@@ -296,59 +341,86 @@ func (vm *VMServer) CreateHandlers(_ context.Context, req *vmproto.CreateHandler
 }
 
 func (vm *VMServer) BuildBlock(_ context.Context, _ *vmproto.BuildBlockRequest) (*vmproto.BuildBlockResponse, error) {
-	blk, err := vm.vm.BuildBlock()
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
+	blk, err := vm.chainVM.BuildBlock()
 	if err != nil {
 		return nil, err
 	}
 	blkID := blk.ID()
 	parentID := blk.Parent().ID()
+	timestamp, err := timestampBytes(blk)
+	if err != nil {
+		return nil, err
+	}
 	return &vmproto.BuildBlockResponse{
-		Id:       blkID[:],
-		ParentID: parentID[:],
-		Bytes:    blk.Bytes(),
-		Height:   blk.Height(),
+		Id:        blkID[:],
+		ParentID:  parentID[:],
+		Bytes:     blk.Bytes(),
+		Height:    blk.Height(),
+		Timestamp: timestamp,
 	}, nil
 }
 
 func (vm *VMServer) ParseBlock(_ context.Context, req *vmproto.ParseBlockRequest) (*vmproto.ParseBlockResponse, error) {
-	blk, err := vm.vm.ParseBlock(req.Bytes)
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
+	blk, err := vm.chainVM.ParseBlock(req.Bytes)
 	if err != nil {
 		return nil, err
 	}
 	blkID := blk.ID()
 	parentID := blk.Parent().ID()
+	timestamp, err := timestampBytes(blk)
+	if err != nil {
+		return nil, err
+	}
 	return &vmproto.ParseBlockResponse{
-		Id:       blkID[:],
-		ParentID: parentID[:],
-		Status:   uint32(blk.Status()),
-		Height:   blk.Height(),
+		Id:        blkID[:],
+		ParentID:  parentID[:],
+		Status:    uint32(blk.Status()),
+		Height:    blk.Height(),
+		Timestamp: timestamp,
 	}, nil
 }
 
 func (vm *VMServer) GetBlock(_ context.Context, req *vmproto.GetBlockRequest) (*vmproto.GetBlockResponse, error) {
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
 	}
-	blk, err := vm.vm.GetBlock(id)
+	blk, err := vm.chainVM.GetBlock(id)
 	if err != nil {
 		return nil, err
 	}
 	parentID := blk.Parent().ID()
+	timestamp, err := timestampBytes(blk)
+	if err != nil {
+		return nil, err
+	}
 	return &vmproto.GetBlockResponse{
-		ParentID: parentID[:],
-		Bytes:    blk.Bytes(),
-		Status:   uint32(blk.Status()),
-		Height:   blk.Height(),
+		ParentID:  parentID[:],
+		Bytes:     blk.Bytes(),
+		Status:    uint32(blk.Status()),
+		Height:    blk.Height(),
+		Timestamp: timestamp,
 	}, nil
 }
 
 func (vm *VMServer) SetPreference(_ context.Context, req *vmproto.SetPreferenceRequest) (*vmproto.SetPreferenceResponse, error) {
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
 	}
-	return &vmproto.SetPreferenceResponse{}, vm.vm.SetPreference(id)
+	return &vmproto.SetPreferenceResponse{}, vm.chainVM.SetPreference(id)
 }
 
 func (vm *VMServer) Health(_ context.Context, req *vmproto.HealthRequest) (*vmproto.HealthResponse, error) {
@@ -385,20 +457,76 @@ func (vm *VMServer) Version(_ context.Context, req *vmproto.VersionRequest) (*vm
 	}, err
 }
 
+func (vm *VMServer) PeerEvents(_ context.Context, req *vmproto.PeerEventsRequest) (*vmproto.PeerEventsResponse, error) {
+	for _, event := range req.Events {
+		nodeID, err := ids.ToShortID(event.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		if !event.Connected {
+			if err := vm.vm.Disconnected(nodeID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		nodeVersion, err := version.VersionParser.Parse(event.Version)
+		if err != nil {
+			return nil, err
+		}
+		if err := vm.vm.Connected(nodeID, nodeVersion); err != nil {
+			return nil, err
+		}
+	}
+	return &vmproto.PeerEventsResponse{}, nil
+}
+
+func (vm *VMServer) Kind(context.Context, *vmproto.KindRequest) (*vmproto.KindResponse, error) {
+	kind := chainVMKind
+	if vm.chainVM == nil {
+		kind = dagVMKind
+	}
+	return &vmproto.KindResponse{
+		Kind: kind,
+	}, nil
+}
+
 func (vm *VMServer) BlockVerify(_ context.Context, req *vmproto.BlockVerifyRequest) (*vmproto.BlockVerifyResponse, error) {
-	blk, err := vm.vm.ParseBlock(req.Bytes)
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
+	blk, err := vm.chainVM.ParseBlock(req.Bytes)
 	if err != nil {
 		return nil, err
 	}
-	return &vmproto.BlockVerifyResponse{}, blk.Verify()
+	if err := blk.Verify(); err != nil {
+		return nil, err
+	}
+	// Some blocks only know when they were produced once they're verified
+	timestamp, err := timestampBytes(blk)
+	return &vmproto.BlockVerifyResponse{
+		Timestamp: timestamp,
+	}, err
+}
+
+// timestampBytes returns the time [blk] was produced at, serialized to be sent
+// to the node, or nil if [blk] doesn't know it
+func timestampBytes(blk snowman.Block) ([]byte, error) {
+	timestamp := block.Timestamp(blk)
+	if timestamp.IsZero() {
+		return nil, nil
+	}
+	return timestamp.MarshalBinary()
 }
 
 func (vm *VMServer) BlockAccept(_ context.Context, req *vmproto.BlockAcceptRequest) (*vmproto.BlockAcceptResponse, error) {
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
 	}
-	blk, err := vm.vm.GetBlock(id)
+	blk, err := vm.chainVM.GetBlock(id)
 	if err != nil {
 		return nil, err
 	}
@@ -409,11 +537,14 @@ func (vm *VMServer) BlockAccept(_ context.Context, req *vmproto.BlockAcceptReque
 }
 
 func (vm *VMServer) BlockReject(_ context.Context, req *vmproto.BlockRejectRequest) (*vmproto.BlockRejectResponse, error) {
+	if vm.chainVM == nil {
+		return nil, errNotChainVM
+	}
 	id, err := ids.ToID(req.Id)
 	if err != nil {
 		return nil, err
 	}
-	blk, err := vm.vm.GetBlock(id)
+	blk, err := vm.chainVM.GetBlock(id)
 	if err != nil {
 		return nil, err
 	}
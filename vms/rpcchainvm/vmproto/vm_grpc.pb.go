@@ -30,6 +30,8 @@ type VMClient interface {
 	SetPreference(ctx context.Context, in *SetPreferenceRequest, opts ...grpc.CallOption) (*SetPreferenceResponse, error)
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	PeerEvents(ctx context.Context, in *PeerEventsRequest, opts ...grpc.CallOption) (*PeerEventsResponse, error)
+	Kind(ctx context.Context, in *KindRequest, opts ...grpc.CallOption) (*KindResponse, error)
 	BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error)
 	BlockAccept(ctx context.Context, in *BlockAcceptRequest, opts ...grpc.CallOption) (*BlockAcceptResponse, error)
 	BlockReject(ctx context.Context, in *BlockRejectRequest, opts ...grpc.CallOption) (*BlockRejectResponse, error)
@@ -151,6 +153,24 @@ func (c *vMClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *vMClient) PeerEvents(ctx context.Context, in *PeerEventsRequest, opts ...grpc.CallOption) (*PeerEventsResponse, error) {
+	out := new(PeerEventsResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/PeerEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMClient) Kind(ctx context.Context, in *KindRequest, opts ...grpc.CallOption) (*KindResponse, error) {
+	out := new(KindResponse)
+	err := c.cc.Invoke(ctx, "/vmproto.VM/Kind", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vMClient) BlockVerify(ctx context.Context, in *BlockVerifyRequest, opts ...grpc.CallOption) (*BlockVerifyResponse, error) {
 	out := new(BlockVerifyResponse)
 	err := c.cc.Invoke(ctx, "/vmproto.VM/BlockVerify", in, out, opts...)
@@ -194,6 +214,8 @@ type VMServer interface {
 	SetPreference(context.Context, *SetPreferenceRequest) (*SetPreferenceResponse, error)
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+	PeerEvents(context.Context, *PeerEventsRequest) (*PeerEventsResponse, error)
+	Kind(context.Context, *KindRequest) (*KindResponse, error)
 	BlockVerify(context.Context, *BlockVerifyRequest) (*BlockVerifyResponse, error)
 	BlockAccept(context.Context, *BlockAcceptRequest) (*BlockAcceptResponse, error)
 	BlockReject(context.Context, *BlockRejectRequest) (*BlockRejectResponse, error)
@@ -240,6 +262,12 @@ func (UnimplementedVMServer) Health(context.Context, *HealthRequest) (*HealthRes
 func (UnimplementedVMServer) Version(context.Context, *VersionRequest) (*VersionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
+func (UnimplementedVMServer) PeerEvents(context.Context, *PeerEventsRequest) (*PeerEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeerEvents not implemented")
+}
+func (UnimplementedVMServer) Kind(context.Context, *KindRequest) (*KindResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kind not implemented")
+}
 func (UnimplementedVMServer) BlockVerify(context.Context, *BlockVerifyRequest) (*BlockVerifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockVerify not implemented")
 }
@@ -478,6 +506,42 @@ func _VM_Version_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VM_PeerEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).PeerEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/PeerEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).PeerEvents(ctx, req.(*PeerEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VM_Kind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KindRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServer).Kind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vmproto.VM/Kind",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServer).Kind(ctx, req.(*KindRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VM_BlockVerify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockVerifyRequest)
 	if err := dec(in); err != nil {
@@ -587,6 +651,14 @@ var VM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _VM_Version_Handler,
 		},
+		{
+			MethodName: "PeerEvents",
+			Handler:    _VM_PeerEvents_Handler,
+		},
+		{
+			MethodName: "Kind",
+			Handler:    _VM_Kind_Handler,
+		},
 		{
 			MethodName: "BlockVerify",
 			Handler:    _VM_BlockVerify_Handler,